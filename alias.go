@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+/* ==================== Aliases e comando padrão (~/.biodoc-runner.yaml) ==================== */
+
+// "aliases: {smoke: run-all --image fixtures/ref.jpg --preclean}" e "default_command: smoke".
+// O alias troca só o comando; as flags digitadas depois dele vêm por último (e ganham)
+func expandAlias(args []string) ([]string, error) {
+	c, err := loadRunnerConfig(configPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return args, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		if c.DefaultCommand == "" {
+			return args, nil
+		}
+		args = []string{c.DefaultCommand}
+	}
+	seen := map[string]bool{}
+	for {
+		def, ok := c.Aliases[args[0]]
+		if !ok {
+			return args, nil
+		}
+		if seen[args[0]] {
+			return nil, fmt.Errorf(tr("alias %q é recursivo"), args[0])
+		}
+		seen[args[0]] = true
+		words, err := splitCommandLine(def)
+		if err != nil {
+			return nil, fmt.Errorf("alias %q: %w", args[0], err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf(tr("alias %q vazio"), args[0])
+		}
+		args = append(words, args[1:]...)
+	}
+}
+
+// separa como o shell: espaços, 'aspas simples', "aspas duplas" e \ fora das simples
+func splitCommandLine(s string) ([]string, error) {
+	var out []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				out = append(out, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New(tr("aspas ou \\ sem fechar"))
+	}
+	if inWord {
+		out = append(out, cur.String())
+	}
+	return out, nil
+}
+
+// lista os aliases do config no usage
+func printAliases() {
+	c, err := loadRunnerConfig(configPath())
+	if err != nil || (len(c.Aliases) == 0 && c.DefaultCommand == "") {
+		return
+	}
+	fmt.Println()
+	fmt.Printf(tr("Aliases (%s):\n"), configPath())
+	names := make([]string, 0, len(c.Aliases))
+	for n := range c.Aliases {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Printf("  %-13s = %s\n", n, c.Aliases[n])
+	}
+	if c.DefaultCommand != "" {
+		fmt.Printf(tr("  (sem comando: %s)\n"), c.DefaultCommand)
+	}
+}
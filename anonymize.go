@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/* ==================== anonymize-image ==================== */
+
+type anonymizeOptions struct {
+	Mode    string // pixelate | blur
+	Block   int    // lado do bloco (pixelate) ou raio (blur), em px; 0 = proporcional à região
+	Regions []image.Rectangle
+	OutDir  string
+}
+
+// --region x,y,w,h (repetível)
+type regionFlag []image.Rectangle
+
+func (f *regionFlag) String() string { return fmt.Sprint([]image.Rectangle(*f)) }
+
+func (f *regionFlag) Set(v string) error {
+	r, err := parseRegion(v)
+	if err != nil {
+		return err
+	}
+	*f = append(*f, r)
+	return nil
+}
+
+// "x,y,w,h" → retângulo
+func parseRegion(s string) (image.Rectangle, error) {
+	p := strings.Split(s, ",")
+	if len(p) != 4 {
+		return image.Rectangle{}, fmt.Errorf(tr("região inválida %q (use x,y,w,h)"), s)
+	}
+	var n [4]int
+	for i, v := range p {
+		x, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || x < 0 {
+			return image.Rectangle{}, fmt.Errorf(tr("região inválida %q (use x,y,w,h)"), s)
+		}
+		n[i] = x
+	}
+	return image.Rect(n[0], n[1], n[0]+n[2], n[1]+n[3]), nil
+}
+
+// região do rosto por heurística de pele (YCbCr): maior mancha de pele numa grade reduzida.
+// Sem mancha relevante, cobre o centro-alto da imagem, onde fica o rosto em selfie/documento.
+func guessFaceRegion(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	step := max(1, b.Dx()/160)
+	gw, gh := b.Dx()/step, b.Dy()/step
+	skin := make([]bool, gw*gh)
+	for gy := 0; gy < gh; gy++ {
+		for gx := 0; gx < gw; gx++ {
+			r, g, bl, _ := img.At(b.Min.X+gx*step, b.Min.Y+gy*step).RGBA()
+			_, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+			skin[gy*gw+gx] = cb >= 77 && cb <= 127 && cr >= 133 && cr <= 173
+		}
+	}
+	seen := make([]bool, len(skin))
+	var best image.Rectangle
+	bestN := 0
+	for i := range skin {
+		if !skin[i] || seen[i] {
+			continue
+		}
+		// busca em largura da mancha
+		queue := []int{i}
+		seen[i] = true
+		r := image.Rect(i%gw, i/gw, i%gw+1, i/gw+1)
+		n := 0
+		for len(queue) > 0 {
+			c := queue[0]
+			queue = queue[1:]
+			n++
+			x, y := c%gw, c/gw
+			r = r.Union(image.Rect(x, y, x+1, y+1))
+			for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				nx, ny := x+d[0], y+d[1]
+				if nx < 0 || ny < 0 || nx >= gw || ny >= gh {
+					continue
+				}
+				if j := ny*gw + nx; skin[j] && !seen[j] {
+					seen[j] = true
+					queue = append(queue, j)
+				}
+			}
+		}
+		if n > bestN {
+			best, bestN = r, n
+		}
+	}
+	if bestN*50 < gw*gh { // < 2% da imagem
+		w, h := b.Dx()/2, b.Dy()*3/5
+		return image.Rect(b.Min.X+(b.Dx()-w)/2, b.Min.Y+b.Dy()/10, b.Min.X+(b.Dx()+w)/2, b.Min.Y+b.Dy()/10+h)
+	}
+	r := image.Rect(best.Min.X*step, best.Min.Y*step, best.Max.X*step, best.Max.Y*step).Add(b.Min)
+	// margem de 20% para pegar cabelo/orelhas
+	mx, my := r.Dx()/5, r.Dy()/5
+	return image.Rect(r.Min.X-mx, r.Min.Y-my, r.Max.X+mx, r.Max.Y+my).Intersect(b)
+}
+
+func pixelate(dst *image.RGBA, r image.Rectangle, block int) {
+	for y := r.Min.Y; y < r.Max.Y; y += block {
+		for x := r.Min.X; x < r.Max.X; x += block {
+			cell := image.Rect(x, y, x+block, y+block).Intersect(r)
+			var sr, sg, sb, n uint32
+			for yy := cell.Min.Y; yy < cell.Max.Y; yy++ {
+				for xx := cell.Min.X; xx < cell.Max.X; xx++ {
+					c := dst.RGBAAt(xx, yy)
+					sr, sg, sb = sr+uint32(c.R), sg+uint32(c.G), sb+uint32(c.B)
+					n++
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			avg := image.NewUniform(color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), 255})
+			draw.Draw(dst, cell, avg, image.Point{}, draw.Src)
+		}
+	}
+}
+
+// três passadas de média móvel (aproxima um gaussiano)
+func boxBlur(dst *image.RGBA, r image.Rectangle, radius int) {
+	for pass := 0; pass < 3; pass++ {
+		for _, horizontal := range []bool{true, false} {
+			src := image.NewRGBA(r)
+			draw.Draw(src, r, dst, r.Min, draw.Src)
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				for x := r.Min.X; x < r.Max.X; x++ {
+					var sr, sg, sb, n int
+					for k := -radius; k <= radius; k++ {
+						xx, yy := x, y
+						if horizontal {
+							xx += k
+						} else {
+							yy += k
+						}
+						if !(image.Point{xx, yy}.In(r)) {
+							continue
+						}
+						c := src.RGBAAt(xx, yy)
+						sr, sg, sb = sr+int(c.R), sg+int(c.G), sb+int(c.B)
+						n++
+					}
+					dst.SetRGBA(x, y, color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), 255})
+				}
+			}
+		}
+	}
+}
+
+// anonimiza um arquivo e grava em outDir (reencodar também descarta o EXIF)
+func anonymizeFile(path string, opt anonymizeOptions) (string, []image.Rectangle, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+	img, format, err := decodeImage(b)
+	if err != nil {
+		return "", nil, fmt.Errorf(tr("decodificar %s: %w"), path, err)
+	}
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	regions := opt.Regions
+	if len(regions) == 0 {
+		regions = []image.Rectangle{guessFaceRegion(img)}
+	}
+	for i, r := range regions {
+		r = r.Add(dst.Bounds().Min).Intersect(dst.Bounds())
+		regions[i] = r
+		if r.Empty() {
+			continue
+		}
+		size := opt.Block
+		if size <= 0 {
+			size = max(4, min(r.Dx(), r.Dy())/12)
+		}
+		if opt.Mode == "blur" {
+			boxBlur(dst, r, size)
+		} else {
+			pixelate(dst, r, size)
+		}
+	}
+
+	var buf bytes.Buffer
+	ext := ".png"
+	if format == "jpeg" {
+		ext = ".jpg"
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(opt.OutDir, 0755); err != nil {
+		return "", nil, err
+	}
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	out := filepath.Join(opt.OutDir, base+".anon"+ext)
+	return out, regions, os.WriteFile(out, buf.Bytes(), 0644)
+}
+
+// imagens usadas pelas execuções com falha no results store ("last" = só a última com falha)
+func failedRunImages(store, runID string) ([]string, error) {
+	runs, err := readRuns(store)
+	if err != nil {
+		return nil, fmt.Errorf("results store: %w", err)
+	}
+	var picked []runRecord
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		if runID == "last" && !r.OK || r.ID == runID {
+			picked = append(picked, r)
+			break
+		}
+		if runID == "all" && !r.OK {
+			picked = append(picked, r)
+		}
+	}
+	if len(picked) == 0 {
+		return nil, fmt.Errorf(tr("nenhuma execução %q com falha em %s"), runID, store)
+	}
+	seen := map[string]bool{}
+	var files []string
+	add := func(v any) {
+		if m, ok := v.(map[string]any); ok {
+			if f, ok := m["file"].(string); ok && f != "" && !seen[f] {
+				seen[f] = true
+				files = append(files, f)
+			}
+		}
+	}
+	for _, r := range picked {
+		add(r.Result["image"])
+		if imgs, ok := r.Result["images"].(map[string]any); ok {
+			for _, v := range imgs {
+				add(v)
+			}
+		}
+		// batch-verify: só as imagens sem match ou com erro
+		if rows, ok := r.Result["results"].([]any); ok {
+			for _, row := range rows {
+				m, _ := row.(map[string]any)
+				if match, _ := m["match"].(bool); match && m["error"] == nil {
+					continue
+				}
+				add(m["image"])
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf(tr("execuções com falha sem imagem registrada em %s"), store)
+	}
+	return files, nil
+}
+
+func cmdAnonymizeImage(files []string, opt anonymizeOptions) error {
+	if opt.Mode != "pixelate" && opt.Mode != "blur" {
+		return usageError(tr("--mode deve ser pixelate ou blur"))
+	}
+	if len(files) == 0 {
+		return usageError(tr("informe as imagens (arquivos, pastas ou --failed)"))
+	}
+	var outs []string
+	failed := 0
+	for _, f := range files {
+		out, regions, err := anonymizeFile(f, opt)
+		if err != nil {
+			outf("[anonymize] ❌ %v\n", err)
+			failed++
+			continue
+		}
+		rs := make([]string, len(regions))
+		for i, r := range regions {
+			rs[i] = fmt.Sprintf("%d,%d,%d,%d", r.Min.X, r.Min.Y, r.Dx(), r.Dy())
+		}
+		outf("[anonymize] %s → %s (região %s)\n", f, out, strings.Join(rs, " "))
+		outs = append(outs, out)
+	}
+	if len(opt.Regions) == 0 {
+		outln("[anonymize] região achada por heurística de cor de pele: confira antes de anexar (ou use --region x,y,w,h)")
+	}
+	setResult("anonymized", outs)
+	if failed > 0 {
+		return fmt.Errorf(tr("%d imagem(ns) não anonimizada(s)"), failed)
+	}
+	return nil
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+/* ==================== api (qualquer operação da spec OpenAPI) ==================== */
+
+type apiOperation struct {
+	ID      string
+	Method  string
+	Path    string
+	Summary string
+	op      map[string]any
+	item    map[string]any
+}
+
+type apiParam struct {
+	Name     string
+	In       string // path, query, header
+	Required bool
+	Type     string
+	Desc     string
+}
+
+var apiMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// operações com operationId, ordenadas pelo id
+func (s *openAPISpec) operations() []apiOperation {
+	var ops []apiOperation
+	paths := s.root["paths"].(map[string]any)
+	for _, p := range sortedKeys(paths) {
+		item, _ := paths[p].(map[string]any)
+		for _, m := range apiMethods {
+			op, ok := item[m].(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				continue
+			}
+			summary, _ := op["summary"].(string)
+			ops = append(ops, apiOperation{ID: id, Method: strings.ToUpper(m), Path: p, Summary: summary, op: op, item: item})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].ID < ops[j].ID })
+	return ops
+}
+
+func (s *openAPISpec) operation(id string) (apiOperation, bool) {
+	for _, o := range s.operations() {
+		if o.ID == id {
+			return o, true
+		}
+	}
+	return apiOperation{}, false
+}
+
+// parâmetros do path item + os da operação (a operação sobrescreve), $ref resolvido
+func (s *openAPISpec) params(o apiOperation) []apiParam {
+	byKey := map[string]apiParam{}
+	var order []string
+	for _, src := range []any{o.item["parameters"], o.op["parameters"]} {
+		for _, p := range schemaList(src) {
+			if ref, ok := p["$ref"].(string); ok {
+				r, err := resolveRef(s.root, ref)
+				if err != nil {
+					continue
+				}
+				p = r
+			}
+			ap := apiParam{}
+			ap.Name, _ = p["name"].(string)
+			ap.In, _ = p["in"].(string)
+			ap.Required, _ = p["required"].(bool)
+			ap.Desc, _ = p["description"].(string)
+			if sch, ok := p["schema"].(map[string]any); ok {
+				ap.Type, _ = sch["type"].(string)
+			} else {
+				ap.Type, _ = p["type"].(string) // OpenAPI 2
+			}
+			if ap.Name == "" || ap.In == "body" || ap.In == "cookie" {
+				continue
+			}
+			key := ap.In + ":" + ap.Name
+			if _, seen := byKey[key]; !seen {
+				order = append(order, key)
+			}
+			byKey[key] = ap
+		}
+	}
+	out := make([]apiParam, 0, len(order))
+	for _, k := range order {
+		out = append(out, byKey[k])
+	}
+	return out
+}
+
+// tipos de conteúdo aceitos no corpo ("" se a operação não tem requestBody)
+func (s *openAPISpec) bodyTypes(o apiOperation) ([]string, bool) {
+	rb, ok := o.op["requestBody"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	if ref, ok := rb["$ref"].(string); ok {
+		if r, err := resolveRef(s.root, ref); err == nil {
+			rb = r
+		}
+	}
+	required, _ := rb["required"].(bool)
+	content, _ := rb["content"].(map[string]any)
+	return sortedKeys(content), required
+}
+
+// security: [] na operação desliga a autenticação (rota pública)
+func (o apiOperation) public() bool {
+	sec, ok := o.op["security"].([]any)
+	return ok && len(sec) == 0
+}
+
+// spec do comando: --spec, OPENAPI_SPEC, ou a do --schema-spec
+func apiSpec(args []string) ([]string, *openAPISpec, error) {
+	args, path, set, err := stripValueFlag(args, "--spec")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !set {
+		path = os.Getenv("OPENAPI_SPEC")
+	}
+	if path == "" {
+		if schemaSpec != nil {
+			return args, schemaSpec, nil
+		}
+		return nil, nil, usageError(tr("informe a spec com --spec ARQ (ou OPENAPI_SPEC)"))
+	}
+	spec, err := loadOpenAPISpec(path)
+	return args, spec, err
+}
+
+func cmdAPI(baseURL, token string, args []string) error {
+	if len(args) == 0 {
+		return usageError(tr("uso: api list | api describe OPERATION_ID | api call OPERATION_ID [--PARAM valor...] [--data JSON|@arq|-] [--form campo=valor|campo=@arq] [--header K:V]"))
+	}
+	sub, rest := args[0], args[1:]
+	rest, spec, err := apiSpec(rest)
+	if err != nil {
+		return err
+	}
+	switch sub {
+	case "list":
+		tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, tr("OPERATION_ID\tMÉTODO\tCAMINHO\tRESUMO"))
+		ops := spec.operations()
+		for _, o := range ops {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", o.ID, o.Method, o.Path, o.Summary)
+		}
+		tw.Flush()
+		setResult("operations", len(ops))
+		return nil
+	case "describe", "call":
+		if len(rest) == 0 || strings.HasPrefix(rest[0], "-") {
+			return usageError(tr("informe o operationId (api list mostra os disponíveis)"))
+		}
+		o, ok := spec.operation(rest[0])
+		if !ok {
+			return usageError(fmt.Sprintf(tr("operationId %q não existe na spec (api list mostra os disponíveis)"), rest[0]))
+		}
+		if sub == "describe" {
+			describeOperation(spec, o)
+			return nil
+		}
+		return callOperation(baseURL, token, spec, o, rest[1:])
+	}
+	return usageError(tr("subcomando desconhecido: api ") + sub + " (list, describe, call)")
+}
+
+func describeOperation(spec *openAPISpec, o apiOperation) {
+	outf("%s %s  (%s)\n", o.Method, o.Path, o.ID)
+	if o.Summary != "" {
+		outln(o.Summary)
+	}
+	for _, p := range spec.params(o) {
+		req := ""
+		if p.Required {
+			req = tr(" (obrigatório)")
+		}
+		outf("  --%s %s  [%s]%s %s\n", p.Name, orDash(p.Type), p.In, req, p.Desc)
+	}
+	if types, required := spec.bodyTypes(o); len(types) > 0 {
+		req := ""
+		if required {
+			req = tr(" (obrigatório)")
+		}
+		outf("  corpo%s: %s (--data para JSON, --form para multipart)\n", req, strings.Join(types, ", "))
+	}
+	if o.public() {
+		outln("  sem autenticação (security: [])")
+	}
+}
+
+// flags dinâmicas: cada parâmetro da operação vira --nome valor; array aceita repetir
+func callOperation(baseURL, token string, spec *openAPISpec, o apiOperation, args []string) error {
+	args, data, dataSet, err := stripValueFlag(args, "--data")
+	if err != nil {
+		return usageError(err.Error())
+	}
+	args, forms, err := stripValueFlags(args, "--form")
+	if err != nil {
+		return usageError(err.Error())
+	}
+	args, extraHeaders, err := stripValueFlags(args, "--header")
+	if err != nil {
+		return usageError(err.Error())
+	}
+	if dataSet && len(forms) > 0 {
+		return usageError(tr("use --data ou --form, não os dois"))
+	}
+
+	params := spec.params(o)
+	byName := map[string]apiParam{}
+	var names []string
+	for _, p := range params {
+		byName[p.Name] = p
+		names = append(names, "--"+p.Name)
+	}
+	vals := map[string][]string{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "--") {
+			return usageError(tr("argumento solto: ") + a)
+		}
+		name, v, hasV := strings.Cut(a[2:], "=")
+		if _, ok := byName[name]; !ok {
+			return usageError(fmt.Sprintf(tr("%s não é parâmetro de %s; aceitos: %s"), a, o.ID, strings.Join(names, " ")))
+		}
+		if !hasV {
+			if i+1 >= len(args) {
+				return usageError("--" + name + tr(" exige um valor"))
+			}
+			v = args[i+1]
+			i++
+		}
+		vals[name] = append(vals[name], v)
+	}
+	for _, p := range params {
+		if p.Required && len(vals[p.Name]) == 0 {
+			return usageError(fmt.Sprintf(tr("--%s é obrigatório em %s"), p.Name, o.ID))
+		}
+	}
+
+	path := o.Path
+	query := url.Values{}
+	headers := http.Header{}
+	if !o.public() {
+		headers = authHeader(token)
+	}
+	headers.Del("Content-Type")
+	for _, p := range params {
+		vs := vals[p.Name]
+		if len(vs) == 0 {
+			continue
+		}
+		switch p.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(vs[0]))
+		case "query":
+			query[p.Name] = vs
+		case "header":
+			headers.Set(p.Name, vs[0])
+		}
+	}
+	for _, h := range extraHeaders {
+		k, v, ok := strings.Cut(h, ":")
+		if !ok {
+			return usageError(tr("--header deve ser Nome:valor, veio ") + h)
+		}
+		headers.Set(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+
+	var body []byte
+	types, bodyRequired := spec.bodyTypes(o)
+	switch {
+	case dataSet:
+		if body, err = readAPIData(data); err != nil {
+			return err
+		}
+		if !json.Valid(body) {
+			return usageError(tr("--data não é JSON válido"))
+		}
+		headers.Set("Content-Type", "application/json")
+	case len(forms) > 0:
+		ct, b, err := buildAPIForm(forms)
+		if err != nil {
+			return err
+		}
+		body = b
+		headers.Set("Content-Type", ct)
+	case bodyRequired:
+		return usageError(fmt.Sprintf(tr("%s exige corpo (%s): use --data ou --form"), o.ID, strings.Join(types, ", ")))
+	}
+
+	u := strings.TrimRight(baseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	resp, rb, err := doRequest(o.Method, u, headers, body)
+	if err != nil {
+		return err
+	}
+	outf("status=%d\n", resp.StatusCode)
+	if len(rb) > 0 && !quiet {
+		outln(string(rb))
+	}
+	setResult("operation", o.ID)
+	setResult("status", resp.StatusCode)
+	var parsed any
+	if json.Unmarshal(rb, &parsed) == nil {
+		setResult("response", parsed)
+	}
+	return checkStatus(resp, rb)
+}
+
+// --data '{"a":1}' | @arquivo.json | - (stdin)
+func readAPIData(v string) ([]byte, error) {
+	switch {
+	case v == "-":
+		return io.ReadAll(os.Stdin)
+	case strings.HasPrefix(v, "@"):
+		return os.ReadFile(v[1:])
+	}
+	return []byte(v), nil
+}
+
+// --form campo=valor ou campo=@arquivo (vira parte de arquivo)
+func buildAPIForm(forms []string) (string, []byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, f := range forms {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			return "", nil, usageError(tr("--form deve ser campo=valor ou campo=@arquivo, veio ") + f)
+		}
+		if !strings.HasPrefix(v, "@") {
+			if err := mw.WriteField(k, v); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+		data, err := os.ReadFile(v[1:])
+		if err != nil {
+			return "", nil, err
+		}
+		ct := mime.TypeByExtension(filepath.Ext(v))
+		if ct == "" {
+			ct = "application/octet-stream"
+		}
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, k, filepath.Base(v[1:])))
+		h.Set("Content-Type", ct)
+		w, err := mw.CreatePart(h)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return "", nil, err
+	}
+	return mw.FormDataContentType(), buf.Bytes(), nil
+}
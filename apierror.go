@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/* ==================== APIError ==================== */
+
+// resposta fora de 2xx: status, código de erro da API (quando vier) e o corpo bruto
+type APIError struct {
+	StatusCode int
+	Code       string // "code"/"errorCode" do corpo; "" se a API não mandou
+	Message    string // "message"/"error" do corpo
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	s := fmt.Sprintf(tr("requisição falhou: %d"), e.StatusCode)
+	switch {
+	case e.Code != "" && e.Message != "":
+		s += fmt.Sprintf(" (%s: %s)", e.Code, e.Message)
+	case e.Code != "" || e.Message != "":
+		s += " (" + e.Code + e.Message + ")"
+	}
+	return s
+}
+
+// nil para 2xx; senão o *APIError com o que der para tirar do corpo
+func checkStatus(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return newAPIError(resp.StatusCode, body)
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	e := &APIError{StatusCode: status, Body: body}
+	var m map[string]any
+	if json.Unmarshal(body, &m) != nil {
+		return e
+	}
+	// formatos vistos: {"code":...,"message":...}, {"errorCode":...}, {"error":"..."}, {"response":{"message":...}}
+	if r, ok := m["response"].(map[string]any); ok {
+		for k, v := range r {
+			if _, exists := m[k]; !exists {
+				m[k] = v
+			}
+		}
+	}
+	e.Code = firstString(m, "code", "errorCode", "error_code")
+	e.Message = firstString(m, "message", "error", "detail")
+	return e
+}
+
+// primeira chave presente com valor escalar (código numérico vira texto)
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case string:
+			if v = strings.TrimSpace(v); v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprint(v)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== Batch create ==================== */
+
+// linha do manifesto do batch-create
+type manifestRow struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Consent *bool  `json:"consent,omitempty"` // nil = usa --consent
+}
+
+// resultado de uma linha
+type batchResult struct {
+	Row       int    `json:"row"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+	Status    int    `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	Cached    bool   `json:"cached,omitempty"` // não enviada: já criada com a mesma imagem (cache)
+	skipped   bool   // não enviada: lote abortado pelo circuit breaker
+}
+
+// lê manifesto .csv (cabeçalho id,name,image[,consent]) ou .json (array de objetos).
+// Caminhos de imagem relativos são resolvidos a partir da pasta do manifesto.
+func readManifest(path string) ([]manifestRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rows []manifestRow
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.NewDecoder(f).Decode(&rows); err != nil {
+			return nil, fmt.Errorf(tr("manifesto JSON: %w"), err)
+		}
+	case ".csv":
+		rows, err = readManifestCSV(f)
+		if err != nil {
+			return nil, fmt.Errorf(tr("manifesto CSV: %w"), err)
+		}
+	default:
+		return nil, fmt.Errorf(tr("manifesto deve ser .csv ou .json: %s"), path)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range rows {
+		if rows[i].ID == "" || rows[i].Image == "" {
+			return nil, fmt.Errorf(tr("linha %d: id e image são obrigatórios"), i+1)
+		}
+		if !filepath.IsAbs(rows[i].Image) && !isObjectURL(rows[i].Image) {
+			rows[i].Image = filepath.Join(dir, rows[i].Image)
+		}
+	}
+	return rows, nil
+}
+
+func readManifestCSV(r io.Reader) ([]manifestRow, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, req := range []string{"id", "image"} {
+		if _, ok := col[req]; !ok {
+			return nil, fmt.Errorf(tr("coluna %q ausente no cabeçalho"), req)
+		}
+	}
+	get := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	var rows []manifestRow
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := manifestRow{ID: get(rec, "id"), Name: get(rec, "name"), Image: get(rec, "image")}
+		if v := get(rec, "consent"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf(tr("consent inválido %q (id=%s)"), v, row.ID)
+			}
+			row.Consent = &b
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// batch-create: cria os cards do manifesto com N workers; linha já criada antes com a mesma
+// imagem (cache de criações) é pulada, a não ser com force
+func cmdBatchCreate(baseURL, token, manifest string, concurrency int, defName string, defConsent bool, resultsPath string, force bool, bo breakerOptions) error {
+	rows, err := readManifest(manifest)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf(tr("manifesto vazio: %s"), manifest)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	outf("[batch] %d linha(s), %d worker(s)\n", len(rows), concurrency)
+
+	registerURL := strings.TrimRight(baseURL, "/") + "/api/card/integration/register"
+	results := make([]batchResult, len(rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	start := time.Now()
+	bar := newProgressBar("batch", len(rows), 0)
+	cb := newCircuitBreaker("batch-create", baseURL, token, bo)
+	cache := processCreateCache()
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				row := rows[i]
+				if !cb.allow() {
+					results[i] = batchResult{Row: i + 1, ID: row.ID, Name: row.Name, Image: row.Image, Error: tr("não enviado: lote abortado pelo circuit breaker"), skipped: true}
+					bar.add(true)
+					continue
+				}
+				name := row.Name
+				if name == "" {
+					name = defName
+				}
+				consent := defConsent
+				if row.Consent != nil {
+					consent = *row.Consent
+				}
+				t0 := time.Now()
+				var resp *http.Response
+				var body []byte
+				// imagem do bucket que não baixou não é falha da API: fica fora do circuit breaker
+				local, cleanup, fetchErr := localImage(row.Image)
+				err := fetchErr
+				var sum string
+				var cached createCacheEntry
+				hit := false
+				if err == nil {
+					if sum, err = fileSHA256(local); err == nil && !force {
+						cached, hit = cache.lookup(baseURL, row.ID, sum)
+					}
+					if err == nil && !hit {
+						resp, body, err = createCard(baseURL, token, local, row.ID, name, consent, "")
+					}
+					cleanup()
+				}
+				lat := time.Since(t0)
+
+				res := batchResult{Row: i + 1, ID: row.ID, Name: name, Image: row.Image, LatencyMS: lat.Milliseconds()}
+				if resp != nil {
+					res.Status = resp.StatusCode
+				}
+				switch {
+				case err != nil:
+					res.Error = err.Error()
+				case hit:
+					res.OK, res.Cached = true, true
+				case resp.StatusCode < 200 || resp.StatusCode >= 300:
+					res.Error = newAPIError(resp.StatusCode, body).Error()
+				default:
+					res.OK = true
+					cache.add(baseURL, row.ID, sum)
+				}
+				results[i] = res
+
+				if hit {
+					skipStep(fmt.Sprintf("create id=%s", row.ID))
+				} else {
+					cb.record(fetchErr == nil && isOutage(res.Status, err))
+					st := stepRecord{Name: fmt.Sprintf("create id=%s", row.ID), Duration: lat, Error: res.Error}
+					st.Calls = []callRecord{newCallRecord(http.MethodPost, registerURL, resp, body, err, 0, lat)}
+					addStep(st)
+				}
+
+				bar.add(!res.OK)
+				mu.Lock()
+				done++
+				switch {
+				case hit:
+					outf("[batch] %d/%d ⏭ id=%s já criado com a mesma imagem em %s (cache; --force recria)\n", done, len(rows), row.ID, cached.CreatedAt.Local().Format(time.DateTime))
+				case res.OK:
+					outf("[batch] %d/%d ✅ id=%s status=%d %dms\n", done, len(rows), row.ID, res.Status, res.LatencyMS)
+				default:
+					outf("[batch] %d/%d ❌ id=%s status=%d %dms %s\n", done, len(rows), row.ID, res.Status, res.LatencyMS, res.Error)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range rows {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	bar.finish()
+
+	if err := cache.save(); err != nil {
+		outf("[batch] cache de criações não gravado: %v\n", err)
+	}
+
+	okCount, cachedCount := 0, 0
+	for _, r := range results {
+		if r.OK {
+			okCount++
+		}
+		if r.Cached {
+			cachedCount++
+		}
+	}
+	failed := len(rows) - okCount
+	outf("[batch] total=%d ok=%d falhas=%d em %s\n", len(rows), okCount, failed, time.Since(start).Round(time.Millisecond))
+	if cachedCount > 0 {
+		outf("[batch] %d linha(s) puladas: já criadas com a mesma imagem (cache de criações)\n", cachedCount)
+	}
+	breakerErr := cb.summary()
+	for _, r := range results {
+		if !r.OK && !r.skipped {
+			outf("  ❌ linha %d id=%s: %s\n", r.Row, r.ID, r.Error)
+		}
+	}
+	setResult("total", len(rows))
+	setResult("ok", okCount)
+	setResult("failed", failed)
+	setResult("cached", cachedCount)
+	setResult("rows", results)
+
+	if resultsPath != "" {
+		if err := writeBatchResults(resultsPath, results); err != nil {
+			return fmt.Errorf(tr("gravar resultados: %w"), err)
+		}
+		outf("[batch] resultados em %s\n", resultsPath)
+	}
+	if breakerErr != nil {
+		return breakerErr
+	}
+	if failed > 0 {
+		return fmt.Errorf(tr("%d de %d linha(s) falharam"), failed, len(rows))
+	}
+	return nil
+}
+
+// grava os resultados por linha em .csv ou .json (pela extensão)
+func writeBatchResults(path string, results []batchResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if strings.ToLower(filepath.Ext(path)) == ".json" {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	cw := csv.NewWriter(f)
+	_ = cw.Write([]string{"row", "id", "name", "image", "status", "latency_ms", "ok", "error", "cached"})
+	for _, r := range results {
+		_ = cw.Write([]string{
+			strconv.Itoa(r.Row), r.ID, r.Name, r.Image, strconv.Itoa(r.Status),
+			strconv.FormatInt(r.LatencyMS, 10), strconv.FormatBool(r.OK), r.Error, strconv.FormatBool(r.Cached),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== Batch verify ==================== */
+
+var imageExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".webp": true}
+
+// resultado de uma imagem do batch-verify
+type verifyResult struct {
+	File       string     `json:"file"`
+	ID         string     `json:"id"`
+	Status     int        `json:"status"`
+	Similarity float64    `json:"similarity"`
+	HasScore   bool       `json:"has_score"`
+	Match      bool       `json:"match"`
+	LatencyMS  int64      `json:"latency_ms"`
+	Error      string     `json:"error,omitempty"`
+	Image      *imageMeta `json:"image,omitempty"`
+	outage     bool       // sem resposta ou 5xx (circuit breaker)
+}
+
+// "98.5", "98,5" ou "98.5%" → 98.5
+func parsePercent(s string) (float64, bool) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "%")
+	s = strings.ReplaceAll(s, ",", ".")
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// lista imagens de um diretório (recursivo), de um glob ou de um bucket (s3://, gs://)
+func collectImages(src string) ([]string, error) {
+	if isObjectURL(src) {
+		return listObjectImages(src)
+	}
+	if strings.ContainsAny(src, "*?[") {
+		matches, err := filepath.Glob(src)
+		if err != nil {
+			return nil, err
+		}
+		// mesmo filtro do diretório: 'fotos/*' não manda .txt/.json para a API
+		var out []string
+		for _, m := range matches {
+			if !imageExts[strings.ToLower(filepath.Ext(m))] {
+				continue
+			}
+			if st, err := os.Stat(m); err == nil && !st.IsDir() {
+				out = append(out, m)
+			}
+		}
+		sort.Strings(out)
+		return out, nil
+	}
+	var out []string
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && imageExts[strings.ToLower(filepath.Ext(p))] {
+			out = append(out, p)
+		}
+		return nil
+	})
+	sort.Strings(out)
+	return out, err
+}
+
+// ID a partir do nome do arquivo: primeiro grupo da regex (ou o match inteiro)
+func idFromFilename(re *regexp.Regexp, path string) (string, bool) {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	m := re.FindStringSubmatch(base)
+	if m == nil {
+		return "", false
+	}
+	if len(m) > 1 {
+		return m[1], true
+	}
+	return m[0], true
+}
+
+func percentileDur(ds []time.Duration, p float64) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	s := append([]time.Duration(nil), ds...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	i := int(p/100*float64(len(s))+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(s) {
+		i = len(s) - 1
+	}
+	return s[i]
+}
+
+type batchVerifyOptions struct {
+	Source      string
+	ID          string // fixo; vazio = deriva do nome do arquivo
+	IDRegex     string
+	Name        string
+	Detail      string
+	Concurrency int
+	Breaker     breakerOptions
+	Endpoint    string // vazio = rota padrão do verify
+	Encoding    string // vazio = data URI
+}
+
+// batch-verify: verifica cada imagem do diretório/glob e imprime tabela agregada
+func cmdBatchVerify(baseURL, token string, opt batchVerifyOptions) error {
+	files, err := collectImages(opt.Source)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf(tr("nenhuma imagem em %s"), opt.Source)
+	}
+	var re *regexp.Regexp
+	if opt.ID == "" {
+		re, err = regexp.Compile(opt.IDRegex)
+		if err != nil {
+			return usageError(tr("--id-regex inválida: ") + err.Error())
+		}
+	}
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 1
+	}
+	outf("[batch-verify] %d imagem(ns), %d worker(s)\n", len(files), opt.Concurrency)
+
+	url := verifyURL(baseURL, "")
+	results := make([]verifyResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	bar := newProgressBar("batch-verify", len(files), 0)
+	cb := newCircuitBreaker("batch-verify", baseURL, token, opt.Breaker)
+
+	for w := 0; w < opt.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if !cb.allow() {
+					results[i] = verifyResult{File: files[i], Error: tr("não enviado: lote abortado pelo circuit breaker")}
+					bar.add(true)
+					continue
+				}
+				results[i] = verifyImageFile(baseURL, token, url, re, files[i], opt)
+				cb.record(results[i].outage)
+				bar.add(results[i].Error != "")
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	bar.finish()
+
+	printVerifyTable(results)
+	errs := printVerifySummary("batch-verify", results, time.Since(start))
+	setResult("results", results)
+	if err := cb.summary(); err != nil {
+		return err
+	}
+	if errs > 0 {
+		return fmt.Errorf(tr("%d de %d verificação(ões) com erro"), errs, len(results))
+	}
+	return nil
+}
+
+// verifica uma imagem (id fixo ou tirado do nome) e registra a etapa; usado pelo batch-verify e pelo watch
+func verifyImageFile(baseURL, token, url string, re *regexp.Regexp, f string, opt batchVerifyOptions) verifyResult {
+	res := verifyResult{File: f, ID: opt.ID}
+	local, cleanup, err := localImage(f)
+	if err != nil {
+		res.Error = err.Error()
+		addStep(stepRecord{Name: "verify " + filepath.Base(f), Error: res.Error})
+		return res
+	}
+	defer cleanup()
+	if meta, err := readImageMeta(local); err == nil {
+		meta.File = f
+		res.Image = &meta
+	}
+	if res.ID == "" {
+		id, ok := idFromFilename(re, f)
+		if !ok {
+			res.Error = tr("id não encontrado no nome do arquivo")
+			addStep(stepRecord{Name: "verify " + filepath.Base(f), Error: res.Error})
+			return res
+		}
+		res.ID = id
+	}
+	t0 := time.Now()
+	resp, body, err := verifyCard(baseURL, token, opt.Endpoint, local, res.ID, opt.Name, opt.Detail, opt.Encoding)
+	lat := time.Since(t0)
+	res.LatencyMS = lat.Milliseconds()
+	if resp != nil {
+		res.Status = resp.StatusCode
+	}
+	res.outage = isOutage(res.Status, err)
+	switch {
+	case err != nil:
+		res.Error = err.Error()
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		res.Error = newAPIError(resp.StatusCode, body).Error()
+	default:
+		var v VerifyResponse
+		if err := json.Unmarshal(body, &v); err != nil {
+			res.Error = tr("resposta inválida: ") + err.Error()
+			break
+		}
+		res.Match = v.Response.Success
+		res.Similarity, res.HasScore = parsePercent(v.Similarity())
+	}
+	st := stepRecord{Name: "verify " + filepath.Base(f), Duration: lat, Error: res.Error}
+	st.Calls = []callRecord{newCallRecord(http.MethodPost, url, resp, body, err, 0, lat)}
+	addStep(st)
+	return res
+}
+
+func printVerifyTable(results []verifyResult) {
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("ARQUIVO\tID\tSIMILARIDADE\tMATCH\tSTATUS\tLATÊNCIA\tERRO"))
+	for _, r := range results {
+		sim := "-"
+		if r.HasScore {
+			sim = fmt.Sprintf("%.2f", r.Similarity)
+		}
+		match := "❌"
+		if r.Match {
+			match = "✅"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%dms\t%s\n",
+			filepath.Base(r.File), r.ID, sim, match, r.Status, r.LatencyMS, r.Error)
+	}
+	tw.Flush()
+}
+
+// imprime agregados e devolve quantas verificações deram erro
+func printVerifySummary(tag string, results []verifyResult, total time.Duration) int {
+	var lats []time.Duration
+	var sims []float64
+	pass, fail, errs := 0, 0, 0
+	for _, r := range results {
+		if r.Error != "" {
+			errs++
+			continue
+		}
+		lats = append(lats, time.Duration(r.LatencyMS)*time.Millisecond)
+		if r.HasScore {
+			sims = append(sims, r.Similarity)
+		}
+		if r.Match {
+			pass++
+		} else {
+			fail++
+		}
+	}
+	outf("[%s] total=%d match=%d sem-match=%d erros=%d em %s\n",
+		tag, len(results), pass, fail, errs, total.Round(time.Millisecond))
+	if len(sims) > 0 {
+		sort.Float64s(sims)
+		var sum float64
+		for _, v := range sims {
+			sum += v
+		}
+		outf("[%s] similaridade min=%.2f média=%.2f max=%.2f\n", tag, sims[0], sum/float64(len(sims)), sims[len(sims)-1])
+	}
+	if len(lats) > 0 {
+		outf("[%s] latência p50=%s p95=%s max=%s\n",
+			tag, percentileDur(lats, 50), percentileDur(lats, 95), percentileDur(lats, 100))
+	}
+	setResult("match", pass)
+	setResult("no_match", fail)
+	setResult("errors", errs)
+	return errs
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+/* ==================== Circuit breaker (batch-create, batch-verify) ==================== */
+
+// API fora do ar no meio do lote: depois de N falhas seguidas (sem resposta ou 5xx) para tudo,
+// espera, sonda com um GET e retoma ou aborta. Falha de linha (4xx) não conta.
+type breakerOptions struct {
+	Threshold int           // falhas seguidas que abrem o circuito; 0 = desligado
+	Pause     time.Duration // espera antes de cada sonda
+	Probes    int           // sondas antes de abortar
+}
+
+type circuitBreaker struct {
+	opt          breakerOptions
+	baseURL      string
+	token        string
+	tag          string
+	mu           sync.Mutex
+	cond         *sync.Cond
+	consecutive  int
+	open         bool
+	aborted      bool
+	trips        int
+	skipped      int
+	pausedFor    time.Duration
+	abortedAfter string
+}
+
+// nil quando desligado; os métodos aceitam nil
+func newCircuitBreaker(tag, baseURL, token string, opt breakerOptions) *circuitBreaker {
+	if opt.Threshold <= 0 {
+		return nil
+	}
+	if opt.Probes < 1 {
+		opt.Probes = 1
+	}
+	b := &circuitBreaker{opt: opt, baseURL: baseURL, token: token, tag: tag}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// segura o worker enquanto o circuito está aberto; false = lote abortado, não envia
+func (b *circuitBreaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.open && !b.aborted {
+		b.cond.Wait()
+	}
+	if b.aborted {
+		b.skipped++
+	}
+	return !b.aborted
+}
+
+// resultado de cada item; quem completa a sequência de falhas faz a pausa e as sondas
+func (b *circuitBreaker) record(outage bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if !outage {
+		b.consecutive = 0
+		b.mu.Unlock()
+		return
+	}
+	b.consecutive++
+	if b.consecutive < b.opt.Threshold || b.open || b.aborted {
+		b.mu.Unlock()
+		return
+	}
+	b.open = true
+	b.trips++
+	n := b.consecutive
+	b.mu.Unlock()
+
+	outf("[breaker] %d falha(s) seguida(s): circuito aberto, envios suspensos\n", n)
+	healthy := false
+	for i := 1; i <= b.opt.Probes && !healthy; i++ {
+		outf("[breaker] sonda %d/%d em %s\n", i, b.opt.Probes, b.opt.Pause)
+		time.Sleep(b.opt.Pause)
+		b.mu.Lock()
+		b.pausedFor += b.opt.Pause
+		b.mu.Unlock()
+		var why string
+		healthy, why = b.probe()
+		if healthy {
+			outf("[breaker] API respondeu (%s): circuito fechado, retomando\n", why)
+		} else {
+			outf("[breaker] sonda falhou: %s\n", why)
+		}
+	}
+
+	b.mu.Lock()
+	b.open = false
+	b.consecutive = 0
+	if !healthy {
+		b.aborted = true
+		b.abortedAfter = fmt.Sprintf(tr("%d falha(s) seguida(s) e %d sonda(s) sem resposta"), n, b.opt.Probes)
+		outf("[breaker] API continua fora: abortando o %s\n", b.tag)
+	}
+	b.cond.Broadcast()
+	b.mu.Unlock()
+}
+
+// GET de um card qualquer, sem retry: qualquer resposta abaixo de 500 (404 inclusive) = API de pé
+func (b *circuitBreaker) probe() (bool, string) {
+	resp, _, err := doOnce(http.MethodGet, cardURL(b.baseURL, "breaker-probe"), authHeader(b.token), nil)
+	switch {
+	case err != nil:
+		return false, err.Error()
+	case resp.StatusCode >= 500:
+		return false, fmt.Sprintf("status %d", resp.StatusCode)
+	}
+	return true, fmt.Sprintf("status %d", resp.StatusCode)
+}
+
+// linha de resumo e erro do lote abortado (nil se não abortou)
+func (b *circuitBreaker) summary() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.trips == 0 {
+		return nil
+	}
+	setResult("breaker", map[string]any{
+		"trips": b.trips, "aborted": b.aborted, "skipped": b.skipped, "paused_ms": b.pausedFor.Milliseconds(),
+	})
+	if !b.aborted {
+		outf("[breaker] circuito abriu %d vez(es), %s em pausa; lote concluído\n", b.trips, b.pausedFor)
+		return nil
+	}
+	outf("[breaker] lote ABORTADO após %s; %d item(ns) não enviado(s)\n", b.abortedAfter, b.skipped)
+	return fmt.Errorf(tr("%s abortado pelo circuit breaker (%d item(ns) não enviado(s))"), b.tag, b.skipped)
+}
+
+// sem resposta ou 5xx: falha da API, não da linha (contrato quebrado também é da linha)
+func isOutage(status int, err error) bool {
+	return (err != nil && !isSchemaError(err)) || status >= 500
+}
+
+// --breaker N --breaker-pause D --breaker-probes N (batch-create e batch-verify)
+func breakerFlags(fs *flag.FlagSet) *breakerOptions {
+	o := &breakerOptions{}
+	fs.IntVar(&o.Threshold, "breaker", 10, "falhas seguidas (sem resposta/5xx) que suspendem o lote; 0 desliga")
+	fs.DurationVar(&o.Pause, "breaker-pause", 30*time.Second, "espera antes de cada sonda com o circuito aberto")
+	fs.IntVar(&o.Probes, "breaker-probes", 3, "sondas sem resposta antes de abortar o lote")
+	return o
+}
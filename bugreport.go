@@ -0,0 +1,203 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ==================== --bug-report (pacote para o chamado do fornecedor) ==================== */
+
+// tudo que o runner imprimiu para humanos nesta execução (só com --bug-report)
+var bugLog bytes.Buffer
+
+// passa a guardar o log; o HAR é ligado depois, junto com o --har (ver main)
+func startBugLog() {
+	humanOut = io.MultiWriter(humanOut, &bugLog)
+}
+
+// variáveis que mudam o comportamento do runner; as com segredo só aparecem como definidas
+var bugReportEnvPrefixes = []string{
+	"BASE_URL", "AUTH_", "CARD_", "VERIFY_", "RETRY_", "OAUTH_", "BIODOC_", "PROXY_URL", "HTTPS_PROXY", "NO_PROXY",
+	"CA_CERT", "CLIENT_", "TIMING_", "RESULTS_", "CAMERA_",
+}
+
+type bugReportInfo struct {
+	RunID      string            `json:"run_id"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args"`
+	StartedAt  time.Time         `json:"started_at"`
+	DurationMS int64             `json:"duration_ms"`
+	ExitCode   int               `json:"exit_code"`
+	Category   string            `json:"category"`
+	Error      string            `json:"error"`
+	Retries    int               `json:"retries"`
+	Version    map[string]string `json:"version"`
+	Config     map[string]any    `json:"config"`
+	Result     map[string]any    `json:"result,omitempty"`
+}
+
+// versão do binário, Go e commit (quando compilado de um checkout git)
+func bugReportVersion() map[string]string {
+	v := map[string]string{"go": runtime.Version(), "os": runtime.GOOS, "arch": runtime.GOARCH}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	v["module"] = bi.Main.Path
+	v["version"] = bi.Main.Version
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			v[strings.TrimPrefix(s.Key, "vcs.")] = s.Value
+		}
+	}
+	return v
+}
+
+// o que define o ambiente da execução, sem segredos: hash do config, perfil, ENV e ajustes globais
+func bugReportConfig(baseURL string) map[string]any {
+	c := map[string]any{
+		"base_url":   baseURL,
+		"profile":    activeProfile,
+		"strict":     strict,
+		"image_prep": imgPrep,
+		"retry": map[string]any{
+			"max_attempts": retryCfg.MaxAttempts, "base_delay": retryCfg.BaseDelay.String(),
+			"jitter": retryCfg.Jitter, "statuses": sortedStatuses(retryCfg.Statuses),
+			"post": retryCfg.POST,
+		},
+	}
+	if p := configPath(); p != "" {
+		if b, err := os.ReadFile(p); err == nil {
+			sum := sha256.Sum256(b)
+			c["config_file"] = p
+			c["config_sha256"] = hex.EncodeToString(sum[:])
+		}
+	}
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		for _, p := range bugReportEnvPrefixes {
+			if !strings.HasPrefix(k, p) {
+				continue
+			}
+			if secretFlag(k) || strings.HasSuffix(k, "_KEY") {
+				v = redacted
+			}
+			env[k] = v
+			break
+		}
+	}
+	c["env"] = env
+	return c
+}
+
+func sortedStatuses(m map[int]bool) []int {
+	out := make([]int, 0, len(m))
+	for s := range m {
+		out = append(out, s)
+	}
+	sort.Ints(out)
+	return out
+}
+
+// valor de --flag=x / --flag x trocado por [REDACTED] quando a flag carrega segredo
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	hide := false
+	for i, a := range args {
+		switch {
+		case hide:
+			out[i], hide = redacted, false
+		case strings.HasPrefix(a, "-"):
+			name, _, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			if secretFlag(name) {
+				if hasVal {
+					a = a[:strings.IndexByte(a, '=')+1] + redacted
+				} else {
+					hide = true
+				}
+			}
+			out[i] = a
+		default:
+			out[i] = a
+		}
+	}
+	return out
+}
+
+// apaga os segredos conhecidos do texto (token no log de erro, na URL...)
+func scrubSecrets(b []byte, secrets ...string) []byte {
+	for _, s := range secrets {
+		// valores curtos (token "x" de teste) apagariam meio log
+		if len(s) >= 8 {
+			b = bytes.ReplaceAll(b, []byte(s), []byte(redacted))
+		}
+	}
+	return b
+}
+
+// report.json + requests.har + log.txt num zip pronto para anexar ao chamado
+func writeBugReport(path, cmd string, rawArgs []string, baseURL, token string, started time.Time, elapsed time.Duration, runErr error, code int) error {
+	info := bugReportInfo{
+		RunID:      runID,
+		Command:    cmd,
+		Args:       redactArgs(rawArgs),
+		StartedAt:  started.UTC(),
+		DurationMS: elapsed.Milliseconds(),
+		ExitCode:   code,
+		Category:   failureCategory(runErr),
+		Retries:    int(retryCount.Load()),
+		Version:    bugReportVersion(),
+		Config:     bugReportConfig(baseURL),
+	}
+	if runErr != nil {
+		info.Error = runErr.Error()
+	}
+	callsMu.Lock()
+	if len(results) > 0 {
+		info.Result = results
+	}
+	callsMu.Unlock()
+
+	secrets := []string{token, os.Getenv("AUTH_TOKEN"), os.Getenv("OAUTH_CLIENT_SECRET")}
+	files := []struct {
+		name string
+		body []byte
+	}{
+		{"report.json", indentJSON(info, "  ")},
+		{"requests.har", harRec.marshal()},
+		{"log.txt", bugLog.Bytes()},
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(f)
+	for _, fl := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: fl.name, Method: zip.Deflate, Modified: started})
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := w.Write(scrubSecrets(fl.body, secrets...)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+/* ==================== --camera (quadro da webcam via ffmpeg) ==================== */
+
+// dispositivo padrão de cada SO; no Windows o dshow exige o nome (ffmpeg -list_devices true -f dshow -i dummy)
+func defaultCameraDevice() string {
+	if d := os.Getenv("CAMERA_DEVICE"); d != "" {
+		return d
+	}
+	switch runtime.GOOS {
+	case "linux":
+		return "/dev/video0"
+	case "darwin":
+		return "0"
+	}
+	return ""
+}
+
+func ffmpegCaptureArgs(device, out string) ([]string, error) {
+	var in []string
+	switch runtime.GOOS {
+	case "linux":
+		in = []string{"-f", "v4l2", "-i", device}
+	case "darwin":
+		in = []string{"-f", "avfoundation", "-framerate", "30", "-i", device}
+	case "windows":
+		if device == "" {
+			return nil, errors.New(tr("--camera no Windows exige --camera-device \"Nome da câmera\" (veja ffmpeg -list_devices true -f dshow -i dummy)"))
+		}
+		in = []string{"-f", "dshow", "-i", "video=" + device}
+	default:
+		return nil, fmt.Errorf(tr("--camera não suportado em %s"), runtime.GOOS)
+	}
+	// descarta o primeiro segundo: a câmera ainda está ajustando exposição e foco
+	args := append([]string{"-hide_banner", "-loglevel", "error", "-y"}, in...)
+	return append(args, "-ss", "1", "-frames:v", "1", "-q:v", "2", out), nil
+}
+
+// captura um quadro num JPEG temporário; cleanup apaga o arquivo
+func captureWebcam(device string, delay time.Duration) (path string, cleanup func(), err error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return "", nil, errors.New(tr("--camera precisa do ffmpeg no PATH"))
+	}
+	dir, err := os.MkdirTemp("", "biodoc-camera-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { _ = os.RemoveAll(dir) }
+	out := filepath.Join(dir, "camera.jpg")
+	args, err := ffmpegCaptureArgs(device, out)
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	for left := delay; left > 0; left -= time.Second {
+		outf("[camera] foto em %ds...\n", int((left+time.Second-1)/time.Second))
+		time.Sleep(min(time.Second, left))
+	}
+	var stderr bytes.Buffer
+	c := exec.Command(ffmpeg, args...)
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf(tr("captura da webcam (%s): %v: %s"), device, err, strings.TrimSpace(stderr.String()))
+	}
+	if st, err := os.Stat(out); err != nil || st.Size() == 0 {
+		cleanup()
+		return "", nil, fmt.Errorf(tr("captura da webcam (%s): nenhum quadro gravado"), device)
+	}
+	outf("[camera] quadro capturado de %s\n", device)
+	return out, cleanup, nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+/* ==================== get-card ==================== */
+
+// metadados de um card (GET /api/card/{id}); nomes de campo variam entre versões da API
+type CardInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+	Status    string `json:"status"`
+	Consent   *bool  `json:"consentTermSigned"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// extrai CardInfo aceitando o objeto no topo ou embrulhado em "response"/"data"
+func parseCardInfo(raw []byte) (CardInfo, error) {
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return CardInfo{}, err
+	}
+	for _, k := range []string{"response", "data", "card"} {
+		if inner, ok := m[k].(map[string]any); ok {
+			m = inner
+			break
+		}
+	}
+	return cardFromMap(m), nil
+}
+
+func cardFromMap(m map[string]any) CardInfo {
+	str := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := m[k]; ok && v != nil {
+				return fmt.Sprint(v)
+			}
+		}
+		return ""
+	}
+	c := CardInfo{
+		ID:        str("id", "idCard", "document"),
+		Name:      str("name", "nome"),
+		CreatedAt: str("createdAt", "creationDate", "created_at", "dateCreated"),
+		Status:    str("status", "situation"),
+		Detail:    str("detail", "details", "metadata"),
+	}
+	for _, k := range []string{"consentTermSigned", "consent"} {
+		if b, ok := m[k].(bool); ok {
+			c.Consent = &b
+			break
+		}
+	}
+	return c
+}
+
+// id escapado: "../x", "?" ou "#" não escapam de /api/card/ para outra rota da API
+func cardURL(baseURL, id string) string {
+	return strings.TrimRight(baseURL, "/") + "/api/card/" + url.PathEscape(id)
+}
+
+// GET /api/card/{id} sem imprimir nada
+func getCard(baseURL, token, id string) (*http.Response, []byte, error) {
+	if id == "" {
+		return nil, nil, fmt.Errorf(tr("--id vazio"))
+	}
+	return doRequest(http.MethodGet, cardURL(baseURL, id), authHeader(token), nil)
+}
+
+// GET /api/card/{id}
+func cmdGetCard(baseURL, token, id string) error {
+	resp, body, err := getCard(baseURL, token, id)
+	if err != nil {
+		return err
+	}
+	outf("status=%d\n", resp.StatusCode)
+	if err := checkStatus(resp, body); err != nil {
+		if !quiet {
+			outln(string(body))
+		}
+		return err
+	}
+	c, err := parseCardInfo(body)
+	if err != nil {
+		return fmt.Errorf(tr("resposta inválida: %w"), err)
+	}
+	consent := "-"
+	if c.Consent != nil {
+		consent = fmt.Sprint(*c.Consent)
+	}
+	outf("[card] id=%s\n", orDash(c.ID))
+	outf("[card] nome=%s\n", orDash(c.Name))
+	outf("[card] criado=%s\n", orDash(c.CreatedAt))
+	outf("[card] status=%s\n", orDash(c.Status))
+	outf("[card] consentimento=%s\n", consent)
+	if !quiet {
+		outln(string(body))
+	}
+	setResult("card", c)
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// decisão do preclean (resultado --output json e log estruturado)
+type precleanDecision struct {
+	ID           string `json:"id"`
+	Exists       string `json:"exists"` // yes, no ou unknown (consulta sem resposta conclusiva)
+	GetStatus    int    `json:"get_status,omitempty"`
+	GetError     string `json:"get_error,omitempty"`
+	Action       string `json:"action"` // delete ou skip
+	DeleteStatus int    `json:"delete_status,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// consulta o card e só deleta se existir; consulta inconclusiva (rota fora, 5xx) cai no delete
+// direto, e aí 404/422 no delete = não existia. Decide pelo status, sem olhar o texto do erro
+func precleanCard(baseURL, token, id string) (precleanDecision, error) {
+	d := precleanDecision{ID: id, Exists: "unknown", Action: "delete"}
+	resp, _, err := getCard(baseURL, token, id)
+	if resp != nil {
+		d.GetStatus = resp.StatusCode
+	}
+	switch {
+	case err != nil:
+		d.GetError = err.Error()
+	case resp.StatusCode == http.StatusNotFound:
+		d.Exists, d.Action = "no", "skip"
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		d.Exists = "yes"
+	}
+	var derr error
+	if d.Action == "delete" {
+		var body []byte
+		resp, body, derr = doRequest(http.MethodDelete, cardURL(baseURL, id), authHeader(token), nil)
+		switch {
+		case derr != nil:
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			d.DeleteStatus = resp.StatusCode
+		case d.Exists == "unknown" && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity):
+			d.DeleteStatus, d.Exists = resp.StatusCode, "no"
+		default:
+			d.DeleteStatus = resp.StatusCode
+			derr = newAPIError(resp.StatusCode, body)
+		}
+		if derr != nil {
+			d.Error = derr.Error()
+		}
+	}
+	attrs := []any{"component", "preclean", "id", id, "exists", d.Exists, "action", d.Action, "get_status", d.GetStatus}
+	if d.Action == "delete" {
+		attrs = append(attrs, "delete_status", d.DeleteStatus)
+	}
+	if derr != nil {
+		logger.Warn("preclean decision", append(attrs, "error", d.Error)...)
+	} else {
+		logger.Info("preclean decision", attrs...)
+	}
+	return d, derr
+}
+
+func cmdPreclean(baseURL, token, id string) error {
+	d, err := precleanCard(baseURL, token, id)
+	setResult("preclean", d)
+	switch {
+	case d.Exists == "unknown" && d.GetStatus == 0:
+		outf("[preclean] id=%s get-card sem resposta (%s); delete direto\n", id, d.GetError)
+	case d.Exists == "unknown":
+		outf("[preclean] id=%s get-card respondeu %d; delete direto\n", id, d.GetStatus)
+	}
+	switch {
+	case err != nil:
+		return err
+	case d.Action == "skip":
+		outf("[preclean] id=%s não existe, nada a deletar\n", id)
+	case d.Exists == "no":
+		outf("[preclean] id=%s não existia (delete respondeu %d)\n", id, d.DeleteStatus)
+	default:
+		outf("[preclean] id=%s existia, deletado (status %d)\n", id, d.DeleteStatus)
+	}
+	return nil
+}
+
+/* ==================== update-card ==================== */
+
+// campos a alterar; nil = não enviar
+type cardUpdate struct {
+	Image   *string // caminho da nova imagem
+	Name    *string
+	Consent *bool
+}
+
+// PATCH/PUT no recurso do card, enviando só os campos informados
+func cmdUpdateCard(baseURL, token, method, endpoint, id string, u cardUpdate) error {
+	if id == "" {
+		return usageError(tr("--id vazio"))
+	}
+	payload := map[string]any{"id": id}
+	if u.Name != nil {
+		payload["name"] = *u.Name
+	}
+	if u.Consent != nil {
+		payload["consentTermSigned"] = *u.Consent
+	}
+	if u.Image != nil {
+		img64, err := readImageAsBase64(*u.Image)
+		if err != nil {
+			return fmt.Errorf(tr("ler imagem: %w"), err)
+		}
+		payload["image"] = img64
+	}
+	if len(payload) == 1 {
+		return usageError(tr("informe ao menos um de --image, --name, --consent"))
+	}
+	method = strings.ToUpper(method)
+	if method != http.MethodPatch && method != http.MethodPut {
+		return usageError(tr("--method deve ser PATCH ou PUT"))
+	}
+	url := strings.TrimRight(baseURL, "/") + strings.ReplaceAll(endpoint, "{id}", url.PathEscape(id))
+
+	resp, body, err := doJSON(method, url, authHeader(token), payload)
+	if err != nil {
+		return err
+	}
+	outf("status=%d\n", resp.StatusCode)
+	if !quiet {
+		outln(string(body))
+	}
+	if err := checkStatus(resp, body); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+/* ==================== Cassetes (gravação de tráfego) ==================== */
+
+// corpo gravado: texto puro, ou base64 quando não for UTF-8 (ex.: mainimage)
+type cassetteBody struct {
+	Encoding string `json:"encoding,omitempty"` // "" = texto, "base64"
+	Data     string `json:"data,omitempty"`
+}
+
+func newCassetteBody(b []byte) cassetteBody {
+	if utf8.Valid(b) {
+		return cassetteBody{Data: string(b)}
+	}
+	return cassetteBody{Encoding: "base64", Data: base64.StdEncoding.EncodeToString(b)}
+}
+
+func (cb cassetteBody) Bytes() []byte {
+	if cb.Encoding == "base64" {
+		b, _ := base64.StdEncoding.DecodeString(cb.Data)
+		return b
+	}
+	return []byte(cb.Data)
+}
+
+type cassetteRequest struct {
+	Method  string       `json:"method"`
+	URL     string       `json:"url"` // path + query, sem host
+	Headers http.Header  `json:"headers,omitempty"`
+	Body    cassetteBody `json:"body"`
+}
+
+type cassetteResponse struct {
+	Status  int          `json:"status"`
+	Headers http.Header  `json:"headers,omitempty"`
+	Body    cassetteBody `json:"body"`
+}
+
+// uma troca requisição/resposta
+type interaction struct {
+	RecordedAt time.Time        `json:"recordedAt"`
+	LatencyMS  int64            `json:"latencyMs"`
+	Request    cassetteRequest  `json:"request"`
+	Response   cassetteResponse `json:"response"`
+}
+
+// versão do formato em disco; mudança incompatível = incrementar e tratar a antiga em readCassette
+const cassetteVersion = 1
+
+// o que foi mascarado na gravação (para quem recebe o arquivo saber se é seguro commitar)
+type cassetteSanitized struct {
+	Secrets bool `json:"secrets"`
+	Images  bool `json:"images"`
+}
+
+type cassette struct {
+	Version      int               `json:"version"`
+	RecordedBy   string            `json:"recordedBy,omitempty"`
+	Target       string            `json:"target,omitempty"` // API de onde veio a gravação
+	Sanitized    cassetteSanitized `json:"sanitized"`
+	Interactions []interaction     `json:"interactions"`
+}
+
+// cassete em disco, regravado a cada interação para não perder a sessão se o processo cair
+type cassetteWriter struct {
+	mu       sync.Mutex
+	path     string
+	sanitize sanitizeOptions
+	c        cassette
+}
+
+func newCassetteWriter(path, target string, so sanitizeOptions) *cassetteWriter {
+	return &cassetteWriter{path: path, sanitize: so, c: cassette{
+		Version:      cassetteVersion,
+		RecordedBy:   "biodoc-go-runner",
+		Target:       target,
+		Sanitized:    cassetteSanitized{Secrets: so.Redact, Images: so.Images},
+		Interactions: []interaction{},
+	}}
+}
+
+// continua um cassete existente (replay com gravação das interações novas)
+func newCassetteWriterFrom(path string, c cassette, so sanitizeOptions) *cassetteWriter {
+	c.Version = cassetteVersion
+	// o arquivo só é "sanitizado" se as gravações antigas e as novas forem
+	c.Sanitized.Secrets = c.Sanitized.Secrets && so.Redact
+	c.Sanitized.Images = c.Sanitized.Images && so.Images
+	c.Interactions = append([]interaction(nil), c.Interactions...)
+	return &cassetteWriter{path: path, sanitize: so, c: c}
+}
+
+// sanitiza antes de guardar: segredo nunca chega ao disco, nem no arquivo temporário.
+// Devolve a interação como ficou gravada.
+func (cw *cassetteWriter) add(it interaction) (interaction, error) {
+	sanitizeInteraction(&it, cw.sanitize)
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	cw.c.Interactions = append(cw.c.Interactions, it)
+	return it, writeCassette(cw.path, cw.c)
+}
+
+func (cw *cassetteWriter) len() int {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return len(cw.c.Interactions)
+}
+
+// grava em arquivo temporário e renomeia, para nunca deixar JSON pela metade
+func writeCassette(path string, c cassette) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// lê um cassete validando a versão; arquivos sem versão (gravados antes do formato versionado) valem como v1
+func readCassette(path string) (cassette, error) {
+	var c cassette
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf(tr("cassete %s inválido: %w"), path, err)
+	}
+	switch {
+	case c.Version == 0:
+		c.Version = cassetteVersion
+	case c.Version > cassetteVersion:
+		return c, fmt.Errorf(tr("cassete %s usa formato v%d; este runner entende até v%d"), path, c.Version, cassetteVersion)
+	}
+	return c, nil
+}
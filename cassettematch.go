@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* ==================== Replay: casamento de requisições ==================== */
+
+// headers que mudam a cada execução e nunca entram na comparação
+var volatileHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Date", "User-Agent", "Content-Length",
+	"Accept-Encoding", "Connection", "X-Request-Id", "X-Correlation-Id", "Traceparent", "Tracestate",
+	"Idempotency-Key", "X-Forwarded-For", "X-Forwarded-Host", "X-Forwarded-Proto", "Via",
+}
+
+// regras de match do replay; method e path sempre são comparados
+type matchRules struct {
+	Query         bool      // compara a query string (ordem dos parâmetros não importa)
+	Headers       bool      // compara headers, exceto os voláteis e IgnoreHeaders
+	IgnoreHeaders []string  // além de volatileHeaders
+	Body          string    // exact | json | structure | none
+	Normalize     normRules // aplicadas aos dois corpos JSON antes de comparar (--ignore-field vira drop)
+}
+
+var bodyMatchModes = []string{"exact", "json", "structure", "none"}
+
+func defaultMatchRules() matchRules {
+	return matchRules{Query: true, Body: "json"}
+}
+
+// "method,path,query,headers,body" → liga/desliga regras; method e path são obrigatórios
+func (mr *matchRules) setMatchers(list string) error {
+	mr.Query, mr.Headers = false, false
+	body := false
+	for _, m := range strings.Split(list, ",") {
+		switch strings.TrimSpace(strings.ToLower(m)) {
+		case "method", "path", "":
+		case "query":
+			mr.Query = true
+		case "headers":
+			mr.Headers = true
+		case "body":
+			body = true
+		default:
+			return fmt.Errorf(tr("matcher desconhecido %q (use method, path, query, headers, body)"), m)
+		}
+	}
+	if !body {
+		mr.Body = "none"
+	} else if mr.Body == "none" {
+		mr.Body = "json"
+	}
+	return nil
+}
+
+func (mr *matchRules) setBodyMode(mode string) error {
+	for _, m := range bodyMatchModes {
+		if m == mode {
+			mr.Body = mode
+			return nil
+		}
+	}
+	return fmt.Errorf(tr("--match-body %q inválido (use %s)"), mode, strings.Join(bodyMatchModes, ", "))
+}
+
+// compara a requisição com a gravada; devolve "" se casar, senão o motivo
+func (mr matchRules) mismatch(method, rawURL string, h http.Header, body []byte, rec cassetteRequest) string {
+	if !strings.EqualFold(method, rec.Method) {
+		return "method"
+	}
+	got, err1 := url.Parse(rawURL)
+	want, err2 := url.Parse(rec.URL)
+	if err1 != nil || err2 != nil {
+		if rawURL != rec.URL {
+			return "url"
+		}
+	} else {
+		if got.Path != want.Path {
+			return "path"
+		}
+		if mr.Query && !reflect.DeepEqual(normQuery(got.Query()), normQuery(want.Query())) {
+			return "query"
+		}
+	}
+	if mr.Headers && !mr.headersEqual(h, rec.Headers) {
+		return "headers"
+	}
+	if !mr.bodyEqual(body, rec.Body.Bytes()) {
+		return "body"
+	}
+	return ""
+}
+
+func normQuery(q url.Values) url.Values {
+	for k := range q {
+		sort.Strings(q[k])
+	}
+	// parâmetro mascarado na gravação vale qualquer valor
+	for k, v := range q {
+		if len(v) == 1 && v[0] == redacted {
+			delete(q, k)
+		}
+	}
+	return q
+}
+
+func (mr matchRules) headersEqual(a, b http.Header) bool {
+	skip := map[string]bool{}
+	for _, k := range append(volatileHeaders, mr.IgnoreHeaders...) {
+		skip[http.CanonicalHeaderKey(k)] = true
+	}
+	keys := map[string]bool{}
+	for k := range a {
+		keys[http.CanonicalHeaderKey(k)] = true
+	}
+	for k := range b {
+		keys[http.CanonicalHeaderKey(k)] = true
+	}
+	for k := range keys {
+		if skip[k] {
+			continue
+		}
+		if strings.Join(a.Values(k), ",") != strings.Join(b.Values(k), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+func (mr matchRules) bodyEqual(got, want []byte) bool {
+	switch mr.Body {
+	case "none":
+		return true
+	case "exact":
+		return string(got) == string(want)
+	}
+	var a, b any
+	errA, errB := json.Unmarshal(got, &a), json.Unmarshal(want, &b)
+	if errA != nil || errB != nil {
+		// corpo vazio ou não-JSON: só bate byte a byte
+		return string(got) == string(want)
+	}
+	a, b = mr.stripJSON(mr.Normalize.apply(a), ""), mr.stripJSON(mr.Normalize.apply(b), "")
+	if mr.Body == "structure" {
+		return reflect.DeepEqual(jsonShape(a), jsonShape(b))
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// troca imagens e valores mascarados por marcadores fixos
+func (mr matchRules) stripJSON(v any, path string) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, vv := range x {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			out[k] = mr.stripJSON(vv, p)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, vv := range x {
+			out[i] = mr.stripJSON(vv, path)
+		}
+		return out
+	case string:
+		key := path[strings.LastIndex(path, ".")+1:]
+		if imageKeys[strings.ToLower(key)] && len(x) > 64 {
+			return "<image>"
+		}
+		if x == redacted || sensitiveKeys[strings.ToLower(key)] {
+			return redacted
+		}
+	}
+	return v
+}
+
+// só chaves e tipos: {"id":"1","n":2} e {"id":"9","n":7} têm a mesma estrutura
+func jsonShape(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, vv := range x {
+			out[k] = jsonShape(vv)
+		}
+		return out
+	case []any:
+		if len(x) == 0 {
+			return []any{}
+		}
+		return []any{jsonShape(x[0])}
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return "null"
+}
+
+// reproduz as interações de um cassete; cada gravação é consumida uma vez, na ordem,
+// e a última que casar é repetida quando as demais já foram usadas
+type cassettePlayer struct {
+	mu    sync.Mutex
+	rules matchRules
+	its   []interaction
+	used  []bool
+}
+
+func newCassettePlayer(c cassette, rules matchRules) *cassettePlayer {
+	return &cassettePlayer{rules: rules, its: c.Interactions, used: make([]bool, len(c.Interactions))}
+}
+
+// procura a gravação para a requisição; se não achar, o motivo cita o candidato mais próximo
+func (p *cassettePlayer) match(method, rawURL string, h http.Header, body []byte) (*interaction, string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	last := -1
+	reason := tr("nenhuma gravação com esse método e path")
+	for i := range p.its {
+		why := p.rules.mismatch(method, rawURL, h, body, p.its[i].Request)
+		if why != "" {
+			if why != "method" && why != "path" {
+				reason = tr("gravação com mesmo path difere em ") + why
+			}
+			continue
+		}
+		if !p.used[i] {
+			p.used[i] = true
+			return &p.its[i], ""
+		}
+		last = i
+	}
+	if last >= 0 {
+		return &p.its[last], ""
+	}
+	return nil, reason
+}
+
+// inclui uma gravação nova (já servida ao vivo, então conta como usada)
+func (p *cassettePlayer) add(it interaction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.its = append(p.its, it)
+	p.used = append(p.used, true)
+}
+
+// gravações nunca servidas (útil para saber se o cenário mudou)
+func (p *cassettePlayer) unused() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n := 0
+	for _, u := range p.used {
+		if !u {
+			n++
+		}
+	}
+	return n
+}
+
+// escreve a resposta gravada
+func writeRecorded(w http.ResponseWriter, it *interaction) {
+	for k, vv := range it.Response.Headers {
+		if strings.EqualFold(k, "Content-Length") {
+			continue
+		}
+		for _, v := range vv {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(it.Response.Status)
+	_, _ = w.Write(it.Response.Body.Bytes())
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+/* ==================== Sanitização de cassetes ==================== */
+
+const redacted = "[REDACTED]"
+
+// headers que nunca vão para o cassete em claro
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"}
+
+// chaves de JSON/query com segredo (comparação sem caixa)
+var sensitiveKeys = map[string]bool{
+	"token": true, "access_token": true, "refresh_token": true, "id_token": true, "authtoken": true,
+	"password": true, "senha": true, "secret": true, "client_secret": true, "apikey": true, "api_key": true,
+}
+
+// chaves de JSON que carregam imagem em base64
+var imageKeys = map[string]bool{"image": true, "img": true, "photo": true, "foto": true, "mainimage": true}
+
+type sanitizeOptions struct {
+	Redact       bool     // tokens, cookies e campos sensíveis
+	Images       bool     // troca imagens por um placeholder
+	ExtraHeaders []string // headers adicionais a mascarar
+}
+
+var (
+	placeholderOnce sync.Once
+	placeholderPNG  []byte
+)
+
+// PNG 8x8 cinza: decodifica como imagem válida, então o replay continua funcionando
+func placeholderImage() []byte {
+	placeholderOnce.Do(func() {
+		img := image.NewGray(image.Rect(0, 0, 8, 8))
+		for i := range img.Pix {
+			img.Pix[i] = color.Gray{Y: 128}.Y
+		}
+		var buf bytes.Buffer
+		_ = png.Encode(&buf, img)
+		placeholderPNG = buf.Bytes()
+	})
+	return placeholderPNG
+}
+
+func sanitizeInteraction(it *interaction, opt sanitizeOptions) {
+	if opt.Redact {
+		redactHeaders(it.Request.Headers, opt.ExtraHeaders)
+		redactHeaders(it.Response.Headers, opt.ExtraHeaders)
+		it.Request.URL = redactQuery(it.Request.URL)
+	}
+	it.Request.Body = sanitizeBody(it.Request.Body, it.Request.Headers, opt)
+	it.Response.Body = sanitizeBody(it.Response.Body, it.Response.Headers, opt)
+}
+
+func redactHeaders(h http.Header, extra []string) {
+	for _, k := range append(sensitiveHeaders, extra...) {
+		if _, ok := h[http.CanonicalHeaderKey(k)]; ok {
+			h.Set(k, redacted)
+		}
+	}
+}
+
+func redactQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	q := u.Query()
+	changed := false
+	for k := range q {
+		if sensitiveKeys[strings.ToLower(k)] {
+			q.Set(k, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+func sanitizeBody(cb cassetteBody, h http.Header, opt sanitizeOptions) cassetteBody {
+	if !opt.Redact && !opt.Images {
+		return cb
+	}
+	// corpo binário de imagem (ex.: resposta do mainimage)
+	if opt.Images && strings.HasPrefix(h.Get("Content-Type"), "image/") {
+		h.Del("Content-Length")
+		h.Set("Content-Type", "image/png")
+		return newCassetteBody(placeholderImage())
+	}
+	var v any
+	if cb.Encoding != "" || json.Unmarshal([]byte(cb.Data), &v) != nil {
+		return cb
+	}
+	v = sanitizeJSON(v, "", opt)
+	b, err := json.Marshal(v)
+	if err != nil {
+		return cb
+	}
+	h.Del("Content-Length")
+	return cassetteBody{Data: string(b)}
+}
+
+func sanitizeJSON(v any, key string, opt sanitizeOptions) any {
+	k := strings.ToLower(key)
+	switch x := v.(type) {
+	case map[string]any:
+		for kk, vv := range x {
+			x[kk] = sanitizeJSON(vv, kk, opt)
+		}
+		return x
+	case []any:
+		for i := range x {
+			x[i] = sanitizeJSON(x[i], key, opt)
+		}
+		return x
+	case string:
+		if opt.Redact && sensitiveKeys[k] {
+			return redacted
+		}
+		if opt.Images && imageKeys[k] && len(x) > 64 {
+			return imagePlaceholderString(x)
+		}
+	}
+	return v
+}
+
+// mantém o formato do original (data URI ou base64 puro)
+func imagePlaceholderString(orig string) string {
+	b64 := base64.StdEncoding.EncodeToString(placeholderImage())
+	if strings.HasPrefix(orig, "data:") {
+		return "data:image/png;base64," + b64
+	}
+	return b64
+}
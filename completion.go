@@ -0,0 +1,346 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+/* ==================== completion bash|zsh|fish|powershell ==================== */
+
+// comandos e flags para o autocompletar. Flag terminada em "=" recebe valor.
+// Ao criar comando ou flag em run(), acrescente aqui também.
+type completionCmd struct {
+	Name  string
+	Help  string
+	Flags []string
+	Subs  []string // subcomandos (history list, report sla...)
+}
+
+var completionCommands = []completionCmd{
+	{Name: "create-card", Help: "cria card a partir de imagem", Flags: []string{"image=", "id=", "name=", "consent", "camera", "camera-device=", "camera-delay=", "encoding="}},
+	{Name: "verify-card", Help: "verifica imagem", Flags: []string{"endpoint=", "image=", "id=", "name=", "detail=", "min-similarity=", "require=", "camera", "camera-device=", "camera-delay=", "encoding="}},
+	{Name: "liveness-check", Help: "prova de vida (anti-spoofing)", Flags: []string{"endpoint=", "image=", "video=", "id=", "min-score=", "score-scale=", "score-field="}},
+	{Name: "verify-document", Help: "lê e confere um documento (OCR)", Flags: []string{"endpoint=", "image=", "encoding=", "expect-name=", "expect-id=", "name-field=", "id-field="}},
+	{Name: "get-card", Help: "mostra os dados do card", Flags: []string{"id="}},
+	{Name: "update-card", Help: "troca imagem, nome ou consentimento", Flags: []string{"id=", "method=", "endpoint=", "image=", "name=", "consent"}},
+	{Name: "list-cards", Help: "lista cards", Flags: []string{"endpoint=", "page=", "size=", "name=", "all"}},
+	{Name: "delete-card", Help: "deleta o card", Flags: []string{"id="}},
+	{Name: "delete-cards", Help: "apaga cards em lote", Flags: []string{"ids-file=", "prefix=", "endpoint=", "size=", "name=", "concurrency=", "dry-run", "breaker=", "breaker-pause=", "breaker-probes="}},
+	{Name: "reap", Help: "apaga cards com TTL vencido", Flags: []string{"endpoint=", "size=", "name=", "all-tags", "dry-run"}},
+	{Name: "main-image", Help: "baixa a imagem principal", Flags: []string{"idcard=", "out=", "decode", "compare=", "max-distance=", "min-ssim="}},
+	{Name: "run-all", Help: "preclean, create, verify, delete", Flags: []string{"image=", "id=", "name=", "detail=", "preclean", "rehearse"}},
+	{Name: "run-matrix", Help: "run-all em paralelo para vários ids", Flags: []string{"manifest=", "ids=", "image=", "images=", "name=", "detail=", "preclean", "concurrency=", "breaker=", "breaker-pause=", "breaker-probes="}},
+	{Name: "run-scenario", Help: "executa um cenário YAML", Flags: []string{"file=", "var=", "normalize=", "update-golden", "rehearse"}},
+	{Name: "run-pipeline", Help: "pipeline nomeada do config", Flags: []string{"var=", "rehearse"}},
+	{Name: "batch-create", Help: "cria cards de um manifesto", Flags: []string{"manifest=", "concurrency=", "name=", "consent", "results=", "force", "breaker=", "breaker-pause=", "breaker-probes="}},
+	{Name: "batch-verify", Help: "verifica as imagens de um diretório", Flags: []string{"dir=", "id=", "id-regex=", "name=", "detail=", "concurrency=", "breaker=", "breaker-pause=", "breaker-probes="}},
+	{Name: "gen-data", Help: "gera massa sintética de cards", Flags: []string{"count=", "out=", "images=", "id-format=", "consent-rate=", "seed=", "create", "concurrency=", "force", "breaker=", "breaker-pause=", "breaker-probes="}},
+	{Name: "watch", Help: "verifica cada imagem nova de uma pasta", Flags: []string{"dir=", "id=", "id-regex=", "name=", "detail=", "interval=", "existing", "max="}},
+	{Name: "interactive", Help: "assistente passo a passo para QA manual"},
+	{Name: "serve", Help: "expõe create/verify/delete como serviço REST", Flags: []string{"addr=", "api-key=", "encoding=", "consent"}},
+	{Name: "anonymize-image", Help: "pixeliza/borra o rosto", Flags: []string{"mode=", "block=", "out=", "failed=", "region="}},
+	{Name: "doctor", Help: "diagnostica o ambiente", Flags: []string{"samples="}},
+	{Name: "token-info", Help: "decodifica o JWT e mostra a validade"},
+	{Name: "craft-jwt", Help: "JWT vencido ou quase, forjado pelo mock", Flags: []string{"mock=", "expires-in=", "sub="}},
+	{Name: "api", Help: "operações da spec OpenAPI", Subs: []string{"list", "describe", "call"}},
+	{Name: "api list", Help: "lista as operações", Flags: []string{"spec="}},
+	{Name: "api describe", Help: "parâmetros de uma operação", Flags: []string{"spec="}},
+	{Name: "api call", Help: "chama uma operação", Flags: []string{"spec=", "data=", "form=", "header="}},
+	{Name: "fixtures", Help: "ferramentas do pool de fixtures", Subs: []string{"dedupe"}},
+	{Name: "fixtures dedupe", Help: "imagens idênticas ou quase iguais", Flags: []string{"dir=", "max-distance="}},
+	{Name: "report", Help: "relatórios do results store", Subs: []string{"sla"}},
+	{Name: "report sla", Help: "disponibilidade e p95 por hora/dia", Flags: []string{"by=", "since=", "until=", "endpoint=", "target=", "csv="}},
+	{Name: "history", Help: "execuções gravadas", Subs: []string{"list", "show", "rerun"}},
+	{Name: "history list", Help: "lista execuções", Flags: []string{"n=", "command="}},
+	{Name: "history show", Help: "detalhes de uma execução", Flags: []string{"json"}},
+	{Name: "history rerun", Help: "repete uma execução", Flags: []string{"dry-run"}},
+	{Name: "login", Help: "guarda o token no keyring", Flags: []string{"oauth"}},
+	{Name: "logout", Help: "remove as credenciais do keyring"},
+	{Name: "diff-runs", Help: "compara duas execuções", Flags: []string{"similarity-tolerance=", "latency-tolerance=", "latency-min="}},
+	{Name: "diff-fuzz", Help: "mesmos payloads em A e B", Flags: []string{"a=", "b=", "a-token=", "b-token=", "a-rewrite=", "b-rewrite=", "image=", "n=", "score-tolerance=", "out="}},
+	{Name: "normalize", Help: "aplica regras de normalização a um JSON", Flags: []string{"rules="}},
+	{Name: "soak", Help: "verify/ciclo por horas com resumo por janela", Flags: []string{"mode=", "endpoint=", "image=", "id=", "name=", "detail=", "duration=", "interval=", "summary-every=", "max-error-rate=", "max-drift="}},
+	{Name: "load-verify", Help: "verify em carga", Flags: []string{"endpoint=", "image=", "id=", "name=", "detail=", "rps=", "workers=", "duration=", "max-error-rate="}},
+	{Name: "mock-server", Help: "Biodoc falso local", Flags: []string{"addr=", "clock=", "clock-start=", "delay=", "scoring=", "threshold=", "require-token=", "token-ttl=", "tls", "tls-expired", "tls-cert-out=", "stubs=", "latency=", "bandwidth=", "fault=", "fail="}},
+	{Name: "proxy", Help: "proxy gravando cassete e métricas", Flags: []string{"listen=", "target=", "cassette=", "redact", "placeholder-images", "redact-header=", "replay=", "match=", "match-body=", "ignore-header=", "ignore-field=", "normalize=", "fallthrough", "record-new", "latency=", "reset=", "fail=", "fault=", "seed="}},
+	{Name: "completion", Help: "script de autocompletar do shell", Subs: []string{"bash", "zsh", "fish", "powershell"}},
+}
+
+// flags globais (main), aceitas em qualquer posição
+var completionGlobalFlags = []string{
+	"quiet", "verbose", "lang=", "jq=", "extract=", "log-level=", "log-format=", "log-file=", "output=", "junit=", "report=", "report-images", "notify-webhook=", "notify-on=",
+	"dry-run", "validate-schema", "schema-spec=", "no-progress", "timing", "budget=", "slo=", "expect-status=", "expect=", "expected-duration=", "max-latency-p95=", "max-latency-p99=", "metrics-addr=", "pushgateway=", "profile=", "tenant=", "token=", "token-file=", "ttl=", "tag=", "env-file=", "results=",
+	"proxy=", "ca-cert=", "client-cert=", "client-key=", "timeout=", "keep-alive=", "idle-timeout=", "max-idle-conns=", "tls-handshake-timeout=", "har=", "har-full-images", "bug-report=",
+	"max-dimension=", "max-bytes=", "jpeg-quality=", "watermark", "watermark-text=", "no-exif-fix",
+	"strip-metadata", "strict-quality", "no-quality-check", "require-single-face", "no-strict",
+	"record=", "replay=", "rps=", "rpm=", "retries=", "retry-delay=", "retry-jitter=", "retry-on=", "retry-post",
+}
+
+// valores fixos de algumas flags
+var completionChoices = map[string][]string{
+	"encoding":   {encDataURI, encBase64, encMultipart},
+	"output":     {"text", "json"},
+	"notify-on":  {"always", "failure"},
+	"method":     {"PATCH", "PUT"},
+	"mode":       {"pixelate", "blur", "verify", "cycle"}, // redact e soak
+	"by":         {"hour", "day"},
+	"clock":      {"real", "sim"},
+	"id-format":  {"cpf", "cns"},
+	"lang":       langs,
+	"log-level":  logLevels,
+	"log-format": logFormats,
+}
+
+func cmdCompletion(w io.Writer, shell, bin string) error {
+	cmds := completionCommands
+	if c, err := loadRunnerConfig(configPath()); err == nil {
+		// aliases do config completam como comandos (sem flags próprias)
+		names := make([]string, 0, len(c.Aliases))
+		for n := range c.Aliases {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			cmds = append(cmds, completionCmd{Name: n, Help: "alias: " + c.Aliases[n]})
+		}
+	}
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, bin, cmds)
+	case "zsh":
+		writeZshCompletion(w, bin, cmds)
+	case "fish":
+		writeFishCompletion(w, bin, cmds)
+	case "powershell", "pwsh":
+		writePowerShellCompletion(w, bin, cmds)
+	default:
+		return usageError(tr("uso: completion bash|zsh|fish|powershell"))
+	}
+	return nil
+}
+
+// nome da função/variável no shell: biodoc-go-runner → biodoc_go_runner
+func completionIdent(bin string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, bin)
+}
+
+func flagWords(flags []string) string {
+	out := make([]string, len(flags))
+	for i, f := range flags {
+		out[i] = "--" + strings.TrimSuffix(f, "=")
+	}
+	return strings.Join(out, " ")
+}
+
+// flags que recebem valor, "--a|--b" para case do bash
+func valueFlagPattern(cmds []completionCmd) string {
+	seen := map[string]bool{}
+	var out []string
+	add := func(fl []string) {
+		for _, f := range fl {
+			if name, ok := strings.CutSuffix(f, "="); ok && !seen[name] {
+				seen[name] = true
+				out = append(out, "--"+name)
+			}
+		}
+	}
+	add(completionGlobalFlags)
+	for _, c := range cmds {
+		add(c.Flags)
+	}
+	sort.Strings(out)
+	return strings.Join(out, "|")
+}
+
+func topLevelNames(cmds []completionCmd) []string {
+	var out []string
+	for _, c := range cmds {
+		if !strings.Contains(c.Name, " ") {
+			out = append(out, c.Name)
+		}
+	}
+	return out
+}
+
+func writeBashCompletion(w io.Writer, bin string, cmds []completionCmd) {
+	fn := "_" + completionIdent(bin)
+	fmt.Fprintf(w, "# bash: source <(%s completion bash)\n", bin)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(w, "    local cmd=\"\" i w skip=0\n")
+	fmt.Fprintf(w, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(w, "        w=\"${COMP_WORDS[i]}\"\n")
+	fmt.Fprintf(w, "        if ((skip)); then skip=0; continue; fi\n")
+	fmt.Fprintf(w, "        case \"$w\" in\n")
+	fmt.Fprintf(w, "            %s) skip=1 ;;\n", valueFlagPattern(cmds))
+	fmt.Fprintf(w, "            -*) ;;\n")
+	fmt.Fprintf(w, "            *) if [[ -z $cmd ]]; then cmd=\"$w\"; elif [[ $cmd != *\" \"* ]]; then cmd=\"$cmd $w\"; fi ;;\n")
+	fmt.Fprintf(w, "        esac\n")
+	fmt.Fprintf(w, "    done\n")
+	fmt.Fprintf(w, "    case \"$prev\" in\n")
+	for _, name := range sortedKeys(completionChoices) {
+		fmt.Fprintf(w, "        --%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(completionChoices[name], " "))
+	}
+	fmt.Fprintf(w, "        %s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", valueFlagPattern(cmds))
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    local global=%q subs=\"\" flags=\"\"\n", flagWords(completionGlobalFlags))
+	fmt.Fprintf(w, "    case \"$cmd\" in\n")
+	fmt.Fprintf(w, "        \"\") subs=%q ;;\n", strings.Join(topLevelNames(cmds), " "))
+	for _, c := range cmds {
+		fmt.Fprintf(w, "        %q) subs=%q flags=%q ;;\n", c.Name, strings.Join(c.Subs, " "), flagWords(c.Flags))
+	}
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    if [[ $cur == -* ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"$flags $global\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "    elif [[ -n $subs ]]; then\n")
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"$subs\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "    fi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -o default -F %s %s\n", fn, bin)
+}
+
+func writeZshCompletion(w io.Writer, bin string, cmds []completionCmd) {
+	fn := "_" + completionIdent(bin)
+	fmt.Fprintf(w, "#compdef %s\n", bin)
+	fmt.Fprintf(w, "# zsh: %s completion zsh > \"${fpath[1]}/_%s\" (ou source <(%s completion zsh))\n", bin, bin, bin)
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "    local -a cmds global flags\n")
+	fmt.Fprintf(w, "    local cmd=\"\" i w skip=0\n")
+	fmt.Fprintf(w, "    for ((i = 2; i < CURRENT; i++)); do\n")
+	fmt.Fprintf(w, "        w=\"${words[i]}\"\n")
+	fmt.Fprintf(w, "        if ((skip)); then skip=0; continue; fi\n")
+	fmt.Fprintf(w, "        case \"$w\" in\n")
+	fmt.Fprintf(w, "            %s) skip=1 ;;\n", valueFlagPattern(cmds))
+	fmt.Fprintf(w, "            -*) ;;\n")
+	fmt.Fprintf(w, "            *) if [[ -z $cmd ]]; then cmd=\"$w\"; elif [[ $cmd != *\" \"* ]]; then cmd=\"$cmd $w\"; fi ;;\n")
+	fmt.Fprintf(w, "        esac\n")
+	fmt.Fprintf(w, "    done\n")
+	fmt.Fprintf(w, "    case \"${words[CURRENT-1]}\" in\n")
+	for _, name := range sortedKeys(completionChoices) {
+		fmt.Fprintf(w, "        --%s) compadd -- %s; return ;;\n", name, strings.Join(completionChoices[name], " "))
+	}
+	fmt.Fprintf(w, "        %s) _files; return ;;\n", valueFlagPattern(cmds))
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    global=(%s)\n", flagWords(completionGlobalFlags))
+	fmt.Fprintf(w, "    case \"$cmd\" in\n")
+	fmt.Fprintf(w, "        \"\")\n")
+	fmt.Fprintf(w, "            cmds=(\n")
+	for _, c := range cmds {
+		if !strings.Contains(c.Name, " ") {
+			fmt.Fprintf(w, "                %s\n", zshQuote(c.Name+":"+tr(c.Help)))
+		}
+	}
+	fmt.Fprintf(w, "            )\n")
+	fmt.Fprintf(w, "            if [[ $PREFIX == -* ]]; then compadd -- $global; else _describe comando cmds; fi\n")
+	fmt.Fprintf(w, "            return ;;\n")
+	for _, c := range cmds {
+		if len(c.Subs) > 0 {
+			fmt.Fprintf(w, "        %s) compadd -- %s; return ;;\n", zshQuote(c.Name), strings.Join(c.Subs, " "))
+			continue
+		}
+		fmt.Fprintf(w, "        %s) flags=(%s) ;;\n", zshQuote(c.Name), flagWords(c.Flags))
+	}
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    if [[ $PREFIX == -* ]]; then compadd -- $flags $global; else _files; fi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "compdef %s %s\n", fn, bin)
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeFishCompletion(w io.Writer, bin string, cmds []completionCmd) {
+	fmt.Fprintf(w, "# fish: %s completion fish > ~/.config/fish/completions/%s.fish\n", bin, bin)
+	fmt.Fprintf(w, "complete -c %s -f\n", bin)
+	fishFlag := func(cond, f string) {
+		name, value := strings.CutSuffix(f, "=")
+		line := fmt.Sprintf("complete -c %s -n %s -l %s", bin, zshQuote(cond), name)
+		if choices, ok := completionChoices[name]; ok {
+			line += " -x -a " + zshQuote(strings.Join(choices, " "))
+		} else if value {
+			line += " -r -F"
+		}
+		fmt.Fprintln(w, line)
+	}
+	for _, f := range completionGlobalFlags {
+		fishFlag("true", f)
+	}
+	for _, c := range cmds {
+		parent, sub, nested := strings.Cut(c.Name, " ")
+		if !nested {
+			fmt.Fprintf(w, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", bin, c.Name, zshQuote(tr(c.Help)))
+		} else {
+			cond := fmt.Sprintf("__fish_seen_subcommand_from %s; and not __fish_seen_subcommand_from %s", parent, sub)
+			fmt.Fprintf(w, "complete -c %s -n %s -a %s -d %s\n", bin, zshQuote(cond), sub, zshQuote(tr(c.Help)))
+		}
+		cond := "__fish_seen_subcommand_from " + c.Name
+		if nested {
+			cond = "__fish_seen_subcommand_from " + parent + "; and __fish_seen_subcommand_from " + sub
+		}
+		for _, f := range c.Flags {
+			fishFlag(cond, f)
+		}
+	}
+}
+
+func writePowerShellCompletion(w io.Writer, bin string, cmds []completionCmd) {
+	psList := func(words []string) string {
+		q := make([]string, len(words))
+		for i, s := range words {
+			q[i] = "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+		return "@(" + strings.Join(q, ", ") + ")"
+	}
+	dashed := func(flags []string) []string {
+		return strings.Fields(flagWords(flags))
+	}
+	fmt.Fprintf(w, "# PowerShell: %s completion powershell | Out-String | Invoke-Expression (ou no $PROFILE)\n", bin)
+	fmt.Fprintf(w, "Register-ArgumentCompleter -Native -CommandName '%s', '%s.exe' -ScriptBlock {\n", bin, bin)
+	fmt.Fprintf(w, "    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprintf(w, "    $global = %s\n", psList(dashed(completionGlobalFlags)))
+	fmt.Fprintf(w, "    $valueFlags = %s\n", psList(strings.Split(valueFlagPattern(cmds), "|")))
+	fmt.Fprintf(w, "    $choices = @{\n")
+	for _, name := range sortedKeys(completionChoices) {
+		fmt.Fprintf(w, "        '--%s' = %s\n", name, psList(completionChoices[name]))
+	}
+	fmt.Fprintf(w, "    }\n")
+	fmt.Fprintf(w, "    $commands = [ordered]@{\n")
+	for _, c := range cmds {
+		words := dashed(c.Flags)
+		if len(c.Subs) > 0 {
+			words = c.Subs
+		}
+		fmt.Fprintf(w, "        '%s' = %s\n", c.Name, psList(words))
+	}
+	fmt.Fprintf(w, "    }\n")
+	fmt.Fprintf(w, "    $words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })\n")
+	fmt.Fprintf(w, "    if ($wordToComplete -ne '' -and $words.Count -gt 0) { $words = $words[0..($words.Count - 2)] }\n")
+	fmt.Fprintf(w, "    $cmd = ''; $skip = $false; $prev = ''\n")
+	fmt.Fprintf(w, "    foreach ($w in $words) {\n")
+	fmt.Fprintf(w, "        $prev = $w\n")
+	fmt.Fprintf(w, "        if ($skip) { $skip = $false; continue }\n")
+	fmt.Fprintf(w, "        if ($valueFlags -contains $w) { $skip = $true; continue }\n")
+	fmt.Fprintf(w, "        if ($w.StartsWith('-')) { continue }\n")
+	fmt.Fprintf(w, "        if ($cmd -eq '') { $cmd = $w } elseif (-not $cmd.Contains(' ')) { $cmd = \"$cmd $w\" }\n")
+	fmt.Fprintf(w, "    }\n")
+	fmt.Fprintf(w, "    if ($skip) {\n")
+	fmt.Fprintf(w, "        if ($choices.Contains($prev)) { $candidates = $choices[$prev] } else { return }\n")
+	fmt.Fprintf(w, "    } elseif ($cmd -eq '') {\n")
+	fmt.Fprintf(w, "        $candidates = if ($wordToComplete.StartsWith('-')) { $global } else { @($commands.Keys | Where-Object { -not $_.Contains(' ') }) }\n")
+	fmt.Fprintf(w, "    } else {\n")
+	fmt.Fprintf(w, "        $own = if ($commands.Contains($cmd)) { $commands[$cmd] } else { @() }\n")
+	fmt.Fprintf(w, "        $candidates = if ($wordToComplete.StartsWith('-')) { $own + $global } else { @($own | Where-Object { -not $_.StartsWith('-') }) }\n")
+	fmt.Fprintf(w, "    }\n")
+	fmt.Fprintf(w, "    $candidates | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	fmt.Fprintf(w, "        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	fmt.Fprintf(w, "    }\n")
+	fmt.Fprintf(w, "}\n")
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== Cache de criações (id + SHA-256 da imagem) ==================== */
+
+// batch-create repetido não recria o card que já foi criado com a mesma imagem no mesmo ambiente.
+// Arquivo em ~/.cache/biodoc-runner/creates.json (ENV CREATE_CACHE; 0 desliga); --force ignora.
+// DELETE/PUT/PATCH em /api/card/{id} (delete-card, preclean, reap, update-card...) tiram o id do cache
+type createCache struct {
+	mu      sync.Mutex
+	path    string
+	Entries map[string]createCacheEntry `json:"entries"`
+	dirty   bool
+}
+
+type createCacheEntry struct {
+	BaseURL   string    `json:"base_url"`
+	ID        string    `json:"id"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// --dry-run e --replay não tocam no cache: nada foi criado de verdade
+func createCachePath() string {
+	if _, replaying := httpClient.Transport.(*replayTransport); dryRun || replaying {
+		return ""
+	}
+	if p := os.Getenv("CREATE_CACHE"); p != "" {
+		if p == "0" {
+			return ""
+		}
+		return p
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "biodoc-runner", "creates.json")
+}
+
+// cache vazio (e sem arquivo) quando desligado; arquivo ilegível recomeça do zero
+func loadCreateCache() *createCache {
+	c := &createCache{path: createCachePath(), Entries: map[string]createCacheEntry{}}
+	if c.path == "" {
+		return c
+	}
+	if b, err := os.ReadFile(c.path); err == nil {
+		_ = json.Unmarshal(b, c)
+		if c.Entries == nil {
+			c.Entries = map[string]createCacheEntry{}
+		}
+	}
+	return c
+}
+
+func createCacheKey(baseURL, id, sum string) string {
+	return strings.TrimRight(baseURL, "/") + "|" + id + "|" + sum
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *createCache) lookup(baseURL, id, sum string) (createCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.Entries[createCacheKey(baseURL, id, sum)]
+	return e, ok
+}
+
+func (c *createCache) add(baseURL, id, sum string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// um id guarda só a última imagem: criar com outra foto substitui a anterior
+	c.forgetLocked(baseURL, id)
+	c.Entries[createCacheKey(baseURL, id, sum)] = createCacheEntry{
+		BaseURL: strings.TrimRight(baseURL, "/"), ID: id, SHA256: sum, CreatedAt: time.Now().UTC(),
+	}
+	c.dirty = true
+}
+
+func (c *createCache) forgetLocked(baseURL, id string) {
+	base := strings.TrimRight(baseURL, "/")
+	for k, e := range c.Entries {
+		if e.BaseURL == base && e.ID == id {
+			delete(c.Entries, k)
+			c.dirty = true
+		}
+	}
+}
+
+func (c *createCache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.dirty = false
+	return os.WriteFile(c.path, b, 0o600)
+}
+
+// um cache por processo, lido na primeira vez que é preciso
+var (
+	createCacheOnce sync.Once
+	sharedCache     *createCache
+)
+
+func processCreateCache() *createCache {
+	createCacheOnce.Do(func() { sharedCache = loadCreateCache() })
+	return sharedCache
+}
+
+// chamado a cada resposta de doRequest: card apagado ou alterado sai do cache (e do arquivo na hora)
+func forgetChangedCard(method, rawURL string, resp *http.Response) {
+	if resp == nil || (method != http.MethodDelete && method != http.MethodPut && method != http.MethodPatch) {
+		return
+	}
+	ok := resp.StatusCode >= 200 && resp.StatusCode < 300 || method == http.MethodDelete && resp.StatusCode == http.StatusNotFound
+	base, id, found := strings.Cut(rawURL, "/api/card/")
+	if !ok || !found || id == "" || strings.Contains(id, "/") || createCachePath() == "" {
+		return
+	}
+	if i := strings.IndexByte(id, '?'); i >= 0 {
+		id = id[:i]
+	}
+	if u, err := url.PathUnescape(id); err == nil {
+		id = u
+	}
+	c := processCreateCache()
+	c.mu.Lock()
+	c.forgetLocked(base, id)
+	c.mu.Unlock()
+	_ = c.save()
+}
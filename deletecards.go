@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== delete-cards (apagar em lote) ==================== */
+
+type deleteCardsOptions struct {
+	IDsFile     string // um id por linha; "-" = stdin
+	Prefix      string // padrão de id ("9998*") aplicado à listagem
+	List        listOptions
+	Concurrency int
+	DryRun      bool
+	Breaker     breakerOptions
+}
+
+type deleteResult struct {
+	ID        string `json:"id"`
+	Status    int    `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Deleted   bool   `json:"deleted"`
+	Missing   bool   `json:"missing,omitempty"` // 404: já não existia
+	Error     string `json:"error,omitempty"`
+	outage    bool   // sem resposta ou 5xx (circuit breaker)
+	skipped   bool   // não enviado: lote abortado
+}
+
+// ids do arquivo: linhas vazias e "#..." ignoradas, repetidos uma vez só
+func readIDsFile(p string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if p != "-" {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var ids []string
+	seen := map[string]bool{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		id, _, _ := strings.Cut(sc.Text(), "#")
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, sc.Err()
+}
+
+// ids da listagem que casam com --prefix ("9998*" ou só "9998"); o padrão precisa começar
+// com um trecho literal: "*" ou "?99*" apagariam a base toda
+func idsByPrefix(baseURL, token string, opt deleteCardsOptions) ([]string, error) {
+	pattern := opt.Prefix
+	if strings.IndexAny(pattern, "*?[\\") == 0 {
+		return nil, usageError(fmt.Sprintf(tr("--prefix %q começa com curinga: informe o início literal dos ids (ex.: 9998*)"), opt.Prefix))
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		pattern += "*"
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, usageError(fmt.Sprintf(tr("--prefix inválido %q: %v"), opt.Prefix, err))
+	}
+	opt.List.All = true
+	cards, _, err := listCards(baseURL, token, opt.List)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, c := range cards {
+		if ok, _ := path.Match(pattern, c.ID); ok {
+			ids = append(ids, c.ID)
+		}
+	}
+	outf("[delete-cards] %d card(s) listados, %d casam com %s\n", len(cards), len(ids), pattern)
+	return ids, nil
+}
+
+// apaga os cards com N workers; 404 conta como "já não existia" e não para o lote
+func cmdDeleteCards(baseURL, token string, opt deleteCardsOptions) error {
+	var ids []string
+	var err error
+	switch {
+	case opt.IDsFile != "" && opt.Prefix != "":
+		return usageError(tr("use --ids-file ou --prefix, não os dois"))
+	case opt.IDsFile != "":
+		ids, err = readIDsFile(opt.IDsFile)
+	case opt.Prefix != "":
+		ids, err = idsByPrefix(baseURL, token, opt)
+	default:
+		return usageError(tr("--ids-file ou --prefix é obrigatório"))
+	}
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		outf("[delete-cards] nenhum id para apagar\n")
+		return nil
+	}
+	if opt.DryRun {
+		for _, id := range ids {
+			outf("[delete-cards] id=%s (dry-run, não apagado)\n", id)
+		}
+		outf("[delete-cards] %d card(s) seriam apagados\n", len(ids))
+		setResult("ids", ids)
+		return nil
+	}
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 1
+	}
+	outf("[delete-cards] %d id(s), %d worker(s)\n", len(ids), opt.Concurrency)
+
+	results := make([]deleteResult, len(ids))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	start := time.Now()
+	bar := newProgressBar("delete-cards", len(ids), 0)
+	cb := newCircuitBreaker("delete-cards", baseURL, token, opt.Breaker)
+
+	for w := 0; w < opt.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if !cb.allow() {
+					results[i] = deleteResult{ID: ids[i], Error: tr("não enviado: lote abortado pelo circuit breaker"), skipped: true}
+					bar.add(true)
+					continue
+				}
+				results[i] = deleteOne(baseURL, token, ids[i])
+				cb.record(results[i].outage)
+				bar.add(results[i].Error != "")
+			}
+		}()
+	}
+	for i := range ids {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	bar.finish()
+
+	var deleted, missing, failed []string
+	for _, r := range results {
+		switch {
+		case r.Deleted:
+			deleted = append(deleted, r.ID)
+		case r.Missing:
+			missing = append(missing, r.ID)
+		default:
+			failed = append(failed, r.ID)
+			if !r.skipped {
+				outf("  ❌ id=%s: %s\n", r.ID, r.Error)
+			}
+		}
+	}
+	outf("[delete-cards] total=%d apagados=%d já-inexistentes=%d falhas=%d em %s\n",
+		len(ids), len(deleted), len(missing), len(failed), time.Since(start).Round(time.Millisecond))
+	breakerErr := cb.summary()
+	setResult("deleted", deleted)
+	setResult("missing", missing)
+	setResult("failed", failed)
+	if breakerErr != nil {
+		return breakerErr
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf(tr("falha ao apagar %d card(s): %s"), len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func deleteOne(baseURL, token, id string) deleteResult {
+	u := cardURL(baseURL, id)
+	t0 := time.Now()
+	resp, body, err := doRequest(http.MethodDelete, u, authHeader(token), nil)
+	lat := time.Since(t0)
+	res := deleteResult{ID: id, LatencyMS: lat.Milliseconds()}
+	if resp != nil {
+		res.Status = resp.StatusCode
+	}
+	res.outage = isOutage(res.Status, err)
+	switch {
+	case err != nil:
+		res.Error = err.Error()
+	case resp.StatusCode == http.StatusNotFound:
+		res.Missing = true
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		res.Error = newAPIError(resp.StatusCode, body).Error()
+	default:
+		res.Deleted = true
+	}
+	st := stepRecord{Name: "delete id=" + id, Duration: lat, Error: res.Error}
+	st.Calls = []callRecord{newCallRecord(http.MethodDelete, u, resp, body, err, 0, lat)}
+	addStep(st)
+	return res
+}
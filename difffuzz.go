@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strings"
+)
+
+/* ==================== diff-fuzz (v1 × v2 / ambiente × ambiente) ==================== */
+
+type fuzzTarget struct {
+	Label   string
+	BaseURL string
+	Token   string
+	Rewrite [2]string // prefixo de path antigo → novo (ex.: /api/card → /api/v2/card)
+}
+
+func (t fuzzTarget) url(path string) string {
+	if t.Rewrite[0] != "" && strings.HasPrefix(path, t.Rewrite[0]) {
+		path = t.Rewrite[1] + strings.TrimPrefix(path, t.Rewrite[0])
+	}
+	return strings.TrimRight(t.BaseURL, "/") + path
+}
+
+type fuzzOptions struct {
+	A, B           fuzzTarget
+	Image          string
+	Cases          int // casos aleatórios além dos fixos
+	Seed           uint64
+	ScoreTolerance float64 // diferença de similaridade tolerada (pontos percentuais)
+	Out            string  // relatório JSON
+}
+
+// payload gerado; o mesmo é enviado aos dois lados
+type fuzzCase struct {
+	Name     string         `json:"name"`
+	Endpoint string         `json:"endpoint"` // register | verify
+	Body     map[string]any `json:"-"`
+}
+
+// o que importa comparar de uma resposta
+type fuzzOutcome struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code,omitempty"`
+	Success *bool  `json:"success,omitempty"`
+	Score   string `json:"score,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type fuzzDiff struct {
+	Case     string      `json:"case"`
+	Endpoint string      `json:"endpoint"`
+	A        fuzzOutcome `json:"a"`
+	B        fuzzOutcome `json:"b"`
+	Fields   []string    `json:"differences"`
+}
+
+// mutação aplicada sobre o payload válido
+type fuzzMutation struct {
+	name string
+	on   string // register, verify ou "" (ambos)
+	fn   func(body map[string]any, rng *rand.Rand)
+}
+
+var fuzzMutations = []fuzzMutation{
+	{"sem-id", "", func(b map[string]any, _ *rand.Rand) { delete(b, "id") }},
+	{"id-vazio", "", func(b map[string]any, _ *rand.Rand) { b["id"] = "" }},
+	{"id-nao-numerico", "", func(b map[string]any, _ *rand.Rand) { b["id"] = "abc-123" }},
+	{"id-longo", "", func(b map[string]any, _ *rand.Rand) { b["id"] = strings.Repeat("9", 200) }},
+	{"id-numero", "", func(b map[string]any, _ *rand.Rand) { b["id"] = 12345 }},
+	{"id-inexistente", "verify", func(b map[string]any, rng *rand.Rand) { b["id"] = fuzzID(rng) }},
+	{"sem-nome", "", func(b map[string]any, _ *rand.Rand) { delete(b, "name") }},
+	{"nome-unicode", "", func(b map[string]any, _ *rand.Rand) { b["name"] = "Ção Ñúñez 🧪 " + strings.Repeat("ã", 120) }},
+	{"nome-null", "", func(b map[string]any, _ *rand.Rand) { b["name"] = nil }},
+	{"sem-imagem", "", func(b map[string]any, _ *rand.Rand) { delete(b, "image") }},
+	{"imagem-vazia", "", func(b map[string]any, _ *rand.Rand) { b["image"] = "" }},
+	{"imagem-base64-invalido", "", func(b map[string]any, _ *rand.Rand) { b["image"] = "não-é-base64!!" }},
+	{"imagem-nao-imagem", "", func(b map[string]any, _ *rand.Rand) {
+		b["image"] = base64.StdEncoding.EncodeToString([]byte("texto qualquer, não é imagem"))
+	}},
+	{"imagem-truncada", "", func(b map[string]any, rng *rand.Rand) {
+		s, _ := b["image"].(string)
+		if len(s) > 8 {
+			b["image"] = s[:len(s)/2+rng.IntN(len(s)/4)]
+		}
+	}},
+	{"imagem-data-uri", "register", func(b map[string]any, _ *rand.Rand) {
+		s, _ := b["image"].(string)
+		b["image"] = "data:image/jpeg;base64," + s
+	}},
+	{"imagem-sem-data-uri", "verify", func(b map[string]any, _ *rand.Rand) {
+		s, _ := b["image"].(string)
+		if i := strings.Index(s, ","); i >= 0 {
+			b["image"] = s[i+1:]
+		}
+	}},
+	{"mime-errado", "verify", func(b map[string]any, _ *rand.Rand) {
+		s, _ := b["image"].(string)
+		if i := strings.Index(s, ";"); i >= 0 {
+			b["image"] = "data:application/pdf" + s[i:]
+		}
+	}},
+	{"consent-false", "register", func(b map[string]any, _ *rand.Rand) { b["consentTermSigned"] = false }},
+	{"consent-string", "register", func(b map[string]any, _ *rand.Rand) { b["consentTermSigned"] = "true" }},
+	{"sem-consent", "register", func(b map[string]any, _ *rand.Rand) { delete(b, "consentTermSigned") }},
+	{"detail-vazio", "verify", func(b map[string]any, _ *rand.Rand) { b["detail"] = "" }},
+	{"detail-objeto", "verify", func(b map[string]any, _ *rand.Rand) { b["detail"] = map[string]any{"guia": "654321"} }},
+	{"detail-grande", "verify", func(b map[string]any, _ *rand.Rand) { b["detail"] = "{'x':'" + strings.Repeat("x", 64*1024) + "'}" }},
+	{"campo-extra", "", func(b map[string]any, _ *rand.Rand) { b["campoDesconhecido"] = true }},
+}
+
+func fuzzID(rng *rand.Rand) string {
+	return fmt.Sprintf("9997%013d", rng.Uint64N(1e13))
+}
+
+// casos fixos (uma mutação por caso) + n combinações aleatórias de 2 a 3 mutações
+func generateFuzzCases(img []byte, baseID string, n int, rng *rand.Rand) []fuzzCase {
+	b64 := base64.StdEncoding.EncodeToString(img)
+	base := func(ep string) map[string]any {
+		if ep == "register" {
+			return map[string]any{"id": baseID, "name": "Fuzz QA", "consentTermSigned": true, "image": b64}
+		}
+		return map[string]any{"id": baseID, "name": "Fuzz QA", "detail": "{'guia':'654321'}", "image": "data:image/jpeg;base64," + b64}
+	}
+	var out []fuzzCase
+	for _, ep := range []string{"register", "verify"} {
+		out = append(out, fuzzCase{Name: "valido", Endpoint: ep, Body: base(ep)})
+		for _, m := range fuzzMutations {
+			if m.on != "" && m.on != ep {
+				continue
+			}
+			b := base(ep)
+			m.fn(b, rng)
+			out = append(out, fuzzCase{Name: m.name, Endpoint: ep, Body: b})
+		}
+	}
+	for i := 0; i < n; i++ {
+		ep := []string{"register", "verify"}[rng.IntN(2)]
+		b := base(ep)
+		var names []string
+		for k := 2 + rng.IntN(2); k > 0; k-- {
+			m := fuzzMutations[rng.IntN(len(fuzzMutations))]
+			if m.on != "" && m.on != ep {
+				continue
+			}
+			m.fn(b, rng)
+			names = append(names, m.name)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		out = append(out, fuzzCase{Name: strings.Join(names, "+"), Endpoint: ep, Body: b})
+	}
+	return out
+}
+
+var fuzzPaths = map[string]string{
+	"register": "/api/card/integration/register",
+	"verify":   "/api/card/integration/verify",
+}
+
+func sendFuzzCase(t fuzzTarget, c fuzzCase) fuzzOutcome {
+	resp, body, err := doJSON(http.MethodPost, t.url(fuzzPaths[c.Endpoint]), authHeader(t.Token), c.Body)
+	if err != nil {
+		return fuzzOutcome{Error: err.Error()}
+	}
+	return summarizeOutcome(resp.StatusCode, body)
+}
+
+func summarizeOutcome(status int, body []byte) fuzzOutcome {
+	o := fuzzOutcome{Status: status}
+	var v any
+	if json.Unmarshal(body, &v) != nil {
+		return o
+	}
+	for _, p := range []string{"code", "errorCode", "error_code", "response.code", "response.errorCode", "error"} {
+		if x, ok := jsonPath(v, p); ok && x != nil {
+			if _, isObj := x.(map[string]any); !isObj {
+				o.Code = fmt.Sprint(x)
+				break
+			}
+		}
+	}
+	for _, p := range []string{"response.success", "success"} {
+		if x, ok := jsonPath(v, p); ok {
+			if bv, ok := x.(bool); ok {
+				o.Success = &bv
+				break
+			}
+		}
+	}
+	for _, p := range []string{"response.percentage", "percentage", "similarity"} {
+		if x, ok := jsonPath(v, p); ok && x != nil && fmt.Sprint(x) != "" {
+			o.Score = fmt.Sprint(x)
+			break
+		}
+	}
+	return o
+}
+
+// diferenças de comportamento entre os dois lados (vazio = iguais)
+func compareOutcomes(a, b fuzzOutcome, tol float64) []string {
+	var d []string
+	if a.Error != "" || b.Error != "" {
+		if (a.Error == "") != (b.Error == "") {
+			d = append(d, fmt.Sprintf(tr("erro de transporte: %q → %q"), a.Error, b.Error))
+		}
+		return d
+	}
+	if a.Status != b.Status {
+		d = append(d, fmt.Sprintf("status %d → %d", a.Status, b.Status))
+	}
+	if a.Code != b.Code {
+		d = append(d, fmt.Sprintf(tr("código %q → %q"), a.Code, b.Code))
+	}
+	if (a.Success == nil) != (b.Success == nil) || (a.Success != nil && *a.Success != *b.Success) {
+		d = append(d, fmt.Sprintf("success %s → %s", boolPtrStr(a.Success), boolPtrStr(b.Success)))
+	}
+	sa, oka := parsePercent(a.Score)
+	sb, okb := parsePercent(b.Score)
+	switch {
+	case oka && okb:
+		if math.Abs(sa-sb) > tol {
+			d = append(d, fmt.Sprintf(tr("similaridade %s → %s"), a.Score, b.Score))
+		}
+	case a.Score != b.Score:
+		d = append(d, fmt.Sprintf(tr("similaridade %q → %q"), a.Score, b.Score))
+	}
+	return d
+}
+
+func boolPtrStr(b *bool) string {
+	if b == nil {
+		return tr("(ausente)")
+	}
+	return fmt.Sprint(*b)
+}
+
+func cmdDiffFuzz(opt fuzzOptions) error {
+	img, err := os.ReadFile(opt.Image)
+	if err != nil {
+		return fmt.Errorf(tr("ler imagem: %w"), err)
+	}
+	seed := opt.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, ^seed))
+	baseID := fuzzID(rng)
+	cases := generateFuzzCases(img, baseID, opt.Cases, rng)
+	outf("[diff-fuzz] A=%s B=%s | %d casos | seed=%d\n", opt.A.url(""), opt.B.url(""), len(cases), seed)
+
+	// card base nos dois lados, para o verify ter com quem comparar
+	b64 := base64.StdEncoding.EncodeToString(img)
+	for _, t := range []fuzzTarget{opt.A, opt.B} {
+		body := map[string]any{"id": baseID, "name": "Fuzz QA", "consentTermSigned": true, "image": b64}
+		if _, _, err := doJSON(http.MethodPost, t.url(fuzzPaths["register"]), authHeader(t.Token), body); err != nil {
+			return fmt.Errorf(tr("criar card base em %s: %w"), t.Label, err)
+		}
+	}
+	defer func() {
+		for _, t := range []fuzzTarget{opt.A, opt.B} {
+			_, _, _ = doRequest(http.MethodDelete, t.url("/api/card/"+baseID), authHeader(t.Token), nil)
+		}
+	}()
+
+	var diffs []fuzzDiff
+	for _, c := range cases {
+		// register mutado ganha id próprio (o mesmo nos dois lados) para não bater no card base
+		if c.Endpoint == "register" {
+			if id, ok := c.Body["id"].(string); ok && id == baseID {
+				c.Body["id"] = fuzzID(rng)
+			}
+		}
+		a := sendFuzzCase(opt.A, c)
+		b := sendFuzzCase(opt.B, c)
+		if c.Endpoint == "register" && (a.Status/100 == 2 || b.Status/100 == 2) {
+			if id, ok := c.Body["id"].(string); ok && id != "" && id != baseID {
+				for _, t := range []fuzzTarget{opt.A, opt.B} {
+					_, _, _ = doRequest(http.MethodDelete, t.url("/api/card/"+id), authHeader(t.Token), nil)
+				}
+			}
+		}
+		if d := compareOutcomes(a, b, opt.ScoreTolerance); len(d) > 0 {
+			diffs = append(diffs, fuzzDiff{Case: c.Name, Endpoint: c.Endpoint, A: a, B: b, Fields: d})
+			outf("[diff] %s/%s: %s\n", c.Endpoint, c.Name, strings.Join(d, "; "))
+		}
+	}
+
+	outf("[diff-fuzz] %d casos, %d com comportamento diferente\n", len(cases), len(diffs))
+	setResult("seed", seed)
+	setResult("cases", len(cases))
+	setResult("diffs", diffs)
+	if opt.Out != "" {
+		rep := map[string]any{
+			"a": opt.A.url(""), "b": opt.B.url(""), "seed": seed, "cases": len(cases), "diffs": diffs,
+		}
+		if err := os.WriteFile(opt.Out, indentJSON(rep, "  "), 0644); err != nil {
+			return err
+		}
+		outf("[diff-fuzz] relatório em %s\n", opt.Out)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf(tr("%d diferença(s) de comportamento entre A e B"), len(diffs))
+	}
+	return nil
+}
+
+// "--rewrite /api/card=/api/v2/card"
+func parseRewrite(s string) ([2]string, error) {
+	if s == "" {
+		return [2]string{}, nil
+	}
+	from, to, ok := strings.Cut(s, "=")
+	if !ok || !strings.HasPrefix(from, "/") {
+		return [2]string{}, usageError(fmt.Sprintf(tr("rewrite inválido %q (use /api/card=/api/v2/card)"), s))
+	}
+	return [2]string{from, to}, nil
+}
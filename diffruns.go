@@ -0,0 +1,308 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== diff-runs ==================== */
+
+type diffRunsOptions struct {
+	SimTolerance float64       // queda de similaridade (pontos) tolerada antes de virar regressão
+	LatTolerance float64       // aumento relativo de latência tolerado (0.2 = +20%)
+	LatMin       time.Duration // aumentos menores que isso não contam (ruído)
+}
+
+// o que se compara de cada chamada/imagem entre as duas execuções
+type runObs struct {
+	Status     int      `json:"status,omitempty"`
+	LatencyMS  int64    `json:"latency_ms"`
+	Similarity *float64 `json:"similarity,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+type runSnapshot struct {
+	Label   string
+	Command string
+	Keys    []string // na ordem em que aconteceram
+	Obs     map[string]runObs
+}
+
+func (s *runSnapshot) add(key string, o runObs) {
+	if _, dup := s.Obs[key]; !dup {
+		s.Keys = append(s.Keys, key)
+	}
+	s.Obs[key] = o
+}
+
+// formatos aceitos: runRecord (results store, history show --json → "record") e --output json
+type runDoc struct {
+	ID      string          `json:"id"`
+	RunID   string          `json:"run_id"`
+	Command string          `json:"command"`
+	Result  map[string]any  `json:"result"`
+	Record  json.RawMessage `json:"record"`
+	Calls   []struct {
+		Method    string `json:"method"`
+		URL       string `json:"url"`
+		Endpoint  string `json:"endpoint"`
+		Status    int    `json:"status"`
+		LatencyMS int64  `json:"latency_ms"`
+		Error     string `json:"error"`
+	} `json:"calls"`
+}
+
+// arquivo JSON, execução do histórico (N, run id, last) ou run id do results store
+func loadRunSnapshot(ref string) (runSnapshot, error) {
+	if b, err := os.ReadFile(ref); err == nil {
+		var d runDoc
+		if err := json.Unmarshal(b, &d); err != nil {
+			return runSnapshot{}, fmt.Errorf(tr("%s não é um resultado JSON: %w"), ref, err)
+		}
+		if len(d.Record) > 0 && string(d.Record) != "null" {
+			var inner runDoc
+			if err := json.Unmarshal(d.Record, &inner); err != nil {
+				return runSnapshot{}, fmt.Errorf("%s: %w", ref, err)
+			}
+			d = inner
+		}
+		return newRunSnapshot(filepath.Base(ref), d), nil
+	}
+	if hp := historyPath(); hp != "" {
+		if db, err := openHistory(hp); err == nil {
+			e, herr := findHistory(db, ref)
+			db.Close()
+			if herr == nil && e.Record != nil {
+				b, _ := json.Marshal(e.Record)
+				var d runDoc
+				_ = json.Unmarshal(b, &d)
+				return newRunSnapshot(fmt.Sprintf("#%d %s", e.N, e.RunID), d), nil
+			}
+		}
+	}
+	if resultsStore != "" {
+		runs, err := readRuns(resultsStore)
+		if err == nil {
+			for i := len(runs) - 1; i >= 0; i-- {
+				if runs[i].ID == ref {
+					b, _ := json.Marshal(runs[i])
+					var d runDoc
+					_ = json.Unmarshal(b, &d)
+					return newRunSnapshot(ref, d), nil
+				}
+			}
+		}
+	}
+	return runSnapshot{}, fmt.Errorf(tr("%s: nem arquivo, nem execução do histórico/results store"), ref)
+}
+
+func newRunSnapshot(label string, d runDoc) runSnapshot {
+	s := runSnapshot{Label: label, Command: d.Command, Obs: map[string]runObs{}}
+	// endpoint repetido (run-all faz dois gets, batch vários registers) ganha #n na ordem
+	total := map[string]int{}
+	for i, c := range d.Calls {
+		if c.Endpoint == "" {
+			d.Calls[i].Endpoint = callEndpoint(c.Method, c.URL)
+		}
+		total[d.Calls[i].Endpoint]++
+	}
+	seen := map[string]int{}
+	lastVerify := ""
+	for _, c := range d.Calls {
+		ep := c.Endpoint
+		key := ep
+		if total[ep] > 1 {
+			seen[ep]++
+			key = fmt.Sprintf("%s#%d", ep, seen[ep])
+		}
+		if ep == "verify" {
+			lastVerify = key
+		}
+		s.add(key, runObs{Status: c.Status, LatencyMS: c.LatencyMS, Error: c.Error})
+	}
+	// verify-card: a similaridade é da última chamada de verify
+	if pct, ok := d.Result["similarity"].(string); ok && lastVerify != "" {
+		if v, ok := parsePercent(pct); ok {
+			o := s.Obs[lastVerify]
+			o.Similarity = &v
+			s.Obs[lastVerify] = o
+		}
+	}
+	// batch-verify: uma linha por imagem
+	if rows, ok := d.Result["results"].([]any); ok {
+		for _, r := range rows {
+			m, ok := r.(map[string]any)
+			if !ok || m["file"] == nil {
+				continue
+			}
+			o := runObs{}
+			if v, ok := m["status"].(float64); ok {
+				o.Status = int(v)
+			}
+			if v, ok := m["latency_ms"].(float64); ok {
+				o.LatencyMS = int64(v)
+			}
+			if v, ok := m["similarity"].(float64); ok && m["has_score"] == true {
+				o.Similarity = &v
+			}
+			o.Error, _ = m["error"].(string)
+			s.add("verify "+filepath.Base(fmt.Sprint(m["file"])), o)
+		}
+	}
+	return s
+}
+
+type runDiffRow struct {
+	Key        string   `json:"key"`
+	A          *runObs  `json:"a,omitempty"`
+	B          *runObs  `json:"b,omitempty"`
+	SimDelta   *float64 `json:"similarity_delta,omitempty"`
+	LatDelta   int64    `json:"latency_delta_ms"`
+	Regression []string `json:"regression,omitempty"` // códigos estáveis (regressionLabels); o texto só na tabela
+}
+
+// código de regressão (JSON, independente de --lang) → rótulo da tabela
+var regressionLabels = map[string]string{
+	"missing_in_b":       "ausente em B",
+	"status":             "status",
+	"similarity":         "similaridade",
+	"no_similarity_in_b": "sem similaridade em B",
+	"latency":            "latência",
+}
+
+func cmdDiffRuns(refA, refB string, opt diffRunsOptions) error {
+	a, err := loadRunSnapshot(refA)
+	if err != nil {
+		return err
+	}
+	b, err := loadRunSnapshot(refB)
+	if err != nil {
+		return err
+	}
+	if a.Command != "" && b.Command != "" && a.Command != b.Command {
+		outf("[diff-runs] ⚠ comandos diferentes: %s × %s\n", a.Command, b.Command)
+	}
+	keys := append([]string(nil), a.Keys...)
+	for _, k := range b.Keys {
+		if _, ok := a.Obs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return errors.New(tr("nenhuma chamada para comparar nas duas execuções"))
+	}
+
+	var rows []runDiffRow
+	regressions := 0
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "[diff-runs] A=%s B=%s\n", a.Label, b.Label)
+	fmt.Fprintf(tw, tr("chamada\tstatus\tsimilaridade\tlatência\t\n"))
+	for _, k := range keys {
+		row := runDiffRow{Key: k}
+		oa, inA := a.Obs[k]
+		ob, inB := b.Obs[k]
+		if inA {
+			row.A = &oa
+		}
+		if inB {
+			row.B = &ob
+		}
+		switch {
+		case !inB:
+			row.Regression = append(row.Regression, "missing_in_b")
+		case !inA:
+			// chamada nova não é regressão por si; o status dela ainda conta
+			if !statusOK(ob.Status) {
+				row.Regression = append(row.Regression, "status")
+			}
+		default:
+			if statusOK(oa.Status) && !statusOK(ob.Status) {
+				row.Regression = append(row.Regression, "status")
+			}
+			if oa.Similarity != nil && ob.Similarity != nil {
+				d := *ob.Similarity - *oa.Similarity
+				row.SimDelta = &d
+				if d < -opt.SimTolerance {
+					row.Regression = append(row.Regression, "similarity")
+				}
+			} else if oa.Similarity != nil {
+				row.Regression = append(row.Regression, "no_similarity_in_b")
+			}
+			row.LatDelta = ob.LatencyMS - oa.LatencyMS
+			if time.Duration(row.LatDelta)*time.Millisecond > opt.LatMin && float64(ob.LatencyMS) > float64(oa.LatencyMS)*(1+opt.LatTolerance) {
+				row.Regression = append(row.Regression, "latency")
+			}
+		}
+		flag := "✓"
+		if len(row.Regression) > 0 {
+			labels := make([]string, len(row.Regression))
+			for i, code := range row.Regression {
+				labels[i] = tr(regressionLabels[code])
+			}
+			flag = "⚠ " + strings.Join(labels, ", ")
+			regressions++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", k, diffStatus(row), diffSimilarity(row), diffLatency(row), flag)
+		rows = append(rows, row)
+	}
+	_ = tw.Flush()
+	setResult("diffs", rows)
+	setResult("regressions", regressions)
+	if regressions > 0 {
+		return fmt.Errorf(tr("%d regressão(ões) de B em relação a A"), regressions)
+	}
+	outln("[diff-runs] sem regressões")
+	return nil
+}
+
+func statusOK(s int) bool { return s >= 200 && s < 300 }
+
+func obsField(o *runObs, f func(runObs) string) string {
+	if o == nil {
+		return "-"
+	}
+	return orDash(f(*o))
+}
+
+func diffStatus(r runDiffRow) string {
+	st := func(o runObs) string {
+		if o.Status == 0 && o.Error != "" {
+			return tr("erro")
+		}
+		return statusText(o.Status)
+	}
+	a, b := obsField(r.A, st), obsField(r.B, st)
+	if a == b {
+		return a
+	}
+	return a + " → " + b
+}
+
+func diffSimilarity(r runDiffRow) string {
+	sim := func(o runObs) string { return simText(o.Similarity) }
+	a, b := obsField(r.A, sim), obsField(r.B, sim)
+	if a == "-" && b == "-" {
+		return "-"
+	}
+	s := a + " → " + b
+	if r.SimDelta != nil {
+		s += fmt.Sprintf(" (%+.2f)", *r.SimDelta)
+	}
+	return s
+}
+
+func diffLatency(r runDiffRow) string {
+	lat := func(o runObs) string { return strconv.FormatInt(o.LatencyMS, 10) + "ms" }
+	s := obsField(r.A, lat) + " → " + obsField(r.B, lat)
+	if r.A != nil && r.B != nil && r.A.LatencyMS > 0 {
+		s += fmt.Sprintf(" (%+.0f%%)", float64(r.LatDelta)*100/float64(r.A.LatencyMS))
+	}
+	return s
+}
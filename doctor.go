@@ -0,0 +1,308 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ==================== doctor (diagnóstico do ambiente) ==================== */
+
+// arquivos .env efetivamente lidos (preenchido no main)
+var envSources []string
+
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // ok, warn, fail, skip
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type doctor struct {
+	checks []doctorCheck
+}
+
+func (d *doctor) add(name, status, detail, fix string) {
+	detail, fix = tr(detail), tr(fix)
+	d.checks = append(d.checks, doctorCheck{Name: name, Status: status, Detail: detail, Fix: fix})
+	mark := map[string]string{"ok": "✅", "warn": "⚠️ ", "fail": "❌", "skip": "➖"}[status]
+	outf("%s %-12s %s\n", mark, tr(name), detail)
+	if fix != "" {
+		outf("   → %s\n", fix)
+	}
+}
+
+func (d *doctor) failed() int {
+	n := 0
+	for _, c := range d.checks {
+		if c.Status == "fail" {
+			n++
+		}
+	}
+	return n
+}
+
+func cmdDoctor(baseURL string, samples int) error {
+	d := &doctor{}
+
+	// configuração
+	var src []string
+	if activeProfile != "" {
+		src = append(src, tr("perfil ")+activeProfile)
+	}
+	src = append(src, envSources...)
+	if len(src) == 0 {
+		d.add("config", "warn", "nenhum .env nem perfil carregado (só variáveis do shell)",
+			"crie um .env no diretório atual, use --env-file ARQ ou --profile NOME (~/.biodoc-runner.yaml)")
+	} else {
+		d.add("config", "ok", strings.Join(src, ", "), "")
+	}
+
+	// BASE_URL
+	u, err := url.Parse(baseURL)
+	switch {
+	case err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https"):
+		d.add("BASE_URL", "fail", fmt.Sprintf(tr("%q não é uma URL http(s)"), baseURL), "ex.: BASE_URL=https://api.develop.biodoc.com.br")
+		return doctorSummary(d)
+	case os.Getenv("BASE_URL") == "":
+		d.add("BASE_URL", "warn", baseURL+tr(" (default; BASE_URL não definido)"), "defina BASE_URL no .env ou no perfil se não for o ambiente de develop")
+	case u.Scheme == "http" && !isLoopbackHost(u.Hostname()):
+		d.add("BASE_URL", "warn", baseURL+tr(" sem TLS"), "use https:// fora da máquina local")
+	default:
+		d.add("BASE_URL", "ok", baseURL, "")
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+	proxied := false
+	if req, err := http.NewRequest(http.MethodGet, baseURL, nil); err == nil {
+		if t, ok := baseTransport.(*http.Transport); ok && t.Proxy != nil {
+			if p, _ := t.Proxy(req); p != nil {
+				proxied = true
+				d.add("proxy", "ok", "via "+redactQuery(p.Redacted()), "")
+			}
+		}
+	}
+
+	// DNS, TCP e TLS direto (com proxy, só o teste HTTP vale)
+	reachable := true
+	if proxied {
+		d.add("DNS/TCP/TLS", "skip", "conexão passa pelo proxy; ver teste HTTP", "")
+	} else {
+		reachable = doctorNetwork(d, host, port, u.Scheme == "https")
+	}
+
+	// token: AUTH_TOKEN (shell, .env, perfil, keyring) ou OAuth
+	loadKeyringSecrets()
+	token := os.Getenv("AUTH_TOKEN")
+	if os.Getenv("OAUTH_TOKEN_URL") != "" {
+		srcO, err := loadOAuthEnv()
+		if err == nil {
+			token, err = srcO.Token()
+		}
+		if err != nil {
+			d.add("OAuth", "fail", err.Error(), "confira OAUTH_TOKEN_URL, OAUTH_CLIENT_ID e o secret (login --oauth guarda no keyring)")
+		} else {
+			d.add("OAuth", "ok", tr("token obtido em ")+os.Getenv("OAUTH_TOKEN_URL"), "")
+		}
+	}
+	doctorToken(d, token)
+
+	// HTTP e latência
+	if reachable {
+		doctorHTTP(d, baseURL, token, samples)
+	}
+	return doctorSummary(d)
+}
+
+func isLoopbackHost(h string) bool {
+	if h == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(h)
+	return ip != nil && ip.IsLoopback()
+}
+
+func doctorNetwork(d *doctor, host, port string, useTLS bool) bool {
+	t0 := time.Now()
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		d.add("DNS", "fail", err.Error(), "confira o host do BASE_URL, VPN e /etc/hosts; atrás de proxy use --proxy")
+		return false
+	}
+	d.add("DNS", "ok", fmt.Sprintf("%s → %s (%s)", host, strings.Join(addrs, ", "), time.Since(t0).Round(time.Millisecond)), "")
+
+	addr := net.JoinHostPort(host, port)
+	t0 = time.Now()
+	conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+	if err != nil {
+		d.add("TCP", "fail", err.Error(), "porta fechada ou bloqueada: firewall, VPN, ou a API está fora do ar")
+		return false
+	}
+	conn.Close()
+	d.add("TCP", "ok", fmt.Sprintf(tr("%s conectou em %s"), addr, time.Since(t0).Round(time.Millisecond)), "")
+
+	if !useTLS {
+		return true
+	}
+	cfg := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+	if t, ok := baseTransport.(*http.Transport); ok && t.TLSClientConfig != nil {
+		cfg = t.TLSClientConfig.Clone()
+		cfg.ServerName = host
+	}
+	tc, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", addr, cfg)
+	if err != nil {
+		fix := tr("cadeia não confiável: passe a CA interna com --ca-cert ARQ.pem (ENV CA_CERT)")
+		var he x509.HostnameError
+		if errors.As(err, &he) {
+			fix = tr("o certificado não cobre esse host: confira o BASE_URL")
+		}
+		d.add("TLS", "fail", err.Error(), fix)
+		return false
+	}
+	defer tc.Close()
+	st := tc.ConnectionState()
+	leaf := st.PeerCertificates[0]
+	left := time.Until(leaf.NotAfter)
+	detail := fmt.Sprintf(tr("%s, emitido por %s, vence em %s (%d dias)"),
+		tls.VersionName(st.Version), leaf.Issuer.CommonName, leaf.NotAfter.Format("2006-01-02"), int(left.Hours()/24))
+	if left < 15*24*time.Hour {
+		d.add("TLS", "warn", detail, "certificado do servidor perto de vencer: avise quem mantém a API")
+	} else {
+		d.add("TLS", "ok", detail, "")
+	}
+	return true
+}
+
+// claims de um JWT sem validar assinatura (só para diagnóstico)
+func decodeJWTClaims(token string) (map[string]any, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+	var claims map[string]any
+	if json.Unmarshal(b, &claims) != nil {
+		return nil, false
+	}
+	return claims, true
+}
+
+func doctorToken(d *doctor, token string) {
+	if token == "" {
+		d.add("token", "fail", "AUTH_TOKEN não definido", "defina AUTH_TOKEN no .env, rode `login`, ou configure OAUTH_TOKEN_URL/OAUTH_CLIENT_ID")
+		return
+	}
+	claims, ok := decodeJWTClaims(token)
+	if !ok {
+		d.add("token", "ok", fmt.Sprintf(tr("presente (%d caracteres, não é JWT: validade não verificável)"), len(token)), "")
+		return
+	}
+	exp, hasExp := claims["exp"].(float64)
+	var who []string
+	for _, k := range []string{"sub", "client_id", "azp", "iss"} {
+		if v, ok := claims[k].(string); ok && v != "" {
+			who = append(who, k+"="+v)
+		}
+	}
+	sort.Strings(who)
+	desc := "JWT " + strings.Join(who, " ")
+	if !hasExp {
+		d.add("token", "ok", desc+tr(" (sem exp)"), "")
+		return
+	}
+	at := time.Unix(int64(exp), 0)
+	left := time.Until(at)
+	switch {
+	case left <= 0:
+		d.add("token", "fail", fmt.Sprintf(tr("%s expirou há %s (%s)"), desc, (-left).Round(time.Second), at.Format(time.RFC3339)),
+			"gere um token novo (login) ou use OAuth para renovação automática")
+	case left < 5*time.Minute:
+		d.add("token", "warn", fmt.Sprintf(tr("%s expira em %s"), desc, left.Round(time.Second)), "execuções longas vão tomar 401: renove antes ou use OAuth")
+	default:
+		d.add("token", "ok", fmt.Sprintf(tr("%s válido até %s (%s)"), desc, at.Format(time.RFC3339), left.Round(time.Minute)), "")
+	}
+}
+
+// GET num card inexistente: 404 é a resposta saudável; mede a latência em algumas amostras
+func doctorHTTP(d *doctor, baseURL, token string, samples int) {
+	samples = max(samples, 1)
+	var lats []time.Duration
+	var last *http.Response
+	var lastErr error
+	for i := 0; i < samples; i++ {
+		req, err := http.NewRequest(http.MethodGet, cardURL(baseURL, "doctor-probe"), nil)
+		if err != nil {
+			lastErr = err
+			break
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		t0 := time.Now()
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			lastErr = err
+			break
+		}
+		resp.Body.Close()
+		lats = append(lats, time.Since(t0))
+		last = resp
+	}
+	if last == nil {
+		d.add("HTTP", "fail", lastErr.Error(), "a API não respondeu: confira BASE_URL, proxy e se o serviço está no ar")
+		return
+	}
+	switch {
+	case last.StatusCode == http.StatusUnauthorized || last.StatusCode == http.StatusForbidden:
+		d.add("HTTP", "fail", fmt.Sprintf(tr("GET /api/card/… → %d: token recusado"), last.StatusCode),
+			"token de outro ambiente, expirado ou sem escopo: confira AUTH_TOKEN/perfil contra o BASE_URL")
+	case last.StatusCode >= 500:
+		d.add("HTTP", "fail", fmt.Sprintf("GET /api/card/… → %d", last.StatusCode), "a API está com erro interno: tente mais tarde ou avise o time da API")
+	default:
+		d.add("HTTP", "ok", fmt.Sprintf(tr("GET /api/card/… → %d (API respondeu, token aceito)"), last.StatusCode), "")
+	}
+
+	if date, err := http.ParseTime(last.Header.Get("Date")); err == nil {
+		if skew := time.Since(date); skew > time.Minute || skew < -time.Minute {
+			d.add("relógio", "warn", fmt.Sprintf(tr("diferença de %s para o servidor"), skew.Round(time.Second)),
+				"sincronize o relógio (NTP): JWT e assinaturas dependem da hora")
+		}
+	}
+
+	sort.Slice(lats, func(i, j int) bool { return lats[i] < lats[j] })
+	med := lats[len(lats)/2]
+	detail := fmt.Sprintf(tr("%d amostra(s): min %s, mediana %s, max %s"), len(lats),
+		lats[0].Round(time.Millisecond), med.Round(time.Millisecond), lats[len(lats)-1].Round(time.Millisecond))
+	if med > time.Second {
+		d.add("latência", "warn", detail, "acima de 1s: VPN/proxy lentos ou API sobrecarregada; budgets e SLOs vão estourar")
+	} else {
+		d.add("latência", "ok", detail, "")
+	}
+}
+
+func doctorSummary(d *doctor) error {
+	setResult("checks", d.checks)
+	n := d.failed()
+	outln()
+	if n == 0 {
+		outln("ambiente ok")
+		return nil
+	}
+	return fmt.Errorf(tr("%d verificação(ões) falharam; veja as correções sugeridas acima"), n)
+}
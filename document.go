@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+/* ==================== verify-document (OCR do documento) ==================== */
+
+// POST /api/card/integration/document com a foto do documento (RG, CNH...); a API devolve os
+// campos lidos. --expect-name/--expect-id conferem o que saiu do OCR: nome sem acento/caixa,
+// número só com letras e dígitos ("123.456.789-09" = "12345678909")
+type documentOptions struct {
+	Endpoint   string
+	Image      string
+	Encoding   string
+	ExpectName string
+	ExpectID   string
+	NameField  string // caminho na resposta; vazio = tenta os nomes conhecidos
+	IDField    string
+}
+
+type documentCheck struct {
+	Field     string `json:"field"`
+	Expected  string `json:"expected"`
+	Extracted string `json:"extracted"`
+	OK        bool   `json:"ok"`
+}
+
+// nomes vistos nas versões da API, na ordem em que são tentados
+var (
+	documentNameFields = []string{"response.fields.name", "response.name", "fields.name", "name", "response.fields.nome", "nome"}
+	documentIDFields   = []string{"response.fields.documentNumber", "response.documentNumber", "fields.documentNumber", "documentNumber",
+		"response.fields.number", "number", "response.fields.cpf", "cpf", "response.fields.rg", "rg"}
+)
+
+func documentURL(baseURL, endpointPath string) string {
+	if endpointPath == "" {
+		endpointPath = "/api/card/integration/document"
+	}
+	return strings.TrimRight(baseURL, "/") + endpointPath
+}
+
+func verifyDocument(baseURL, token string, opt documentOptions) (*http.Response, []byte, error) {
+	ct, body, err := encodeImagePayload(map[string]any{}, opt.Image, opt.Encoding)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("ler/encode imagem: %w"), err)
+	}
+	h := authHeader(token)
+	h.Set("Content-Type", ct)
+	return doRequestBody(http.MethodPost, documentURL(baseURL, opt.Endpoint), h, body)
+}
+
+// primeiro campo de texto (ou número) presente; devolve também o caminho achado
+func documentField(doc any, override string, candidates []string) (string, string) {
+	if override != "" {
+		candidates = []string{override}
+	}
+	for _, p := range candidates {
+		v, ok := lookupPath(doc, p)
+		if !ok || v == nil {
+			continue
+		}
+		switch x := v.(type) {
+		case string:
+			return x, p
+		case float64:
+			return fmt.Sprint(int64(x)), p
+		}
+	}
+	return "", ""
+}
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// "  JOSÉ  da Silva" = "jose da silva"
+func foldName(s string) string {
+	return strings.Join(strings.Fields(accentFolder.Replace(strings.ToLower(s))), " ")
+}
+
+// só letras e dígitos, em maiúsculas (pontuação do OCR não conta)
+func foldDocNumber(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+func cmdVerifyDocument(baseURL, token string, opt documentOptions) error {
+	if opt.Image == "" {
+		return usageError(tr("--image vazio: informe a foto do documento (ou DOCUMENT_IMAGE)"))
+	}
+	outf("[document] POST %s (%s)\n", documentURL(baseURL, opt.Endpoint), opt.Image)
+	resp, raw, err := verifyDocument(baseURL, token, opt)
+	if err != nil {
+		return err
+	}
+	outf("status=%d\n", resp.StatusCode)
+	if !quiet {
+		outln(string(raw))
+	}
+	if err := checkStatus(resp, raw); err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf(tr("resposta inválida, não dá para ler os campos do documento: %w"), err)
+	}
+	name, namePath := documentField(doc, opt.NameField, documentNameFields)
+	number, idPath := documentField(doc, opt.IDField, documentIDFields)
+	outf("[document] nome=%q (%s) | número=%q (%s)\n", name, orDash(namePath), number, orDash(idPath))
+
+	var checks []documentCheck
+	if opt.ExpectName != "" {
+		checks = append(checks, documentCheck{Field: "name", Expected: opt.ExpectName, Extracted: name,
+			OK: name != "" && foldName(name) == foldName(opt.ExpectName)})
+	}
+	if opt.ExpectID != "" {
+		checks = append(checks, documentCheck{Field: "documentNumber", Expected: opt.ExpectID, Extracted: number,
+			OK: number != "" && foldDocNumber(number) == foldDocNumber(opt.ExpectID)})
+	}
+	var failed []string
+	for _, c := range checks {
+		mark := "✅"
+		if !c.OK {
+			mark = "❌"
+			failed = append(failed, fmt.Sprintf(tr("%s: esperado %q, extraído %q"), c.Field, c.Expected, c.Extracted))
+		}
+		outf("[document] %s %s: esperado %q, extraído %q\n", mark, c.Field, c.Expected, c.Extracted)
+	}
+	setResult("document", map[string]any{"name": name, "documentNumber": number, "checks": checks})
+	if len(failed) > 0 {
+		return matchError(tr("documento não confere: ") + strings.Join(failed, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+/* ==================== --dry-run (monta e imprime, não envia) ==================== */
+
+var (
+	dryRun      bool
+	dryRunCount atomic.Int64
+)
+
+// comandos com --dry-run próprio (reap lista sem apagar, history rerun só mostra o comando)
+var ownDryRunCommands = map[string]bool{"reap": true, "history": true, "delete-cards": true}
+
+// primeiro argumento que não é flag (o comando, depois das globais já retiradas)
+func firstNonFlag(args []string) string {
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			return a
+		}
+	}
+	return ""
+}
+
+// o que sendOnce devolve no lugar da resposta: 200 com {} para o fluxo seguir até o fim
+func dryRunResponse(req *http.Request, body reqBody) (*http.Response, []byte) {
+	dryRunCount.Add(1)
+	printDryRun(req, body)
+	resp := &http.Response{
+		Status:     "200 OK (dry-run)",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": {"application/json"}, "X-Dry-Run": {"1"}},
+		Body:       http.NoBody,
+		Request:    req,
+	}
+	return resp, []byte("{}")
+}
+
+func printDryRun(req *http.Request, body reqBody) {
+	outf("[dry-run] %s %s\n", req.Method, redactQuery(req.URL.String()))
+	h := req.Header.Clone()
+	redactHeaders(h, nil)
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		outf("[dry-run]   %s: %s\n", k, strings.Join(h[k], ", "))
+	}
+	b := body.dumpable()
+	if len(b) == 0 {
+		return
+	}
+	outf("[dry-run]   corpo (%d bytes):\n", len(b))
+	if mt, params, _ := mime.ParseMediaType(h.Get("Content-Type")); mt == "multipart/form-data" {
+		outln(describeMultipart(b, params["boundary"], body.stream != nil))
+		return
+	}
+	outln(abbreviateBody(b, false))
+}
+
+// uma linha por parte; arquivo vira nome, tipo e tamanho (em stream, o resumo do dumpable)
+func describeMultipart(b []byte, boundary string, streamed bool) string {
+	mr := multipart.NewReader(bytes.NewReader(b), boundary)
+	var out []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			out = append(out, fmt.Sprintf(tr("  <multipart ilegível: %v>"), err))
+			break
+		}
+		data, _ := io.ReadAll(p)
+		if p.FileName() != "" && streamed {
+			out = append(out, fmt.Sprintf("  %s: %s (%s)", p.FormName(), data, p.Header.Get("Content-Type")))
+			continue
+		}
+		if p.FileName() != "" {
+			out = append(out, fmt.Sprintf(tr("  %s: <arquivo %s, %s, %d bytes>"), p.FormName(), p.FileName(), p.Header.Get("Content-Type"), len(data)))
+			continue
+		}
+		v := string(data)
+		if sensitiveKeys[strings.ToLower(p.FormName())] {
+			v = redacted
+		}
+		out = append(out, fmt.Sprintf("  %s: %s", p.FormName(), v))
+	}
+	return strings.Join(out, "\n")
+}
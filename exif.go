@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	stddraw "image/draw"
+	"strings"
+)
+
+/* ==================== EXIF (leitura mínima de JPEG) ==================== */
+
+// campos do EXIF que interessam à análise de score
+type exifInfo struct {
+	Make        string
+	Model       string
+	DateTime    string // DateTimeOriginal, ou DateTime do IFD0
+	Orientation int    // 1..8 (0 = ausente)
+	HasGPS      bool
+}
+
+const (
+	exifTagMake        = 0x010F
+	exifTagModel       = 0x0110
+	exifTagOrientation = 0x0112
+	exifTagDateTime    = 0x0132
+	exifTagExifIFD     = 0x8769
+	exifTagGPSIFD      = 0x8825
+	exifTagDateTimeOrg = 0x9003
+)
+
+// segmento APP1 "Exif\0\0" de um JPEG: devolve o bloco TIFF e a posição do segmento no arquivo
+func jpegExifSegment(b []byte) (tiff []byte, start, end int, ok bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != 0xD8 {
+		return nil, 0, 0, false
+	}
+	i := 2
+	for i+4 <= len(b) && b[i] == 0xFF {
+		marker := b[i+1]
+		if marker == 0xDA || marker == 0xD9 { // início dos dados da imagem / fim
+			break
+		}
+		segLen := int(binary.BigEndian.Uint16(b[i+2:]))
+		if segLen < 2 || i+2+segLen > len(b) {
+			break
+		}
+		seg := b[i+4 : i+2+segLen]
+		if marker == 0xE1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+			return seg[6:], i, i + 2 + segLen, true
+		}
+		i += 2 + segLen
+	}
+	return nil, 0, 0, false
+}
+
+func parseJPEGExif(b []byte) (exifInfo, bool) {
+	tiff, _, _, ok := jpegExifSegment(b)
+	if !ok {
+		return exifInfo{}, false
+	}
+	return parseTIFFExif(tiff)
+}
+
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	raw   []byte // 4 bytes do campo valor/offset
+}
+
+func parseTIFFExif(t []byte) (exifInfo, bool) {
+	var info exifInfo
+	if len(t) < 8 {
+		return info, false
+	}
+	var bo binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return info, false
+	}
+	if bo.Uint16(t[2:]) != 42 {
+		return info, false
+	}
+	readIFD := func(off uint32) map[uint16]tiffEntry {
+		if int(off)+2 > len(t) {
+			return nil
+		}
+		n := int(bo.Uint16(t[off:]))
+		m := make(map[uint16]tiffEntry, n)
+		for k := 0; k < n; k++ {
+			p := int(off) + 2 + k*12
+			if p+12 > len(t) {
+				break
+			}
+			m[bo.Uint16(t[p:])] = tiffEntry{typ: bo.Uint16(t[p+2:]), count: bo.Uint32(t[p+4:]), raw: t[p+8 : p+12]}
+		}
+		return m
+	}
+	ascii := func(e tiffEntry) string {
+		if e.typ != 2 || e.count == 0 {
+			return ""
+		}
+		var s []byte
+		if e.count <= 4 {
+			s = e.raw[:e.count]
+		} else {
+			off := bo.Uint32(e.raw)
+			if uint64(off)+uint64(e.count) > uint64(len(t)) {
+				return ""
+			}
+			s = t[off : off+e.count]
+		}
+		return strings.TrimSpace(strings.TrimRight(string(s), "\x00"))
+	}
+
+	ifd0 := readIFD(bo.Uint32(t[4:]))
+	if ifd0 == nil {
+		return info, false
+	}
+	info.Make = ascii(ifd0[exifTagMake])
+	info.Model = ascii(ifd0[exifTagModel])
+	info.DateTime = ascii(ifd0[exifTagDateTime])
+	if e, ok := ifd0[exifTagOrientation]; ok && e.typ == 3 {
+		info.Orientation = int(bo.Uint16(e.raw))
+	}
+	_, info.HasGPS = ifd0[exifTagGPSIFD]
+	if e, ok := ifd0[exifTagExifIFD]; ok {
+		if sub := readIFD(bo.Uint32(e.raw)); sub != nil {
+			if d := ascii(sub[exifTagDateTimeOrg]); d != "" {
+				info.DateTime = d
+			}
+		}
+	}
+	return info, true
+}
+
+// aplica a rotação/espelhamento da tag Orientation (2..8) nos pixels
+func applyOrientation(img image.Image, orient int) image.Image {
+	if orient < 2 || orient > 8 {
+		return img
+	}
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	stddraw.Draw(src, src.Bounds(), img, b.Min, stddraw.Src)
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orient >= 5 { // 5..8 trocam largura e altura
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orient {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):][:4], src.Pix[src.PixOffset(x, y):][:4])
+		}
+	}
+	return dst
+}
+
+// remove EXIF/XMP (APP1) e IPTC (APP13) sem reencodar; ICC (APP2) e Adobe (APP14) ficam
+func stripJPEGMetadata(b []byte) []byte {
+	if len(b) < 4 || b[0] != 0xFF || b[1] != 0xD8 {
+		return b
+	}
+	out := append(make([]byte, 0, len(b)), b[:2]...)
+	i := 2
+	for i+4 <= len(b) && b[i] == 0xFF {
+		marker := b[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		segLen := int(binary.BigEndian.Uint16(b[i+2:]))
+		if segLen < 2 || i+2+segLen > len(b) {
+			break
+		}
+		if marker != 0xE1 && marker != 0xED {
+			out = append(out, b[i:i+2+segLen]...)
+		}
+		i += 2 + segLen
+	}
+	return append(out, b[i:]...)
+}
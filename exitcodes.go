@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+/* ==================== Exit codes ==================== */
+
+// um código por classe de falha, para o CI distinguir sem ler a saída (documentados no usage)
+const (
+	exitOK       = 0
+	exitFailure  = 1 // demais falhas: expectativa do cenário, --expect, golden, arquivo local...
+	exitUsage    = 2 // flag inválida ou faltando, argumento solto
+	exitAuth     = 3 // 401/403, token ausente ou OAuth sem token
+	exitNotFound = 4 // 404 (card inexistente)
+	exitNoMatch  = 5 // verify sem match ou similaridade abaixo do mínimo
+	exitNetwork  = 6 // sem resposta: DNS, conexão recusada, TLS, timeout
+	exitServer   = 7 // 5xx mesmo depois das retentativas
+)
+
+// a API respondeu, mas a face não bateu (ou ficou abaixo do --min-similarity) → exit 5
+type matchError string
+
+func (e matchError) Error() string { return string(e) }
+
+// só erros tipados definem a classe; erro comum (expectativa do cenário, arquivo...) é exit 1,
+// mesmo que alguma chamada anterior da execução tenha dado 404 esperado
+func exitCodeFor(err error) int {
+	switch failureCategory(err) {
+	case "":
+		return exitOK
+	case "usage":
+		return exitUsage
+	case "auth":
+		return exitAuth
+	case "no_match":
+		return exitNoMatch
+	case "network", "timeout":
+		return exitNetwork
+	case "http_5xx":
+		return exitServer
+	case "http_4xx":
+		var ae *APIError
+		if errors.As(err, &ae) && ae.StatusCode == http.StatusNotFound {
+			return exitNotFound
+		}
+	}
+	return exitFailure
+}
+
+func isMatchError(err error) bool {
+	var me matchError
+	return errors.As(err, &me)
+}
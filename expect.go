@@ -0,0 +1,218 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/* ==================== Asserções na resposta (--expect-status, --expect) ==================== */
+
+// qualquer comando vira teste: confere o status e campos do JSON da última resposta
+type expectation struct {
+	Path string
+	Op   string // ==, !=, >=, <=, >, <
+	Want any    // literal JSON (true, 200, "x", null) ou o texto cru
+	raw  string
+}
+
+type expectResult struct {
+	Expr  string `json:"expr"`
+	Got   any    `json:"got"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// asserção que não bateu → exit 1, como a expectativa de cenário
+type expectError string
+
+func (e expectError) Error() string { return string(e) }
+
+func isExpectError(err error) bool {
+	var ee expectError
+	return errors.As(err, &ee)
+}
+
+var expectOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// "response.success==true", ".response.percentage >= 90"
+func parseExpectation(s string) (expectation, error) {
+	for _, op := range expectOps {
+		path, lit, ok := strings.Cut(s, op)
+		if !ok {
+			continue
+		}
+		path, lit = strings.TrimSpace(path), strings.TrimSpace(lit)
+		if path == "" || lit == "" {
+			break
+		}
+		e := expectation{Path: path, Op: op, Want: lit, raw: s}
+		var v any
+		if json.Unmarshal([]byte(lit), &v) == nil {
+			e.Want = v
+		}
+		return e, nil
+	}
+	return expectation{}, usageError(fmt.Sprintf(tr("--expect inválido %q (use caminho==valor; operadores %s)"), s, strings.Join(expectOps, " ")))
+}
+
+var pathIndexRe = regexp.MustCompile(`\[(\d+)\]`)
+
+// caminho no estilo jq (.response.percentage, .items[0].id) ou com pontos (response.percentage)
+func lookupPath(doc any, path string) (any, bool) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), ".")
+	if path == "" {
+		return doc, true
+	}
+	path = pathIndexRe.ReplaceAllString(path, ".$1")
+	return jsonPath(doc, strings.TrimPrefix(path, "."))
+}
+
+// número de um valor do JSON; similaridade em texto ("98,5%") também conta
+func expectNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case string:
+		return parsePercent(x)
+	}
+	return 0, false
+}
+
+func (e expectation) eval(doc any) expectResult {
+	r := expectResult{Expr: e.raw}
+	got, ok := lookupPath(doc, e.Path)
+	if !ok {
+		r.Error = fmt.Sprintf(tr("campo %s ausente"), e.Path)
+		return r
+	}
+	r.Got = got
+	gn, gok := expectNumber(got)
+	wn, wok := expectNumber(e.Want)
+	switch e.Op {
+	case "==", "!=":
+		eq := fmt.Sprint(got) == fmt.Sprint(e.Want)
+		if gok && wok {
+			eq = gn == wn
+		}
+		r.OK = eq == (e.Op == "==")
+	default:
+		if !gok || !wok {
+			r.Error = fmt.Sprintf(tr("%s %s exige números, veio %v"), e.Path, e.Op, got)
+			return r
+		}
+		switch e.Op {
+		case ">=":
+			r.OK = gn >= wn
+		case "<=":
+			r.OK = gn <= wn
+		case ">":
+			r.OK = gn > wn
+		case "<":
+			r.OK = gn < wn
+		}
+	}
+	return r
+}
+
+// última chamada HTTP da execução
+func lastCall() (callRecord, bool) {
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	if len(calls) == 0 {
+		return callRecord{}, false
+	}
+	return calls[len(calls)-1], true
+}
+
+// avalia no fim da execução sobre a última resposta. Com --expect-status, o status esperado
+// vence o erro HTTP do comando (delete-card --expect-status 404 passa)
+func checkExpectations(status int, exps []expectation, runErr error) error {
+	c, ok := lastCall()
+	if !ok || c.Status == 0 {
+		if runErr != nil {
+			return runErr
+		}
+		return expectError(tr("--expect: nenhuma resposta HTTP para conferir"))
+	}
+	var fails []string
+	if status != 0 {
+		mark := "✅"
+		if c.Status != status {
+			mark = "❌"
+			fails = append(fails, fmt.Sprintf(tr("status %d, esperado %d"), c.Status, status))
+		}
+		outf("[expect] %s status==%d (veio %d)\n", mark, status, c.Status)
+		var ae *APIError
+		if errors.As(runErr, &ae) && ae.StatusCode == c.Status {
+			runErr = nil
+		}
+	}
+	if runErr != nil {
+		return runErr
+	}
+	var doc any
+	if len(exps) > 0 {
+		if err := json.Unmarshal(c.Response, &doc); err != nil {
+			return expectError(tr("--expect: resposta não é JSON"))
+		}
+	}
+	var res []expectResult
+	for _, e := range exps {
+		r := e.eval(doc)
+		res = append(res, r)
+		if r.OK {
+			outf("[expect] ✅ %s\n", e.raw)
+			continue
+		}
+		why := r.Error
+		if why == "" {
+			why = fmt.Sprintf(tr("valor %v"), compactJSON(r.Got))
+		}
+		outf("[expect] ❌ %s (%s)\n", e.raw, why)
+		fails = append(fails, e.raw+": "+why)
+	}
+	setResult("expectations", res)
+	if len(fails) > 0 {
+		return expectError(tr("asserção falhou: ") + strings.Join(fails, "; "))
+	}
+	return nil
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// --expect-status N e --expect EXPR (repetível), em qualquer posição
+func stripExpectFlags(args []string) ([]string, int, []expectation, error) {
+	args, st, set, err := stripValueFlag(args, "--expect-status")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	status := 0
+	if set {
+		if status, err = strconv.Atoi(st); err != nil || status < 100 || status > 599 {
+			return nil, 0, nil, usageError(fmt.Sprintf(tr("--expect-status inválido: %q"), st))
+		}
+	}
+	args, raw, err := stripValueFlags(args, "--expect")
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	var exps []expectation
+	for _, s := range raw {
+		e, err := parseExpectation(s)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		exps = append(exps, e)
+	}
+	return args, status, exps, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+/* ==================== Extração de campos (--jq, --extract) ==================== */
+
+// --jq '.response.id_Log': só o valor vai para o stdout (texto puro, como jq -r; o resto em JSON
+// compacto); a saída normal do comando passa para o stderr. Repetível: um valor por linha
+func stripExtractFlags(args []string) ([]string, []string, error) {
+	args, paths, err := stripValueFlags(args, "--jq")
+	if err != nil {
+		return nil, nil, err
+	}
+	args, more, err := stripValueFlags(args, "--extract")
+	if err != nil {
+		return nil, nil, err
+	}
+	return args, append(paths, more...), nil
+}
+
+// campo ausente é erro: o script não deve seguir com um id vazio
+func printExtracted(paths []string) error {
+	c, ok := lastCall()
+	if !ok || len(c.Response) == 0 {
+		return fmt.Errorf(tr("--jq: nenhuma resposta para extrair"))
+	}
+	var doc any
+	if err := json.Unmarshal(c.Response, &doc); err != nil {
+		return fmt.Errorf(tr("--jq: resposta não é JSON"))
+	}
+	var values []any
+	for _, p := range paths {
+		v, ok := lookupPath(doc, p)
+		if !ok {
+			return fmt.Errorf(tr("--jq: campo %s ausente na resposta"), p)
+		}
+		values = append(values, v)
+	}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			fmt.Fprintln(os.Stdout, s)
+			continue
+		}
+		fmt.Fprintln(os.Stdout, compactJSON(v))
+	}
+	return nil
+}
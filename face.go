@@ -0,0 +1,103 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"image"
+	"sync"
+
+	pigo "github.com/esimov/pigo/core"
+	"golang.org/x/image/draw"
+)
+
+/* ==================== --require-single-face (detector local, pigo) ==================== */
+
+// cascata de rosto frontal do pigo (MIT, ver cascade/LICENSE.pigo)
+//
+//go:embed cascade/facefinder
+var facefinderCascade []byte
+
+// rejeita antes do envio imagem sem rosto ou com mais de um
+var requireSingleFace bool
+
+// score mínimo de uma detecção (o mesmo do CLI do pigo)
+const faceMinQ = 5.0
+
+var (
+	faceClassifier     *pigo.Pigo
+	faceClassifierErr  error
+	faceClassifierOnce sync.Once
+)
+
+type faceBox struct {
+	X, Y, Size int
+	Q          float32
+}
+
+// rostos frontais, em coordenadas da imagem original
+func detectFaces(img image.Image) ([]faceBox, error) {
+	faceClassifierOnce.Do(func() {
+		faceClassifier, faceClassifierErr = pigo.NewPigo().Unpack(facefinderCascade)
+	})
+	if faceClassifierErr != nil {
+		return nil, fmt.Errorf(tr("cascata de rosto: %w"), faceClassifierErr)
+	}
+	// reduz a 640px no maior lado: o detector é sensível a escala, não a resolução
+	b := img.Bounds()
+	scale := 1.0
+	w, h := b.Dx(), b.Dy()
+	if long := max(w, h); long > 640 {
+		scale = float64(long) / 640
+		w, h = max(1, int(float64(w)/scale)), max(1, int(float64(h)/scale))
+	}
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(g, g.Bounds(), img, b, draw.Src, nil)
+
+	cp := pigo.CascadeParams{
+		MinSize:     max(20, min(w, h)/10),
+		MaxSize:     max(w, h),
+		ShiftFactor: 0.1,
+		ScaleFactor: 1.1,
+		ImageParams: pigo.ImageParams{Pixels: g.Pix, Rows: h, Cols: w, Dim: g.Stride},
+	}
+	dets := faceClassifier.ClusterDetections(faceClassifier.RunCascade(cp, 0), 0.2)
+	var out []faceBox
+	for _, d := range dets {
+		if d.Q < faceMinQ {
+			continue
+		}
+		out = append(out, faceBox{
+			X:    int(float64(d.Col) * scale),
+			Y:    int(float64(d.Row) * scale),
+			Size: int(float64(d.Scale) * scale),
+			Q:    d.Q,
+		})
+	}
+	return out, nil
+}
+
+// --require-single-face: zero ou vários rostos viram erro antes da chamada
+func checkSingleFace(path string, img image.Image, head []byte) error {
+	if !requireSingleFace {
+		return nil
+	}
+	// foto em retrato deitada: o detector só acha rosto de pé
+	if info, ok := parseJPEGExif(head); ok && !imgPrep.NoExifFix {
+		img = applyOrientation(img, info.Orientation)
+	}
+	faces, err := detectFaces(img)
+	if err != nil {
+		return err
+	}
+	switch len(faces) {
+	case 1:
+		if verbosity > 0 {
+			f := faces[0]
+			outf("[face] %s: 1 rosto em (%d,%d) %dpx, q=%.1f\n", path, f.X, f.Y, f.Size, f.Q)
+		}
+		return nil
+	case 0:
+		return fmt.Errorf(tr("%s: nenhum rosto encontrado (--require-single-face)"), path)
+	}
+	return fmt.Errorf(tr("%s: %d rostos encontrados, esperado 1 (--require-single-face)"), path, len(faces))
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* ==================== fixtures dedupe ==================== */
+
+type fixtureHash struct {
+	File  string
+	SHA   [32]byte
+	DHash uint64
+	Err   error
+}
+
+// grupo de imagens quase iguais (distância de Hamming do dHash ≤ limite)
+type dupGroup struct {
+	Files       []string `json:"files"`
+	MaxDistance int      `json:"max_distance"`
+	Exact       bool     `json:"exact"`     // todos os arquivos com bytes idênticos
+	CrossSet    bool     `json:"cross_set"` // arquivos em subpastas diferentes (ex.: genuine × impostor)
+}
+
+func hashFixtures(files []string) []fixtureHash {
+	out := make([]fixtureHash, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				h := fixtureHash{File: files[i]}
+				b, err := os.ReadFile(files[i])
+				if err == nil {
+					h.SHA = sha256.Sum256(b)
+					img, _, derr := decodeImage(b)
+					if derr == nil {
+						h.DHash = dHash(img)
+					}
+					err = derr
+				}
+				h.Err = err
+				out[i] = h
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return out
+}
+
+// agrupa por união-busca: a~b e b~c caem no mesmo grupo
+func groupNearDuplicates(hs []fixtureHash, root string, maxDist int) []dupGroup {
+	parent := make([]int, len(hs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range hs {
+		if hs[i].Err != nil {
+			continue
+		}
+		for j := i + 1; j < len(hs); j++ {
+			if hs[j].Err != nil {
+				continue
+			}
+			if hs[i].SHA == hs[j].SHA || hammingDistance(hs[i].DHash, hs[j].DHash) <= maxDist {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+	members := map[int][]int{}
+	for i := range hs {
+		if hs[i].Err == nil {
+			members[find(i)] = append(members[find(i)], i)
+		}
+	}
+	var groups []dupGroup
+	for _, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		g := dupGroup{Exact: true}
+		sets := map[string]bool{}
+		for a, i := range idx {
+			g.Files = append(g.Files, hs[i].File)
+			sets[fixtureSet(root, hs[i].File)] = true
+			for _, j := range idx[a+1:] {
+				g.MaxDistance = max(g.MaxDistance, hammingDistance(hs[i].DHash, hs[j].DHash))
+				g.Exact = g.Exact && hs[i].SHA == hs[j].SHA
+			}
+		}
+		g.CrossSet = len(sets) > 1
+		sort.Strings(g.Files)
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Files[0] < groups[j].Files[0] })
+	return groups
+}
+
+// primeira pasta abaixo da raiz (genuine/, impostor/...); "." se o arquivo está na raiz
+func fixtureSet(root, file string) string {
+	rel, err := filepath.Rel(root, file)
+	if err != nil {
+		return filepath.Dir(file)
+	}
+	if i := strings.IndexAny(rel, `/\`); i >= 0 {
+		return rel[:i]
+	}
+	return "."
+}
+
+func cmdFixturesDedupe(src string, maxDist int) error {
+	if maxDist < 0 || maxDist > 64 {
+		return usageError(tr("--max-distance deve estar entre 0 e 64"))
+	}
+	files, err := collectImages(src)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf(tr("nenhuma imagem em %s"), src)
+	}
+	outf("[dedupe] %d imagem(ns) em %s, distância máxima %d/64\n", len(files), src, maxDist)
+	hs := hashFixtures(files)
+	var unreadable []string
+	for _, h := range hs {
+		if h.Err != nil {
+			outf("[dedupe] ignorada %s: %v\n", h.File, h.Err)
+			unreadable = append(unreadable, h.File)
+		}
+	}
+	root := src
+	if strings.ContainsAny(src, "*?[") {
+		root = filepath.Dir(src)
+	}
+	groups := groupNearDuplicates(hs, root, maxDist)
+	cross := 0
+	for i, g := range groups {
+		kind := fmt.Sprintf(tr("quase iguais (distância ≤ %d)"), g.MaxDistance)
+		if g.Exact {
+			kind = tr("idênticas")
+		}
+		mark := ""
+		if g.CrossSet {
+			mark = tr(" ⚠ em conjuntos diferentes")
+			cross++
+		}
+		outf("[dedupe] grupo %d: %d imagens %s%s\n", i+1, len(g.Files), kind, mark)
+		for _, f := range g.Files {
+			outf("    %s\n", f)
+		}
+	}
+	outf("[dedupe] %d grupo(s) de duplicatas, %d atravessando conjuntos\n", len(groups), cross)
+	setResult("groups", groups)
+	setResult("unreadable", unreadable)
+	if len(groups) > 0 {
+		return fmt.Errorf(tr("%d grupo(s) de imagens duplicadas no pool de fixtures"), len(groups))
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/* ==================== gen-data (massa sintética para smoke) ==================== */
+
+type genDataOptions struct {
+	Count       int
+	Out         string // manifesto .csv ou .json; vazio = CSV no stdout
+	Images      string // pool (pasta/glob); vazio = sem imagem (só dá para criar com --create se houver pool)
+	IDFormat    string // cpf ou cns
+	ConsentRate float64
+	Seed        uint64
+}
+
+var (
+	genFirstNames = []string{
+		"Ana", "Beatriz", "Bruno", "Camila", "Carlos", "Daniela", "Eduardo", "Fernanda", "Gabriel", "Helena",
+		"Igor", "Juliana", "João", "Larissa", "Lucas", "Mariana", "Mateus", "Natália", "Paulo", "Rafaela",
+		"Ricardo", "Sofia", "Thiago", "Vanessa", "Vitor",
+	}
+	genSurnames = []string{
+		"Almeida", "Alves", "Barbosa", "Cardoso", "Costa", "Dias", "Ferreira", "Gomes", "Lima", "Martins",
+		"Melo", "Oliveira", "Pereira", "Ribeiro", "Rocha", "Santos", "Silva", "Souza", "Teixeira", "Vieira",
+	}
+)
+
+// CPF com dígitos verificadores válidos (nunca todos iguais)
+func genCPF(rng *rand.Rand) string {
+	d := make([]int, 11)
+	for {
+		same := true
+		for i := 0; i < 9; i++ {
+			d[i] = rng.IntN(10)
+			same = same && d[i] == d[0]
+		}
+		if !same {
+			break
+		}
+	}
+	for k := 9; k <= 10; k++ {
+		sum := 0
+		for i := 0; i < k; i++ {
+			sum += d[i] * (k + 1 - i)
+		}
+		r := sum * 10 % 11
+		if r == 10 {
+			r = 0
+		}
+		d[k] = r
+	}
+	var b strings.Builder
+	for _, v := range d {
+		b.WriteByte(byte('0' + v))
+	}
+	return b.String()
+}
+
+// CNS definitivo (começa com 1 ou 2), 15 dígitos que passam no módulo 11
+func genCNS(rng *rand.Rand) string {
+	pis := strconv.Itoa(1 + rng.IntN(2))
+	for i := 0; i < 10; i++ {
+		pis += strconv.Itoa(rng.IntN(10))
+	}
+	sum := 0
+	for i, c := range pis {
+		sum += int(c-'0') * (15 - i)
+	}
+	dv := 11 - sum%11
+	if dv == 11 {
+		dv = 0
+	}
+	if dv == 10 {
+		sum += 2
+		dv = 11 - sum%11
+		return pis + "001" + strconv.Itoa(dv)
+	}
+	return pis + "000" + strconv.Itoa(dv)
+}
+
+// gera as linhas; ids não se repetem dentro da mesma massa
+func genManifest(opt genDataOptions) ([]manifestRow, error) {
+	if opt.Count < 1 {
+		return nil, usageError(tr("--count deve ser ≥ 1"))
+	}
+	var gen func(*rand.Rand) string
+	switch opt.IDFormat {
+	case "cpf":
+		gen = genCPF
+	case "cns":
+		gen = genCNS
+	default:
+		return nil, usageError(tr("--id-format deve ser cpf ou cns"))
+	}
+	var pool []string
+	if opt.Images != "" {
+		files, err := collectImages(opt.Images)
+		if err != nil {
+			return nil, err
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf(tr("nenhuma imagem em %s"), opt.Images)
+		}
+		for _, f := range files {
+			abs, err := filepath.Abs(f)
+			if err != nil {
+				return nil, err
+			}
+			pool = append(pool, abs)
+		}
+	}
+	seed := opt.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	rng := rand.New(rand.NewPCG(seed, ^seed))
+	seen := map[string]bool{}
+	rows := make([]manifestRow, 0, opt.Count)
+	for len(rows) < opt.Count {
+		id := gen(rng)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		name := genFirstNames[rng.IntN(len(genFirstNames))] + " " +
+			genSurnames[rng.IntN(len(genSurnames))] + " " + genSurnames[rng.IntN(len(genSurnames))]
+		consent := rng.Float64() < opt.ConsentRate
+		row := manifestRow{ID: id, Name: name, Consent: &consent}
+		if len(pool) > 0 {
+			row.Image = pool[rng.IntN(len(pool))]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// mesmo formato que o batch-create lê
+func writeManifest(w io.Writer, rows []manifestRow, asJSON bool) error {
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"id", "name", "image", "consent"})
+	for _, r := range rows {
+		_ = cw.Write([]string{r.ID, r.Name, r.Image, strconv.FormatBool(*r.Consent)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func cmdGenData(opt genDataOptions) error {
+	rows, err := genManifest(opt)
+	if err != nil {
+		return err
+	}
+	if opt.Out == "" {
+		return writeManifest(os.Stdout, rows, false)
+	}
+	f, err := os.Create(opt.Out)
+	if err != nil {
+		return err
+	}
+	if err := writeManifest(f, rows, strings.ToLower(filepath.Ext(opt.Out)) == ".json"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	outf("[gen-data] %d card(s) sintético(s) (%s) em %s\n", len(rows), opt.IDFormat, opt.Out)
+	if opt.Images == "" {
+		outln("[gen-data] sem --images: preencha a coluna image antes do batch-create")
+	}
+	setResult("manifest", opt.Out)
+	setResult("count", len(rows))
+	return nil
+}
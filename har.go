@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+/* ==================== --har (captura HTTP Archive 1.2) ==================== */
+
+type harNV struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harNV      `json:"cookies"`
+	Headers     []harNV      `json:"headers"`
+	QueryString []harNV      `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type harResponse struct {
+	Status      int        `json:"status"`
+	StatusText  string     `json:"statusText"`
+	HTTPVersion string     `json:"httpVersion"`
+	Cookies     []harNV    `json:"cookies"`
+	Headers     []harNV    `json:"headers"`
+	Content     harContent `json:"content"`
+	RedirectURL string     `json:"redirectURL"`
+	HeadersSize int        `json:"headersSize"`
+	BodySize    int        `json:"bodySize"`
+}
+
+// tempos em ms; -1 = não se aplica (conexão reaproveitada, sem TLS...)
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harLog struct {
+	Version string `json:"version"`
+	Creator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+// grava todas as requisições do httpClient; o arquivo é escrito no fim da execução
+type harTransport struct {
+	base       http.RoundTripper
+	fullImages bool // false = imagens em base64 viram um resumo
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+var harRec *harTransport
+
+func enableHAR(fullImages bool) {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	harRec = &harTransport{base: base, fullImages: fullImages}
+	httpClient.Transport = harRec
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	req, rt := withTrace(req)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.add(req, reqBody, nil, nil, rt, err)
+		return nil, err
+	}
+	body, rerr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.add(req, reqBody, resp, body, rt, rerr)
+	return resp, rerr
+}
+
+func msBetween(a, b time.Time) float64 {
+	if a.IsZero() || b.IsZero() {
+		return -1
+	}
+	return float64(b.Sub(a).Microseconds()) / 1000
+}
+
+func (t *harTransport) add(req *http.Request, reqBody []byte, resp *http.Response, body []byte, rt *requestTrace, err error) {
+	end := time.Now()
+	h := req.Header.Clone()
+	redactHeaders(h, nil)
+	e := harEntry{
+		StartedDateTime: rt.start.Format(time.RFC3339Nano),
+		Time:            msBetween(rt.start, end),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         redactQuery(req.URL.String()),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNV{},
+			Headers:     harHeaders(h),
+			QueryString: []harNV{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response:        harResponse{Cookies: []harNV{}, Headers: []harNV{}, HeadersSize: -1, BodySize: -1},
+		ServerIPAddress: strings.Split(rt.remoteAddr, ":")[0],
+	}
+	for k, vv := range req.URL.Query() {
+		for _, v := range vv {
+			if sensitiveKeys[strings.ToLower(k)] {
+				v = redacted
+			}
+			e.Request.QueryString = append(e.Request.QueryString, harNV{k, v})
+		}
+	}
+	if len(reqBody) > 0 {
+		e.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: t.bodyText(reqBody)}
+	}
+
+	// wait/receive a partir dos marcos do httptrace (send fica embutido no wait)
+	e.Timings = harTimings{
+		Blocked: -1,
+		DNS:     msBetween(rt.dnsStart, rt.dnsDone),
+		Connect: msBetween(rt.connStart, rt.connDone),
+		SSL:     msBetween(rt.tlsStart, rt.tlsDone),
+		Send:    0,
+		Wait:    msBetween(rt.gotConn, rt.firstByte),
+		Receive: msBetween(rt.firstByte, end),
+	}
+	if e.Timings.Wait < 0 {
+		e.Timings.Wait = 0
+	}
+	if e.Timings.Receive < 0 {
+		e.Timings.Receive = 0
+	}
+
+	if err != nil {
+		e.Comment = tr("erro: ") + err.Error()
+	}
+	if resp != nil {
+		rh := resp.Header.Clone()
+		redactHeaders(rh, nil)
+		e.Response.Status = resp.StatusCode
+		e.Response.StatusText = http.StatusText(resp.StatusCode)
+		e.Response.HTTPVersion = resp.Proto
+		e.Request.HTTPVersion = resp.Proto
+		e.Response.Headers = harHeaders(rh)
+		e.Response.BodySize = len(body)
+		e.Response.Content = t.content(resp.Header.Get("Content-Type"), body)
+	}
+	t.mu.Lock()
+	t.entries = append(t.entries, e)
+	t.mu.Unlock()
+}
+
+// mesma janela do trimCalls: só as últimas keep entradas (nil = --har desligado)
+func (t *harTransport) trim(keep int) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	if n := len(t.entries); n > keep {
+		t.entries = append(t.entries[:0], t.entries[n-keep:]...)
+		clear(t.entries[keep:n])
+	}
+	t.mu.Unlock()
+}
+
+func harHeaders(h http.Header) []harNV {
+	out := []harNV{}
+	for _, k := range sortedKeys(h) {
+		for _, v := range h[k] {
+			out = append(out, harNV{k, v})
+		}
+	}
+	return out
+}
+
+// JSON com segredos mascarados; imagens em base64 resumidas, salvo --har-full-images
+func (t *harTransport) bodyText(b []byte) string {
+	var v any
+	if json.Unmarshal(b, &v) != nil {
+		if utf8.Valid(b) {
+			return string(b)
+		}
+		return base64.StdEncoding.EncodeToString(b)
+	}
+	v = sanitizeJSON(v, "", sanitizeOptions{Redact: true})
+	if !t.fullImages {
+		v = abbreviateJSON(v, "")
+	}
+	return strings.TrimSuffix(string(indentJSON(v, "")), "\n")
+}
+
+func (t *harTransport) content(mimeType string, body []byte) harContent {
+	c := harContent{Size: len(body), MimeType: mimeType}
+	switch {
+	case len(body) == 0:
+	case strings.HasPrefix(mimeType, "image/") || !utf8.Valid(body):
+		if t.fullImages {
+			c.Text, c.Encoding = base64.StdEncoding.EncodeToString(body), "base64"
+		} else {
+			c.Comment = tr("corpo binário omitido (use --har-full-images)")
+		}
+	default:
+		c.Text = t.bodyText(body)
+	}
+	return c
+}
+
+func (t *harTransport) write(path string) error {
+	return os.WriteFile(path, t.marshal(), 0644)
+}
+
+func (t *harTransport) marshal() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var l harLog
+	l.Version = "1.2"
+	l.Creator.Name = "biodoc-go-runner"
+	l.Creator.Version = "dev"
+	l.Entries = t.entries
+	if l.Entries == nil {
+		l.Entries = []harEntry{}
+	}
+	return indentJSON(map[string]any{"log": l}, "  ")
+}
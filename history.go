@@ -0,0 +1,552 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+/* ==================== Histórico local (SQLite) e history rerun ==================== */
+
+// cada invocação vira uma linha; history rerun N repete com as mesmas flags resolvidas
+type historyEntry struct {
+	N          int        `json:"n"`
+	At         time.Time  `json:"at"`
+	RunID      string     `json:"run_id"`
+	Dir        string     `json:"dir"`
+	Global     []string   `json:"global,omitempty"` // flags globais como digitadas (-q, --results...)
+	Command    string     `json:"command"`
+	Args       []string   `json:"args"` // flags do comando (depois do alias) + defaults resolvidos
+	Profile    string     `json:"profile,omitempty"`
+	BaseURL    string     `json:"base_url"`
+	ExitCode   int        `json:"exit_code"`
+	Duration   int64      `json:"duration_ms"`
+	RerunFrom  int        `json:"rerun_from,omitempty"`
+	CardID     string     `json:"card_id,omitempty"`    // --id do comando
+	Status     int        `json:"status,omitempty"`     // status HTTP da última chamada
+	Similarity *float64   `json:"similarity,omitempty"` // verify: similaridade devolvida
+	LatencyMS  int64      `json:"latency_ms"`           // soma das chamadas HTTP
+	Error      string     `json:"error,omitempty"`
+	Record     *runRecord `json:"record,omitempty"` // execução completa (chamadas, resultado), para history show
+}
+
+// comandos que não entram no histórico
+var noHistoryCommands = map[string]bool{"history": true, "mock-server": true, "proxy": true, "serve": true, "interactive": true}
+
+// último FlagSet lido por parseFlags (defaults resolvidos do comando)
+var lastFlagSet *flag.FlagSet
+
+// BIODOC_HISTORY=0 desliga; outro valor é o caminho do banco
+func historyPath() string {
+	if p := os.Getenv("BIODOC_HISTORY"); p != "" {
+		if p == "0" {
+			return ""
+		}
+		return p
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "biodoc-runner", "history.db")
+}
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	n           INTEGER PRIMARY KEY AUTOINCREMENT,
+	run_id      TEXT NOT NULL,
+	at          TEXT NOT NULL,
+	dir         TEXT NOT NULL DEFAULT '',
+	global      TEXT NOT NULL DEFAULT '[]',
+	command     TEXT NOT NULL,
+	args        TEXT NOT NULL DEFAULT '[]',
+	profile     TEXT NOT NULL DEFAULT '',
+	base_url    TEXT NOT NULL DEFAULT '',
+	card_id     TEXT NOT NULL DEFAULT '',
+	exit_code   INTEGER NOT NULL,
+	status      INTEGER NOT NULL DEFAULT 0,
+	similarity  REAL,
+	latency_ms  INTEGER NOT NULL DEFAULT 0,
+	duration_ms INTEGER NOT NULL,
+	error       TEXT NOT NULL DEFAULT '',
+	rerun_from  INTEGER NOT NULL DEFAULT 0,
+	record      TEXT NOT NULL DEFAULT '{}'
+);
+CREATE INDEX IF NOT EXISTS runs_run_id ON runs(run_id);
+CREATE INDEX IF NOT EXISTS runs_at ON runs(at);
+`
+
+// abre (e cria) o banco; execuções paralelas esperam o lock em vez de falhar
+func openHistory(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	legacy, err := legacyHistory(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf(tr("histórico %s: %w"), path, err)
+	}
+	_ = os.Chmod(path, 0600)
+	if legacy != "" {
+		if err := importLegacyHistory(db, legacy); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// histórico antigo em JSONL: o próprio caminho (BIODOC_HISTORY apontando para o .jsonl)
+// ou o history.jsonl ao lado do banco novo. Devolve o arquivo a importar ("" = nada)
+func legacyHistory(path string) (string, error) {
+	head := make([]byte, 16)
+	if f, err := os.Open(path); err == nil {
+		n, _ := f.Read(head)
+		f.Close()
+		if n == 0 || bytes.HasPrefix(head[:n], []byte("SQLite format 3")) {
+			return "", nil
+		}
+		// JSONL no lugar do banco: vira .jsonl.migrated e o banco nasce no mesmo caminho
+		moved := path + ".migrated"
+		if err := os.Rename(path, moved); err != nil {
+			return "", err
+		}
+		return moved, nil
+	}
+	old := filepath.Join(filepath.Dir(path), "history.jsonl")
+	if _, err := os.Stat(old); err != nil {
+		return "", nil
+	}
+	moved := old + ".migrated"
+	if err := os.Rename(old, moved); err != nil {
+		return "", err
+	}
+	return moved, nil
+}
+
+func importLegacyHistory(db *sql.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1<<20), 16<<20)
+	n := 0
+	for sc.Scan() {
+		var e historyEntry
+		if json.Unmarshal(sc.Bytes(), &e) != nil {
+			continue
+		}
+		if err := insertHistory(tx, e); err != nil {
+			return err
+		}
+		n++
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	outf("[history] %d entradas importadas de %s\n", n, path)
+	return nil
+}
+
+type sqlExecer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertHistory(db sqlExecer, e historyEntry) error {
+	global, _ := json.Marshal(e.Global)
+	args, _ := json.Marshal(e.Args)
+	record := []byte("{}")
+	if e.Record != nil {
+		record, _ = json.Marshal(e.Record)
+	}
+	_, err := db.Exec(`INSERT INTO runs (run_id, at, dir, global, command, args, profile, base_url, card_id,
+		exit_code, status, similarity, latency_ms, duration_ms, error, rerun_from, record)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.RunID, e.At.UTC().Format(time.RFC3339Nano), e.Dir, string(global), e.Command, string(args), e.Profile, e.BaseURL, e.CardID,
+		e.ExitCode, e.Status, e.Similarity, e.LatencyMS, e.Duration, e.Error, e.RerunFrom, string(record))
+	return err
+}
+
+const historyColumns = `n, run_id, at, dir, global, command, args, profile, base_url, card_id,
+	exit_code, status, similarity, latency_ms, duration_ms, error, rerun_from`
+
+func scanHistory(rows interface{ Scan(...any) error }, withRecord bool) (historyEntry, error) {
+	var e historyEntry
+	var at, global, args, record string
+	var sim sql.NullFloat64
+	dest := []any{&e.N, &e.RunID, &at, &e.Dir, &global, &e.Command, &args, &e.Profile, &e.BaseURL, &e.CardID,
+		&e.ExitCode, &e.Status, &sim, &e.LatencyMS, &e.Duration, &e.Error, &e.RerunFrom}
+	if withRecord {
+		dest = append(dest, &record)
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return e, err
+	}
+	e.At, _ = time.Parse(time.RFC3339Nano, at)
+	_ = json.Unmarshal([]byte(global), &e.Global)
+	_ = json.Unmarshal([]byte(args), &e.Args)
+	if sim.Valid {
+		e.Similarity = &sim.Float64
+	}
+	if withRecord && record != "{}" {
+		e.Record = &runRecord{}
+		_ = json.Unmarshal([]byte(record), e.Record)
+	}
+	return e, nil
+}
+
+// últimas limit execuções (0 = todas), da mais antiga para a mais nova; command filtra
+func readHistory(db *sql.DB, limit int, command string) ([]historyEntry, error) {
+	q := `SELECT ` + historyColumns + ` FROM runs`
+	var params []any
+	if command != "" {
+		q += ` WHERE command = ?`
+		params = append(params, command)
+	}
+	q += ` ORDER BY n DESC`
+	if limit > 0 {
+		q += ` LIMIT ?`
+		params = append(params, limit)
+	}
+	rows, err := db.Query(q, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []historyEntry
+	for rows.Next() {
+		e, err := scanHistory(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append([]historyEntry{e}, out...)
+	}
+	return out, rows.Err()
+}
+
+// uma execução por número, run id ou "last"
+func findHistory(db *sql.DB, ref string) (historyEntry, error) {
+	q := `SELECT ` + historyColumns + `, record FROM runs `
+	var row *sql.Row
+	if ref == "last" {
+		row = db.QueryRow(q + `ORDER BY n DESC LIMIT 1`)
+	} else if n, err := strconv.Atoi(ref); err == nil {
+		row = db.QueryRow(q+`WHERE n = ?`, n)
+	} else {
+		row = db.QueryRow(q+`WHERE run_id = ? ORDER BY n DESC LIMIT 1`, ref)
+	}
+	e, err := scanHistory(row, true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, fmt.Errorf(tr("%s não está no histórico (veja history list)"), ref)
+	}
+	return e, err
+}
+
+// flags globais = o que foi digitado menos o comando e seus argumentos (na ordem, de trás para frente)
+func globalArgs(raw, cmdArgs []string) []string {
+	var out []string
+	j := len(cmdArgs) - 1
+	for i := len(raw) - 1; i >= 0; i-- {
+		if j >= 0 && raw[i] == cmdArgs[j] {
+			j--
+			continue
+		}
+		out = append([]string{raw[i]}, out...)
+	}
+	return dropSecretArgs(out)
+}
+
+// flag com segredo não vai para o arquivo (no rerun vale o token do ambiente);
+// a URL de webhook do Slack/Teams é a própria credencial
+func secretFlag(name string) bool {
+	n := strings.ToLower(name)
+	if n == "token-file" {
+		return false // só o caminho; o rerun precisa dele
+	}
+	return strings.Contains(n, "token") || strings.Contains(n, "secret") || strings.Contains(n, "password") || strings.Contains(n, "webhook") || strings.Contains(n, "api-key")
+}
+
+// args sem as flags de segredo (e seus valores)
+func dropSecretArgs(args []string) []string {
+	var out []string
+	skip := false
+	for _, a := range args {
+		if skip {
+			skip = false
+			continue
+		}
+		if name, _, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "="); strings.HasPrefix(a, "-") && secretFlag(name) {
+			skip = !hasVal
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// argumentos do comando + defaults das flags não informadas (CARD_ID, imagem do perfil...) e a seed usada
+func resolvedArgs(args []string) []string {
+	out := dropSecretArgs(args)
+	if lastFlagSet == nil {
+		return out
+	}
+	set := map[string]bool{}
+	lastFlagSet.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	// seed sorteada (diff-fuzz --seed 0): grava a usada para reproduzir o mesmo resultado
+	callsMu.Lock()
+	seed, hasSeed := results["seed"]
+	callsMu.Unlock()
+	if f := lastFlagSet.Lookup("seed"); f != nil && hasSeed && !set["seed"] {
+		out = append(out, fmt.Sprintf("--seed=%v", seed))
+		set["seed"] = true
+	}
+	lastFlagSet.VisitAll(func(f *flag.Flag) {
+		if set[f.Name] || secretFlag(f.Name) {
+			return
+		}
+		// só tipos simples voltam a ser parseados a partir de String() (flags repetíveis ficam de fora)
+		g, ok := f.Value.(flag.Getter)
+		if !ok {
+			return
+		}
+		switch g.Get().(type) {
+		case string, bool, int, int64, uint, uint64, float64, time.Duration:
+			out = append(out, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	return out
+}
+
+// grava a execução atual; falha ao gravar só aparece com -v
+func recordHistory(path string, e historyEntry, rec runRecord) {
+	e.Dir, _ = os.Getwd()
+	e.RerunFrom, _ = strconv.Atoi(os.Getenv("BIODOC_HISTORY_RERUN"))
+	e.CardID = flagValue(e.Args, "--id")
+	e.Error = rec.Error
+	for _, c := range rec.Calls {
+		e.LatencyMS += c.LatencyMS
+		if c.Status != 0 {
+			e.Status = c.Status
+		}
+	}
+	if s, ok := rec.Result["similarity"].(string); ok {
+		if v, ok := parsePercent(s); ok {
+			e.Similarity = &v
+		}
+	}
+	e.Record = &rec
+	db, err := openHistory(path)
+	if err == nil {
+		err = insertHistory(db, e)
+		db.Close()
+	}
+	if err != nil && verbosity > 0 {
+		outf("[history] %v\n", err)
+	}
+}
+
+// valor de --nome x / --nome=x (último vence); "" se ausente
+func flagValue(args []string, name string) string {
+	v := ""
+	for i, a := range args {
+		if a == name || a == "-"+strings.TrimLeft(name, "-") {
+			if i+1 < len(args) {
+				v = args[i+1]
+			}
+		} else if after, ok := strings.CutPrefix(a, name+"="); ok {
+			v = after
+		}
+	}
+	return v
+}
+
+// linha de comando equivalente (para mostrar e para o rerun)
+func (e historyEntry) argv() []string {
+	argv := append([]string(nil), e.Global...)
+	if e.Profile != "" && !containsFlag(e.Global, "--profile") {
+		argv = append(argv, "--profile", e.Profile)
+	}
+	return append(append(argv, e.Command), e.Args...)
+}
+
+func containsFlag(args []string, name string) bool {
+	for _, a := range args {
+		if a == name || strings.HasPrefix(a, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func shellQuote(args []string) string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t'\"\\$`{}*?;&|<>()") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		out[i] = a
+	}
+	return strings.Join(out, " ")
+}
+
+// abre o histórico para os subcomandos (desligado = erro de uso)
+func historyDB() (*sql.DB, error) {
+	path := historyPath()
+	if path == "" {
+		return nil, usageError(tr("histórico desligado (BIODOC_HISTORY=0)"))
+	}
+	return openHistory(path)
+}
+
+func cmdHistoryList(limit int, command string) error {
+	db, err := historyDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	entries, err := readHistory(db, limit, command)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		outln("histórico vazio")
+		return nil
+	}
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, tr("n\tquando\trun id\texit\tstatus\tsimilaridade\tlatência\tambiente\tcomando\n"))
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\t%s\t%dms\t%s\t%s\n", e.N, e.At.Local().Format("2006-01-02 15:04:05"), e.RunID,
+			e.ExitCode, orDash(statusText(e.Status)), orDash(simText(e.Similarity)), e.LatencyMS, e.environment(), shellQuote(e.argv()))
+	}
+	return tw.Flush()
+}
+
+// history show N|RUN_ID|last: a execução com chamadas e resultado
+func cmdHistoryShow(ref string, asJSON bool) error {
+	db, err := historyDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	e, err := findHistory(db, ref)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		_, err := humanOut.Write(indentJSON(e, "  "))
+		return err
+	}
+	outf("#%d  run %s  %s\n", e.N, e.RunID, e.At.Local().Format("2006-01-02 15:04:05"))
+	outf("  comando:      %s\n", shellQuote(e.argv()))
+	outf("  diretório:    %s\n", e.Dir)
+	outf("  ambiente:     %s\n", e.environment())
+	outf("  card:         %s\n", orDash(e.CardID))
+	outf("  exit:         %d (duração %dms, HTTP %dms)\n", e.ExitCode, e.Duration, e.LatencyMS)
+	outf("  status:       %s\n", orDash(statusText(e.Status)))
+	outf("  similaridade: %s\n", orDash(simText(e.Similarity)))
+	if e.Error != "" {
+		outf("  erro:         %s\n", e.Error)
+	}
+	if e.RerunFrom > 0 {
+		outf("  rerun de:     #%d\n", e.RerunFrom)
+	}
+	if e.Record == nil {
+		return nil
+	}
+	for _, c := range e.Record.Calls {
+		line := fmt.Sprintf("  %s %s %s → %s %dms", c.At.Local().Format("15:04:05.000"), c.Method, c.Endpoint, orDash(statusText(c.Status)), c.LatencyMS)
+		if c.Attempts > 1 {
+			line += fmt.Sprintf(tr(" (%d tentativas)"), c.Attempts)
+		}
+		if c.Error != "" {
+			line += tr(" erro: ") + c.Error
+		}
+		outln(line)
+	}
+	return nil
+}
+
+func (e historyEntry) environment() string {
+	if e.Profile != "" {
+		return e.Profile + " (" + e.BaseURL + ")"
+	}
+	return e.BaseURL
+}
+
+func statusText(status int) string {
+	if status == 0 {
+		return ""
+	}
+	return strconv.Itoa(status)
+}
+
+func simText(sim *float64) string {
+	if sim == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*sim, 'f', 2, 64)
+}
+
+// history rerun N|RUN_ID|last: roda de novo no mesmo diretório e com a mesma BASE_URL
+func cmdHistoryRerun(ref string, dryRun bool) error {
+	db, err := historyDB()
+	if err != nil {
+		return err
+	}
+	e, err := findHistory(db, ref)
+	db.Close()
+	if err != nil {
+		return err
+	}
+	argv := e.argv()
+	outf("[history] #%d em %s: BASE_URL=%s %s\n", e.N, e.Dir, e.BaseURL, shellQuote(argv))
+	if dryRun {
+		return nil
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	c := exec.Command(self, argv...)
+	c.Dir = e.Dir
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), "BASE_URL="+e.BaseURL, "BIODOC_HISTORY_RERUN="+strconv.Itoa(e.N))
+	if err := c.Run(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			exit(ee.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
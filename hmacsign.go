@@ -0,0 +1,130 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* ==================== Assinatura HMAC das requisições (gateway de produção) ==================== */
+
+// ativa com HMAC_SECRET (ENV, .env cifrado ou env: do perfil). Cada requisição leva, além do Bearer:
+//
+//	X-Timestamp: unix em segundos      (HMAC_TIMESTAMP_HEADER)
+//	X-Signature: hex(HMAC(secret, s))  (HMAC_HEADER; HMAC_ENCODING=base64 muda a codificação)
+//
+// s = campos de HMAC_FIELDS (default method,path,body,timestamp) unidos por "\n":
+// method em maiúsculas, path com a query, body = sha256 hex do corpo, timestamp igual ao header
+type hmacSigner struct {
+	secret    []byte
+	header    string
+	tsHeader  string
+	fields    []string
+	algo      func() hash.Hash
+	base64Enc bool
+}
+
+var hmacFields = map[string]bool{"method": true, "path": true, "body": true, "timestamp": true}
+
+// nil = requisições sem assinatura
+var signer *hmacSigner
+
+func loadHMACEnv() (*hmacSigner, error) {
+	secret := os.Getenv("HMAC_SECRET")
+	if secret == "" {
+		return nil, nil
+	}
+	s := &hmacSigner{
+		secret:   []byte(secret),
+		header:   envOr("HMAC_HEADER", "X-Signature"),
+		tsHeader: envOr("HMAC_TIMESTAMP_HEADER", "X-Timestamp"),
+		algo:     sha256.New,
+	}
+	for _, f := range strings.Split(envOr("HMAC_FIELDS", "method,path,body,timestamp"), ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !hmacFields[f] {
+			return nil, fmt.Errorf(tr("HMAC_FIELDS: campo desconhecido %q (use method, path, body, timestamp)"), f)
+		}
+		s.fields = append(s.fields, f)
+	}
+	switch a := strings.ToLower(envOr("HMAC_ALGORITHM", "sha256")); a {
+	case "sha256":
+	case "sha512":
+		s.algo = sha512.New
+	default:
+		return nil, fmt.Errorf(tr("HMAC_ALGORITHM inválido: %q (use sha256 ou sha512)"), a)
+	}
+	switch e := strings.ToLower(envOr("HMAC_ENCODING", "hex")); e {
+	case "hex":
+	case "base64":
+		s.base64Enc = true
+	default:
+		return nil, fmt.Errorf(tr("HMAC_ENCODING inválido: %q (use hex ou base64)"), e)
+	}
+	return s, nil
+}
+
+// sha256 hex do corpo; em stream relê a imagem do disco (nada fica na memória)
+func bodySHA256(body reqBody) (string, error) {
+	h := sha256.New()
+	r, _, err := body.open()
+	if err != nil {
+		return "", err
+	}
+	if r != nil {
+		if _, err := io.Copy(h, r); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// texto assinado, na ordem de HMAC_FIELDS
+func (s *hmacSigner) canonical(req *http.Request, bodyHash, ts string) string {
+	parts := make([]string, 0, len(s.fields))
+	for _, f := range s.fields {
+		switch f {
+		case "method":
+			parts = append(parts, strings.ToUpper(req.Method))
+		case "path":
+			parts = append(parts, req.URL.RequestURI())
+		case "body":
+			parts = append(parts, bodyHash)
+		case "timestamp":
+			parts = append(parts, ts)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// assina a requisição; chamado a cada tentativa (o retry ganha timestamp novo). nil = sem HMAC
+func (s *hmacSigner) sign(req *http.Request, body reqBody, now time.Time) error {
+	if s == nil {
+		return nil
+	}
+	bodyHash, err := bodySHA256(body)
+	if err != nil {
+		return fmt.Errorf(tr("hmac: hash do corpo: %w"), err)
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	m := hmac.New(s.algo, s.secret)
+	m.Write([]byte(s.canonical(req, bodyHash, ts)))
+	sum := m.Sum(nil)
+	sig := hex.EncodeToString(sum)
+	if s.base64Enc {
+		sig = base64.StdEncoding.EncodeToString(sum)
+	}
+	req.Header.Set(s.tsHeader, ts)
+	req.Header.Set(s.header, sig)
+	return nil
+}
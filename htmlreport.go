@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+/* ==================== --report out.html (evidência de teste) ==================== */
+
+// imagens enviadas na execução, na ordem (registradas por openUploadImage quando há --report);
+// a miniatura sai na hora porque a foto da --camera é apagada depois do envio
+var (
+	reportImagesMu sync.Mutex
+	reportImages   []reportImage
+	reportEnabled  bool
+	reportThumbs   bool // --report-images
+)
+
+func noteReportImage(path string) {
+	if !reportEnabled {
+		return
+	}
+	reportImagesMu.Lock()
+	defer reportImagesMu.Unlock()
+	for _, ri := range reportImages {
+		if ri.Path == path {
+			return
+		}
+	}
+	ri := reportImage{Path: path}
+	if reportThumbs {
+		uri, err := thumbnailDataURI(path)
+		if err != nil {
+			ri.Error = err.Error()
+		}
+		ri.Thumb = template.URL(uri)
+	}
+	reportImages = append(reportImages, ri)
+}
+
+type reportCall struct {
+	Method, Endpoint, Status string
+	LatencyMS                int64
+	Attempts                 int
+	Error                    string
+}
+
+type reportStep struct {
+	Name       string
+	State      string // ok, falha, pulada
+	MS         int64
+	Error      string
+	Calls      []reportCall
+	Similarity string
+}
+
+type reportBar struct {
+	Label string
+	Value float64
+	Match bool
+	Width float64 // px da barra (0..barMax)
+	Y     int
+}
+
+type reportImage struct {
+	Path  string
+	Thumb template.URL // data URI; vazio sem --report-images
+	Error string
+}
+
+type reportData struct {
+	Command   string
+	OK        bool
+	ExitCode  int
+	Error     string
+	RunID     string
+	Started   string
+	Duration  string
+	Env       [][2]string
+	Steps     []reportStep
+	Failed    int
+	Skipped   int
+	Bars      []reportBar
+	ChartH    int
+	Latency   []timingRow
+	Images    []reportImage
+	Generated string
+}
+
+const (
+	reportBarMax   = 420
+	reportBarH     = 22
+	reportThumbMax = 160
+)
+
+// --report: uma página só (CSS, gráfico SVG e miniaturas embutidos), sem nada externo
+func writeHTMLReport(path, cmd, baseURL string, started time.Time, total time.Duration, st []stepRecord, runErr error, code int) error {
+	d := reportData{
+		Command:   cmd,
+		OK:        runErr == nil,
+		ExitCode:  code,
+		RunID:     runID,
+		Started:   started.Format("2006-01-02 15:04:05 MST"),
+		Duration:  total.Round(time.Millisecond).String(),
+		Generated: time.Now().Format(time.RFC3339),
+	}
+	if runErr != nil {
+		d.Error = runErr.Error()
+	}
+	d.Env = append(d.Env, [2]string{"BASE_URL", baseURL})
+	if activeProfile != "" {
+		d.Env = append(d.Env, [2]string{"perfil", activeProfile})
+	}
+	v := bugReportVersion()
+	d.Env = append(d.Env, [2]string{"runner", orDash(v["version"])}, [2]string{"go", v["go"] + " " + v["os"] + "/" + v["arch"]})
+	if host, err := os.Hostname(); err == nil {
+		d.Env = append(d.Env, [2]string{"máquina", host})
+	}
+	if imgPrep != (imagePrep{}) {
+		d.Env = append(d.Env, [2]string{"pré-processamento", fmt.Sprintf("%+v", imgPrep)})
+	}
+
+	byEP := map[string][]time.Duration{}
+	var epOrder []string
+	for _, s := range st {
+		rs := reportStep{Name: s.Name, State: "ok", MS: s.MS, Error: s.Error}
+		switch {
+		case s.Skipped:
+			rs.State = "pulada"
+			d.Skipped++
+		case s.Error != "":
+			rs.State = "falha"
+			d.Failed++
+		}
+		for _, c := range s.Calls {
+			ep := callEndpoint(c.Method, c.URL)
+			rs.Calls = append(rs.Calls, reportCall{
+				Method: c.Method, Endpoint: ep, Status: orDash(statusText(c.Status)),
+				LatencyMS: c.LatencyMS, Attempts: c.Attempts, Error: c.Error,
+			})
+			if _, seen := byEP[ep]; !seen {
+				epOrder = append(epOrder, ep)
+			}
+			byEP[ep] = append(byEP[ep], time.Duration(c.LatencyMS)*time.Millisecond)
+			// similaridade de cada verify, tirada da resposta gravada
+			if ep != "verify" || len(c.Response) == 0 {
+				continue
+			}
+			var vr VerifyResponse
+			if json.Unmarshal(c.Response, &vr) != nil {
+				continue
+			}
+			if sim, ok := parsePercent(vr.Similarity()); ok {
+				rs.Similarity = simText(&sim)
+				d.Bars = append(d.Bars, reportBar{Label: s.Name, Value: sim, Match: vr.Response.Success})
+			}
+		}
+		d.Steps = append(d.Steps, rs)
+	}
+	for i := range d.Bars {
+		d.Bars[i].Width = max(0, min(100, d.Bars[i].Value)) / 100 * reportBarMax
+		d.Bars[i].Y = i * (reportBarH + 6)
+	}
+	d.ChartH = len(d.Bars) * (reportBarH + 6)
+	for _, ep := range epOrder {
+		d.Latency = append(d.Latency, newTimingRow(ep, byEP[ep], nil))
+	}
+
+	reportImagesMu.Lock()
+	d.Images = append(d.Images, reportImages...)
+	reportImagesMu.Unlock()
+
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, d); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// miniatura JPEG em data URI (endireitada pelo EXIF, como foi enviada)
+func thumbnailDataURI(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	img, _, err := decodeImage(b)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if info, ok := parseJPEGExif(b); ok && info.Orientation > 1 {
+		img = applyOrientation(img, info.Orientation)
+	}
+	out, err := encodeJPEG(resizeLongSide(img, reportThumbMax), 75)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(out), nil
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+<meta charset="utf-8">
+<title>biodoc-go-runner · {{.Command}} · {{.RunID}}</title>
+<style>
+body{font:14px/1.45 system-ui,-apple-system,"Segoe UI",sans-serif;margin:2rem auto;max-width:1100px;color:#1d2327;padding:0 1rem}
+h1{font-size:1.4rem;margin:0 0 .3rem}h2{font-size:1.1rem;margin:2rem 0 .6rem;border-bottom:1px solid #dcdcde;padding-bottom:.3rem}
+.badge{display:inline-block;padding:.15rem .6rem;border-radius:1rem;font-weight:600;color:#fff}
+.ok{background:#1a7f37}.falha{background:#cf222e}.pulada{background:#8c8f94}
+table{border-collapse:collapse;width:100%}th,td{text-align:left;padding:.35rem .6rem;border-bottom:1px solid #eee;vertical-align:top}
+th{background:#f6f7f7;font-weight:600}td.num{text-align:right;font-variant-numeric:tabular-nums}
+.calls{font:12px ui-monospace,Menlo,Consolas,monospace;color:#50575e}.err{color:#cf222e}
+.meta td:first-child{color:#50575e;width:12rem}
+.imgs{display:flex;flex-wrap:wrap;gap:1rem}.imgs figure{margin:0;width:170px}.imgs img{max-width:160px;max-height:160px;border:1px solid #dcdcde}
+.imgs figcaption{font-size:12px;word-break:break-all;color:#50575e}
+footer{margin-top:2rem;color:#8c8f94;font-size:12px}
+</style>
+</head>
+<body>
+<h1>{{.Command}} <span class="badge {{if .OK}}ok{{else}}falha{{end}}">{{if .OK}}OK{{else}}FALHOU (exit {{.ExitCode}}){{end}}</span></h1>
+{{if .Error}}<p class="err">{{.Error}}</p>{{end}}
+
+<table class="meta">
+<tr><td>run id</td><td>{{.RunID}}</td></tr>
+<tr><td>início</td><td>{{.Started}}</td></tr>
+<tr><td>duração</td><td>{{.Duration}}</td></tr>
+{{range .Env}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>
+
+<h2>Etapas ({{len .Steps}}{{if .Failed}}, {{.Failed}} com falha{{end}}{{if .Skipped}}, {{.Skipped}} pulada(s){{end}})</h2>
+<table>
+<tr><th>etapa</th><th>resultado</th><th>duração</th><th>similaridade</th><th>chamadas</th></tr>
+{{range .Steps}}<tr>
+<td>{{.Name}}</td><td><span class="badge {{.State}}">{{.State}}</span></td><td class="num">{{.MS}}ms</td><td class="num">{{if .Similarity}}{{.Similarity}}{{else}}-{{end}}</td>
+<td class="calls">{{range .Calls}}{{.Method}} {{.Endpoint}} → {{.Status}} · {{.LatencyMS}}ms{{if gt .Attempts 1}} · {{.Attempts}} tentativas{{end}}{{if .Error}} · <span class="err">{{.Error}}</span>{{end}}<br>{{end}}{{if .Error}}<span class="err">{{.Error}}</span>{{end}}</td>
+</tr>
+{{end}}</table>
+
+{{if .Bars}}<h2>Similaridade</h2>
+<svg width="760" height="{{.ChartH}}" role="img" aria-label="similaridade por verificação">
+{{range .Bars}}<g transform="translate(0,{{.Y}})">
+<text x="0" y="15" font-size="12">{{.Label}}</text>
+<rect x="260" y="0" width="420" height="22" fill="#f0f0f1"/>
+<rect x="260" y="0" width="{{printf "%.1f" .Width}}" height="22" fill="{{if .Match}}#1a7f37{{else}}#cf222e{{end}}"/>
+<text x="690" y="15" font-size="12">{{printf "%.2f" .Value}}%</text>
+</g>
+{{end}}</svg>{{end}}
+
+{{if .Latency}}<h2>Latência por endpoint</h2>
+<table>
+<tr><th>endpoint</th><th>n</th><th>média</th><th>p95</th><th>máx</th><th>total</th></tr>
+{{range .Latency}}<tr><td>{{.Name}}</td><td class="num">{{.Count}}</td><td class="num">{{.AvgMS}}ms</td><td class="num">{{.P95MS}}ms</td><td class="num">{{.MaxMS}}ms</td><td class="num">{{.TotalMS}}ms</td></tr>
+{{end}}</table>{{end}}
+
+{{if .Images}}<h2>Imagens usadas</h2>
+<div class="imgs">{{range .Images}}<figure>{{if .Thumb}}<img src="{{.Thumb}}" alt="">{{end}}<figcaption>{{.Path}}{{if .Error}} <span class="err">{{.Error}}</span>{{end}}</figcaption></figure>
+{{end}}</div>{{end}}
+
+<footer>gerado por biodoc-go-runner em {{.Generated}}</footer>
+</body>
+</html>
+`))
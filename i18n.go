@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+)
+
+/* ==================== Idioma (--lang en|pt-BR) ==================== */
+
+// as mensagens nascem em português (a chave do catálogo é o próprio texto); com --lang en,
+// tr() troca pelo texto do catalogEN. Texto sem tradução sai como está
+var lang = "pt-BR"
+
+var langs = []string{"en", "pt-BR"}
+
+// --lang ganha; depois BIODOC_LANG e o locale do sistema (LC_ALL, LC_MESSAGES, LANG)
+func setLang(flagValue string) error {
+	v := flagValue
+	for _, k := range []string{"BIODOC_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v != "" {
+			break
+		}
+		v = os.Getenv(k)
+	}
+	switch l := strings.ToLower(v); {
+	case l == "" || l == "c" || l == "posix" || strings.HasPrefix(l, "pt"):
+		lang = "pt-BR"
+	case strings.HasPrefix(l, "en"):
+		lang = "en"
+	case flagValue != "":
+		return usageError(tr("--lang deve ser en ou pt-BR, veio ") + flagValue)
+	default:
+		// locale de outro idioma: português, o idioma original das mensagens
+		lang = "pt-BR"
+	}
+	return nil
+}
+
+func tr(s string) string {
+	if lang != "en" {
+		return s
+	}
+	if t, ok := catalogEN[s]; ok {
+		return t
+	}
+	return s
+}
+
+// textos de ajuda das flags, antes do Parse (o -h imprime o uso já traduzido)
+func translateFlags(fs *flag.FlagSet) {
+	fs.VisitAll(func(f *flag.Flag) { f.Usage = tr(f.Usage) })
+}
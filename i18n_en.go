@@ -0,0 +1,1156 @@
+package main
+
+// textos em inglês, pela mensagem original; verbos de formato na mesma ordem
+var catalogEN = map[string]string{
+	// i18n.go
+	"--lang deve ser en ou pt-BR, veio ": "--lang must be en or pt-BR, got ",
+
+	// alias.go
+	"  (sem comando: %s)\n":  "  (no command: %s)\n",
+	"alias %q é recursivo":   "alias %q is recursive",
+	"alias %q vazio":         "alias %q is empty",
+	"aspas ou \\ sem fechar": "unclosed quote or \\",
+	"Aliases (%s):\n":        "Aliases (%s):\n",
+
+	// anonymize.go
+	"decodificar %s: %w":                                "decode %s: %w",
+	"nenhuma execução %q com falha em %s":               "no failed run %q in %s",
+	"execuções com falha sem imagem registrada em %s":   "failed runs without a recorded image in %s",
+	"--mode deve ser pixelate ou blur":                  "--mode must be pixelate or blur",
+	"informe as imagens (arquivos, pastas ou --failed)": "provide the images (files, folders or --failed)",
+	"[anonymize] %s → %s (região %s)\n":                 "[anonymize] %s → %s (region %s)\n",
+	"[anonymize] região achada por heurística de cor de pele: confira antes de anexar (ou use --region x,y,w,h)": "[anonymize] region found by skin-color heuristic: check it before attaching (or use --region x,y,w,h)",
+	"%d imagem(ns) não anonimizada(s)": "%d image(s) not anonymized",
+	"região inválida %q (use x,y,w,h)": "invalid region %q (use x,y,w,h)",
+
+	// apicall.go
+	"informe a spec com --spec ARQ (ou OPENAPI_SPEC)": "provide the spec with --spec FILE (or OPENAPI_SPEC)",
+	"uso: api list | api describe OPERATION_ID | api call OPERATION_ID [--PARAM valor...] [--data JSON|@arq|-] [--form campo=valor|campo=@arq] [--header K:V]": "usage: api list | api describe OPERATION_ID | api call OPERATION_ID [--PARAM value...] [--data JSON|@file|-] [--form field=value|field=@file] [--header K:V]",
+	"OPERATION_ID\tMÉTODO\tCAMINHO\tRESUMO":                              "OPERATION_ID\tMETHOD\tPATH\tSUMMARY",
+	"informe o operationId (api list mostra os disponíveis)":             "provide the operationId (api list shows the available ones)",
+	"operationId %q não existe na spec (api list mostra os disponíveis)": "operationId %q is not in the spec (api list shows the available ones)",
+	"subcomando desconhecido: api ":                                      "unknown subcommand: api ",
+	" (obrigatório)":                                                     " (required)",
+	"  corpo%s: %s (--data para JSON, --form para multipart)\n":          "  body%s: %s (--data for JSON, --form for multipart)\n",
+	"  sem autenticação (security: [])":                                  "  no authentication (security: [])",
+	"use --data ou --form, não os dois":                                  "use --data or --form, not both",
+	"argumento solto: ":                                                  "stray argument: ",
+	"%s não é parâmetro de %s; aceitos: %s":                              "%s is not a parameter of %s; accepted: %s",
+	" exige um valor":                                                    " requires a value",
+	"--%s é obrigatório em %s":                                           "--%s is required for %s",
+	"--header deve ser Nome:valor, veio ":                                "--header must be Name:value, got ",
+	"--data não é JSON válido":                                           "--data is not valid JSON",
+	"%s exige corpo (%s): use --data ou --form":                          "%s requires a body (%s): use --data or --form",
+	"--form deve ser campo=valor ou campo=@arquivo, veio ":               "--form must be field=value or field=@file, got ",
+
+	// apierror.go
+	"requisição falhou: %d": "request failed: %d",
+
+	// batch.go
+	"consent inválido %q (id=%s)":                     "invalid consent %q (id=%s)",
+	"manifesto vazio: %s":                             "empty manifest: %s",
+	"[batch] %d linha(s), %d worker(s)\n":             "[batch] %d row(s), %d worker(s)\n",
+	"não enviado: lote abortado pelo circuit breaker": "not sent: batch aborted by the circuit breaker",
+	"[batch] total=%d ok=%d falhas=%d em %s\n":        "[batch] total=%d ok=%d failures=%d in %s\n",
+	"  ❌ linha %d id=%s: %s\n":                        "  ❌ row %d id=%s: %s\n",
+	"gravar resultados: %w":                           "write results: %w",
+	"[batch] resultados em %s\n":                      "[batch] results in %s\n",
+	"%d de %d linha(s) falharam":                      "%d of %d row(s) failed",
+	"manifesto JSON: %w":                              "JSON manifest: %w",
+	"manifesto CSV: %w":                               "CSV manifest: %w",
+	"manifesto deve ser .csv ou .json: %s":            "manifest must be .csv or .json: %s",
+	"linha %d: id e image são obrigatórios":           "row %d: id and image are required",
+	"coluna %q ausente no cabeçalho":                  "column %q missing from header",
+	"[batch] %d/%d ⏭ id=%s já criado com a mesma imagem em %s (cache; --force recria)\n": "[batch] %d/%d ⏭ id=%s already created with the same image at %s (cache; --force recreates)\n",
+	"[batch] %d linha(s) puladas: já criadas com a mesma imagem (cache de criações)\n":   "[batch] %d row(s) skipped: already created with the same image (create cache)\n",
+	"[batch] cache de criações não gravado: %v\n":                                        "[batch] create cache not saved: %v\n",
+
+	// batchverify.go
+	"nenhuma imagem em %s":                                     "no images in %s",
+	"--id-regex inválida: ":                                    "invalid --id-regex: ",
+	"[batch-verify] %d imagem(ns), %d worker(s)\n":             "[batch-verify] %d image(s), %d worker(s)\n",
+	"%d de %d verificação(ões) com erro":                       "%d of %d verification(s) failed",
+	"id não encontrado no nome do arquivo":                     "id not found in file name",
+	"resposta inválida: ":                                      "invalid response: ",
+	"ARQUIVO\tID\tSIMILARIDADE\tMATCH\tSTATUS\tLATÊNCIA\tERRO": "FILE\tID\tSIMILARITY\tMATCH\tSTATUS\tLATENCY\tERROR",
+	"[%s] total=%d match=%d sem-match=%d erros=%d em %s\n":     "[%s] total=%d match=%d no-match=%d errors=%d in %s\n",
+	"[%s] similaridade min=%.2f média=%.2f max=%.2f\n":         "[%s] similarity min=%.2f avg=%.2f max=%.2f\n",
+	"[%s] latência p50=%s p95=%s max=%s\n":                     "[%s] latency p50=%s p95=%s max=%s\n",
+
+	// breaker.go
+	"[breaker] sonda falhou: %s\n":                                          "[breaker] probe failed: %s\n",
+	"%d falha(s) seguida(s) e %d sonda(s) sem resposta":                     "%d consecutive failure(s) and %d unanswered probe(s)",
+	"[breaker] API continua fora: abortando o %s\n":                         "[breaker] API still down: aborting %s\n",
+	"[breaker] circuito abriu %d vez(es), %s em pausa; lote concluído\n":    "[breaker] circuit opened %d time(s), %s paused; batch completed\n",
+	"[breaker] lote ABORTADO após %s; %d item(ns) não enviado(s)\n":         "[breaker] batch ABORTED after %s; %d item(s) not sent\n",
+	"%s abortado pelo circuit breaker (%d item(ns) não enviado(s))":         "%s aborted by the circuit breaker (%d item(s) not sent)",
+	"falhas seguidas (sem resposta/5xx) que suspendem o lote; 0 desliga":    "consecutive failures (no response/5xx) that pause the batch; 0 disables",
+	"sondas sem resposta antes de abortar o lote":                           "unanswered probes before aborting the batch",
+	"[breaker] %d falha(s) seguida(s): circuito aberto, envios suspensos\n": "[breaker] %d consecutive failure(s): circuit open, sending paused\n",
+	"[breaker] sonda %d/%d em %s\n":                                         "[breaker] probe %d/%d in %s\n",
+	"[breaker] API respondeu (%s): circuito fechado, retomando\n":           "[breaker] API responded (%s): circuit closed, resuming\n",
+	"espera antes de cada sonda com o circuito aberto":                      "wait before each probe while the circuit is open",
+
+	// camera.go
+	"--camera no Windows exige --camera-device \"Nome da câmera\" (veja ffmpeg -list_devices true -f dshow -i dummy)": "--camera on Windows requires --camera-device \"Camera name\" (see ffmpeg -list_devices true -f dshow -i dummy)",
+	"--camera não suportado em %s":                  "--camera not supported on %s",
+	"--camera precisa do ffmpeg no PATH":            "--camera needs ffmpeg in PATH",
+	"[camera] foto em %ds...\n":                     "[camera] photo in %ds...\n",
+	"captura da webcam (%s): %v: %s":                "webcam capture (%s): %v: %s",
+	"captura da webcam (%s): nenhum quadro gravado": "webcam capture (%s): no frame written",
+	"[camera] quadro capturado de %s\n":             "[camera] frame captured from %s\n",
+
+	// card.go
+	"[preclean] id=%s não existe, nada a deletar\n":                "[preclean] id=%s does not exist, nothing to delete\n",
+	"[preclean] id=%s get-card sem resposta (%s); delete direto\n": "[preclean] id=%s get-card got no response (%s); deleting directly\n",
+	"[preclean] id=%s get-card respondeu %d; delete direto\n":      "[preclean] id=%s get-card returned %d; deleting directly\n",
+	"[preclean] id=%s não existia (delete respondeu %d)\n":         "[preclean] id=%s did not exist (delete returned %d)\n",
+	"[preclean] id=%s existia, deletado (status %d)\n":             "[preclean] id=%s existed, deleted (status %d)\n",
+	"ler imagem: %w": "read image: %w",
+	"informe ao menos um de --image, --name, --consent": "provide at least one of --image, --name, --consent",
+	"--method deve ser PATCH ou PUT":                    "--method must be PATCH or PUT",
+	"--id vazio":                                        "empty --id",
+	"resposta inválida: %w":                             "invalid response: %w",
+	"[card] nome=%s\n":                                  "[card] name=%s\n",
+	"[card] criado=%s\n":                                "[card] created=%s\n",
+	"[card] consentimento=%s\n":                         "[card] consent=%s\n",
+
+	// cassette.go, cassettematch.go
+	"cassete %s inválido: %w":                                          "invalid cassette %s: %w",
+	"cassete %s usa formato v%d; este runner entende até v%d":          "cassette %s uses format v%d; this runner understands up to v%d",
+	"nenhuma gravação com esse método e path":                          "no recording with this method and path",
+	"gravação com mesmo path difere em ":                               "recording with the same path differs in ",
+	"matcher desconhecido %q (use method, path, query, headers, body)": "unknown matcher %q (use method, path, query, headers, body)",
+	"--match-body %q inválido (use %s)":                                "invalid --match-body %q (use %s)",
+
+	// completion.go
+	"uso: completion bash|zsh|fish|powershell":     "usage: completion bash|zsh|fish|powershell",
+	"cria card a partir de imagem":                 "create a card from an image",
+	"verifica imagem":                              "verify an image",
+	"mostra os dados do card":                      "show the card data",
+	"troca imagem, nome ou consentimento":          "change image, name or consent",
+	"lista cards":                                  "list cards",
+	"deleta o card":                                "delete the card",
+	"apaga cards em lote":                          "delete cards in bulk",
+	"apaga cards com TTL vencido":                  "delete cards with an expired TTL",
+	"baixa a imagem principal":                     "download the main image",
+	"run-all em paralelo para vários ids":          "run-all in parallel for several ids",
+	"executa um cenário YAML":                      "run a YAML scenario",
+	"cria cards de um manifesto":                   "create cards from a manifest",
+	"verifica as imagens de um diretório":          "verify the images in a directory",
+	"gera massa sintética de cards":                "generate synthetic card data",
+	"verifica cada imagem nova de uma pasta":       "verify each new image in a folder",
+	"assistente passo a passo para QA manual":      "step-by-step wizard for manual QA",
+	"expõe create/verify/delete como serviço REST": "expose create/verify/delete as a REST service",
+	"pixeliza/borra o rosto":                       "pixelate/blur the face",
+	"diagnostica o ambiente":                       "diagnose the environment",
+	"operações da spec OpenAPI":                    "OpenAPI spec operations",
+	"lista as operações":                           "list the operations",
+	"parâmetros de uma operação":                   "parameters of an operation",
+	"chama uma operação":                           "call an operation",
+	"ferramentas do pool de fixtures":              "fixture pool tools",
+	"imagens idênticas ou quase iguais":            "identical or near-identical images",
+	"relatórios do results store":                  "results store reports",
+	"disponibilidade e p95 por hora/dia":           "availability and p95 per hour/day",
+	"execuções gravadas":                           "recorded runs",
+	"lista execuções":                              "list runs",
+	"detalhes de uma execução":                     "details of a run",
+	"repete uma execução":                          "repeat a run",
+	"guarda o token no keyring":                    "store the token in the keyring",
+	"remove as credenciais do keyring":             "remove the credentials from the keyring",
+	"compara duas execuções":                       "compare two runs",
+	"mesmos payloads em A e B":                     "same payloads against A and B",
+	"aplica regras de normalização a um JSON":      "apply normalization rules to a JSON",
+	"verify em carga":                              "verify under load",
+	"Biodoc falso local":                           "local fake Biodoc",
+	"proxy gravando cassete e métricas":            "proxy recording cassette and metrics",
+	"script de autocompletar do shell":             "shell completion script",
+
+	// difffuzz.go
+	"erro de transporte: %q → %q":                            "transport error: %q → %q",
+	"código %q → %q":                                         "code %q → %q",
+	"similaridade %s → %s":                                   "similarity %s → %s",
+	"similaridade %q → %q":                                   "similarity %q → %q",
+	"(ausente)":                                              "(missing)",
+	"[diff-fuzz] A=%s B=%s | %d casos | seed=%d\n":           "[diff-fuzz] A=%s B=%s | %d cases | seed=%d\n",
+	"criar card base em %s: %w":                              "create base card on %s: %w",
+	"[diff-fuzz] %d casos, %d com comportamento diferente\n": "[diff-fuzz] %d cases, %d with different behavior\n",
+	"[diff-fuzz] relatório em %s\n":                          "[diff-fuzz] report in %s\n",
+	"%d diferença(s) de comportamento entre A e B":           "%d behavior difference(s) between A and B",
+	"rewrite inválido %q (use /api/card=/api/v2/card)":       "invalid rewrite %q (use /api/card=/api/v2/card)",
+
+	// diffruns.go
+	"%s: nem arquivo, nem execução do histórico/results store": "%s: neither a file nor a run from history/results store",
+	"[diff-runs] ⚠ comandos diferentes: %s × %s\n":             "[diff-runs] ⚠ different commands: %s × %s\n",
+	"nenhuma chamada para comparar nas duas execuções":         "no calls to compare in the two runs",
+	"chamada\tstatus\tsimilaridade\tlatência\t\n":              "call\tstatus\tsimilarity\tlatency\t\n",
+	"ausente em B":          "missing in B",
+	"similaridade":          "similarity",
+	"sem similaridade em B": "no similarity in B",
+	"latência":              "latency",
+	"%d regressão(ões) de B em relação a A": "%d regression(s) of B compared to A",
+	"[diff-runs] sem regressões":            "[diff-runs] no regressions",
+	"erro":                                  "error",
+	"%s não é um resultado JSON: %w":        "%s is not a JSON result: %w",
+
+	// doctor.go
+	"perfil ": "profile ",
+	"nenhum .env nem perfil carregado (só variáveis do shell)":                                      "no .env or profile loaded (shell variables only)",
+	"crie um .env no diretório atual, use --env-file ARQ ou --profile NOME (~/.biodoc-runner.yaml)": "create a .env in the current directory, use --env-file FILE or --profile NAME (~/.biodoc-runner.yaml)",
+	"%q não é uma URL http(s)":                                              "%q is not an http(s) URL",
+	"ex.: BASE_URL=https://api.develop.biodoc.com.br":                       "e.g. BASE_URL=https://api.develop.biodoc.com.br",
+	" (default; BASE_URL não definido)":                                     " (default; BASE_URL not set)",
+	"defina BASE_URL no .env ou no perfil se não for o ambiente de develop": "set BASE_URL in .env or in the profile if this is not the develop environment",
+	" sem TLS":                                 " without TLS",
+	"use https:// fora da máquina local":       "use https:// outside the local machine",
+	"conexão passa pelo proxy; ver teste HTTP": "connection goes through the proxy; see the HTTP check",
+	"confira OAUTH_TOKEN_URL, OAUTH_CLIENT_ID e o secret (login --oauth guarda no keyring)": "check OAUTH_TOKEN_URL, OAUTH_CLIENT_ID and the secret (login --oauth stores it in the keyring)",
+	"token obtido em ": "token obtained from ",
+	"confira o host do BASE_URL, VPN e /etc/hosts; atrás de proxy use --proxy": "check the BASE_URL host, VPN and /etc/hosts; behind a proxy use --proxy",
+	"porta fechada ou bloqueada: firewall, VPN, ou a API está fora do ar":      "port closed or blocked: firewall, VPN, or the API is down",
+	"%s conectou em %s": "%s connected in %s",
+	"cadeia não confiável: passe a CA interna com --ca-cert ARQ.pem (ENV CA_CERT)":          "untrusted chain: pass the internal CA with --ca-cert FILE.pem (ENV CA_CERT)",
+	"o certificado não cobre esse host: confira o BASE_URL":                                 "the certificate does not cover this host: check BASE_URL",
+	"%s, emitido por %s, vence em %s (%d dias)":                                             "%s, issued by %s, expires on %s (%d days)",
+	"certificado do servidor perto de vencer: avise quem mantém a API":                      "server certificate about to expire: tell whoever maintains the API",
+	"AUTH_TOKEN não definido":                                                               "AUTH_TOKEN not set",
+	"defina AUTH_TOKEN no .env, rode `login`, ou configure OAUTH_TOKEN_URL/OAUTH_CLIENT_ID": "set AUTH_TOKEN in .env, run `login`, or configure OAUTH_TOKEN_URL/OAUTH_CLIENT_ID",
+	"presente (%d caracteres, não é JWT: validade não verificável)":                         "present (%d characters, not a JWT: expiry cannot be checked)",
+	" (sem exp)":            " (no exp)",
+	"%s expirou há %s (%s)": "%s expired %s ago (%s)",
+	"gere um token novo (login) ou use OAuth para renovação automática": "generate a new token (login) or use OAuth for automatic renewal",
+	"%s expira em %s": "%s expires in %s",
+	"execuções longas vão tomar 401: renove antes ou use OAuth":                                    "long runs will get 401: renew it first or use OAuth",
+	"%s válido até %s (%s)":                                                                        "%s valid until %s (%s)",
+	"a API não respondeu: confira BASE_URL, proxy e se o serviço está no ar":                       "the API did not respond: check BASE_URL, proxy and whether the service is up",
+	"GET /api/card/… → %d: token recusado":                                                         "GET /api/card/… → %d: token rejected",
+	"token de outro ambiente, expirado ou sem escopo: confira AUTH_TOKEN/perfil contra o BASE_URL": "token from another environment, expired or missing scope: check AUTH_TOKEN/profile against BASE_URL",
+	"a API está com erro interno: tente mais tarde ou avise o time da API":                         "the API has an internal error: try later or tell the API team",
+	"GET /api/card/… → %d (API respondeu, token aceito)":                                           "GET /api/card/… → %d (API responded, token accepted)",
+	"relógio":                         "clock",
+	"diferença de %s para o servidor": "%s off from the server",
+	"sincronize o relógio (NTP): JWT e assinaturas dependem da hora":                   "sync the clock (NTP): JWTs and signatures depend on the time",
+	"%d amostra(s): min %s, mediana %s, max %s":                                        "%d sample(s): min %s, median %s, max %s",
+	"acima de 1s: VPN/proxy lentos ou API sobrecarregada; budgets e SLOs vão estourar": "above 1s: slow VPN/proxy or overloaded API; budgets and SLOs will be exceeded",
+	"ambiente ok": "environment ok",
+	"%d verificação(ões) falharam; veja as correções sugeridas acima": "%d check(s) failed; see the suggested fixes above",
+
+	// dryrun.go
+	"[dry-run]   corpo (%d bytes):\n":  "[dry-run]   body (%d bytes):\n",
+	"  <multipart ilegível: %v>":       "  <unreadable multipart: %v>",
+	"  %s: <arquivo %s, %s, %d bytes>": "  %s: <file %s, %s, %d bytes>",
+
+	// face.go
+	"%s: nenhum rosto encontrado (--require-single-face)":           "%s: no face found (--require-single-face)",
+	"%s: %d rostos encontrados, esperado 1 (--require-single-face)": "%s: %d faces found, expected 1 (--require-single-face)",
+	"cascata de rosto: %w":                         "face cascade: %w",
+	"[face] %s: 1 rosto em (%d,%d) %dpx, q=%.1f\n": "[face] %s: 1 face at (%d,%d) %dpx, q=%.1f\n",
+
+	// fixtures.go
+	"--max-distance deve estar entre 0 e 64":                          "--max-distance must be between 0 and 64",
+	"[dedupe] %d imagem(ns) em %s, distância máxima %d/64\n":          "[dedupe] %d image(s) in %s, max distance %d/64\n",
+	"[dedupe] ignorada %s: %v\n":                                      "[dedupe] skipped %s: %v\n",
+	"quase iguais (distância ≤ %d)":                                   "near-identical (distance ≤ %d)",
+	"idênticas":                                                       "identical",
+	" ⚠ em conjuntos diferentes":                                      " ⚠ in different sets",
+	"[dedupe] grupo %d: %d imagens %s%s\n":                            "[dedupe] group %d: %d images %s%s\n",
+	"[dedupe] %d grupo(s) de duplicatas, %d atravessando conjuntos\n": "[dedupe] %d duplicate group(s), %d spanning sets\n",
+	"%d grupo(s) de imagens duplicadas no pool de fixtures":           "%d group(s) of duplicate images in the fixture pool",
+
+	// gendata.go
+	"--id-format deve ser cpf ou cns":                                        "--id-format must be cpf or cns",
+	"[gen-data] %d card(s) sintético(s) (%s) em %s\n":                        "[gen-data] %d synthetic card(s) (%s) in %s\n",
+	"[gen-data] sem --images: preencha a coluna image antes do batch-create": "[gen-data] no --images: fill in the image column before batch-create",
+	"--count deve ser ≥ 1":                                                   "--count must be ≥ 1",
+
+	// har.go
+	"erro: ": "error: ",
+	"corpo binário omitido (use --har-full-images)": "binary body omitted (use --har-full-images)",
+
+	// history.go
+	"histórico %s: %w":                             "history %s: %w",
+	"[history] %d entradas importadas de %s\n":     "[history] %d entries imported from %s\n",
+	"%s não está no histórico (veja history list)": "%s is not in the history (see history list)",
+	"histórico desligado (BIODOC_HISTORY=0)":       "history disabled (BIODOC_HISTORY=0)",
+	"histórico vazio":                              "history is empty",
+	"n\tquando\trun id\texit\tstatus\tsimilaridade\tlatência\tambiente\tcomando\n": "n\twhen\trun id\texit\tstatus\tsimilarity\tlatency\tenvironment\tcommand\n",
+	"  comando:      %s\n":                           "  command:      %s\n",
+	"  diretório:    %s\n":                           "  directory:    %s\n",
+	"  ambiente:     %s\n":                           "  environment:  %s\n",
+	"  exit:         %d (duração %dms, HTTP %dms)\n": "  exit:         %d (duration %dms, HTTP %dms)\n",
+	"  similaridade: %s\n":                           "  similarity:   %s\n",
+	"  erro:         %s\n":                           "  error:        %s\n",
+	"  rerun de:     #%d\n":                          "  rerun of:     #%d\n",
+	" (%d tentativas)":                               " (%d attempts)",
+	" erro: ":                                        " error: ",
+	"[history] #%d em %s: BASE_URL=%s %s\n":          "[history] #%d at %s: BASE_URL=%s %s\n",
+
+	// imageprep.go
+	"pré-processar %s: %w":                    "preprocess %s: %w",
+	"[prep] metadados removidos (%d bytes)\n": "[prep] metadata removed (%d bytes)\n",
+	"[prep] orientação EXIF %d aplicada (--no-exif-fix para enviar como está)\n": "[prep] EXIF orientation %d applied (--no-exif-fix to send as is)\n",
+	"[prep] %dx%d %dKB → lado %dpx, jpeg q%d, %dKB\n":                            "[prep] %dx%d %dKB → side %dpx, jpeg q%d, %dKB\n",
+	"não coube em %d bytes nem com %dpx/q%d":                                     "did not fit in %d bytes even at %dpx/q%d",
+	"%s inválido: %q": "invalid %s: %q",
+
+	// interactive.go
+	"Verificar imagem contra um card":                   "Verify an image against a card",
+	"Cadastrar card":                                    "Register card",
+	"Fluxo completo (apaga, cadastra, verifica, apaga)": "Full flow (delete, register, verify, delete)",
+	"Consultar card":                                    "Look up card",
+	"Apagar card":                                       "Delete card",
+	"biodoc-go-runner · modo interativo":                "biodoc-go-runner · interactive mode",
+	"Enter aceita o valor entre colchetes; Ctrl+D sai.": "Enter accepts the value in brackets; Ctrl+D quits.",
+	"  0) Sair":                                         "  0) Quit",
+	"Opção":                                             "Option",
+	"opção inválida":                                    "invalid option",
+	"ID do card":                                        "Card ID",
+	"Nome":                                              "Name",
+	"Imagem":                                            "Image",
+	"SEM MATCH":                                         "NO MATCH",
+	"  ▶ %s · similaridade %s%%":                        "  ▶ %s · similarity %s%%",
+	"  ✔ concluído":                                     "  ✔ done",
+	" em ":                                              " in ",
+	"  ..) pasta acima":                                 "  ..) parent folder",
+	"Número ou caminho":                                 "Number or path",
+	"Número, caminho ou Enter para a atual":             "Number, path or Enter for the current one",
+	"número fora da lista":                              "number not in the list",
+	"não encontrado: ":                                  "not found: ",
+
+	// junit.go
+	"%s %s → %d (%dms, %d tentativa(s))\n": "%s %s → %d (%dms, %d attempt(s))\n",
+	"erro: %s\n":                           "error: %s\n",
+	"…(truncado)":                          "…(truncated)",
+
+	// keyring.go
+	"ler segredo do stdin: %w":                "read secret from stdin: %w",
+	"segredo vazio, nada gravado":             "empty secret, nothing stored",
+	"gravar no keyring do sistema: %w":        "write to the system keyring: %w",
+	"[login] %s gravado no keyring (%s/%s)\n": "[login] %s stored in the keyring (%s/%s)\n",
+	"[login] pode apagar o valor do .env; os comandos leem do keyring quando a variável não está definida": "[login] you can remove the value from .env; commands read from the keyring when the variable is not set",
+	"[logout] %s removido do keyring\n":       "[logout] %s removed from the keyring\n",
+	"remover %s do keyring: %w":               "remove %s from the keyring: %w",
+	"[logout] nada guardado para este perfil": "[logout] nothing stored for this profile",
+
+	// listcards.go
+	"--size deve ser >= 1":                        "--size must be >= 1",
+	"ID\tNOME\tCRIADO\tSTATUS":                    "ID\tNAME\tCREATED\tSTATUS",
+	"[list] %d card(s) listados de %d no total\n": "[list] %d card(s) listed out of %d total\n",
+	"[list] %d card(s) listados\n":                "[list] %d card(s) listed\n",
+	"listagem falhou (página %d): %w":             "listing failed (page %d): %w",
+	"resposta inválida (página %d): %w":           "invalid response (page %d): %w",
+
+	// loadverify.go
+	"[load] t=%.0fs enviadas=%d (%.1f req/s) erros=%d\n": "[load] t=%.0fs sent=%d (%.1f req/s) errors=%d\n",
+	"nenhuma requisição concluída em %s":                 "no request completed in %s",
+	"[load] %d requisições em %s → %.1f req/s\n":         "[load] %d requests in %s → %.1f req/s\n",
+	"[load] erros=%d (%.2f%%) status=%s":                 "[load] errors=%d (%.2f%%) status=%s",
+	" sem worker livre=%d":                               " no free worker=%d",
+	"[load] latência p50=%s p95=%s p99=%s máx=%s\n":      "[load] latency p50=%s p95=%s p99=%s max=%s\n",
+	"taxa de erro %.2f%% acima do limite %.2f%%":         "error rate %.2f%% above the limit %.2f%%",
+	"erro=%d":                                 "error=%d",
+	"--workers deve ser >= 1":                 "--workers must be >= 1",
+	"--duration deve ser > 0":                 "--duration must be > 0",
+	"ler/encode imagem: %w":                   "read/encode image: %w",
+	"malha fechada":                           "closed loop",
+	"[load] POST %s por %s, %d workers, %s\n": "[load] POST %s for %s, %d workers, %s\n",
+	"[load] ⚠ --rpm/RATE_LIMIT ativo: as latências incluem a espera pelo limite": "[load] ⚠ --rpm/RATE_LIMIT active: latencies include the wait for the limit",
+
+	// soak.go
+	"[soak] %s a cada %s por %s (resumo a cada %s)\n":                                                                      "[soak] %s every %s for %s (summary every %s)\n",
+	"[soak] t=%s janela %d: %d iterações, erros=%d (%.2f%%) p50=%dms p95=%dms drift p95=%+.1f%% renovações=%d status=%s\n": "[soak] t=%s window %d: %d iterations, errors=%d (%.2f%%) p50=%dms p95=%dms p95 drift=%+.1f%% renewals=%d status=%s\n",
+	"[soak] interrompido: resumo parcial":                                                                                  "[soak] interrupted: partial summary",
+	"[soak] %d iterações em %s, erros=%d (%.2f%%) p50=%s p95=%s renovações de token=%d\n":                                  "[soak] %d iterations in %s, errors=%d (%.2f%%) p50=%s p95=%s token renewals=%d\n",
+	"--mode inválido: %q (use verify ou cycle)":                                                                            "invalid --mode: %q (use verify or cycle)",
+	"--duration, --interval e --summary-every devem ser > 0":                                                               "--duration, --interval and --summary-every must be > 0",
+	"--id vazio: o modo cycle cria e apaga esse card":                                                                      "empty --id: cycle mode creates and deletes that card",
+	"nenhuma iteração concluída em %s":                                                                                     "no iteration completed in %s",
+	"início":                                                                                                               "start",
+	"erros":                                                                                                                "errors",
+	"p95 da última janela %.1f%% acima da primeira (limite %.1f%%)":                                                        "last window p95 %.1f%% above the first (limit %.1f%%)",
+
+	// main.go: mensagens
+	"--jq e --output json não combinam":                                                                         "--jq and --output json cannot be combined",
+	"--expected-duration inválido: %q (use uma duração, ex.: 45m)":                                              "invalid --expected-duration: %q (use a duration, e.g. 45m)",
+	"verify sem match (success=false)":                                                                          "verify without match (success=false)",
+	"AUTH_TOKEN não definido (ENV, --profile, login ou OAUTH_*); --no-strict segue mesmo assim":                 "AUTH_TOKEN not set (ENV, --profile, login or OAUTH_*); --no-strict continues anyway",
+	"[aviso] AUTH_TOKEN não definido; endpoints protegidos vão falhar":                                          "[warning] AUTH_TOKEN not set; protected endpoints will fail",
+	"[record] %d interações gravadas em %s\n":                                                                   "[record] %d interactions recorded in %s\n",
+	"[har] %d requisições em %s\n":                                                                              "[har] %d requests in %s\n",
+	"[dry-run] %d requisição(ões) montada(s), nenhuma enviada\n":                                                "[dry-run] %d request(s) built, none sent\n",
+	"[bug-report] pacote para o chamado em %s (requisições, config e log, sem tokens)\n":                        "[bug-report] ticket bundle in %s (requests, config and log, no tokens)\n",
+	"[dica] --bug-report ARQ.zip junta requisições, config e log para anexar ao chamado\n":                      "[tip] --bug-report FILE.zip bundles requests, config and log to attach to a ticket\n",
+	"[junit] relatório em %s\n":                                                                                 "[junit] report in %s\n",
+	"[report] relatório HTML em %s\n":                                                                           "[report] HTML report in %s\n",
+	"--idcard é obrigatório":                                                                                    "--idcard is required",
+	"--manifest é obrigatório":                                                                                  "--manifest is required",
+	"--dir é obrigatório":                                                                                       "--dir is required",
+	"--record-new exige --fallthrough":                                                                          "--record-new requires --fallthrough",
+	"--file é obrigatório":                                                                                      "--file is required",
+	"--failed precisa do results store (--results ou RESULTS_STORE)":                                            "--failed needs the results store (--results or RESULTS_STORE)",
+	"--consent-rate deve estar entre 0 e 1":                                                                     "--consent-rate must be between 0 and 1",
+	"--create exige --images":                                                                                   "--create requires --images",
+	"uso: fixtures dedupe --dir PASTA|GLOB [--max-distance N]":                                                  "usage: fixtures dedupe --dir FOLDER|GLOB [--max-distance N]",
+	"[retry] %d retentativa(s) nesta execução\n":                                                                "[retry] %d retry(ies) in this run\n",
+	"uso: report sla [--by hour|day] [--since D] [--until D] [--endpoint E] [--target 99.5] [--csv ARQ]":        "usage: report sla [--by hour|day] [--since D] [--until D] [--endpoint E] [--target 99.5] [--csv FILE]",
+	"informe o results store com --results ARQ.jsonl (ou RESULTS_STORE)":                                        "provide the results store with --results FILE.jsonl (or RESULTS_STORE)",
+	"uso: history show N|RUN_ID|last [--json]":                                                                  "usage: history show N|RUN_ID|last [--json]",
+	"uso: history rerun N|RUN_ID|last [--dry-run]":                                                              "usage: history rerun N|RUN_ID|last [--dry-run]",
+	"uso: history [list [--n N] [--command C] | show N|RUN_ID|last [--json] | rerun N|RUN_ID|last [--dry-run]]": "usage: history [list [--n N] [--command C] | show N|RUN_ID|last [--json] | rerun N|RUN_ID|last [--dry-run]]",
+	"uso: diff-runs A B (arquivo JSON, N/RUN_ID do history ou run id do --results) [--similarity-tolerance P] [--latency-tolerance F]": "usage: diff-runs A B (JSON file, N/RUN_ID from history or run id from --results) [--similarity-tolerance P] [--latency-tolerance F]",
+	"A e B são iguais: use --b com outro ambiente ou --b-rewrite com outra versão":                                                     "A and B are the same: use --b with another environment or --b-rewrite with another version",
+	"--rules é obrigatório":                "--rules is required",
+	"use --manifest ou --ids, não os dois": "use --manifest or --ids, not both",
+	"informe --manifest ou --ids":          "provide --manifest or --ids",
+	"preclean falhou":                      "preclean failed",
+	"create falhou":                        "create failed",
+	"verify falhou":                        "verify failed",
+	"delete final falhou":                  "final delete failed",
+	"✅ fluxo completo: preclean → create → verify → delete": "✅ full flow: preclean → create → verify → delete",
+	"marshal body: %w":                     "marshal body: %w",
+	"[429] %s %s → esperando %s (%d/%d)\n": "[429] %s %s → waiting %s (%d/%d)\n",
+	"[429] %s %s → Retry-After de %s passa do teto %s (RETRY_429_MAX_WAIT), desistindo\n": "[429] %s %s → Retry-After of %s exceeds the cap %s (RETRY_429_MAX_WAIT), giving up\n",
+	"[retry] %s %s → %s; tentativa %d/%d em %s\n":                                         "[retry] %s %s → %s; attempt %d/%d in %s\n",
+	"[oauth] 401 e a renovação falhou: %v\n":                                              "[oauth] 401 and the renewal failed: %v\n",
+	"[oauth] 401 em %s %s → token renovado, repetindo\n":                                  "[oauth] 401 on %s %s → token renewed, retrying\n",
+	"read body: resposta maior que %d MiB, abortada":                                      "read body: response larger than %d MiB, aborted",
+	"esperado 200, veio %d":                                                               "expected 200, got %d",
+	"imagem salva em %s (%s)\n":                                                           "image saved to %s (%s)\n",
+	"main-image: resposta não é imagem (%d bytes, %s; Content-Type %q)":                   "main-image: response is not an image (%d bytes, %s; Content-Type %q)",
+	"[main-image] Content-Type %s, mas o conteúdo é %s\n":                                 "[main-image] Content-Type %s, but the content is %s\n",
+	"main-image: %s não decodifica: %v":                                                   "main-image: %s does not decode: %v",
+	"[main-image] %s tem extensão %s, mas o conteúdo é %s\n":                              "[main-image] %s has extension %s, but the content is %s\n",
+	"resposta inválida, não dá para checar --min-similarity: %w":                          "invalid response, cannot check --min-similarity: %w",
+	"[verify] %s match | similaridade=%s | status=%d | idLog=%s\n":                        "[verify] %s match | similarity=%s | status=%d | idLog=%s\n",
+	"similaridade ausente ou inválida: %q":                                                "similarity missing or invalid: %q",
+	"similaridade %.2f abaixo do mínimo %.2f":                                             "similarity %.2f below the minimum %.2f",
+	"--id vazio (defina CARD_ID no .env ou use defaultID())":                              "empty --id (set CARD_ID in .env or use defaultID())",
+	"%s exige um valor": "%s requires a value",
+	"--notify-on deve ser always ou failure, veio %q\n": "--notify-on must be always or failure, got %q\n",
+	"--record e --replay não podem ser usados juntos":   "--record and --replay cannot be used together",
+	"Erro ao carregar o arquivo .env":                   "Error loading the .env file",
+
+	// main.go: flags
+	"nome do executável no PATH":         "executable name in PATH",
+	"caminho da imagem (ENV CARD_IMAGE)": "image path (ENV CARD_IMAGE)",
+	"documento/id do card":               "card document/id",
+	"nome":                               "name",
+	"captura um quadro da webcam (ffmpeg) no lugar de --image":                                "capture a webcam frame (ffmpeg) instead of --image",
+	"webcam (ENV CAMERA_DEVICE; /dev/video0, 0 no macOS, nome no Windows)":                    "webcam (ENV CAMERA_DEVICE; /dev/video0, 0 on macOS, name on Windows)",
+	"contagem regressiva antes da foto":                                                       "countdown before the photo",
+	"formato da imagem: base64, datauri (JSON) ou multipart":                                  "image format: base64, datauri (JSON) or multipart",
+	"valor do header idCard (obrigatório)":                                                    "idCard header value (required)",
+	"arquivo de saída; sem extensão, ganha a do conteúdo (default: mainimage.jpg/.png/.webp)": "output file; without an extension, gets the content's (default: mainimage.jpg/.png/.webp)",
+	"decodifica a imagem inteira para garantir que não veio truncada":                         "decode the whole image to make sure it is not truncated",
+	"original local: falha (exit 5) se a imagem guardada divergir dele (dHash + SSIM)":        "local original: fail (exit 5) if the stored image diverges from it (dHash + SSIM)",
+	"distância máxima do dHash no --compare (0..64)":                                          "maximum dHash distance for --compare (0..64)",
+	"SSIM mínimo no --compare (0..1)":                                                         "minimum SSIM for --compare (0..1)",
+	"--compare: %v":                                                                           "--compare: %v",
+	"path da rota verify":                                                                     "verify route path",
+	"imagem para verificação (ENV VERIFY_IMAGE); repetível ou glob entre aspas (\"fotos/*.jpg\") para veredito agregado": "image to verify (ENV VERIFY_IMAGE); repeatable or a quoted glob (\"photos/*.jpg\") for an aggregate verdict",
+	"com várias imagens, quantas precisam passar: all, majority ou any":                                                  "with several images, how many must pass: all, majority or any",
+	"--require deve ser all, majority ou any, veio ":                                                                     "--require must be all, majority or any, got ",
+	"id do cadastro (string)":                                                      "registration id (string)",
+	"detalhes (string). Ex.: \"{'guia': '654321', ...}\"":                          "details (string). E.g. \"{'guia': '654321', ...}\"",
+	"falha (exit 5) se success=false ou similaridade abaixo disso (0 = não checa)": "fail (exit 5) if success=false or similarity below this (0 = no check)",
+	"formato da imagem: datauri, base64 (JSON) ou multipart":                       "image format: datauri, base64 (JSON) or multipart",
+	"ID do card (usa CARD_ID ou default se vazio)":                                 "card ID (uses CARD_ID or default if empty)",
+	"ID do card a alterar":                                                         "ID of the card to change",
+	"PATCH ou PUT":                                                                 "PATCH or PUT",
+	"path da rota ({id} é substituído)":                                            "route path ({id} is replaced)",
+	"nova imagem":                                                                  "new image",
+	"novo nome":                                                                    "new name",
+	"novo consentTermSigned":                                                       "new consentTermSigned",
+	"path da rota de listagem":                                                     "listing route path",
+	"página inicial (começa em 0)":                                                 "first page (starts at 0)",
+	"itens por página":                                                             "items per page",
+	"filtra por nome":                                                              "filter by name",
+	"segue paginando até acabar":                                                   "keep paging until the end",
+	"itens por página na listagem":                                                 "items per page when listing",
+	"só cards com esse nome":                                                       "only cards with this name",
+	"ignora a tag (--tag/CARD_TAG) e apaga vencidos de qualquer tag":               "ignore the tag (--tag/CARD_TAG) and delete expired cards of any tag",
+	"só lista o que seria apagado":                                                 "only list what would be deleted",
+	"ID do card para deletar (usa CARD_ID ou default se vazio)":                    "ID of the card to delete (uses CARD_ID or default if empty)",
+	"manifesto .csv (id,name,image,consent) ou .json; image aceita s3:// e gs:// (obrigatório)":                                  "manifest .csv (id,name,image,consent) or .json; image accepts s3:// and gs:// (required)",
+	"recria mesmo o que o cache diz já ter sido criado com a mesma imagem":                                                       "recreate even what the cache says was already created with the same image",
+	"arquivo com um id por linha (# comenta; - = stdin)":                                                                         "file with one id per line (# comments; - = stdin)",
+	"apaga os cards da listagem cujo id casa, ex.: \"9998*\"":                                                                    "delete the listed cards whose id matches, e.g. \"9998*\"",
+	"path da rota de listagem (com --prefix)":                                                                                    "listing route path (with --prefix)",
+	"só cards com esse nome (com --prefix)":                                                                                      "only cards with this name (with --prefix)",
+	"requisições simultâneas":                                                                                                    "concurrent requests",
+	"nome quando a linha não tiver":                                                                                              "name when the row has none",
+	"consentTermSigned quando a linha não tiver":                                                                                 "consentTermSigned when the row has none",
+	"grava resultado por linha (.csv ou .json)":                                                                                  "write per-row results (.csv or .json)",
+	"diretório (recursivo), glob (\"fotos/*.jpg\") ou bucket (s3://bucket/prefixo, gs://...) (obrigatório)":                      "directory (recursive), glob (\"photos/*.jpg\") or bucket (s3://bucket/prefix, gs://...) (required)",
+	"id fixo do card; vazio = extrai do nome do arquivo":                                                                         "fixed card id; empty = taken from the file name",
+	"regex aplicada ao nome do arquivo (1º grupo = id)":                                                                          "regex applied to the file name (1st group = id)",
+	"pasta observada (recursiva) (obrigatório)":                                                                                  "watched folder (recursive) (required)",
+	"intervalo entre varreduras da pasta":                                                                                        "interval between folder scans",
+	"verifica também as imagens que já estavam na pasta":                                                                         "also verify the images already in the folder",
+	"encerra depois de N imagens (0 = até Ctrl+C)":                                                                               "stop after N images (0 = until Ctrl+C)",
+	"endereço de escuta (fora do loopback, use --api-key)":                                                                       "listen address (outside loopback, use --api-key)",
+	"exige esse valor no header X-API-Key (ENV SERVE_API_KEY)":                                                                   "require this value in the X-API-Key header (ENV SERVE_API_KEY)",
+	"formato da imagem repassada: datauri, base64 ou multipart (default de cada rota)":                                           "forwarded image format: datauri, base64 or multipart (default per route)",
+	"consentTermSigned no /create quando o pedido não mandar":                                                                    "consentTermSigned on /create when the request does not send it",
+	"endereço de escuta":                                                                                                         "listen address",
+	"relógio: real ou sim (avança via POST /__admin/clock)":                                                                      "clock: real or sim (advanced via POST /__admin/clock)",
+	"instante inicial do relógio sim (RFC3339, default 2024-01-01T00:00:00Z)":                                                    "start time of the sim clock (RFC3339, default 2024-01-01T00:00:00Z)",
+	"atraso de cada resposta, contado no relógio do mock":                                                                        "delay of each response, counted on the mock clock",
+	"score do verify: fixed:N, random:MIN-MAX ou phash (hash perceptual)":                                                        "verify score: fixed:N, random:MIN-MAX or phash (perceptual hash)",
+	"semente do scoring random e da latência (0 = aleatória)":                                                                    "seed for random scoring and latency (0 = random)",
+	"similaridade mínima para success=true":                                                                                      "minimum similarity for success=true",
+	"exige Authorization: Bearer com esse valor (401 caso contrário); jwt = qualquer JWT cujo exp não passou no relógio do mock": "require Authorization: Bearer with this value (401 otherwise); jwt = any JWT whose exp has not passed on the mock clock",
+	"serve HTTPS com certificado autoassinado gerado na hora":                                                                    "serve HTTPS with a self-signed certificate generated on the fly",
+	"HTTPS com certificado já vencido (implica --tls)":                                                                           "HTTPS with an already expired certificate (implies --tls)",
+	"grava o certificado PEM gerado nesse arquivo":                                                                               "write the generated PEM certificate to this file",
+	"[endpoint=]fixed:200ms | normal:200ms,50ms | pareto:100ms,1.5 (repetível; endpoints: register, verify, mainimage, liveness, document, get, list, update, delete)": "[endpoint=]fixed:200ms | normal:200ms,50ms | pareto:100ms,1.5 (repeatable; endpoints: register, verify, mainimage, liveness, document, get, list, update, delete)",
+	"[endpoint=]bytes/s, ex.: 64KB ou mainimage=16KB (repetível)":                                                           "[endpoint=]bytes/s, e.g. 64KB or mainimage=16KB (repeatable)",
+	"[endpoint=]tipo[:taxa]: malformed-json, wrong-content-type, gzip-bomb, chunked-truncation (repetível)":                 "[endpoint=]type[:rate]: malformed-json, wrong-content-type, gzip-bomb, chunked-truncation (repeatable)",
+	"[endpoint=]fixed:2s | normal:800ms,200ms | pareto:100ms,1.5, com @taxa opcional (ex.: verify=fixed:3s@0.2); repetível": "[endpoint=]fixed:2s | normal:800ms,200ms | pareto:100ms,1.5, with optional @rate (e.g. verify=fixed:3s@0.2); repeatable",
+	"[endpoint=]taxa[:before|after]: derruba a conexão antes de repassar ou depois da resposta da API (repetível)":          "[endpoint=]rate[:before|after]: drop the connection before forwarding or after the API responds (repeatable)",
+	"[endpoint=]taxa[:status]: responde erro HTTP (default 503) sem repassar (repetível)":                                   "[endpoint=]rate[:status]: answer with an HTTP error (default 503) without forwarding (repeatable)",
+	"semente do sorteio das falhas (0 = aleatória)":                                                                         "seed for fault draws (0 = random)",
+	"[endpoint=]taxa[:status]: responde erro HTTP (default 503) nessa fração das requisições (repetível)":                   "[endpoint=]rate[:status]: answer with an HTTP error (default 503) for this fraction of requests (repeatable)",
+	"arquivo JSON/YAML com respostas prontas (mesmo formato de POST /__admin/stubs)":                                        "JSON/YAML file with canned responses (same format as POST /__admin/stubs)",
+	"endereço de escuta (aponte o app para cá)":                                                                             "listen address (point the app here)",
+	"API real para onde o tráfego é repassado":                                                                              "real API the traffic is forwarded to",
+	"grava as interações nesse arquivo JSON (vazio = só métricas)":                                                          "record the interactions to this JSON file (empty = metrics only)",
+	"mascara tokens, cookies e campos sensíveis no cassete":                                                                 "mask tokens, cookies and sensitive fields in the cassette",
+	"troca as imagens do cassete por um PNG 8x8 (arquivo pequeno e sem biometria)":                                          "replace the cassette images with an 8x8 PNG (small file, no biometrics)",
+	"headers extras a mascarar, separados por vírgula":                                                                      "extra headers to mask, comma-separated",
+	"serve as respostas desse cassete em vez de repassar à API":                                                             "serve responses from this cassette instead of forwarding to the API",
+	"o que comparar no replay: method,path,query,headers,body":                                                              "what to compare on replay: method,path,query,headers,body",
+	"comparação do corpo: exact, json (ignora bytes de imagem), structure (só chaves/tipos) ou none":                        "body comparison: exact, json (ignores image bytes), structure (keys/types only) or none",
+	"headers extras fora da comparação, separados por vírgula":                                                              "extra headers left out of the comparison, comma-separated",
+	"campos JSON fora da comparação (ex.: detail,meta.requestId)":                                                           "JSON fields left out of the comparison (e.g. detail,meta.requestId)",
+	"regras de normalização (YAML: drop, mask, round, sort) aplicadas antes de comparar corpos":                             "normalization rules (YAML: drop, mask, round, sort) applied before comparing bodies",
+	"replay: requisição sem gravação vai para --target em vez de 501":                                                       "replay: requests without a recording go to --target instead of 501",
+	"com --fallthrough: grava as interações novas (em --cassette, ou no próprio arquivo do --replay)":                       "with --fallthrough: record new interactions (to --cassette, or to the --replay file itself)",
+	"imagem enviada em todas as requisições":                                                                                "image sent in every request",
+	"id do cadastro": "registration id",
+	"taxa alvo em req/s (0 = cada worker dispara assim que a anterior volta)": "target rate in req/s (0 = each worker fires as soon as the previous one returns)",
+	"duração do teste": "test duration",
+	"verify ou cycle (preclean → create → verify → delete)": "verify or cycle (preclean → create → verify → delete)",
+	"imagem enviada em todas as iterações":                  "image sent in every iteration",
+	"duração total":                                         "total duration",
+	"intervalo entre iterações":                             "interval between iterations",
+	"tamanho da janela de cada resumo":                      "window size of each summary",
+	"falha se o p95 da última janela passar o da primeira em mais de N% (-1 = não checa)": "fail if the last window's p95 exceeds the first's by more than N% (-1 = no check)",
+	"falha se a taxa de erro (0..1) passar disso (-1 = não checa)":                        "fail if the error rate (0..1) exceeds this (-1 = no check)",
+	"cenário YAML (obrigatório)":                                                          "YAML scenario (required)",
+	"nome=valor para ${nome} no cenário (repetível)":                                      "name=value for ${name} in the scenario (repeatable)",
+	"regras de normalização (YAML) somadas às do cenário, aplicadas antes do golden":      "normalization rules (YAML) added to the scenario's, applied before the golden",
+	"regrava os arquivos golden com a resposta atual (normalizada)":                       "rewrite the golden files with the current (normalized) response",
+	"roda o cenário antes contra o mock embutido e só segue se passar":                    "run the scenario against the built-in mock first and only continue if it passes",
+	"nome=valor para ${nome} na pipeline (repetível)":                                     "name=value for ${name} in the pipeline (repeatable)",
+	"roda a pipeline antes contra o mock embutido e só segue se passar":                   "run the pipeline against the built-in mock first and only continue if it passes",
+	"guarda o OAUTH_CLIENT_SECRET em vez do AUTH_TOKEN":                                   "store OAUTH_CLIENT_SECRET instead of AUTH_TOKEN",
+	"pixelate ou blur": "pixelate or blur",
+	"tamanho do bloco (pixelate) ou raio (blur) em px; 0 = proporcional ao rosto": "block size (pixelate) or radius (blur) in px; 0 = proportional to the face",
+	"pasta de saída": "output folder",
+	"pega as imagens das execuções com falha no results store: last, all ou o id da execução": "take the images of failed runs from the results store: last, all or the run id",
+	"x,y,w,h a cobrir (repetível); sem ela, a região do rosto é estimada":                     "x,y,w,h to cover (repeatable); without it, the face region is estimated",
+	"quantidade de cards": "number of cards",
+	"manifesto gerado (.csv ou .json); vazio = CSV no stdout":                                       "generated manifest (.csv or .json); empty = CSV on stdout",
+	"pool de imagens (pasta recursiva ou glob), sorteadas por card":                                 "image pool (recursive folder or glob), picked at random per card",
+	"formato do id: cpf ou cns (dígitos verificadores válidos)":                                     "id format: cpf or cns (valid check digits)",
+	"fração dos cards com consentimento (0 a 1)":                                                    "fraction of cards with consent (0 to 1)",
+	"semente (mesma semente = mesma massa); 0 = aleatória":                                          "seed (same seed = same data); 0 = random",
+	"cadastra a massa na hora (batch-create; exige --images)":                                       "register the data right away (batch-create; requires --images)",
+	"requisições simultâneas no --create":                                                           "concurrent requests with --create",
+	"requisições para medir a latência":                                                             "requests to measure latency",
+	"pasta (recursiva) ou glob com o pool de imagens":                                               "folder (recursive) or glob with the image pool",
+	"distância de Hamming máxima (de 64 bits do dHash) para considerar duplicata":                   "maximum Hamming distance (out of the 64 dHash bits) to count as duplicate",
+	"agrupamento: hour ou day":                                                                      "grouping: hour or day",
+	"a partir de (2006-01-02 ou RFC3339)":                                                           "from (2006-01-02 or RFC3339)",
+	"até, exclusivo (2006-01-02 ou RFC3339)":                                                        "until, exclusive (2006-01-02 or RFC3339)",
+	"só esse endpoint (register, verify, get, list, update, delete, mainimage, liveness, document)": "only this endpoint (register, verify, get, list, update, delete, mainimage, liveness, document)",
+	"disponibilidade alvo em %, base do orçamento de erro":                                          "target availability in %, basis of the error budget",
+	"grava também em CSV":                                                                           "also write as CSV",
+	"quantas entradas mostrar (0 = todas)":                                                          "how many entries to show (0 = all)",
+	"só execuções desse comando (ex.: verify-card)":                                                 "only runs of this command (e.g. verify-card)",
+	"imprime a entrada completa em JSON":                                                            "print the full entry as JSON",
+	"só mostra o comando":                                                                           "only show the command",
+	"queda de similaridade (pontos) tolerada antes de apontar regressão":                            "similarity drop (points) tolerated before flagging a regression",
+	"aumento relativo de latência tolerado (0.2 = +20%)":                                            "tolerated relative latency increase (0.2 = +20%)",
+	"aumentos de latência menores que isso são ruído":                                               "latency increases smaller than this are noise",
+	"base URL do lado A (ex.: v1 ou ambiente atual)":                                                "base URL of side A (e.g. v1 or the current environment)",
+	"base URL do lado B (ex.: v2 ou outro ambiente)":                                                "base URL of side B (e.g. v2 or another environment)",
+	"token do lado A": "token for side A",
+	"token do lado B": "token for side B",
+	"troca prefixo de path no lado A (ex.: /api/card=/api/v1/card)":                "rewrite path prefix on side A (e.g. /api/card=/api/v1/card)",
+	"troca prefixo de path no lado B (ex.: /api/card=/api/v2/card)":                "rewrite path prefix on side B (e.g. /api/card=/api/v2/card)",
+	"imagem válida usada como base dos payloads":                                   "valid image used as the base of the payloads",
+	"casos aleatórios (combinações de mutações) além dos fixos":                    "random cases (combinations of mutations) besides the fixed ones",
+	"semente do gerador (0 = aleatória; a usada aparece no relatório)":             "generator seed (0 = random; the one used is shown in the report)",
+	"diferença de similaridade tolerada, em pontos percentuais":                    "tolerated similarity difference, in percentage points",
+	"grava o relatório JSON nesse arquivo":                                         "write the JSON report to this file",
+	"arquivo YAML de regras (drop, mask, round, sort)":                             "YAML rules file (drop, mask, round, sort)",
+	"manifesto .csv/.json (id,name,image), o mesmo do batch-create":                "manifest .csv/.json (id,name,image), same as batch-create",
+	"ids separados por vírgula (alternativa ao --manifest)":                        "comma-separated ids (alternative to --manifest)",
+	"imagem usada com --ids (vazio = CARD_IMAGE)":                                  "image used with --ids (empty = CARD_IMAGE)",
+	"pool (pasta/glob) distribuído em rodízio entre os --ids, no lugar do --image": "pool (folder/glob) assigned round-robin to the --ids, instead of --image",
+	"deleta cada id antes do create (404 = não existia)":                           "delete each id before create (404 = did not exist)",
+	"ciclos simultâneos":                                             "concurrent cycles",
+	"imagem para criar/verificar (ENV CARD_IMAGE)":                   "image to create/verify (ENV CARD_IMAGE)",
+	"id do card (usa CARD_ID do .env se existir)":                    "card id (uses CARD_ID from .env if present)",
+	"deletar antes se existir (consulta via get-card)":               "delete first if it exists (checked via get-card)",
+	"roda o fluxo antes contra o mock embutido e só segue se passar": "run the flow against the built-in mock first and only continue if it passes",
+
+	// main.go: usage()
+	"Comandos:": "Commands:",
+	"  create-card   - Cria card a partir de imagem (--encoding base64|datauri|multipart)":                                                  "  create-card   - Create a card from an image (--encoding base64|datauri|multipart)",
+	"  verify-card   - Verifica imagem atual (POST /api/card/integration/verify; --camera: foto da webcam)":                                 "  verify-card   - Verify the current image (POST /api/card/integration/verify; --camera: webcam photo)",
+	"                  várias --image (ou glob): melhor/pior/média e veredito agregado (--require all|majority|any)":                        "                  several --image (or a glob): best/worst/average and an aggregate verdict (--require all|majority|any)",
+	"  get-card      - Mostra os dados do card (GET /api/card/{id})":                                                                        "  get-card      - Show the card data (GET /api/card/{id})",
+	"  update-card   - Troca imagem, nome ou consentimento de um card (PATCH/PUT)":                                                          "  update-card   - Change a card's image, name or consent (PATCH/PUT)",
+	"  list-cards    - Lista cards com paginação e filtro por nome":                                                                         "  list-cards    - List cards with paging and a name filter",
+	"  delete-card   - Deleta a carteirinha (DELETE /api/card/{id})":                                                                        "  delete-card   - Delete the card (DELETE /api/card/{id})",
+	"  delete-cards  - Apaga em lote (--ids-file ids.txt ou --prefix 9998*), com workers; 404 não para o lote":                              "  delete-cards  - Bulk delete (--ids-file ids.txt or --prefix 9998*) with workers; a 404 does not stop the batch",
+	"  reap          - Apaga cards cujo TTL (gravado no detail com --ttl) já venceu":                                                        "  reap          - Delete cards whose TTL (stored in detail with --ttl) has expired",
+	"  main-image    - Baixa imagem principal (header idCard) com a extensão do conteúdo; valida e mostra as dimensões":                     "  main-image    - Download the main image (idCard header) with the content's extension; validates it and shows the dimensions",
+	"                  --compare original.jpg: falha se a foto guardada divergir do original (dHash/SSIM)":                                  "                  --compare original.jpg: fail if the stored photo diverges from the original (dHash/SSIM)",
+	"  run-all       - preclean → create → verify → delete (--rehearse: ensaia antes no mock embutido)":                                     "  run-all       - preclean → create → verify → delete (--rehearse: rehearse first on the built-in mock)",
+	"  run-matrix    - Ciclo completo (preclean → create → verify → delete) para vários ids em paralelo, com tabela pass/fail":              "  run-matrix    - Full cycle (preclean → create → verify → delete) for several ids in parallel, with a pass/fail table",
+	"  run-scenario  - Executa as etapas de um cenário YAML (--file, --var nome=valor)":                                                     "  run-scenario  - Run the steps of a YAML scenario (--file, --var name=value)",
+	"  run-pipeline  - Executa uma pipeline nomeada do config (pipelines:); etapas com when: always|on_failure e retries; sem nome, lista":  "  run-pipeline  - Run a named pipeline from the config (pipelines:); steps with when: always|on_failure and retries; without a name, list them",
+	"  batch-create  - Cria vários cards a partir de um manifesto CSV/JSON (imagens locais ou s3://, gs://)":                                "  batch-create  - Create several cards from a CSV/JSON manifest (local images or s3://, gs://)",
+	"                  pula id+imagem já criados antes (cache em CREATE_CACHE, 0 desliga; --force recria)":                                  "                  skips id+image already created before (cache in CREATE_CACHE, 0 disables; --force recreates)",
+	"  batch-verify  - Verifica todas as imagens de um diretório/glob ou de um bucket (s3://bucket/prefixo, gs://...)":                      "  batch-verify  - Verify every image in a directory/glob or a bucket (s3://bucket/prefix, gs://...)",
+	"  watch         - Observa uma pasta e verifica cada imagem nova que aparecer (id do nome do arquivo ou --id)":                          "  watch         - Watch a folder and verify each new image that shows up (id from the file name or --id)",
+	"  anonymize-image - Pixeliza/borra o rosto das imagens (ou das execuções com falha) para anexar em chamados":                           "  anonymize-image - Pixelate/blur the face in images (or in failed runs) to attach to tickets",
+	"  doctor        - Diagnostica o ambiente: .env/perfil, BASE_URL, DNS/TCP/TLS, token (exp do JWT), latência, com correções":             "  doctor        - Diagnose the environment: .env/profile, BASE_URL, DNS/TCP/TLS, token (JWT exp), latency, with fixes",
+	"  token-info    - Decodifica o JWT do AUTH_TOKEN: issuer, audience, subject e expiração (exit 3 se vencido)":                           "  token-info    - Decode the AUTH_TOKEN JWT: issuer, audience, subject and expiry (exit 3 if expired)",
+	"  api           - Qualquer operação da spec OpenAPI: api list | describe ID | call ID --param valor (--spec ARQ ou OPENAPI_SPEC)":      "  api           - Any OpenAPI spec operation: api list | describe ID | call ID --param value (--spec FILE or OPENAPI_SPEC)",
+	"  gen-data      - Gera manifesto de cards sintéticos (CPF/CNS válidos, nomes, imagens do pool); --create já cadastra":                  "  gen-data      - Generate a manifest of synthetic cards (valid CPF/CNS, names, pool images); --create registers them",
+	"  fixtures dedupe - Aponta imagens idênticas ou quase iguais (hash perceptual) no pool de fixtures":                                    "  fixtures dedupe - Flag identical or near-identical images (perceptual hash) in the fixture pool",
+	"  report sla    - Disponibilidade, p95 e orçamento de erro por hora/dia a partir do results store":                                     "  report sla    - Availability, p95 and error budget per hour/day from the results store",
+	"  history       - Execuções gravadas em SQLite: list, show N|RUN_ID, rerun N repete com as mesmas flags (BIODOC_HISTORY=0 desliga)":    "  history       - Runs recorded in SQLite: list, show N|RUN_ID, rerun N repeats with the same flags (BIODOC_HISTORY=0 disables)",
+	"  login         - Guarda AUTH_TOKEN (ou --oauth: client secret) no keyring do SO, lido do stdin":                                       "  login         - Store AUTH_TOKEN (or --oauth: client secret) in the OS keyring, read from stdin",
+	"  logout        - Remove as credenciais do perfil atual do keyring":                                                                    "  logout        - Remove the current profile's credentials from the keyring",
+	"  diff-runs A B - Compara status, similaridade e latência de duas execuções (history, results store ou JSON) e aponta regressões":      "  diff-runs A B - Compare status, similarity and latency of two runs (history, results store or JSON) and flag regressions",
+	"  diff-fuzz     - Manda os mesmos payloads gerados a A e B (versões/ambientes) e aponta diferenças":                                    "  diff-fuzz     - Send the same generated payloads to A and B (versions/environments) and flag differences",
+	"  normalize     - Aplica regras de normalização a um JSON (arquivo ou stdin)":                                                          "  normalize     - Apply normalization rules to a JSON (file or stdin)",
+	"  load-verify   - Dispara verify em carga (--rps ou --workers) e mede vazão, erros e p50/p95/p99":                                      "  load-verify   - Fire verify under load (--rps or --workers) and measure throughput, errors and p50/p95/p99",
+	"  soak          - verify (ou --mode cycle) em ritmo baixo por horas: taxa de erro, drift de latência e renovações de token por janela": "  soak          - verify (or --mode cycle) at a low rate for hours: error rate, latency drift and token renewals per window",
+	"  interactive   - Assistente passo a passo: escolhe a operação, a imagem (navegando pelas pastas) e o ID; destaca a similaridade":      "  interactive   - Step-by-step wizard: pick the operation, the image (browsing folders) and the ID; highlights similarity",
+	"  serve         - Expõe POST /create, POST /verify e DELETE /delete/{id} localmente, repassando ao Biodoc com o token do runner":       "  serve         - Expose POST /create, POST /verify and DELETE /delete/{id} locally, forwarding to Biodoc with the runner's token",
+	"  completion    - Script de autocompletar de comandos e flags: completion [--bin NOME] bash|zsh|fish|powershell":                       "  completion    - Command and flag completion script: completion [--bin NAME] bash|zsh|fish|powershell",
+	"  mock-server   - Sobe um Biodoc falso local (register/verify/delete/mainimage/liveness/document)":                                     "  mock-server   - Start a local fake Biodoc (register/verify/delete/mainimage/liveness/document)",
+	"  proxy         - Repassa tráfego para a API gravando cassete e métricas; --replay serve um cassete":                                   "  proxy         - Forward traffic to the API recording a cassette and metrics; --replay serves a cassette",
+	"                  --latency/--reset/--fail/--fault injetam falhas sorteadas (teste de retry e circuit breaker)":                        "                  --latency/--reset/--fail/--fault inject random faults (retry and circuit breaker testing)",
+	"Geral (ENV): BASE_URL, AUTH_TOKEN, CARD_ID, CARD_IMAGE, VERIFY_IMAGE (opcionais)":                                                      "General (ENV): BASE_URL, AUTH_TOKEN, CARD_ID, CARD_IMAGE, VERIFY_IMAGE (optional)",
+	"  --image - lê a imagem do stdin; --image https://... baixa antes do envio (também em CARD_IMAGE/VERIFY_IMAGE)":                        "  --image - reads the image from stdin; --image https://... downloads it before sending (also in CARD_IMAGE/VERIFY_IMAGE)",
+	"S3/GCS (ENV): AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION, AWS_ENDPOINT_URL_S3;":                           "S3/GCS (ENV): AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION, AWS_ENDPOINT_URL_S3;",
+	"  GCS_ACCESS_TOKEN (ou GOOGLE_OAUTH_ACCESS_TOKEN), STORAGE_EMULATOR_HOST; sem credenciais, bucket público":                             "  GCS_ACCESS_TOKEN (or GOOGLE_OAUTH_ACCESS_TOKEN), STORAGE_EMULATOR_HOST; without credentials, public bucket",
+	"  sem AUTH_TOKEN/OAUTH_CLIENT_SECRET definidos, lê do keyring gravado pelo login (por perfil)":                                         "  without AUTH_TOKEN/OAUTH_CLIENT_SECRET set, reads from the keyring written by login (per profile)",
+	"  client credentials no lugar do AUTH_TOKEN; renova sozinho antes de vencer e em 401":                                                  "  client credentials instead of AUTH_TOKEN; renews itself before expiry and on 401",
+	"  assina toda requisição: HMAC de method, path, sha256 do corpo e timestamp (X-Signature + X-Timestamp)":                               "  signs every request: HMAC of method, path, body sha256 and timestamp (X-Signature + X-Timestamp)",
+	"Telemetria (opt-in): telemetry: {enabled: true, url: ...} no config ou BIODOC_TELEMETRY=1 + BIODOC_TELEMETRY_URL":                      "Telemetry (opt-in): telemetry: {enabled: true, url: ...} in the config or BIODOC_TELEMETRY=1 + BIODOC_TELEMETRY_URL",
+	"  envia só comando, nomes das flags, duração e categoria da falha; BIODOC_TELEMETRY=0 desliga":                                         "  sends only the command, flag names, duration and failure category; BIODOC_TELEMETRY=0 disables",
+	"Flags globais (qualquer posição):":                                                                                                              "Global flags (any position):",
+	"  -q, --quiet          - não imprime o corpo das respostas":                                                                                     "  -q, --quiet          - do not print response bodies",
+	"  -v, -vv, -vvv        - dump das requisições: tempos (DNS/connect/TLS/TTFB), headers, corpos (token mascarado)":                                "  -v, -vv, -vvv        - request dump: timings (DNS/connect/TLS/TTFB), headers, bodies (token masked)",
+	"  --output text|json   - json: um objeto por comando em stdout (texto vai para stderr)":                                                         "  --output text|json   - json: one object per command on stdout (text goes to stderr)",
+	"  --log-level NÍVEL    - log estruturado (slog) em stderr: debug (cada requisição), info, warn, error (ENV LOG_LEVEL)":                          "  --log-level LEVEL    - structured log (slog) on stderr: debug (every request), info, warn, error (ENV LOG_LEVEL)",
+	"  --log-format FMT     - text (chave=valor) ou json, um registro por linha (ENV LOG_FORMAT)":                                                    "  --log-format FMT     - text (key=value) or json, one record per line (ENV LOG_FORMAT)",
+	"  --log-file ARQ       - acrescenta o log ao arquivo no lugar do stderr (ENV LOG_FILE); o resumo segue no stdout":                               "  --log-file FILE      - appends the log to the file instead of stderr (ENV LOG_FILE); the summary stays on stdout",
+	"  --jq CAMINHO         - imprime só esse campo da última resposta (.response.id_Log, .items[0].id; repetível; também --extract)":                "  --jq PATH            - print only this field of the last response (.response.id_Log, .items[0].id; repeatable; also --extract)",
+	"  --junit ARQ.xml      - grava relatório JUnit (uma etapa por testcase)":                                                                        "  --junit FILE.xml     - write a JUnit report (one step per testcase)",
+	"  --notify-webhook URL - posta o resumo (ok/falhas, etapas com falha, links) no Slack/Teams (ENV NOTIFY_WEBHOOK)":                               "  --notify-webhook URL - post the summary (ok/failures, failed steps, links) to Slack/Teams (ENV NOTIFY_WEBHOOK)",
+	"  --notify-on failure  - só notifica quando o comando falha (default always; ENV NOTIFY_ON)":                                                    "  --notify-on failure  - only notify when the command fails (default always; ENV NOTIFY_ON)",
+	"  --report ARQ.html    - relatório HTML autocontido: etapas, similaridade, latências, ambiente (--report-images: miniaturas)":                   "  --report FILE.html   - self-contained HTML report: steps, similarity, latencies, environment (--report-images: thumbnails)",
+	"  --validate-schema    - falha a etapa se a resposta 2xx não bater com o schema embutido do endpoint (ENV VALIDATE_SCHEMA=1)":                   "  --validate-schema    - fail the step if the 2xx response does not match the endpoint's bundled schema (ENV VALIDATE_SCHEMA=1)",
+	"  --schema-spec ARQ    - valida contra a spec OpenAPI (YAML/JSON) no lugar dos embutidos (ENV SCHEMA_SPEC)":                                     "  --schema-spec FILE   - validate against the OpenAPI spec (YAML/JSON) instead of the bundled ones (ENV SCHEMA_SPEC)",
+	"  --no-progress        - sem barra de progresso (batch-create, batch-verify, load-verify); sem terminal já não aparece":                         "  --no-progress        - no progress bar (batch-create, batch-verify, load-verify); it never shows without a terminal",
+	"  --max-latency-p95 D  - falha se o p95 das respostas da API passar de D (também --max-latency-p99)":                                            "  --max-latency-p95 D  - fail if the p95 of API responses exceeds D (also --max-latency-p99)",
+	"  --slo LISTA          - SLOs que falham a execução, ex.: verify.p95=800ms,create.p99=2s,total.max=30s (ENV LATENCY_SLO)":                       "  --slo LIST           - SLOs that fail the run, e.g. verify.p95=800ms,create.p99=2s,total.max=30s (ENV LATENCY_SLO)",
+	"  --expect-status N    - falha (exit 1) se a última resposta não tiver esse status; com ele, o erro HTTP esperado passa":                        "  --expect-status N    - fail (exit 1) if the last response does not have this status; with it, the expected HTTP error passes",
+	"  --expect EXPR        - asserção no JSON da última resposta, repetível: 'response.success==true', '.response.percentage>=90'":                  "  --expect EXPR        - assertion on the last response's JSON, repeatable: 'response.success==true', '.response.percentage>=90'",
+	"  --expected-duration D - duração prevista; JWT que vence antes disso aborta (exit 3) antes de começar (ENV EXPECTED_DURATION)":                 "  --expected-duration D - expected run time; a JWT expiring before it aborts (exit 3) before starting (ENV EXPECTED_DURATION)",
+	"                         lotes, run-matrix, watch, serve, load-verify e soak já têm estimativa própria":                                         "                         batches, run-matrix, watch, serve, load-verify and soak have their own estimate",
+	"  --timing             - no fim, tempo por etapa e por endpoint":                                                                                "  --timing             - at the end, time per step and per endpoint",
+	"  --budget LISTA       - orçamentos, ex.: verify=1500ms,create=2s,total=10s (ENV TIMING_BUDGETS)":                                               "  --budget LIST        - budgets, e.g. verify=1500ms,create=2s,total=10s (ENV TIMING_BUDGETS)",
+	"  --metrics-addr ADDR  - expõe métricas Prometheus em http://ADDR/metrics durante a execução":                                                   "  --metrics-addr ADDR  - expose Prometheus metrics at http://ADDR/metrics during the run",
+	"  --pushgateway URL    - envia as métricas a um Pushgateway a cada 10s e no fim":                                                                "  --pushgateway URL    - push the metrics to a Pushgateway every 10s and at the end",
+	"  --profile NOME       - usa o perfil do ~/.biodoc-runner.yaml (ENV BIODOC_PROFILE, BIODOC_RUNNER_CONFIG)":                                      "  --profile NAME       - use the profile from ~/.biodoc-runner.yaml (ENV BIODOC_PROFILE, BIODOC_RUNNER_CONFIG)",
+	"  --tenant NOME        - token do tenant em tokens: do perfil (ENV BIODOC_TENANT); --token/--token-file dão o token direto":                     "  --tenant NAME        - token of the tenant in the profile's tokens: (ENV BIODOC_TENANT); --token/--token-file give the token directly",
+	"                         os três valem só para esta execução, na frente de AUTH_TOKEN, keyring e OAuth":                                         "                         all three apply to this run only, ahead of AUTH_TOKEN, keyring and OAuth",
+	"  --ttl D, --tag T     - cards criados levam tag e expiração no detail (ENV CARD_TTL, CARD_TAG)":                                                "  --ttl D, --tag T     - created cards carry a tag and expiry in detail (ENV CARD_TTL, CARD_TAG)",
+	"  --env-file ARQ       - carrega esse .env em vez do diretório atual (repetível; o último ganha)":                                               "  --env-file FILE      - load this .env instead of the current directory's (repeatable; the last one wins)",
+	"                         cifrado com age (chave em AGE_KEY_FILE/AGE_KEY) ou SOPS é decifrado na hora":                                           "                         age-encrypted (key in AGE_KEY_FILE/AGE_KEY) or SOPS files are decrypted on load",
+	"  --results ARQ.jsonl  - acrescenta cada execução ao results store (ENV RESULTS_STORE)":                                                         "  --results FILE.jsonl - append each run to the results store (ENV RESULTS_STORE)",
+	"  --proxy URL          - proxy HTTP(S) (ENV PROXY_URL; sem ele vale HTTPS_PROXY/NO_PROXY)":                                                      "  --proxy URL          - HTTP(S) proxy (ENV PROXY_URL; otherwise HTTPS_PROXY/NO_PROXY apply)",
+	"  --ca-cert ARQ.pem    - CAs extras (rede corporativa) somadas às do sistema (ENV CA_CERT)":                                                     "  --ca-cert FILE.pem   - extra CAs (corporate network) added to the system ones (ENV CA_CERT)",
+	"  --client-cert/--client-key ARQ.pem - certificado de cliente para mTLS (ENV CLIENT_CERT, CLIENT_KEY)":                                          "  --client-cert/--client-key FILE.pem - client certificate for mTLS (ENV CLIENT_CERT, CLIENT_KEY)",
+	"  --timeout DUR        - limite por requisição (ENV HTTP_TIMEOUT; 0 = sem limite). Padrão 20s; 60s em create-card,":                             "  --timeout DUR        - per-request limit (ENV HTTP_TIMEOUT; 0 = no limit). Default 20s; 60s for create-card,",
+	"                         main-image, update-card, batch-create, watch e serve; 5s no doctor":                                                    "                         main-image, update-card, batch-create, watch and serve; 5s for doctor",
+	"  --keep-alive DUR     - intervalo do TCP keep-alive; 0 desliga conexões persistentes (ENV HTTP_KEEP_ALIVE)":                                    "  --keep-alive DUR     - TCP keep-alive interval; 0 disables persistent connections (ENV HTTP_KEEP_ALIVE)",
+	"  --idle-timeout DUR   - tempo de uma conexão ociosa no pool (ENV HTTP_IDLE_TIMEOUT; padrão 90s)":                                               "  --idle-timeout DUR   - how long an idle connection stays pooled (ENV HTTP_IDLE_TIMEOUT; default 90s)",
+	"  --max-idle-conns N   - conexões ociosas guardadas, total e por host (ENV HTTP_MAX_IDLE_CONNS)":                                                "  --max-idle-conns N   - idle connections kept, total and per host (ENV HTTP_MAX_IDLE_CONNS)",
+	"  --tls-handshake-timeout DUR - teto do handshake TLS (ENV HTTP_TLS_HANDSHAKE_TIMEOUT; padrão 10s)":                                             "  --tls-handshake-timeout DUR - TLS handshake limit (ENV HTTP_TLS_HANDSHAKE_TIMEOUT; default 10s)",
+	"  --har ARQ.har        - grava todo o tráfego em HAR 1.2 (tokens mascarados; --har-full-images mantém imagens)":                                 "  --har FILE.har       - record all traffic as HAR 1.2 (tokens masked; --har-full-images keeps images)",
+	"  --bug-report ARQ.zip - se o comando falhar, gera zip com requisições/respostas, config, versão e log (sem tokens)":                            "  --bug-report FILE.zip - if the command fails, build a zip with requests/responses, config, version and log (no tokens)",
+	"  --max-dimension PX   - reduz a imagem para esse maior lado antes do envio (reencoda em JPEG)":                                                 "  --max-dimension PX   - shrink the image to this longest side before sending (re-encodes as JPEG)",
+	"  --max-bytes N        - baixa qualidade/resolução até a imagem caber em N bytes":                                                               "  --max-bytes N        - lower quality/resolution until the image fits in N bytes",
+	"  --jpeg-quality Q     - qualidade do JPEG reencodado, 1..100 (default 85)":                                                                     "  --jpeg-quality Q     - quality of the re-encoded JPEG, 1..100 (default 85)",
+	"  --watermark          - carimba \"TEST <run id>\" numa faixa na base das imagens enviadas (--watermark-text T)":                                "  --watermark          - stamp \"TEST <run id>\" on a strip at the bottom of sent images (--watermark-text T)",
+	"  --no-exif-fix        - não aplica a orientação do EXIF (por padrão a foto é endireitada e reencodada)":                                        "  --no-exif-fix        - do not apply the EXIF orientation (by default the photo is straightened and re-encoded)",
+	"  --strip-metadata     - remove EXIF/XMP/IPTC (GPS, aparelho) do JPEG antes do envio":                                                           "  --strip-metadata     - remove EXIF/XMP/IPTC (GPS, device) from the JPEG before sending",
+	"  --strict-quality     - aborta antes do envio se a imagem estiver escura, desfocada ou pequena (sem ela só avisa)":                             "  --strict-quality     - abort before sending if the image is dark, blurry or small (without it, only warn)",
+	"  --no-quality-check   - não mede a qualidade da imagem antes do envio":                                                                         "  --no-quality-check   - do not measure image quality before sending",
+	"  --require-single-face - detecta rostos localmente e recusa imagem sem rosto ou com mais de um":                                                "  --require-single-face - detect faces locally and reject images with no face or more than one",
+	"  --dry-run            - monta e imprime as requisições (token mascarado, imagem resumida) sem enviar; reap/history/delete-cards têm o próprio": "  --dry-run            - build and print the requests (token masked, image summarized) without sending; reap/history/delete-cards have their own",
+	"  --no-strict          - aceita argumentos soltos, imagem inexistente e AUTH_TOKEN ausente (só avisa)":                                          "  --no-strict          - accept stray arguments, missing image and missing AUTH_TOKEN (only warn)",
+	"  --record ARQ.json    - grava requisições/respostas num cassete (tokens mascarados)":                                                           "  --record FILE.json   - record requests/responses to a cassette (tokens masked)",
+	"  --replay ARQ.json    - responde a partir do cassete, sem rede (sem gravação → 501)":                                                           "  --replay FILE.json   - answer from the cassette, no network (no recording → 501)",
+	"  --rps N, --rpm N     - no máximo N requisições por segundo/minuto, somando todos os workers (ENV RATE_LIMIT_RPS, RATE_LIMIT_RPM)":             "  --rps N, --rpm N     - at most N requests per second/minute, across all workers (ENV RATE_LIMIT_RPS, RATE_LIMIT_RPM)",
+	"  --retries N          - total de tentativas por requisição (ENV RETRY_MAX, default 3)":                                                         "  --retries N          - total attempts per request (ENV RETRY_MAX, default 3)",
+	"  --retry-delay D      - espera base, dobra a cada tentativa (ENV RETRY_BASE_DELAY, default 500ms)":                                             "  --retry-delay D      - base wait, doubles on each attempt (ENV RETRY_BASE_DELAY, default 500ms)",
+	"  --retry-jitter F     - variação aleatória da espera, 0..1 (ENV RETRY_JITTER, default 0.2)":                                                    "  --retry-jitter F     - random variation of the wait, 0..1 (ENV RETRY_JITTER, default 0.2)",
+	"  --retry-on LISTA     - status que disparam retry (ENV RETRY_STATUS, default 502,503,504)":                                                     "  --retry-on LIST      - statuses that trigger a retry (ENV RETRY_STATUS, default 502,503,504)",
+	"  --retry-post         - repete também POST (cadastro pode duplicar; ENV RETRY_POST=1)":                                                         "  --retry-post         - retry POST too (a registration may be duplicated; ENV RETRY_POST=1)",
+	"  429 espera o Retry-After (ou backoff) sem gastar tentativa: ENV RETRY_429_MAX (default 5), RETRY_429_MAX_WAIT (default 2m)":                   "  429 waits for Retry-After (or backoff) without using an attempt: ENV RETRY_429_MAX (default 5), RETRY_429_MAX_WAIT (default 2m)",
+	"  --lang en|pt-BR      - idioma das mensagens (ENV BIODOC_LANG; sem ele, o locale em LANG)":                                                     "  --lang en|pt-BR      - message language (ENV BIODOC_LANG; otherwise the locale in LANG)",
+	"Exit codes: 0 ok, 1 outras falhas (cenário, --expect, golden, arquivo), 2 uso, 3 autenticação (401/403, sem token),":                            "Exit codes: 0 ok, 1 other failures (scenario, --expect, golden, file), 2 usage, 3 authentication (401/403, no token),",
+	"  4 não encontrado (404), 5 sem match/similaridade abaixo do mínimo, 6 rede/timeout, 7 erro do servidor (5xx)":                                  "  4 not found (404), 5 no match/similarity below the minimum, 6 network/timeout, 7 server error (5xx)",
+	"Exemplos:": "Examples:",
+
+	// matrix.go
+	"sem match":               "no match",
+	"nenhum id para rodar":    "no ids to run",
+	"id repetido na matriz: ": "duplicate id in the matrix: ",
+	" (ciclos em paralelo precisam de ids distintos)":                            " (parallel cycles need distinct ids)",
+	"[matrix] %d ciclo(s), %d em paralelo\n":                                     "[matrix] %d cycle(s), %d in parallel\n",
+	"não rodou: matriz abortada pelo circuit breaker":                            "not run: matrix aborted by the circuit breaker",
+	"[matrix] total=%d pass=%d fail=%d em %s\n":                                  "[matrix] total=%d pass=%d fail=%d in %s\n",
+	"%d de %d ciclo(s) falharam":                                                 "%d of %d cycle(s) failed",
+	"ID\tPRECLEAN\tCREATE\tVERIFY\tSIMILARIDADE\tDELETE\tTOTAL\tRESULTADO\tERRO": "ID\tPRECLEAN\tCREATE\tVERIFY\tSIMILARITY\tDELETE\tTOTAL\tRESULT\tERROR",
+
+	// metrics.go
+	"[metrics] Prometheus em http://%s/metrics\n": "[metrics] Prometheus at http://%s/metrics\n",
+	"[metrics] pushgateway respondeu %d\n":        "[metrics] pushgateway returned %d\n",
+
+	// mock*.go
+	"--clock deve ser real ou sim, veio %q":                                         "--clock must be real or sim, got %q",
+	"[mock] ouvindo em http://%s (relógio %s)\n":                                    "[mock] listening on http://%s (clock %s)\n",
+	"[mock] ouvindo em https://%s (relógio %s)\n":                                   "[mock] listening on https://%s (clock %s)\n",
+	"gerar certificado: %w":                                                         "generate certificate: %w",
+	"[mock] certificado salvo em %s\n":                                              "[mock] certificate saved to %s\n",
+	", exige JWT dentro do exp":                                                     ", requires an unexpired JWT",
+	", exige bearer token":                                                          ", requires bearer token",
+	", cert vencido":                                                                ", expired cert",
+	"--clock-start inválido (use RFC3339): %w":                                      "invalid --clock-start (use RFC3339): %w",
+	"--fail %s: taxa deve estar entre 0 e 1, veio %q":                               "--fail %s: rate must be between 0 and 1, got %q",
+	"--fail %s: status deve ser 4xx/5xx, veio %q":                                   "--fail %s: status must be 4xx/5xx, got %q",
+	"--fault %s: taxa deve estar entre 0 e 1, veio %q":                              "--fault %s: rate must be between 0 and 1, got %q",
+	"falha desconhecida %q (use %s)":                                                "unknown fault %q (use %s)",
+	"latência inválida %q (use fixed:200ms, normal:200ms,50ms ou pareto:100ms,1.5)": "invalid latency %q (use fixed:200ms, normal:200ms,50ms or pareto:100ms,1.5)",
+	"tamanho inválido %q (ex.: 512, 64KB, 1.5MB)":                                   "invalid size %q (e.g. 512, 64KB, 1.5MB)",
+	"scoring fixed:N com N entre 0 e 100, veio %q":                                  "scoring fixed:N with N between 0 and 100, got %q",
+	"data URI sem vírgula":                                                          "data URI without a comma",
+	"payload inválido":                                                              "invalid payload",
+	"imagem inválida":                                                               "invalid image",
+	"set %s está antes do relógio atual %s: o relógio não volta":                    "set %s is before the current clock %s: the clock does not go back",
+	"informe expiresIn (ex.: -5m vencido, 30s quase vencendo)":                      "give expiresIn (e.g. -5m expired, 30s about to expire)",
+	"scoring random:MIN-MAX (0..100), veio %q":                                      "scoring random:MIN-MAX (0..100), got %q",
+	"scoring desconhecido %q (use fixed:N, random:MIN-MAX ou phash)":                "unknown scoring %q (use fixed:N, random:MIN-MAX or phash)",
+	"stubs %s: esperado lista de stubs: %w":                                         "stubs %s: expected a list of stubs: %w",
+	"status inválido: %d":                                                           "invalid status: %d",
+	"failRate deve estar entre 0 e 1":                                               "failRate must be between 0 and 1",
+	"delay inválido: %q":                                                            "invalid delay: %q",
+
+	// normalize.go
+	"(raiz)":                 "(root)",
+	"%s: (ausente) → %s":     "%s: (missing) → %s",
+	"%s: %s → (ausente)":     "%s: %s → (missing)",
+	"entrada não é JSON: %w": "input is not JSON: %w",
+	"regras %s: %w":          "rules %s: %w",
+
+	// notify.go
+	"FALHOU (exit %d)":                  "FAILED (exit %d)",
+	"%s %s %s em %s — %s\n":             "%s %s %s in %s — %s\n",
+	"%d etapa(s): %d ok, %d com falha":  "%d step(s): %d ok, %d failed",
+	", %d pulada(s)":                    ", %d skipped",
+	"• … e mais %d\n":                   "• … and %d more\n",
+	"[notify] envio falhou: %v\n":       "[notify] sending failed: %v\n",
+	"[notify] webhook respondeu %d\n":   "[notify] webhook returned %d\n",
+	"[notify] resumo enviado para %s\n": "[notify] summary sent to %s\n",
+	"execução no CI":                    "CI run",
+	"relatório":                         "report",
+
+	// oauth.go
+	"oauth: pedir token: %w":                                               "oauth: request token: %w",
+	"oauth: ler resposta: %w":                                              "oauth: read response: %w",
+	"sem access_token na resposta":                                         "no access_token in the response",
+	"oauth: token endpoint respondeu %d (%s)":                              "oauth: token endpoint returned %d (%s)",
+	"[oauth] token obtido, vence em %s\n":                                  "[oauth] token obtained, expires in %s\n",
+	"[oauth] %v; usando o token anterior\n":                                "[oauth] %v; using the previous token\n",
+	"OAUTH_TOKEN_URL definido: faltam OAUTH_CLIENT_ID/OAUTH_CLIENT_SECRET": "OAUTH_TOKEN_URL set: missing OAUTH_CLIENT_ID/OAUTH_CLIENT_SECRET",
+
+	// openapi.go
+	"spec %s: documento não é um objeto":  "spec %s: document is not an object",
+	"spec %s: sem \"paths\" (é OpenAPI?)": "spec %s: no \"paths\" (is it OpenAPI?)",
+	"$ref externo não suportado: %s":      "external $ref not supported: %s",
+	"$ref inválido: %s":                   "invalid $ref: %s",
+	"$ref não encontrado: %s":             "$ref not found: %s",
+	"$ref não aponta para um objeto: %s":  "$ref does not point to an object: %s",
+
+	// output.go
+	"--output deve ser text ou json, veio %q": "--output must be text or json, got %q",
+
+	// profile.go
+	"(BASE_URL padrão)": "(default BASE_URL)",
+	"perfil %q não encontrado em %s (disponíveis: %s)":                   "profile %q not found in %s (available: %s)",
+	"[profile] aviso: %s vazio (token_env do perfil %s)\n":               "[profile] warning: %s empty (token_env of profile %s)\n",
+	"use só um de --token, --token-file e --tenant":                      "use only one of --token, --token-file and --tenant",
+	"--token-file: %s está vazio":                                        "--token-file: %s is empty",
+	"--tenant %s: nenhum perfil ativo (use --profile ou default: em %s)": "--tenant %s: no active profile (use --profile or default: in %s)",
+	"tenant %q não está em tokens: do perfil %s (disponíveis: %s)":       "tenant %q is not in tokens: of profile %s (available: %s)",
+	"tenant %s do perfil %s sem token":                                   "tenant %s of profile %s has no token",
+	"[tenant] %s (perfil %s)\n":                                          "[tenant] %s (profile %s)\n",
+	"perfil %s: token_file: %w":                                          "profile %s: token_file: %w",
+	"perfil %q: %w":                                                      "profile %q: %w",
+	"perfil %s sem base_url":                                             "profile %s has no base_url",
+	"perfil %q pedido, mas %s não existe":                                "profile %q requested, but %s does not exist",
+
+	// progress.go
+	"%d enviadas":                   "%d sent",
+	" · erros %d (%.1f%%) · %.1f/s": " · errors %d (%.1f%%) · %.1f/s",
+
+	// proxy.go
+	"--target inválido: %q":                                                 "invalid --target: %q",
+	"[proxy] falha ao gravar cassete: %v\n":                                 "[proxy] failed to write cassette: %v\n",
+	"[replay] ouvindo em http://%s, %d interações de %s (body=%s)\n":        "[replay] listening on http://%s, %d interactions from %s (body=%s)\n",
+	"[replay] sem gravação → %s\n":                                          "[replay] no recording → %s\n",
+	"[replay] interações novas vão para %s\n":                               "[replay] new interactions go to %s\n",
+	"[replay] %d gravações não usadas\n":                                    "[replay] %d recordings not used\n",
+	"[proxy] ouvindo em http://%s → %s\n":                                   "[proxy] listening on http://%s → %s\n",
+	"[proxy] gravando cassete em %s (segredos mascarados=%v, imagens=%v)\n": "[proxy] recording cassette to %s (secrets masked=%v, images=%v)\n",
+	"resposta maior que %d bytes":                                           "response larger than %d bytes",
+	"[proxy] %s %s → erro: %v\n":                                            "[proxy] %s %s → error: %v\n",
+	"[replay] %s %s → sem gravação (%s), repassando\n":                      "[replay] %s %s → no recording (%s), forwarding\n",
+	"[replay] %s %s → sem gravação (%s)\n":                                  "[replay] %s %s → no recording (%s)\n",
+	"[proxy] encerrado":                                                     "[proxy] stopped",
+	"[chaos] injetando falhas (seed=%d; mesma seed = mesmo sorteio para a mesma sequência de requisições)\n": "[chaos] injecting faults (seed=%d; same seed = same draws for the same request sequence)\n",
+
+	// proxychaos.go
+	"--latency %s: taxa deve estar entre 0 e 1, veio %q":                "--latency %s: rate must be between 0 and 1, got %q",
+	"--reset %s: taxa deve estar entre 0 e 1, veio %q":                  "--reset %s: rate must be between 0 and 1, got %q",
+	"--reset %s: use before ou after, veio %q":                          "--reset %s: use before or after, got %q",
+	"reset (depois da API)":                                             "reset (after the API)",
+	"[chaos] nenhuma falha injetada (seed=%d)\n":                        "[chaos] no faults injected (seed=%d)\n",
+	"[chaos] falhas injetadas: %s (seed=%d)\n":                          "[chaos] faults injected: %s (seed=%d)\n",
+	"[proxy] %-9s n=%d erros=%d status=%s p50=%dms p95=%dms max=%dms\n": "[proxy] %-9s n=%d errors=%d status=%s p50=%dms p95=%dms max=%dms\n",
+	"[proxy] %d interações em %s\n":                                     "[proxy] %d interactions in %s\n",
+
+	// quality.go
+	"qualidade de %s: %s (--strict-quality)":             "quality of %s: %s (--strict-quality)",
+	"resolução baixa (%dx%d, mínimo %dpx no menor lado)": "low resolution (%dx%d, minimum %dpx on the shortest side)",
+	"desfocada (nitidez %.0f < %.0f)":                    "blurry (sharpness %.0f < %.0f)",
+	"escura (brilho %.0f < %.0f)":                        "dark (brightness %.0f < %.0f)",
+	"estourada (brilho %.0f > %.0f)":                     "overexposed (brightness %.0f > %.0f)",
+	"[quality] %s: %dx%d brilho=%.0f nitidez=%.0f\n":     "[quality] %s: %dx%d brightness=%.0f sharpness=%.0f\n",
+
+	// ratelimit.go
+	"[rate] %d requisição(ões) seguradas pelo limite (espera total %s)": "[rate] %d request(s) held by the limit (total wait %s)",
+	"use --rps ou --rpm, não os dois":                                   "use --rps or --rpm, not both",
+	"limite de taxa inválido: %q (número > 0)":                          "invalid rate limit: %q (number > 0)",
+
+	// expect.go
+	"--expect inválido %q (use caminho==valor; operadores %s)": "invalid --expect %q (use path==value; operators %s)",
+	"%s %s exige números, veio %v":                             "%s %s requires numbers, got %v",
+	"--expect: nenhuma resposta HTTP para conferir":            "--expect: no HTTP response to check",
+	"status %d, esperado %d":                                   "status %d, expected %d",
+	"[expect] %s status==%d (veio %d)\n":                       "[expect] %s status==%d (got %d)\n",
+	"--expect: resposta não é JSON":                            "--expect: response is not JSON",
+	"valor %v":                                                 "value %v",
+	"asserção falhou: ":                                        "assertion failed: ",
+	"--expect-status inválido: %q":                             "invalid --expect-status: %q",
+
+	// extract.go
+	"--jq: nenhuma resposta para extrair": "--jq: no response to extract from",
+	"--jq: resposta não é JSON":           "--jq: response is not JSON",
+	"--jq: campo %s ausente na resposta":  "--jq: field %s missing from the response",
+
+	// deletecards.go
+	"--prefix inválido %q: %v": "invalid --prefix %q: %v",
+	"--prefix %q começa com curinga: informe o início literal dos ids (ex.: 9998*)": "--prefix %q starts with a wildcard: give the literal start of the ids (e.g. 9998*)",
+	"[delete-cards] %d card(s) listados, %d casam com %s\n":                         "[delete-cards] %d card(s) listed, %d match %s\n",
+	"use --ids-file ou --prefix, não os dois":                                       "use --ids-file or --prefix, not both",
+	"--ids-file ou --prefix é obrigatório":                                          "--ids-file or --prefix is required",
+	"[delete-cards] nenhum id para apagar\n":                                        "[delete-cards] no id to delete\n",
+	"[delete-cards] id=%s (dry-run, não apagado)\n":                                 "[delete-cards] id=%s (dry-run, not deleted)\n",
+	"[delete-cards] %d card(s) seriam apagados\n":                                   "[delete-cards] %d card(s) would be deleted\n",
+	"[delete-cards] %d id(s), %d worker(s)\n":                                       "[delete-cards] %d id(s), %d worker(s)\n",
+	"[delete-cards] total=%d apagados=%d já-inexistentes=%d falhas=%d em %s\n":      "[delete-cards] total=%d deleted=%d already-missing=%d failed=%d in %s\n",
+
+	// reap.go
+	"[reap] id=%s tag=%s venceu em %s (dry-run, não apagado)\n":    "[reap] id=%s tag=%s expired at %s (dry-run, not deleted)\n",
+	"[reap] id=%s falhou: %v\n":                                    "[reap] id=%s failed: %v\n",
+	"[reap] id=%s tag=%s venceu em %s, apagado\n":                  "[reap] id=%s tag=%s expired at %s, deleted\n",
+	"[reap] id=%s falhou: status %d\n":                             "[reap] id=%s failed: status %d\n",
+	"[reap] %d cards analisados, %d vencidos, %d ainda no prazo\n": "[reap] %d cards checked, %d expired, %d still within TTL\n",
+	"falha ao apagar %d card(s): %s":                               "failed to delete %d card(s): %s",
+	"CARD_TTL inválido: %q":                                        "invalid CARD_TTL: %q",
+	"--ttl inválido: %q (ex.: 24h)":                                "invalid --ttl: %q (e.g. 24h)",
+
+	// recordreplay.go
+	"[replay] %d interações de %s (sem rede)\n": "[replay] %d interactions from %s (no network)\n",
+	"[record] falha ao gravar cassete: %v\n":    "[record] failed to write cassette: %v\n",
+
+	// rehearse.go
+	"ensaio: %w": "rehearsal: %w",
+	"[rehearse] ensaio de %s contra o mock em %s\n":                 "[rehearse] rehearsing %s against the mock at %s\n",
+	"ensaio contra o mock falhou, ambiente real não foi tocado: %w": "rehearsal against the mock failed, the real environment was not touched: %w",
+	"[rehearse] ✅ ensaio ok em %s; seguindo para %s\n":              "[rehearse] ✅ rehearsal ok in %s; moving on to %s\n",
+
+	// results.go
+	"[results] %s:%d ignorada: %v\n": "[results] %s:%d skipped: %v\n",
+
+	// retry.go
+	"RETRY_429_MAX inválido: %q":                "invalid RETRY_429_MAX: %q",
+	"RETRY_429_MAX_WAIT inválido: %q (ex.: 2m)": "invalid RETRY_429_MAX_WAIT: %q (e.g. 2m)",
+	"[429] %d resposta(s) 429, espera total %s": "[429] %d 429 response(s), total wait %s",
+	"retries inválido: %q (mínimo 1)":           "invalid retries: %q (minimum 1)",
+	"retry-delay inválido: %q (ex.: 500ms, 2s)": "invalid retry-delay: %q (e.g. 500ms, 2s)",
+	"retry-jitter inválido: %q (entre 0 e 1)":   "invalid retry-jitter: %q (between 0 and 1)",
+	"retry-on inválido: %q":                     "invalid retry-on: %q",
+
+	// pipeline.go
+	"pipeline %q não existe em %s (disponíveis: %s)": "pipeline %q does not exist in %s (available: %s)",
+	"[pipeline] nenhuma pipeline em %s\n":            "[pipeline] no pipeline in %s\n",
+	"pipeline %s falhou em: %s":                      "pipeline %s failed at: %s",
+	"✅ pipeline completa: %s\n":                      "✅ pipeline complete: %s\n",
+	"pipeline nomeada do config":                     "named pipeline from the config",
+
+	// scenario.go
+	"%s, etapa %d: tipo desconhecido %q":                                    "%s, step %d: unknown type %q",
+	"%s, etapa %d: request exige path":                                      "%s, step %d: request requires path",
+	"%s, etapa %d: when deve ser on_success, always ou on_failure, veio %q": "%s, step %d: when must be on_success, always or on_failure, got %q",
+	"%s, etapa %d: retries negativo":                                        "%s, step %d: negative retries",
+	"cenário ":                                                              "scenario ",
+	"[%s] tentativa %d/%d falhou: %v; de novo em %s\n":                      "[%s] attempt %d/%d failed: %v; retrying in %s\n",
+	"status %d, esperado 2xx":                                               "status %d, expected 2xx",
+	"status %d, esperado %v":                                                "status %d, expected %v",
+	"resposta não é JSON: %w":                                               "response is not JSON: %w",
+	"campo %s ausente":                                                      "field %s missing",
+	"campo %s = %v, esperado %v":                                            "field %s = %v, expected %v",
+	"resposta sem similaridade":                                             "response without similarity",
+	"similaridade %.2f acima do máximo %.2f":                                "similarity %.2f above the maximum %.2f",
+	"golden: resposta não é JSON: %w":                                       "golden: response is not JSON: %w",
+	"[golden] %s atualizado\n":                                              "[golden] %s updated\n",
+	"golden: %w (rode com --update-golden para criar)":                      "golden: %w (run with --update-golden to create it)",
+	"golden %s inválido: %w":                                                "invalid golden %s: %w",
+	"resposta difere do golden %s (%d diferenças)":                          "response differs from golden %s (%d differences)",
+	"[%s] %s (%d etapas)\n":                                                 "[%s] %s (%d steps)\n",
+	"[%s] perfil %s → %s\n":                                                 "[%s] profile %s → %s\n",
+	"cenário falhou em: %s":                                                 "scenario failed at: %s",
+	"✅ cenário completo: %s\n":                                              "✅ scenario complete: %s\n",
+	"use --var nome=valor":                                                  "use --var name=value",
+	"cenário %s: %w":                                                        "scenario %s: %w",
+	"%s sem etapas":                                                         "%s has no steps",
+
+	// schema.go
+	"… e mais %d": "… and %d more",
+	"%s: não bate com nenhuma opção de %s":   "%s: matches none of the %s options",
+	"%s: esperado %v, veio %s":               "%s: expected %v, got %s",
+	"%s: %v fora do enum %v":                 "%s: %v not in enum %v",
+	"%s: campo obrigatório %q ausente":       "%s: required field %q missing",
+	"%s: campo %q não previsto":              "%s: unexpected field %q",
+	"%s: %v < mínimo %v":                     "%s: %v < minimum %v",
+	"%s: %v > máximo %v":                     "%s: %v > maximum %v",
+	"%s: tamanho %d < minLength %v":          "%s: length %d < minLength %v",
+	"%s: tamanho %d > maxLength %v":          "%s: length %d > maxLength %v",
+	"%s: %q não casa com %s":                 "%s: %q does not match %s",
+	"resposta de %s fora do schema (%s): %s": "response from %s does not match the schema (%s): %s",
+	"JSON inválido: ":                        "invalid JSON: ",
+
+	// timetravel.go
+	"%s respondeu %d (%s): o destino é um mock-server?":                                                    "%s answered %d (%s): is the target a mock-server?",
+	"[%s] relógio do mock → %s\n":                                                                          "[%s] mock clock → %s\n",
+	"[%s] JWT forjado: exp=%s (relógio do mock %s)\n":                                                      "[%s] crafted JWT: exp=%s (mock clock %s)\n",
+	"expect.refreshed: sem OAuth (OAUTH_TOKEN_URL) o token nunca é renovado":                               "expect.refreshed: without OAuth (OAUTH_TOKEN_URL) the token is never renewed",
+	"esperava 401 → renovação do token nesta etapa, e não houve":                                           "expected a 401 → token renewal in this step, and there was none",
+	"o token foi renovado %d vez(es) nesta etapa, esperado nenhuma":                                        "the token was renewed %d time(s) in this step, expected none",
+	"--expires-in é obrigatório (ex.: -5m vencido, 30s quase vencendo)":                                    "--expires-in is required (e.g. -5m expired, 30s about to expire)",
+	"[jwt] exp=%s (relógio do mock %s)\n":                                                                  "[jwt] exp=%s (mock clock %s)\n",
+	"%s, etapa %d: clock exige advance ou set (um dos dois)":                                               "%s, step %d: clock requires advance or set (exactly one)",
+	"%s, etapa %d: token exige expiresIn (ex.: -5m)":                                                       "%s, step %d: token requires expiresIn (e.g. -5m)",
+	"  craft-jwt     - Pede ao mock-server um JWT vencido ou quase (--expires-in -5m); imprime só o token": "  craft-jwt     - Ask the mock-server for an expired or nearly expired JWT (--expires-in -5m); prints only the token",
+	"URL do mock-server que forja o token (exp no relógio dele)":                                           "URL of the mock-server that crafts the token (exp on its clock)",
+	"exp relativo ao relógio do mock: -5m = vencido, 30s = quase vencendo (obrigatório)":                   "exp relative to the mock clock: -5m = expired, 30s = about to expire (required)",
+	"claim sub do token":                      "token sub claim",
+	"JWT vencido ou quase, forjado pelo mock": "expired or nearly expired JWT, crafted by the mock",
+	"validade dos tokens emitidos em POST /oauth/token (no relógio do mock)": "lifetime of tokens issued by POST /oauth/token (on the mock clock)",
+
+	// liveness.go
+	"--video: formato não suportado %q (use mp4, mov, webm ou avi)":                                            "--video: unsupported format %q (use mp4, mov, webm or avi)",
+	"--video: %s tem %d MB; o limite é %d MB (use um clipe curto)":                                             "--video: %s is %d MB; the limit is %d MB (use a short clip)",
+	"use --image ou --video, não os dois":                                                                      "use --image or --video, not both",
+	"resposta inválida, não dá para ler o score: %w":                                                           "invalid response, cannot read the score: %w",
+	"score ausente ou inválido em %s":                                                                          "score missing or invalid at %s",
+	"score ausente na resposta (tente --score-field)":                                                          "score missing from the response (try --score-field)",
+	"[liveness] %s score=%.2f (mín %.2f, campo %s) | live=%s\n":                                                "[liveness] %s score=%.2f (min %.2f, field %s) | live=%s\n",
+	"liveness reprovado: a API marcou a captura como não viva (live=false)":                                    "liveness failed: the API flagged the capture as not live (live=false)",
+	"score de liveness %.2f abaixo do mínimo %.2f":                                                             "liveness score %.2f below the minimum %.2f",
+	"  liveness-check - Prova de vida com selfie ou vídeo curto; falha se o score ficar abaixo de --min-score": "  liveness-check - Liveness check with a selfie or short video; fails if the score is below --min-score",
+	"path da rota de liveness":                                                                                 "liveness route path",
+	"selfie para a prova de vida (default: VERIFY_IMAGE)":                                                      "selfie for the liveness check (default: VERIFY_IMAGE)",
+	"vídeo curto (mp4, mov, webm ou avi; até 20 MB) no lugar de --image":                                       "short video (mp4, mov, webm or avi; up to 20 MB) instead of --image",
+	"id do card a que a prova de vida se refere (opcional)":                                                    "id of the card the liveness check refers to (optional)",
+	"falha (exit 5) se o score (0-100) ficar abaixo disso ou a API responder live=false":                       "fail (exit 5) if the score (0-100) is below this or the API answers live=false",
+	"escala do score na resposta: 100 (0-100) ou 1 (0-1, levado a 0-100)":                                      "score scale in the response: 100 (0-100) or 1 (0-1, scaled to 0-100)",
+	"score %v em %s fora da escala 0-%v (ajuste --score-scale)":                                                "score %v at %s outside the 0-%v scale (adjust --score-scale)",
+	"--score-scale deve ser 1 ou 100, veio %v":                                                                 "--score-scale must be 1 or 100, got %v",
+	"caminho do score na resposta (ex.: response.liveness.score); vazio = score, livenessScore, percentage...": "score path in the response (e.g. response.liveness.score); empty = score, livenessScore, percentage...",
+	"prova de vida (anti-spoofing)":                                                                            "liveness check (anti-spoofing)",
+
+	// document.go
+	"--image vazio: informe a foto do documento (ou DOCUMENT_IMAGE)":                                 "empty --image: pass the document photo (or DOCUMENT_IMAGE)",
+	"resposta inválida, não dá para ler os campos do documento: %w":                                  "invalid response, cannot read the document fields: %w",
+	"[document] nome=%q (%s) | número=%q (%s)\n":                                                     "[document] name=%q (%s) | number=%q (%s)\n",
+	"%s: esperado %q, extraído %q":                                                                   "%s: expected %q, extracted %q",
+	"[document] %s %s: esperado %q, extraído %q\n":                                                   "[document] %s %s: expected %q, extracted %q\n",
+	"documento não confere: ":                                                                        "document does not match: ",
+	"  verify-document - Lê nome e número de um documento (OCR); --expect-name/--expect-id conferem": "  verify-document - Read name and number from a document (OCR); --expect-name/--expect-id cross-check them",
+	"path da rota de leitura de documento":                                                           "document-reading route path",
+	"foto do documento (ENV DOCUMENT_IMAGE)":                                                         "document photo (ENV DOCUMENT_IMAGE)",
+	"nome esperado; falha (exit 5) se o extraído não conferir (ignora acento e caixa)":               "expected name; fail (exit 5) if the extracted one does not match (ignores accents and case)",
+	"número do documento esperado; falha (exit 5) se não conferir (ignora pontuação)":                "expected document number; fail (exit 5) if it does not match (ignores punctuation)",
+	"caminho do nome na resposta (vazio = response.fields.name, name...)":                            "name path in the response (empty = response.fields.name, name...)",
+	"caminho do número na resposta (vazio = response.fields.documentNumber, cpf...)":                 "number path in the response (empty = response.fields.documentNumber, cpf...)",
+	"lê e confere um documento (OCR)":                                                                "read and cross-check a document (OCR)",
+
+	// hmacsign.go
+	"HMAC_FIELDS: campo desconhecido %q (use method, path, body, timestamp)": "HMAC_FIELDS: unknown field %q (use method, path, body, timestamp)",
+	"HMAC_ALGORITHM inválido: %q (use sha256 ou sha512)":                     "invalid HMAC_ALGORITHM: %q (use sha256 or sha512)",
+	"HMAC_ENCODING inválido: %q (use hex ou base64)":                         "invalid HMAC_ENCODING: %q (use hex or base64)",
+	"hmac: hash do corpo: %w":                                                "hmac: body hash: %w",
+
+	// secretsfile.go
+	"%s decifrado não é um .env válido: %v":                                                    "decrypted %s is not a valid .env: %v",
+	"[secrets] %s decifrado (%s, %d variáveis)\n":                                              "[secrets] %s decrypted (%s, %d variables)\n",
+	"arquivo cifrado com age: defina AGE_KEY_FILE (ou AGE_KEY com a chave AGE-SECRET-KEY-...)": "age-encrypted file: set AGE_KEY_FILE (or AGE_KEY with the AGE-SECRET-KEY-... key)",
+	"%s está cifrado com age: instale o age (https://age-encryption.org) no PATH":              "%s is age-encrypted: install age (https://age-encryption.org) on the PATH",
+	"%s está cifrado com SOPS: instale o sops no PATH":                                         "%s is SOPS-encrypted: install sops on the PATH",
+	"decifrar %s (%s): %s": "decrypt %s (%s): %s",
+
+	// serve.go
+	"[serve] ⚠ %s aceita conexões de fora sem --api-key: qualquer um na rede usa o token do runner\n":       "[serve] ⚠ %s accepts outside connections without --api-key: anyone on the network can use the runner's token\n",
+	"[serve] ouvindo em http://%s → %s (POST /create, POST /verify, DELETE /delete/{id}); Ctrl+C encerra\n": "[serve] listening on http://%s → %s (POST /create, POST /verify, DELETE /delete/{id}); Ctrl+C stops\n",
+
+	// sla.go
+	"todos os endpoints": "all endpoints",
+	"[sla] %d execuções, %d requisições (%s), alvo %.2f%%\n":                "[sla] %d runs, %d requests (%s), target %.2f%%\n",
+	"período\treq\tfalhas\tdisponib.\tp50\tp95\torçamento usado\t\n":        "period\treq\tfailures\tavailab.\tp50\tp95\tbudget used\t\n",
+	"[sla] orçamento de erro: %.2f falhas permitidas, %d usadas (%.1f%%)\n": "[sla] error budget: %.2f failures allowed, %d used (%.1f%%)\n",
+	"[sla] CSV em %s\n":                             "[sla] CSV at %s\n",
+	"data inválida %q (use 2006-01-02 ou RFC3339)":  "invalid date %q (use 2006-01-02 or RFC3339)",
+	"--by deve ser hour ou day":                     "--by must be hour or day",
+	"--target deve estar entre 0 e 100 (ex.: 99.5)": "--target must be between 0 and 100 (e.g. 99.5)",
+	"nenhuma requisição no período em %s":           "no requests in the period in %s",
+
+	// slo.go
+	"[slo]\tn\tmedido\tlimite\t\n":                 "[slo]\tn\tmeasured\tlimit\t\n",
+	"  %s\t0\t-\t%s\tsem amostras\n":               "  %s\t0\t-\t%s\tno samples\n",
+	"SLO de latência violado: ":                    "latency SLO breached: ",
+	"SLO inválido %q (use alvo.p95=800ms)":         "invalid SLO %q (use target.p95=800ms)",
+	"SLO inválido %q: percentil %q":                "invalid SLO %q: percentile %q",
+	"SLO inválido %q: use p50, p95, p99... ou max": "invalid SLO %q: use p50, p95, p99... or max",
+
+	// strict.go
+	" (--no-strict ignora)\n":                      " (--no-strict ignores it)\n",
+	"argumento inesperado: %q":                     "unexpected argument: %q",
+	"imagem não encontrada: %s":                    "image not found: %s",
+	"--image %q casou %d imagens; %s usa uma só\n": "--image %q matched %d images; %s takes only one\n",
+
+	// multiverify.go
+	"--id é obrigatório com várias imagens":               "--id is required with several images",
+	"[verify] %d imagem(ns) contra o id=%s (exige: %s)\n": "[verify] %d image(s) against id=%s (requires: %s)\n",
+	"não": "no",
+	"sim": "yes",
+	"[verify] %s veredito: %d/%d passaram | maioria=%s | exige=%s\n": "[verify] %s verdict: %d/%d passed | majority=%s | requires=%s\n",
+	"veredito agregado: %d de %d imagem(ns) passaram (exige %s)":     "aggregate verdict: %d of %d image(s) passed (requires %s)",
+
+	// objectstore.go
+	"URL de bucket inválida %q (use s3://bucket/prefixo ou gs://bucket/prefixo)": "invalid bucket URL %q (use s3://bucket/prefix or gs://bucket/prefix)",
+	"listando %s: %w":              "listing %s: %w",
+	"objeto passa de %d MB":        "object exceeds %d MB",
+	"resposta do S3 inválida: %w":  "invalid S3 response: %w",
+	"resposta do GCS inválida: %w": "invalid GCS response: %w",
+
+	// imagecompare.go
+	"%s não decodifica: %v":                                                                         "%s does not decode: %v",
+	"main-image: a imagem baixada não decodifica: %v":                                               "main-image: the downloaded image does not decode: %v",
+	"[compare] %s %s: dHash distância=%d (máx %d) | SSIM=%.3f (mín %.2f)\n":                         "[compare] %s %s: dHash distance=%d (max %d) | SSIM=%.3f (min %.2f)\n",
+	"a imagem guardada diverge de %s (distância %d, SSIM %.3f): a API pode ter guardado outra foto": "the stored image diverges from %s (distance %d, SSIM %.3f): the API may have stored another photo",
+
+	// imagepath.go
+	"glob inválido %q: %v":       "invalid glob %q: %v",
+	"nenhuma imagem casa com %q": "no image matches %q",
+	"--image - só pode aparecer uma vez (o stdin é lido uma vez só)": "--image - can only appear once (stdin is read only once)",
+	"lendo a imagem do stdin: %w":                                    "reading the image from stdin: %w",
+	"--image %s: nenhum byte recebido":                               "--image %s: no bytes received",
+	"--image %s: imagem passa de %d MB":                              "--image %s: image exceeds %d MB",
+	"--image %s: conteúdo não é imagem (%s)":                         "--image %s: content is not an image (%s)",
+	"baixando %s: %w":                                                "downloading %s: %w",
+
+	// telemetry.go
+	"[telemetry] envio falhou: %v\n": "[telemetry] sending failed: %v\n",
+
+	// tokeninfo.go
+	"AUTH_TOKEN não definido (ENV, --profile, login ou OAUTH_*)":                      "AUTH_TOKEN not set (ENV, --profile, login or OAUTH_*)",
+	"[token] opaco (%d caracteres, não é JWT): emissor e validade não verificáveis\n": "[token] opaque (%d characters, not a JWT): issuer and expiry cannot be checked\n",
+	"emitido":             "issued",
+	"válido de":           "not before",
+	"expira":              "expires",
+	"sem exp":             "no exp",
+	"%s (expirou há %s)":  "%s (expired %s ago)",
+	"%s (em %s)":          "%s (in %s)",
+	"token expirou em %s": "token expired at %s",
+	"token expirou há %s": "token expired %s ago",
+	"token expira em %s, antes do fim esperado da execução (~%s)":           "token expires in %s, before the expected end of the run (~%s)",
+	"; renove (login/OAuth), ajuste --expected-duration ou use --no-strict": "; renew it (login/OAuth), adjust --expected-duration or use --no-strict",
+	"[aviso] %s\n": "[warning] %s\n",
+
+	// timing.go
+	"[timing]\tn\ttotal\tmédia\tp95\tmáx\torçamento\t\n": "[timing]\tn\ttotal\tavg\tp95\tmax\tbudget\t\n",
+	"etapa ":                              "step ",
+	"[timing] ⚠ acima do orçamento: %s\n": "[timing] ⚠ over budget: %s\n",
+	"orçamento inválido %q (use nome=duração, ex.: verify=1500ms)": "invalid budget %q (use name=duration, e.g. verify=1500ms)",
+
+	// log.go
+	"--log-level deve ser um de %s, veio %q":      "--log-level must be one of %s, got %q",
+	"--log-format deve ser text ou json, veio %q": "--log-format must be text or json, got %q",
+
+	// transport.go
+	"--proxy inválido: %q":                             "invalid --proxy: %q",
+	"--ca-cert: nenhum certificado PEM em %s":          "--ca-cert: no PEM certificate in %s",
+	"--client-cert e --client-key precisam vir juntos": "--client-cert and --client-key must be used together",
+	"certificado do cliente: %w":                       "client certificate: %w",
+	"%s inválido: %q (use uma duração, ex.: 30s)":      "invalid %s: %q (use a duration, e.g. 30s)",
+	"--max-idle-conns inválido: %q":                    "invalid --max-idle-conns: %q",
+
+	// upload.go
+	"%s mudou durante o envio":                                   "%s changed while being sent",
+	"--encoding inválido: %q (use multipart, base64 ou datauri)": "invalid --encoding: %q (use multipart, base64 or datauri)",
+
+	// verbose.go
+	" (conexão reaproveitada)":          " (reused connection)",
+	"[http] ← erro: %v | %s\n":          "[http] ← error: %v | %s\n",
+	"[http] %s corpo (%d bytes):\n%s\n": "[http] %s body (%d bytes):\n%s\n",
+	"<binário, %d bytes>":               "<binary, %d bytes>",
+	"… (+%d bytes; -vvv mostra tudo)":   "… (+%d bytes; -vvv shows everything)",
+	"<%simagem base64, %d bytes>":       "<%sbase64 image, %d bytes>",
+	"--verbose deve ser 0..3, veio %q":  "--verbose must be 0..3, got %q",
+
+	// watch.go
+	"[watch] encerrado sem nenhuma imagem nova":                                           "[watch] stopped without any new image",
+	"[watch] ❌ %s id=%s sem match, similaridade=%s (%dms)\n":                              "[watch] ❌ %s id=%s no match, similarity=%s (%dms)\n",
+	"[watch] ✅ %s id=%s similaridade=%s (%dms)\n":                                         "[watch] ✅ %s id=%s similarity=%s (%dms)\n",
+	" não é um diretório":                                                                 " is not a directory",
+	"[watch] observando %s (%d imagem(ns) já presentes entram na fila); Ctrl+C encerra\n": "[watch] watching %s (%d image(s) already present are queued); Ctrl+C stops\n",
+	"[watch] observando %s (ignorando %d imagem(ns) já presentes); Ctrl+C encerra\n":      "[watch] watching %s (ignoring %d image(s) already present); Ctrl+C stops\n",
+	"[watch] ⚠ varredura falhou: %v\n":                                                    "[watch] ⚠ scan failed: %v\n",
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"os"
+)
+
+/* ==================== main-image --compare (imagem guardada × original local) ==================== */
+
+// a API guardou a foto certa? dHash pega troca de pessoa/foto; o SSIM pega corte, borrão ou
+// compressão pesada. Os dois são medidos em tons de cinza e numa grade fixa: tamanho e
+// reencode (--max-dimension, JPEG) não contam
+type imageComparison struct {
+	Original    string  `json:"original"`
+	Distance    int     `json:"dhash_distance"`
+	Similarity  float64 `json:"phash_similarity"`
+	SSIM        float64 `json:"ssim"`
+	MaxDistance int     `json:"max_distance"`
+	MinSSIM     float64 `json:"min_ssim"`
+	OK          bool    `json:"ok"`
+}
+
+// imagem local como o runner a envia: com a Orientation do EXIF aplicada
+func decodeOriented(path string) (image.Image, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err := decodeImage(b)
+	if err != nil {
+		return nil, fmt.Errorf(tr("%s não decodifica: %v"), path, err)
+	}
+	if x, ok := parseJPEGExif(b); ok && !imgPrep.NoExifFix {
+		img = applyOrientation(img, x.Orientation)
+	}
+	return img, nil
+}
+
+// SSIM médio em janelas 8×8 sobre a grade 64×64 de cinza (1 = idênticas)
+func ssim(a, b image.Image) float64 {
+	const n, win = 64, 8
+	ga, gb := grayGrid(a, n, n), grayGrid(b, n, n)
+	c1, c2 := math.Pow(0.01*255, 2), math.Pow(0.03*255, 2)
+	var total float64
+	windows := 0
+	for y0 := 0; y0 < n; y0 += win {
+		for x0 := 0; x0 < n; x0 += win {
+			var ma, mb float64
+			for y := y0; y < y0+win; y++ {
+				for x := x0; x < x0+win; x++ {
+					ma += ga[y][x]
+					mb += gb[y][x]
+				}
+			}
+			k := float64(win * win)
+			ma, mb = ma/k, mb/k
+			var va, vb, cov float64
+			for y := y0; y < y0+win; y++ {
+				for x := x0; x < x0+win; x++ {
+					da, db := ga[y][x]-ma, gb[y][x]-mb
+					va += da * da
+					vb += db * db
+					cov += da * db
+				}
+			}
+			va, vb, cov = va/(k-1), vb/(k-1), cov/(k-1)
+			total += ((2*ma*mb + c1) * (2*cov + c2)) / ((ma*ma + mb*mb + c1) * (va + vb + c2))
+			windows++
+		}
+	}
+	return total / float64(windows)
+}
+
+func compareWithOriginal(downloaded []byte, original string, maxDistance int, minSSIM float64) (imageComparison, error) {
+	c := imageComparison{Original: original, MaxDistance: maxDistance, MinSSIM: minSSIM}
+	got, _, err := decodeImage(downloaded)
+	if err != nil {
+		return c, fmt.Errorf(tr("main-image: a imagem baixada não decodifica: %v"), err)
+	}
+	want, err := decodeOriented(original)
+	if err != nil {
+		return c, err
+	}
+	c.Distance = hammingDistance(dHash(got), dHash(want))
+	c.Similarity = 100 * (1 - float64(c.Distance)/64)
+	c.SSIM = math.Round(ssim(got, want)*1000) / 1000
+	// em imagem quase lisa (desfocada, fundo uniforme) o dHash compara ruído: SSIM alto basta
+	c.OK = c.SSIM >= minSSIM && (c.Distance <= maxDistance || c.SSIM >= 0.95)
+	return c, nil
+}
+
+// imprime a comparação; divergência vira matchError (exit 5)
+func reportComparison(c imageComparison) error {
+	setResult("compare", c)
+	mark := "✅"
+	if !c.OK {
+		mark = "❌"
+	}
+	outf("[compare] %s %s: dHash distância=%d (máx %d) | SSIM=%.3f (mín %.2f)\n", mark, c.Original, c.Distance, c.MaxDistance, c.SSIM, c.MinSSIM)
+	if c.OK {
+		return nil
+	}
+	return matchError(fmt.Sprintf(tr("a imagem guardada diverge de %s (distância %d, SSIM %.3f): a API pode ter guardado outra foto"), c.Original, c.Distance, c.SSIM))
+}
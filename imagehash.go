@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/bits"
+)
+
+/* ==================== Hash perceptual ==================== */
+
+// decodifica jpeg/png/gif a partir dos bytes; devolve também o formato
+func decodeImage(b []byte) (image.Image, string, error) {
+	return image.Decode(bytes.NewReader(b))
+}
+
+// luminância média (0..255) do retângulo r
+func meanGray(img image.Image, r image.Rectangle) float64 {
+	var sum float64
+	n := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, _ := img.At(x, y).RGBA()
+			sum += (0.299*float64(cr) + 0.587*float64(cg) + 0.114*float64(cb)) / 257
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// reduz a imagem a uma grade w×h de tons de cinza (média de cada bloco)
+func grayGrid(img image.Image, w, h int) [][]float64 {
+	b := img.Bounds()
+	grid := make([][]float64, h)
+	for gy := 0; gy < h; gy++ {
+		grid[gy] = make([]float64, w)
+		y0 := b.Min.Y + gy*b.Dy()/h
+		y1 := b.Min.Y + (gy+1)*b.Dy()/h
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for gx := 0; gx < w; gx++ {
+			x0 := b.Min.X + gx*b.Dx()/w
+			x1 := b.Min.X + (gx+1)*b.Dx()/w
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			grid[gy][gx] = meanGray(img, image.Rect(x0, y0, x1, y1))
+		}
+	}
+	return grid
+}
+
+// dHash de 64 bits: compara vizinhos horizontais numa grade 9×8
+func dHash(img image.Image) uint64 {
+	g := grayGrid(img, 9, 8)
+	var h uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			h <<= 1
+			if g[y][x] < g[y][x+1] {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+func hammingDistance(a, b uint64) int { return bits.OnesCount64(a ^ b) }
+
+// similaridade 0..100 pelo dHash; sem decodificar, só bytes idênticos valem 100
+func phashSimilarity(a, b []byte) float64 {
+	ia, _, errA := decodeImage(a)
+	ib, _, errB := decodeImage(b)
+	if errA != nil || errB != nil {
+		if bytes.Equal(a, b) {
+			return 100
+		}
+		return 0
+	}
+	d := hammingDistance(dHash(ia), dHash(ib))
+	return 100 * (1 - float64(d)/64)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "golang.org/x/image/webp"
+)
+
+/* ==================== Metadados da imagem ==================== */
+
+// propriedades da imagem enviada, gravadas junto do resultado do verify
+type imageMeta struct {
+	File        string  `json:"file"`
+	Bytes       int64   `json:"bytes"`
+	Format      string  `json:"format"`
+	Width       int     `json:"width,omitempty"`
+	Height      int     `json:"height,omitempty"`
+	Megapixels  float64 `json:"megapixels,omitempty"`
+	CameraMake  string  `json:"camera_make,omitempty"`
+	CameraModel string  `json:"camera_model,omitempty"`
+	CapturedAt  string  `json:"captured_at,omitempty"` // como está no EXIF (2006:01:02 15:04:05)
+	Orientation int     `json:"orientation,omitempty"`
+	HasGPS      bool    `json:"has_gps,omitempty"`
+}
+
+func readImageMeta(path string) (imageMeta, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return imageMeta{}, err
+	}
+	m := imageMeta{File: path, Bytes: int64(len(b))}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(b))
+	if err == nil {
+		m.Format, m.Width, m.Height = format, cfg.Width, cfg.Height
+		m.Megapixels = float64(int(float64(cfg.Width*cfg.Height)/1e4+0.5)) / 100
+	} else {
+		// formato que o Go não decodifica (heic...): fica a extensão
+		m.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if x, ok := parseJPEGExif(b); ok {
+		m.CameraMake, m.CameraModel = x.Make, x.Model
+		m.CapturedAt, m.Orientation, m.HasGPS = x.DateTime, x.Orientation, x.HasGPS
+	}
+	return m, nil
+}
+
+// "1200x1600 jpeg 350KB Apple iPhone 12"
+func (m imageMeta) String() string {
+	s := fmt.Sprintf("%dx%d %s %dKB", m.Width, m.Height, m.Format, (m.Bytes+512)/1024)
+	if cam := strings.TrimSpace(m.CameraMake + " " + m.CameraModel); cam != "" {
+		s += " " + cam
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* ==================== Caminhos de imagem (glob, separador do SO, stdin, URL) ==================== */
+
+// comandos que aceitam várias imagens (glob ou --image repetido)
+var multiImageCommands = map[string]bool{"verify-card": true}
+
+// --image repetível; o primeiro Set descarta o default
+type imageListFlag struct {
+	paths []string
+	set   bool
+}
+
+func (f *imageListFlag) String() string { return strings.Join(f.paths, ",") }
+
+func (f *imageListFlag) Set(v string) error {
+	if !f.set {
+		f.paths, f.set = nil, true
+	}
+	f.paths = append(f.paths, v)
+	return nil
+}
+
+// "image\created_1.jpg" vindo de um .env/perfil do Windows também abre no Linux/macOS
+func localPath(p string) string {
+	p = expandHome(p)
+	if filepath.Separator == '/' && strings.Contains(p, `\`) {
+		if _, err := os.Stat(p); err != nil {
+			return strings.ReplaceAll(p, `\`, "/")
+		}
+	}
+	return p
+}
+
+func hasGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// --image: caminho único ou glob ("fotos/*.jpg"), em ordem alfabética; glob sem nenhum arquivo é erro
+func expandImageArg(p string) ([]string, error) {
+	p = localPath(p)
+	if !hasGlob(p) {
+		return []string{p}, nil
+	}
+	matches, err := filepath.Glob(p)
+	if err != nil {
+		return nil, usageError(fmt.Sprintf(tr("glob inválido %q: %v"), p, err))
+	}
+	var out []string
+	for _, m := range matches {
+		if st, err := os.Stat(m); err == nil && !st.IsDir() {
+			out = append(out, m)
+		}
+	}
+	if len(out) == 0 {
+		return nil, usageError(fmt.Sprintf(tr("nenhuma imagem casa com %q"), p))
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// maior imagem aceita do stdin ou de uma URL
+const maxFetchedImage = 32 << 20
+
+// extensão do arquivo temporário pelo conteúdo: o MIME do envio sai dela (guessMIME)
+var sniffedImageExt = map[string]string{
+	"image/jpeg": ".jpg", "image/png": ".png", "image/webp": ".webp", "image/gif": ".gif", "image/bmp": ".bmp",
+}
+
+var (
+	tempImagesMu   sync.Mutex
+	tempImageDirs  []string
+	stdinImageUsed bool
+)
+
+func isRemoteImage(p string) bool {
+	return strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://")
+}
+
+// "--image -" lê o stdin; "--image https://..." baixa antes do envio. Os dois viram um arquivo
+// temporário (apagado no fim), e o resto (qualidade, EXIF, encoding) segue como com um caminho local
+func fetchImageArg(p string) (string, error) {
+	var data []byte
+	name := "stdin"
+	if p == "-" {
+		if stdinImageUsed {
+			return "", usageError(tr("--image - só pode aparecer uma vez (o stdin é lido uma vez só)"))
+		}
+		stdinImageUsed = true
+		b, err := io.ReadAll(io.LimitReader(os.Stdin, maxFetchedImage+1))
+		if err != nil {
+			return "", fmt.Errorf(tr("lendo a imagem do stdin: %w"), err)
+		}
+		data = b
+	} else {
+		b, err := downloadImage(p)
+		if err != nil {
+			return "", err
+		}
+		data = b
+		if u, err := url.Parse(p); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+			name = strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+		} else {
+			name = "download"
+		}
+	}
+	if len(data) == 0 {
+		return "", usageError(fmt.Sprintf(tr("--image %s: nenhum byte recebido"), p))
+	}
+	if len(data) > maxFetchedImage {
+		return "", usageError(fmt.Sprintf(tr("--image %s: imagem passa de %d MB"), p, maxFetchedImage>>20))
+	}
+	ct := http.DetectContentType(data)
+	ext, ok := sniffedImageExt[ct]
+	if !ok {
+		return "", usageError(fmt.Sprintf(tr("--image %s: conteúdo não é imagem (%s)"), p, ct))
+	}
+	dir, err := os.MkdirTemp("", "biodoc-image-")
+	if err != nil {
+		return "", err
+	}
+	tempImagesMu.Lock()
+	tempImageDirs = append(tempImageDirs, dir)
+	tempImagesMu.Unlock()
+	out := filepath.Join(dir, name+ext)
+	if err := os.WriteFile(out, data, 0o600); err != nil {
+		return "", err
+	}
+	if p == "-" {
+		p = "stdin"
+	}
+	outf("[image] %s → %d bytes (%s)\n", p, len(data), ct)
+	return out, nil
+}
+
+// GET sem o token do Biodoc, pelo mesmo transporte (proxy, CA, timeout)
+func downloadImage(u string) ([]byte, error) {
+	resp, err := httpClient.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchedImage+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf(tr("baixando %s: %w"), u, newAPIError(resp.StatusCode, body))
+	}
+	return body, nil
+}
+
+// apaga o que veio do stdin/URL; chamado no exit
+func removeTempImages() {
+	tempImagesMu.Lock()
+	defer tempImagesMu.Unlock()
+	for _, d := range tempImageDirs {
+		_ = os.RemoveAll(d)
+	}
+	tempImageDirs = nil
+}
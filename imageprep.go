@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	stddraw "image/draw"
+	"image/jpeg"
+	"io"
+	"os"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+/* ==================== Pré-processamento da imagem antes do upload ==================== */
+
+// --max-dimension, --max-bytes, --jpeg-quality (globais: valem para todo comando que envia imagem)
+type imagePrep struct {
+	MaxDimension int    // maior lado em px (0 = sem limite)
+	MaxBytes     int    // tamanho máximo do arquivo enviado (0 = sem limite)
+	JPEGQuality  int    // 1..100; 0 = 85 e só reencoda se precisar
+	Watermark    string // texto carimbado numa faixa na base ("" = sem carimbo)
+	NoExifFix    bool   // --no-exif-fix: envia sem aplicar a Orientation do EXIF
+	StripMeta    bool   // --strip-metadata: remove EXIF/XMP/IPTC (GPS, aparelho) do JPEG
+}
+
+var imgPrep imagePrep
+
+const defaultJPEGQuality = 85
+
+func stripImagePrepFlags(args []string) ([]string, error) {
+	for _, f := range []struct {
+		name string
+		dst  *int
+		max  int
+	}{
+		{"--max-dimension", &imgPrep.MaxDimension, 1 << 16},
+		{"--max-bytes", &imgPrep.MaxBytes, 1 << 30},
+		{"--jpeg-quality", &imgPrep.JPEGQuality, 100},
+	} {
+		var v string
+		var ok bool
+		var err error
+		args, v, ok, err = stripValueFlag(args, f.name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > f.max {
+			return nil, fmt.Errorf(tr("%s inválido: %q"), f.name, v)
+		}
+		*f.dst = n
+	}
+	return args, nil
+}
+
+// lê a imagem já pronta para envio; sem pré-processamento devolve o arquivo como está
+func loadUploadImage(path string) ([]byte, string, error) {
+	data, _, mimeType, err := openUploadImage(path)
+	if err != nil || data != nil {
+		return data, mimeType, err
+	}
+	b, err := os.ReadFile(path)
+	return b, mimeType, err
+}
+
+// EXIF fica nos primeiros 64KB (APP1); sobra margem para APP0/APP2 antes dele
+const imageHeadBytes = 256 << 10
+
+// como loadUploadImage, mas quando nada muda não lê o arquivo inteiro: data == nil e
+// size é o tamanho no disco (o corpo vai em stream, ver upload.go)
+func openUploadImage(path string) (data []byte, size int64, mimeType string, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, "", err
+	}
+	head := make([]byte, min(st.Size(), imageHeadBytes))
+	_, err = io.ReadFull(f, head)
+	f.Close()
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if err := preflightImage(path, head); err != nil {
+		return nil, 0, "", err
+	}
+	noteReportImage(path)
+	if !imgPrep.needsDecode(head) {
+		return nil, st.Size(), guessMIME(path), nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	out, changed, err := imgPrep.apply(b)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf(tr("pré-processar %s: %w"), path, err)
+	}
+	if !changed {
+		return b, int64(len(b)), guessMIME(path), nil
+	}
+	return out, int64(len(out)), "image/jpeg", nil
+}
+
+// algum ajuste pode mudar os bytes (flags de pré-processamento ou Orientation no EXIF)
+func (p imagePrep) needsDecode(head []byte) bool {
+	if p.MaxDimension > 0 || p.MaxBytes > 0 || p.JPEGQuality > 0 || p.Watermark != "" || p.StripMeta {
+		return true
+	}
+	info, ok := parseJPEGExif(head)
+	return ok && !p.NoExifFix && info.Orientation > 1
+}
+
+// endireita pela Orientation do EXIF, reduz e reencoda em JPEG; changed=false quando
+// o original já serve como está
+func (p imagePrep) apply(b []byte) ([]byte, bool, error) {
+	// foto em retrato do celular: pixels deitados + Orientation 6/8; a API não gira
+	orient := 0
+	if info, ok := parseJPEGExif(b); ok && !p.NoExifFix && info.Orientation > 1 {
+		orient = info.Orientation
+	}
+	stripped := false
+	if p.StripMeta {
+		if s := stripJPEGMetadata(b); len(s) < len(b) {
+			outf("[prep] metadados removidos (%d bytes)\n", len(b)-len(s))
+			b, stripped = s, true
+		}
+	}
+	resize := p.MaxDimension > 0 || p.MaxBytes > 0 || p.JPEGQuality > 0 || p.Watermark != ""
+	if orient == 0 && !resize {
+		return b, stripped, nil
+	}
+
+	img, _, err := decodeImage(b)
+	if err != nil {
+		return nil, false, err
+	}
+	// o JPEG reencodado sai sem EXIF, então a rotação não é aplicada duas vezes
+	if orient > 0 {
+		img = applyOrientation(img, orient)
+		outf("[prep] orientação EXIF %d aplicada (--no-exif-fix para enviar como está)\n", orient)
+	}
+	bounds := img.Bounds()
+	long := max(bounds.Dx(), bounds.Dy())
+	fitsDim := p.MaxDimension == 0 || long <= p.MaxDimension
+	fitsBytes := p.MaxBytes == 0 || len(b) <= p.MaxBytes
+	if fitsDim && fitsBytes && p.JPEGQuality == 0 && p.Watermark == "" && orient == 0 {
+		return b, stripped, nil
+	}
+
+	target := long
+	if !fitsDim {
+		target = p.MaxDimension
+	}
+	q := p.JPEGQuality
+	if q == 0 {
+		q = defaultJPEGQuality
+	}
+	// baixa a qualidade até 40 e depois reduz 20% por rodada até caber em MaxBytes
+	for {
+		frame := resizeLongSide(img, target)
+		if p.Watermark != "" {
+			frame = stampWatermark(frame, p.Watermark)
+		}
+		out, err := encodeJPEG(frame, q)
+		if err != nil {
+			return nil, false, err
+		}
+		if p.MaxBytes == 0 || len(out) <= p.MaxBytes {
+			outf("[prep] %dx%d %dKB → lado %dpx, jpeg q%d, %dKB\n",
+				bounds.Dx(), bounds.Dy(), len(b)/1024, target, q, len(out)/1024)
+			return out, true, nil
+		}
+		switch {
+		case q > 40:
+			q = max(40, q-10)
+		case target > 64:
+			target = target * 4 / 5
+		default:
+			return nil, false, fmt.Errorf(tr("não coube em %d bytes nem com %dpx/q%d"), p.MaxBytes, target, q)
+		}
+	}
+}
+
+// redimensiona mantendo a proporção para o maior lado = long (não amplia)
+func resizeLongSide(img image.Image, long int) image.Image {
+	b := img.Bounds()
+	cur := max(b.Dx(), b.Dy())
+	if long >= cur {
+		return img
+	}
+	w := max(1, b.Dx()*long/cur)
+	h := max(1, b.Dy()*long/cur)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// JPEG não tem transparência: compõe sobre branco antes
+func encodeJPEG(img image.Image, q int) ([]byte, error) {
+	flat := image.NewRGBA(img.Bounds())
+	stddraw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, stddraw.Src)
+	stddraw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, stddraw.Over)
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: q}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* ==================== interactive (assistente para QA manual) ==================== */
+
+// operações do menu; cada uma vira uma chamada normal de run() com as flags montadas
+type interactiveAction struct {
+	Label   string
+	Command string
+	Image   bool // pede imagem
+	Name    bool // pede nome
+}
+
+var interactiveActions = []interactiveAction{
+	{"Verificar imagem contra um card", "verify-card", true, true},
+	{"Cadastrar card", "create-card", true, true},
+	{"Fluxo completo (apaga, cadastra, verifica, apaga)", "run-all", true, true},
+	{"Consultar card", "get-card", false, false},
+	{"Apagar card", "delete-card", false, false},
+}
+
+// sem terminal (pipe, CI) ou com NO_COLOR, sem cores
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" || outputJSON {
+		return false
+	}
+	return isTerminal(os.Stdout)
+}
+
+func paint(code, s string) string {
+	if !useColor() {
+		return s
+	}
+	return "\033[" + code + "m" + s + "\033[0m"
+}
+
+type prompter struct {
+	in *bufio.Reader
+}
+
+// lê uma linha; vazio devolve def. EOF (Ctrl+D) encerra o assistente
+func (p *prompter) ask(label, def string) (string, error) {
+	label = tr(label)
+	if def != "" {
+		outf("%s [%s]: ", label, def)
+	} else {
+		outf("%s: ", label)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", io.EOF
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+func cmdInteractive(baseURL, token string) error {
+	p := &prompter{in: bufio.NewReader(os.Stdin)}
+	outln(paint("1", tr("biodoc-go-runner · modo interativo")) + " (" + baseURL + ")")
+	outln("Enter aceita o valor entre colchetes; Ctrl+D sai.")
+	id, name, image := defaultID(), "Celso QA", defaultVerifyImage()
+	for {
+		outln()
+		for i, a := range interactiveActions {
+			outf("  %d) %s\n", i+1, tr(a.Label))
+		}
+		outln("  0) Sair")
+		choice, err := p.ask("Opção", "1")
+		if err != nil || choice == "0" {
+			outln()
+			return nil
+		}
+		n, err := strconv.Atoi(choice)
+		if err != nil || n < 1 || n > len(interactiveActions) {
+			outln(paint("33", tr("opção inválida")))
+			continue
+		}
+		a := interactiveActions[n-1]
+
+		if a.Image {
+			picked, err := p.pickImage(image)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				outln(paint("33", err.Error()))
+				continue
+			}
+			image = picked
+		}
+		if id, err = p.ask("ID do card", id); err != nil {
+			return nil
+		}
+		if a.Name {
+			if name, err = p.ask("Nome", name); err != nil {
+				return nil
+			}
+		}
+
+		args := []string{"--id", id}
+		if a.Image {
+			args = append(args, "--image", image)
+		}
+		if a.Name {
+			args = append(args, "--name", name)
+		}
+		outln(paint("2", "→ "+a.Command+" "+strings.Join(args, " ")))
+		callsMu.Lock()
+		delete(results, "similarity")
+		delete(results, "match")
+		callsMu.Unlock()
+		runErr := run(a.Command, args, baseURL, token)
+		printInteractiveResult(runErr)
+	}
+}
+
+// resultado em destaque: similaridade grande e colorida quando houve verify
+func printInteractiveResult(runErr error) {
+	callsMu.Lock()
+	pct, hasSim := results["similarity"].(string)
+	match, _ := results["match"].(bool)
+	callsMu.Unlock()
+	outln()
+	if hasSim {
+		color, verdict := "1;32", "MATCH"
+		if !match {
+			color, verdict = "1;31", tr("SEM MATCH")
+		}
+		if pct == "" {
+			pct = "-"
+		}
+		outln(paint(color, fmt.Sprintf(tr("  ▶ %s · similaridade %s%%"), verdict, strings.TrimSuffix(pct, "%"))))
+	}
+	if runErr != nil {
+		outln(paint("1;31", "  ✖ "+runErr.Error()))
+		return
+	}
+	if !hasSim {
+		outln(paint("1;32", tr("  ✔ concluído")))
+	}
+}
+
+// seletor de arquivo: navega por pastas numeradas, ou aceita um caminho digitado
+func (p *prompter) pickImage(current string) (string, error) {
+	dir := "."
+	if current != "" {
+		if st, err := os.Stat(current); err == nil && !st.IsDir() {
+			dir = filepath.Dir(current)
+		}
+	}
+	for {
+		abs, _ := filepath.Abs(dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return "", err
+		}
+		var dirs, imgs []string
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			if e.IsDir() {
+				dirs = append(dirs, e.Name())
+			} else if imageExts[strings.ToLower(filepath.Ext(e.Name()))] {
+				imgs = append(imgs, e.Name())
+			}
+		}
+		sort.Strings(dirs)
+		sort.Strings(imgs)
+		outln()
+		outln(paint("1", tr("Imagem")) + tr(" em ") + abs)
+		outln("  ..) pasta acima")
+		for i, d := range dirs {
+			outf("  %d) %s/\n", i+1, d)
+		}
+		for i, f := range imgs {
+			outf("  %d) %s\n", len(dirs)+i+1, f)
+		}
+		def := ""
+		if st, err := os.Stat(current); err == nil && !st.IsDir() {
+			def = current
+		}
+		label := tr("Número ou caminho")
+		if def != "" {
+			label = tr("Número, caminho ou Enter para a atual")
+		}
+		in, err := p.ask(label, def)
+		if err != nil {
+			return "", err
+		}
+		if in == "" {
+			continue
+		}
+		if in == ".." {
+			dir = filepath.Join(dir, "..")
+			continue
+		}
+		if n, err := strconv.Atoi(in); err == nil {
+			switch {
+			case n >= 1 && n <= len(dirs):
+				dir = filepath.Join(dir, dirs[n-1])
+				continue
+			case n > len(dirs) && n <= len(dirs)+len(imgs):
+				return filepath.Join(dir, imgs[n-len(dirs)-1]), nil
+			}
+			outln(paint("33", tr("número fora da lista")))
+			continue
+		}
+		st, err := os.Stat(in)
+		switch {
+		case err != nil:
+			outln(paint("33", tr("não encontrado: ")+in))
+		case st.IsDir():
+			dir = in
+		default:
+			return in, nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+/* ==================== Relatório JUnit ==================== */
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *struct{}     `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// limite do corpo de resposta anexado a cada falha
+const junitMaxBody = 64 << 10
+
+func secs(d time.Duration) string { return fmt.Sprintf("%.3f", d.Seconds()) }
+
+// --junit: grava cada etapa como testcase (falhas levam as respostas HTTP da etapa)
+func writeJUnit(path, cmd string, started time.Time, total time.Duration, st []stepRecord) error {
+	suite := junitSuite{
+		Name:      "biodoc-go-runner." + cmd,
+		Time:      secs(total),
+		Timestamp: started.Format("2006-01-02T15:04:05"),
+	}
+	for _, s := range st {
+		c := junitCase{Name: s.Name, Classname: "biodoc-go-runner." + cmd, Time: secs(s.Duration)}
+		switch {
+		case s.Skipped:
+			c.Skipped = &struct{}{}
+			suite.Skipped++
+		case s.Error != "":
+			c.Failure = &junitFailure{Message: s.Error, Body: callsDump(s.Calls)}
+			suite.Failures++
+		}
+		suite.Tests++
+		c.SystemOut = callsSummary(s.Calls)
+		suite.Cases = append(suite.Cases, c)
+	}
+	b, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	b = append([]byte(xml.Header), b...)
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
+
+// "POST url → 200 (123ms)" por chamada
+func callsSummary(cs []callRecord) string {
+	var sb strings.Builder
+	for _, c := range cs {
+		fmt.Fprintf(&sb, tr("%s %s → %d (%dms, %d tentativa(s))\n"), c.Method, c.URL, c.Status, c.LatencyMS, c.Attempts)
+	}
+	return sb.String()
+}
+
+// corpos de resposta das chamadas, truncados
+func callsDump(cs []callRecord) string {
+	var sb strings.Builder
+	for _, c := range cs {
+		fmt.Fprintf(&sb, "%s %s → status=%d\n", c.Method, c.URL, c.Status)
+		if c.Error != "" {
+			fmt.Fprintf(&sb, tr("erro: %s\n"), c.Error)
+		}
+		body := string(c.Response)
+		if len(body) > junitMaxBody {
+			body = body[:junitMaxBody] + tr("…(truncado)")
+		}
+		if body != "" {
+			sb.WriteString(body)
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+/* ==================== Credenciais no keyring do SO ==================== */
+
+// Windows Credential Manager, macOS Keychain ou Secret Service (libsecret)
+const keyringService = "biodoc-runner"
+
+// segredos guardados pelo login, por perfil
+var keyringSecrets = []string{"AUTH_TOKEN", "OAUTH_CLIENT_SECRET"}
+
+// perfil em uso (vazio = "default"); separa as credenciais de cada ambiente
+var activeProfile string
+
+func keyringAccount(profile, key string) string {
+	if profile == "" {
+		profile = "default"
+	}
+	return profile + "/" + key
+}
+
+// preenche AUTH_TOKEN/OAUTH_CLIENT_SECRET a partir do keyring quando ninguém definiu
+// (shell, perfil e .env continuam valendo por cima)
+func loadKeyringSecrets() {
+	for _, key := range keyringSecrets {
+		if os.Getenv(key) != "" {
+			continue
+		}
+		v, err := keyring.Get(keyringService, keyringAccount(activeProfile, key))
+		if err != nil {
+			continue // sem entrada ou keyring indisponível (CI): segue sem
+		}
+		_ = os.Setenv(key, v)
+	}
+}
+
+// lê o segredo do stdin (pipe ou digitado); nunca por flag, para não cair no histórico do shell
+func readSecret(prompt string) (string, error) {
+	if fi, err := os.Stdin.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+		fmt.Fprint(os.Stderr, prompt)
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf(tr("ler segredo do stdin: %w"), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// login: guarda o token (ou, com --oauth, o client secret) no keyring do perfil atual
+func cmdLogin(oauthSecret bool) error {
+	key, prompt := "AUTH_TOKEN", "Token (AUTH_TOKEN): "
+	if oauthSecret {
+		key, prompt = "OAUTH_CLIENT_SECRET", "Client secret (OAUTH_CLIENT_SECRET): "
+	}
+	v, err := readSecret(prompt)
+	if err != nil {
+		return err
+	}
+	if v == "" {
+		return usageError(tr("segredo vazio, nada gravado"))
+	}
+	acct := keyringAccount(activeProfile, key)
+	if err := keyring.Set(keyringService, acct, v); err != nil {
+		return fmt.Errorf(tr("gravar no keyring do sistema: %w"), err)
+	}
+	outf("[login] %s gravado no keyring (%s/%s)\n", key, keyringService, acct)
+	outln("[login] pode apagar o valor do .env; os comandos leem do keyring quando a variável não está definida")
+	return nil
+}
+
+// logout: remove os segredos do perfil atual do keyring
+func cmdLogout() error {
+	removed := 0
+	for _, key := range keyringSecrets {
+		acct := keyringAccount(activeProfile, key)
+		err := keyring.Delete(keyringService, acct)
+		switch {
+		case err == nil:
+			removed++
+			outf("[logout] %s removido do keyring\n", acct)
+		case errors.Is(err, keyring.ErrNotFound):
+		default:
+			return fmt.Errorf(tr("remover %s do keyring: %w"), acct, err)
+		}
+	}
+	if removed == 0 {
+		outln("[logout] nada guardado para este perfil")
+	}
+	return nil
+}
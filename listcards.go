@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+/* ==================== list-cards ==================== */
+
+// uma página da listagem, independente do formato (Spring page, items/total, array puro)
+type cardPage struct {
+	Cards      []CardInfo
+	Total      int  // -1 se a API não informar
+	TotalPages int  // -1 se a API não informar
+	Last       bool // API sinalizou última página
+}
+
+func parseCardPage(raw []byte) (cardPage, error) {
+	p := cardPage{Total: -1, TotalPages: -1}
+	var arr []map[string]any
+	if json.Unmarshal(raw, &arr) == nil {
+		for _, m := range arr {
+			p.Cards = append(p.Cards, cardFromMap(m))
+		}
+		return p, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return p, err
+	}
+	if inner, ok := m["response"].(map[string]any); ok {
+		m = inner
+	}
+	for _, k := range []string{"content", "items", "data", "cards", "results"} {
+		if list, ok := m[k].([]any); ok {
+			for _, it := range list {
+				if cm, ok := it.(map[string]any); ok {
+					p.Cards = append(p.Cards, cardFromMap(cm))
+				}
+			}
+			break
+		}
+	}
+	num := func(keys ...string) int {
+		for _, k := range keys {
+			if v, ok := m[k].(float64); ok {
+				return int(v)
+			}
+		}
+		return -1
+	}
+	p.Total = num("totalElements", "total", "count")
+	p.TotalPages = num("totalPages", "pages")
+	if v, ok := m["last"].(bool); ok {
+		p.Last = v
+	}
+	return p, nil
+}
+
+type listOptions struct {
+	Endpoint string
+	Page     int // 0-based
+	Size     int
+	Name     string
+	All      bool
+}
+
+// maxListPages evita loop infinito se a API nunca sinalizar o fim
+const maxListPages = 10000
+
+// GET de uma página sem imprimir nada
+func listCardsPage(baseURL, token string, opt listOptions, page int) (cardPage, error) {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("size", strconv.Itoa(opt.Size))
+	if opt.Name != "" {
+		q.Set("name", opt.Name)
+	}
+	u := strings.TrimRight(baseURL, "/") + opt.Endpoint + "?" + q.Encode()
+	resp, body, err := doRequest(http.MethodGet, u, authHeader(token), nil)
+	if err != nil {
+		return cardPage{}, err
+	}
+	if err := checkStatus(resp, body); err != nil {
+		if !quiet {
+			outln(string(body))
+		}
+		return cardPage{}, fmt.Errorf(tr("listagem falhou (página %d): %w"), page, err)
+	}
+	p, err := parseCardPage(body)
+	if err != nil {
+		return cardPage{}, fmt.Errorf(tr("resposta inválida (página %d): %w"), page, err)
+	}
+	return p, nil
+}
+
+// percorre as páginas (uma só, ou todas com opt.All)
+func listCards(baseURL, token string, opt listOptions) ([]CardInfo, int, error) {
+	var all []CardInfo
+	total := -1
+	for page, n := opt.Page, 0; n < maxListPages; page, n = page+1, n+1 {
+		p, err := listCardsPage(baseURL, token, opt, page)
+		if err != nil {
+			return all, total, err
+		}
+		all = append(all, p.Cards...)
+		if p.Total >= 0 {
+			total = p.Total
+		}
+		if !opt.All || p.Last || len(p.Cards) == 0 || len(p.Cards) < opt.Size ||
+			(p.TotalPages >= 0 && page+1 >= p.TotalPages) {
+			break
+		}
+	}
+	return all, total, nil
+}
+
+func cmdListCards(baseURL, token string, opt listOptions) error {
+	if opt.Size < 1 {
+		return usageError(tr("--size deve ser >= 1"))
+	}
+	cards, total, err := listCards(baseURL, token, opt)
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("ID\tNOME\tCRIADO\tSTATUS"))
+	for _, c := range cards {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", orDash(c.ID), orDash(c.Name), orDash(c.CreatedAt), orDash(c.Status))
+	}
+	tw.Flush()
+	if total >= 0 {
+		outf("[list] %d card(s) listados de %d no total\n", len(cards), total)
+	} else {
+		outf("[list] %d card(s) listados\n", len(cards))
+	}
+	setResult("cards", cards)
+	setResult("count", len(cards))
+	if total >= 0 {
+		setResult("total", total)
+	}
+	return nil
+}
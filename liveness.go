@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/* ==================== liveness-check (anti-spoofing) ==================== */
+
+// POST /api/card/integration/liveness com uma selfie (--image) ou um vídeo curto (--video),
+// ambos em data URI no JSON ("image" ou "video"). O score vem na escala de --score-scale
+// (0-100 ou 0-1), é levado a 0-100 e comparado com --min-score
+type livenessOptions struct {
+	Endpoint   string
+	Image      string
+	Video      string
+	ID         string  // opcional: liga a prova de vida a um card
+	MinScore   float64 // 0-100
+	ScoreScale float64 // escala da API: 100 (0-100) ou 1 (0-1)
+	ScoreField string  // caminho do score na resposta; vazio = tenta os nomes conhecidos
+}
+
+type livenessResult struct {
+	Media    string  `json:"media"`
+	Score    float64 `json:"score"`
+	Live     *bool   `json:"live,omitempty"`
+	MinScore float64 `json:"min_score"`
+	OK       bool    `json:"ok"`
+}
+
+// vídeo vai inteiro no JSON (+33% do base64): só clipes curtos
+const maxLivenessVideo = 20 << 20
+
+var videoMIME = map[string]string{
+	".mp4":  "video/mp4",
+	".mov":  "video/quicktime",
+	".webm": "video/webm",
+	".avi":  "video/x-msvideo",
+}
+
+// nomes vistos nas versões da API, na ordem em que são tentados
+var (
+	livenessScoreFields = []string{"score", "response.score", "livenessScore", "response.livenessScore", "liveness_score", "response.liveness_score", "percentage", "response.percentage"}
+	livenessLiveFields  = []string{"live", "response.live", "isLive", "response.isLive"}
+)
+
+func livenessURL(baseURL, endpointPath string) string {
+	if endpointPath == "" {
+		endpointPath = "/api/card/integration/liveness"
+	}
+	return strings.TrimRight(baseURL, "/") + endpointPath
+}
+
+// {"id":...,"video":"data:video/mp4;base64,..."}
+func encodeVideoPayload(fields map[string]any, path string) ([]byte, error) {
+	mimeType, ok := videoMIME[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, usageError(fmt.Sprintf(tr("--video: formato não suportado %q (use mp4, mov, webm ou avi)"), filepath.Ext(path)))
+	}
+	st, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if st.Size() > maxLivenessVideo {
+		return nil, usageError(fmt.Sprintf(tr("--video: %s tem %d MB; o limite é %d MB (use um clipe curto)"), path, st.Size()>>20, maxLivenessVideo>>20))
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	fields["video"] = "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(b)
+	return json.Marshal(fields)
+}
+
+func livenessCheck(baseURL, token string, opt livenessOptions) (*http.Response, []byte, error) {
+	fields := map[string]any{}
+	if opt.ID != "" {
+		fields["id"] = opt.ID
+	}
+	h := authHeader(token)
+	if opt.Video != "" {
+		body, err := encodeVideoPayload(fields, opt.Video)
+		if err != nil {
+			return nil, nil, err
+		}
+		return doRequest(http.MethodPost, livenessURL(baseURL, opt.Endpoint), h, body)
+	}
+	ct, body, err := encodeImagePayload(fields, opt.Image, encDataURI)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("ler/encode imagem: %w"), err)
+	}
+	h.Set("Content-Type", ct)
+	return doRequestBody(http.MethodPost, livenessURL(baseURL, opt.Endpoint), h, body)
+}
+
+// score da resposta, já em 0-100
+func livenessScore(doc any, field string, scale float64) (float64, string, error) {
+	fields := livenessScoreFields
+	if field != "" {
+		fields = []string{field}
+	}
+	for _, f := range fields {
+		v, ok := lookupPath(doc, f)
+		if !ok {
+			continue
+		}
+		if n, ok := expectNumber(v); ok {
+			// fora da escala declarada (93 com --score-scale 1): a escala está errada, não o score
+			if n < 0 || n > scale {
+				return 0, f, fmt.Errorf(tr("score %v em %s fora da escala 0-%v (ajuste --score-scale)"), n, f, scale)
+			}
+			return n * 100 / scale, f, nil
+		}
+	}
+	if field != "" {
+		return 0, "", fmt.Errorf(tr("score ausente ou inválido em %s"), field)
+	}
+	return 0, "", fmt.Errorf(tr("score ausente na resposta (tente --score-field)"))
+}
+
+func cmdLivenessCheck(baseURL, token string, opt livenessOptions) error {
+	if opt.ScoreScale != 1 && opt.ScoreScale != 100 {
+		return usageError(fmt.Sprintf(tr("--score-scale deve ser 1 ou 100, veio %v"), opt.ScoreScale))
+	}
+	if opt.Image != "" && opt.Video != "" {
+		return usageError(tr("use --image ou --video, não os dois"))
+	}
+	media := opt.Video
+	if media == "" {
+		if opt.Image == "" {
+			opt.Image = defaultVerifyImage()
+		}
+		media = opt.Image
+	}
+	outf("[liveness] POST %s (%s)\n", livenessURL(baseURL, opt.Endpoint), media)
+	resp, raw, err := livenessCheck(baseURL, token, opt)
+	if err != nil {
+		return err
+	}
+	outf("status=%d\n", resp.StatusCode)
+	if !quiet {
+		outln(string(raw))
+	}
+	if err := checkStatus(resp, raw); err != nil {
+		return err
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf(tr("resposta inválida, não dá para ler o score: %w"), err)
+	}
+	score, field, err := livenessScore(doc, opt.ScoreField, opt.ScoreScale)
+	if err != nil {
+		return err
+	}
+	res := livenessResult{Media: media, Score: score, MinScore: opt.MinScore}
+	for _, f := range livenessLiveFields {
+		if v, ok := lookupPath(doc, f); ok {
+			if b, ok := v.(bool); ok {
+				res.Live = &b
+				break
+			}
+		}
+	}
+	// a API dizendo que não é pessoa real reprova mesmo com score alto
+	res.OK = score >= opt.MinScore && (res.Live == nil || *res.Live)
+	mark := "✅"
+	if !res.OK {
+		mark = "❌"
+	}
+	live := "-"
+	if res.Live != nil {
+		live = fmt.Sprint(*res.Live)
+	}
+	outf("[liveness] %s score=%.2f (mín %.2f, campo %s) | live=%s\n", mark, score, opt.MinScore, field, live)
+	setResult("liveness", res)
+	if res.OK {
+		return nil
+	}
+	if res.Live != nil && !*res.Live {
+		return matchError(tr("liveness reprovado: a API marcou a captura como não viva (live=false)"))
+	}
+	return matchError(fmt.Sprintf(tr("score de liveness %.2f abaixo do mínimo %.2f"), score, opt.MinScore))
+}
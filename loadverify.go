@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ==================== load-verify ==================== */
+
+type loadOptions struct {
+	Endpoint     string
+	Image        string
+	ID           string
+	Name         string
+	Detail       string
+	RPS          float64 // 0 = cada worker dispara assim que a anterior volta
+	Workers      int
+	Duration     time.Duration
+	MaxErrorRate float64 // < 0 = não falha por taxa de erro
+}
+
+type loadSample struct {
+	latency time.Duration
+	status  int
+	err     bool
+}
+
+// limites (superiores) das faixas do histograma
+var loadBuckets = []time.Duration{
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second,
+}
+
+func cmdLoadVerify(baseURL, token string, opt loadOptions) error {
+	if opt.Workers < 1 {
+		return usageError(tr("--workers deve ser >= 1"))
+	}
+	if opt.Duration <= 0 {
+		return usageError(tr("--duration deve ser > 0"))
+	}
+	// imagem codificada uma vez só: o custo medido é o da API, não o do runner
+	dataURI, err := buildDataURIImage(opt.Image)
+	if err != nil {
+		return fmt.Errorf(tr("ler/encode imagem: %w"), err)
+	}
+	body, err := json.Marshal(map[string]any{"id": opt.ID, "name": opt.Name, "detail": opt.Detail, "image": dataURI})
+	if err != nil {
+		return err
+	}
+	url := verifyURL(baseURL, opt.Endpoint)
+	h := authHeader(token)
+
+	mode := tr("malha fechada")
+	if opt.RPS > 0 {
+		mode = fmt.Sprintf("%.1f req/s", opt.RPS)
+	}
+	outf("[load] POST %s por %s, %d workers, %s\n", url, opt.Duration, opt.Workers, mode)
+	if limiter != nil {
+		outln("[load] ⚠ --rpm/RATE_LIMIT ativo: as latências incluem a espera pelo limite")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opt.Duration)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		samples []loadSample
+		sent    atomic.Int64
+		errs    atomic.Int64
+		dropped atomic.Int64 // ticks sem worker livre (a API não acompanhou a taxa pedida)
+	)
+	bar := newProgressBar("load", 0, opt.Duration)
+	fire := func() {
+		start := time.Now()
+		resp, _, err := doOnce(http.MethodPost, url, h, body)
+		s := loadSample{latency: time.Since(start), err: err != nil}
+		if resp != nil {
+			s.status = resp.StatusCode
+			s.err = s.err || resp.StatusCode < 200 || resp.StatusCode >= 300
+		}
+		sent.Add(1)
+		bar.add(s.err)
+		if s.err {
+			errs.Add(1)
+		}
+		mu.Lock()
+		samples = append(samples, s)
+		mu.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan struct{})
+	for i := 0; i < opt.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if opt.RPS <= 0 {
+				for ctx.Err() == nil {
+					fire()
+				}
+				return
+			}
+			for range jobs {
+				fire()
+			}
+		}()
+	}
+
+	// progresso a cada 5s (sem a barra, ex.: log de CI)
+	started := time.Now()
+	go func() {
+		if bar != nil {
+			return
+		}
+		t := time.NewTicker(5 * time.Second)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				el := time.Since(started).Seconds()
+				outf("[load] t=%.0fs enviadas=%d (%.1f req/s) erros=%d\n", el, sent.Load(), float64(sent.Load())/el, errs.Load())
+			}
+		}
+	}()
+
+	if opt.RPS > 0 {
+		tick := time.NewTicker(time.Duration(float64(time.Second) / opt.RPS))
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case <-tick.C:
+				select {
+				case jobs <- struct{}{}:
+				default:
+					dropped.Add(1)
+				}
+			}
+		}
+		tick.Stop()
+		close(jobs)
+	}
+	wg.Wait()
+	bar.finish()
+	elapsed := time.Since(started)
+
+	return printLoadReport(samples, elapsed, dropped.Load(), opt)
+}
+
+func printLoadReport(samples []loadSample, elapsed time.Duration, dropped int64, opt loadOptions) error {
+	n := len(samples)
+	if n == 0 {
+		return fmt.Errorf(tr("nenhuma requisição concluída em %s"), elapsed.Round(time.Millisecond))
+	}
+	lats := make([]time.Duration, 0, n)
+	statuses := map[int]int{}
+	errCount := 0
+	for _, s := range samples {
+		lats = append(lats, s.latency)
+		statuses[s.status]++
+		if s.err {
+			errCount++
+		}
+	}
+	throughput := float64(n) / elapsed.Seconds()
+	errRate := float64(errCount) / float64(n)
+	p50, p95, p99 := percentileDur(lats, 50), percentileDur(lats, 95), percentileDur(lats, 99)
+
+	outf("[load] %d requisições em %s → %.1f req/s\n", n, elapsed.Round(time.Millisecond), throughput)
+	outf("[load] erros=%d (%.2f%%) status=%s", errCount, errRate*100, formatStatusCounts(statuses))
+	if dropped > 0 {
+		outf(" sem worker livre=%d", dropped)
+	}
+	outln()
+	outf("[load] latência p50=%s p95=%s p99=%s máx=%s\n",
+		p50.Round(time.Millisecond), p95.Round(time.Millisecond), p99.Round(time.Millisecond),
+		percentileDur(lats, 100).Round(time.Millisecond))
+
+	counts := make([]int, len(loadBuckets)+1)
+	for _, l := range lats {
+		i := 0
+		for i < len(loadBuckets) && l > loadBuckets[i] {
+			i++
+		}
+		counts[i]++
+	}
+	maxCount := 0
+	for _, c := range counts {
+		maxCount = max(maxCount, c)
+	}
+	for i, c := range counts {
+		label := "> " + loadBuckets[len(loadBuckets)-1].String()
+		if i < len(loadBuckets) {
+			label = "≤ " + loadBuckets[i].String()
+		}
+		bar := strings.Repeat("█", c*40/maxCount)
+		outf("  %-8s %6d %s\n", label, c, bar)
+	}
+
+	setResult("load", map[string]any{
+		"requests":       n,
+		"duration_ms":    elapsed.Milliseconds(),
+		"throughput_rps": throughput,
+		"errors":         errCount,
+		"error_rate":     errRate,
+		"dropped":        dropped,
+		"status":         statuses,
+		"p50_ms":         p50.Milliseconds(),
+		"p95_ms":         p95.Milliseconds(),
+		"p99_ms":         p99.Milliseconds(),
+	})
+	if opt.MaxErrorRate >= 0 && errRate > opt.MaxErrorRate {
+		return fmt.Errorf(tr("taxa de erro %.2f%% acima do limite %.2f%%"), errRate*100, opt.MaxErrorRate*100)
+	}
+	return nil
+}
+
+// {200: 10, 0: 2} → "200=10 erro=2" (0 = sem resposta)
+func formatStatusCounts(m map[int]int) string {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == 0 {
+			parts = append(parts, fmt.Sprintf(tr("erro=%d"), m[k]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d=%d", k, m[k]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
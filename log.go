@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+/* ==================== Log estruturado (--log-level, --log-format, --log-file) ==================== */
+
+// desligado por padrão: o resumo humano continua no stdout (outf/outln) e o log só sai
+// quando alguma das flags (ou LOG_LEVEL, LOG_FORMAT, LOG_FILE) aparece
+var logger = slog.New(nopHandler{})
+
+var (
+	logLevels  = []string{"debug", "info", "warn", "error"}
+	logFormats = []string{"text", "json"}
+)
+
+type nopHandler struct{}
+
+func (nopHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (nopHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h nopHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h nopHandler) WithGroup(string) slog.Handler           { return h }
+
+type logOptions struct {
+	Level, Format, File string
+}
+
+func (o logOptions) enabled() bool {
+	return o.Level != "" || o.Format != "" || o.File != ""
+}
+
+// flags globais de log (sobrescrevem LOG_* do ambiente)
+func stripLogFlags(args []string) ([]string, logOptions, error) {
+	o := logOptions{
+		Level:  os.Getenv("LOG_LEVEL"),
+		Format: os.Getenv("LOG_FORMAT"),
+		File:   os.Getenv("LOG_FILE"),
+	}
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{"--log-level", &o.Level},
+		{"--log-format", &o.Format},
+		{"--log-file", &o.File},
+	} {
+		var v string
+		var ok bool
+		var err error
+		args, v, ok, err = stripValueFlag(args, f.name)
+		if err != nil {
+			return nil, o, err
+		}
+		if ok {
+			*f.dst = v
+		}
+	}
+	return args, o, nil
+}
+
+// monta o logger: stderr por padrão, --log-file acrescenta ao arquivo; nível info e formato text
+func configureLogging(o logOptions) error {
+	if !o.enabled() {
+		return nil
+	}
+	level := slog.LevelInfo
+	if o.Level != "" {
+		if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+			return usageError(fmt.Sprintf(tr("--log-level deve ser um de %s, veio %q"), strings.Join(logLevels, ", "), o.Level))
+		}
+	}
+	var w io.Writer = os.Stderr
+	if o.File != "" {
+		f, err := os.OpenFile(expandHome(o.File), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("--log-file: %w", err)
+		}
+		w = f
+	}
+	hopts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(o.Format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, hopts)
+	case "json":
+		h = slog.NewJSONHandler(w, hopts)
+	default:
+		return usageError(fmt.Sprintf(tr("--log-format deve ser text ou json, veio %q"), o.Format))
+	}
+	logger = slog.New(h).With("run_id", runID)
+	return nil
+}
+
+// as linhas "[tag] texto" do outf também viram registro, com a tag em "component";
+// o texto vai sem tradução para a chave não mudar com o --lang
+func logTagged(format string, a ...any) {
+	// warn é o nível mais alto daqui: se nem ele sai, nada sai
+	if !strings.HasPrefix(format, "[") || !logger.Enabled(context.Background(), slog.LevelWarn) {
+		return
+	}
+	tag, rest, ok := strings.Cut(strings.TrimSpace(fmt.Sprintf(format, a...)), "] ")
+	if !ok {
+		return
+	}
+	tag = strings.TrimPrefix(tag, "[")
+	level := slog.LevelInfo
+	switch tag {
+	case "retry", "429", "oauth", "breaker":
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
+	logger.Log(context.Background(), level, strings.TrimSpace(rest), "component", tag)
+}
+
+// uma requisição HTTP enviada (nível debug); a query sai com os segredos mascarados
+func logHTTP(method, url string, status int, elapsed time.Duration, err error) {
+	if !logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		"component", "http", "method", method, "url", redactQuery(url),
+		"endpoint", callEndpoint(method, url), "latency_ms", elapsed.Milliseconds(),
+	}
+	if err != nil {
+		logger.Debug("request failed", append(attrs, "error", err.Error())...)
+		return
+	}
+	logger.Debug("request", append(attrs, "status", status)...)
+}
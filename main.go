@@ -6,20 +6,24 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"image"
 	"io"
+	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
-
-	"github.com/joho/godotenv"
-	"mime"
 )
 
 /* ==================== Config & Helpers ==================== */
 
-var httpClient = &http.Client{Timeout: 20 * time.Second}
+var httpClient = &http.Client{Timeout: defaultTimeout}
+
+// teto do corpo de resposta lido (protege contra respostas infladas, ex.: gzip-bomb)
+const maxResponseBytes = 64 << 20
+
 var quiet bool // controlado por --quiet/-q
 
 // remove --quiet/-q de qualquer posição e retorna args limpos + se quiet foi pedido
@@ -36,6 +40,132 @@ func stripQuiet(all []string) ([]string, bool) {
 	return out, q
 }
 
+// remove a flag booleana "--name" de qualquer posição
+func stripBoolFlag(all []string, name string) ([]string, bool) {
+	out := make([]string, 0, len(all))
+	found := false
+	for _, a := range all {
+		if a == name {
+			found = true
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, found
+}
+
+// remove "--name valor" ou "--name=valor" de qualquer posição; retorna o último valor
+func stripValueFlag(all []string, name string) ([]string, string, bool, error) {
+	out := make([]string, 0, len(all))
+	val, found := "", false
+	for i := 0; i < len(all); i++ {
+		a := all[i]
+		if a == name {
+			if i+1 >= len(all) {
+				return nil, "", false, fmt.Errorf(tr("%s exige um valor"), name)
+			}
+			val, found = all[i+1], true
+			i++
+			continue
+		}
+		if strings.HasPrefix(a, name+"=") {
+			val, found = strings.TrimPrefix(a, name+"="), true
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, val, found, nil
+}
+
+// flag repetível (--env-file a --env-file b): devolve os valores na ordem
+func stripValueFlags(all []string, name string) ([]string, []string, error) {
+	out := make([]string, 0, len(all))
+	var vals []string
+	for i := 0; i < len(all); i++ {
+		a := all[i]
+		if a == name {
+			if i+1 >= len(all) {
+				return nil, nil, fmt.Errorf(tr("%s exige um valor"), name)
+			}
+			vals = append(vals, all[i+1])
+			i++
+			continue
+		}
+		if v, ok := strings.CutPrefix(a, name+"="); ok {
+			vals = append(vals, v)
+			continue
+		}
+		out = append(out, a)
+	}
+	return out, vals, nil
+}
+
+// lê os arquivos em ordem (o posterior sobrescreve o anterior) e só define o que o ambiente não tem
+func loadEnvFiles(paths []string) error {
+	merged := map[string]string{}
+	for _, p := range paths {
+		m, err := readEnvFile(expandHome(p))
+		if err != nil {
+			return fmt.Errorf("--env-file %s: %w", p, err)
+		}
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+	for k, v := range merged {
+		if _, exists := os.LookupEnv(k); !exists {
+			_ = os.Setenv(k, v)
+		}
+	}
+	return nil
+}
+
+// flags globais de retry (sobrescrevem RETRY_* do ambiente)
+func stripRetryFlags(args []string) ([]string, error) {
+	setters := []struct {
+		name string
+		set  func(string) error
+	}{
+		{"--retries", setRetryMax},
+		{"--retry-delay", setRetryDelay},
+		{"--retry-jitter", setRetryJitter},
+		{"--retry-on", setRetryStatuses},
+	}
+	if rest, ok := stripBoolFlag(args, "--retry-post"); ok {
+		args, retryCfg.POST = rest, true
+	}
+	for _, s := range setters {
+		var v string
+		var ok bool
+		var err error
+		args, v, ok, err = stripValueFlag(args, s.name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			if err := s.set(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return args, nil
+}
+
+// encerra o processo mostrando antes o total de retentativas (se houve)
+func exit(code int) {
+	if n := retryCount.Load(); n > 0 {
+		outf("[retry] %d retentativa(s) nesta execução\n", n)
+	}
+	if s := throttleSummary(); s != "" {
+		outln(s)
+	}
+	if s := limiter.summary(); s != "" {
+		outln(s)
+	}
+	removeTempImages()
+	os.Exit(code)
+}
+
 // pega valor do ambiente com default
 func envOr(key, def string) string {
 	if v := os.Getenv(key); v != "" {
@@ -80,17 +210,16 @@ func guessMIME(path string) string {
 }
 
 func buildDataURIImage(path string) (string, error) {
-	b, err := os.ReadFile(path)
+	b, m, err := loadUploadImage(path)
 	if err != nil {
 		return "", err
 	}
-	m := guessMIME(path)
 	b64 := base64.StdEncoding.EncodeToString(b)
 	return "data:" + m + ";base64," + b64, nil
 }
 
 func readImageAsBase64(path string) (string, error) {
-	b, err := os.ReadFile(path)
+	b, _, err := loadUploadImage(path)
 	if err != nil {
 		return "", err
 	}
@@ -105,33 +234,141 @@ func authHeader(token string) http.Header {
 }
 
 func doJSON(method, url string, headers http.Header, body any) (*http.Response, []byte, error) {
-	var rdr io.Reader
+	var jb []byte
 	if body != nil {
-		jb, err := json.Marshal(body)
+		var err error
+		jb, err = json.Marshal(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf(tr("marshal body: %w"), err)
+		}
+	}
+	return doRequest(method, url, headers, jb)
+}
+
+// executa a requisição com retry (ver retryCfg) e devolve a resposta com o corpo já lido
+func doRequest(method, url string, headers http.Header, body []byte) (*http.Response, []byte, error) {
+	return doRequestBody(method, url, headers, reqBody{data: body})
+}
+
+func doRequestBody(method, url string, headers http.Header, body reqBody) (*http.Response, []byte, error) {
+	start := time.Now()
+	throttled := 0
+	for attempt := 1; ; attempt++ {
+		resp, b, err := doOnceBody(method, url, headers, body)
+		// 429: espera o Retry-After (todos os workers juntos) sem gastar tentativa do retry
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests && throttled < throttleCfg.MaxRetries {
+			wait := retryAfterDelay(resp.Header.Get("Retry-After"), throttled+1)
+			if wait <= throttleCfg.MaxWait {
+				throttled++
+				pauseForThrottle(wait)
+				outf("[429] %s %s → esperando %s (%d/%d)\n", method, url, wait.Round(time.Millisecond), throttled, throttleCfg.MaxRetries)
+				attempt--
+				continue
+			}
+			outf("[429] %s %s → Retry-After de %s passa do teto %s (RETRY_429_MAX_WAIT), desistindo\n", method, url, wait.Round(time.Second), throttleCfg.MaxWait)
+		}
+		if attempt >= retryCfg.MaxAttempts || !shouldRetry(method, resp, err) {
+			if err == nil {
+				err = validateResponse(resp, b)
+			}
+			recordCall(method, url, resp, b, err, attempt, time.Since(start))
+			forgetChangedCard(method, url, resp)
+			return resp, b, err
+		}
+		wait := backoffDelay(attempt)
+		reason := ""
 		if err != nil {
-			return nil, nil, fmt.Errorf("marshal body: %w", err)
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("status=%d", resp.StatusCode)
+		}
+		retryCount.Add(1)
+		outf("[retry] %s %s → %s; tentativa %d/%d em %s\n",
+			method, url, reason, attempt+1, retryCfg.MaxAttempts, wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+}
+
+func doOnce(method, url string, headers http.Header, body []byte) (*http.Response, []byte, error) {
+	return doOnceBody(method, url, headers, reqBody{data: body})
+}
+
+func doOnceBody(method, url string, headers http.Header, body reqBody) (*http.Response, []byte, error) {
+	resp, b, used, err := sendOnce(method, url, headers, body)
+	// token OAuth expirou no meio da execução: renova e repete uma vez
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && used != "" {
+		if _, rerr := oauth.refresh(used); rerr != nil {
+			outf("[oauth] 401 e a renovação falhou: %v\n", rerr)
+			return resp, b, err
 		}
-		rdr = bytes.NewReader(jb)
+		outf("[oauth] 401 em %s %s → token renovado, repetindo\n", method, url)
+		resp, b, _, err = sendOnce(method, url, headers, body)
+	}
+	return resp, b, err
+}
+
+// uma requisição; devolve também o token OAuth usado ("" se não houve)
+func sendOnce(method, url string, headers http.Header, body reqBody) (*http.Response, []byte, string, error) {
+	rdr, size, err := body.open()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("build request: %w", err)
 	}
 	req, err := http.NewRequest(method, url, rdr)
 	if err != nil {
-		return nil, nil, fmt.Errorf("build request: %w", err)
+		return nil, nil, "", fmt.Errorf("build request: %w", err)
+	}
+	if body.stream != nil {
+		req.ContentLength = size
 	}
 	for k, vv := range headers {
 		for _, v := range vv {
 			req.Header.Add(k, v)
 		}
 	}
+	if dryRun {
+		if err := signer.sign(req, body, time.Now()); err != nil {
+			return nil, nil, "", err
+		}
+		resp, b := dryRunResponse(req, body)
+		return resp, b, "", nil
+	}
+	used := applyOAuth(req)
+	waitThrottle()
+	limiter.wait()
+	// assina depois das esperas (--rpm, Retry-After): o X-Timestamp sai com a hora do envio
+	if err := signer.sign(req, body, time.Now()); err != nil {
+		return nil, nil, "", err
+	}
+	var rt *requestTrace
+	if verbosity > 0 {
+		req, rt = withTrace(req)
+		dumpRequest(req, body.dumpable())
+	}
+	start := time.Now()
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, nil, fmt.Errorf("do request: %w", err)
+		prom.observe(method, url, 0, true, time.Since(start))
+		logHTTP(method, url, 0, time.Since(start), err)
+		if rt != nil {
+			dumpResponse(nil, nil, rt, err)
+		}
+		return nil, nil, used, fmt.Errorf("do request: %w", err)
 	}
+	prom.observe(method, url, resp.StatusCode, false, time.Since(start))
+	sloLat.observe(method, url, time.Since(start))
+	logHTTP(method, url, resp.StatusCode, time.Since(start), nil)
 	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	if rt != nil {
+		dumpResponse(resp, b, rt, nil)
+	}
 	if err != nil {
-		return resp, nil, fmt.Errorf("read body: %w", err)
+		return resp, nil, used, fmt.Errorf("read body: %w", err)
 	}
-	return resp, b, nil
+	if len(b) > maxResponseBytes {
+		return resp, nil, used, fmt.Errorf(tr("read body: resposta maior que %d MiB, abortada"), maxResponseBytes>>20)
+	}
+	return resp, b, used, nil
 }
 
 /* ==================== Tipos de resposta ==================== */
@@ -148,170 +385,236 @@ type VerifyResponse struct {
 	} `json:"response"`
 }
 
+// percentual de similaridade (response.percentage, ou o do topo se vazio)
+func (v VerifyResponse) Similarity() string {
+	if v.Response.Percentage != "" {
+		return v.Response.Percentage
+	}
+	return v.Percentage
+}
+
 /* ==================== Comandos ==================== */
 
 // POST /api/card/integration/register
-func cmdCreateCard(baseURL, token, imagePath, id, name string, consent bool) error {
-	img64, err := readImageAsBase64(imagePath)
-	if err != nil {
-		return fmt.Errorf("ler imagem: %w", err)
-	}
-	payload := map[string]any{
-		"id":                 id,
-		"name":               name,
-		"consentTermSigned":  consent,
-		"image":              img64,
-	}
-	url := strings.TrimRight(baseURL, "/") + "/api/card/integration/register"
-	resp, body, err := doJSON(http.MethodPost, url, authHeader(token), payload)
+func cmdCreateCard(baseURL, token, imagePath, id, name string, consent bool, enc string) error {
+	resp, body, err := createCard(baseURL, token, imagePath, id, name, consent, enc)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("status=%d\n", resp.StatusCode)
+	outf("status=%d\n", resp.StatusCode)
 	if !quiet {
-		fmt.Println(string(body))
+		outln(string(body))
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("requisição falhou: %d", resp.StatusCode)
+	if err := checkStatus(resp, body); err != nil {
+		return err
 	}
 	return nil
 }
 
-// GET /api/card/integration/mainimage (header idCard); salva arquivo
-func cmdMainImage(baseURL, token, idCard, outPath string) error {
+// register sem imprimir nada (usado também pelo batch); enc "" = base64
+func createCard(baseURL, token, imagePath, id, name string, consent bool, enc string) (*http.Response, []byte, error) {
+	if enc == "" {
+		enc = encBase64
+	}
+	payload := map[string]any{
+		"id":                id,
+		"name":              name,
+		"consentTermSigned": consent,
+	}
+	if d := ttlDetail(time.Now()); d != "" {
+		payload["detail"] = d
+	}
+	ct, body, err := encodeImagePayload(payload, imagePath, enc)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("ler imagem: %w"), err)
+	}
+	h := authHeader(token)
+	h.Set("Content-Type", ct)
+	url := strings.TrimRight(baseURL, "/") + "/api/card/integration/register"
+	return doRequestBody(http.MethodPost, url, h, body)
+}
+
+type mainImageOptions struct {
+	IDCard      string
+	Out         string
+	Decode      bool
+	Compare     string // original local; vazio = não compara
+	MaxDistance int
+	MinSSIM     float64
+}
+
+// GET /api/card/integration/mainimage (header idCard); salva arquivo com a extensão do conteúdo
+func cmdMainImage(baseURL, token string, opt mainImageOptions) error {
+	idCard, outPath := opt.IDCard, opt.Out
 	url := strings.TrimRight(baseURL, "/") + "/api/card/integration/mainimage"
 	h := authHeader(token)
 	h.Set("idCard", idCard)
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	resp, b, err := doRequest(http.MethodGet, url, h, nil)
 	if err != nil {
 		return err
 	}
-	for k, vv := range h {
-		for _, v := range vv {
-			req.Header.Add(k, v)
+	outf("status=%d\n", resp.StatusCode)
+	if resp.StatusCode != 200 {
+		if !quiet {
+			outln(string(b))
 		}
+		if err := checkStatus(resp, b); err != nil {
+			return err
+		}
+		return fmt.Errorf(tr("esperado 200, veio %d"), resp.StatusCode)
 	}
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return err
+	// os bytes mandam: o Content-Type do servidor às vezes é octet-stream ou está errado
+	sniffed := http.DetectContentType(b)
+	ext, ok := sniffedImageExt[sniffed]
+	if !ok {
+		return fmt.Errorf(tr("main-image: resposta não é imagem (%d bytes, %s; Content-Type %q)"), len(b), sniffed, resp.Header.Get("Content-Type"))
 	}
-	defer resp.Body.Close()
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
+	if ct, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";"); ct != "" && strings.HasPrefix(ct, "image/") && ct != sniffed {
+		outf("[main-image] Content-Type %s, mas o conteúdo é %s\n", ct, sniffed)
 	}
-	fmt.Printf("status=%d\n", resp.StatusCode)
-	if resp.StatusCode != 200 {
-		if !quiet {
-			fmt.Println(string(b))
+	if opt.Decode {
+		if _, _, err := image.Decode(bytes.NewReader(b)); err != nil {
+			return fmt.Errorf(tr("main-image: %s não decodifica: %v"), sniffed, err)
 		}
-		return fmt.Errorf("esperado 200, veio %d", resp.StatusCode)
 	}
-	if outPath == "" {
-		outPath = "mainimage.bin"
+	switch {
+	case outPath == "":
+		outPath = "mainimage" + ext
+	case filepath.Ext(outPath) == "":
+		outPath += ext
+	case !sameImageExt(filepath.Ext(outPath), ext):
+		outf("[main-image] %s tem extensão %s, mas o conteúdo é %s\n", outPath, filepath.Ext(outPath), sniffed)
 	}
 	if err := os.WriteFile(outPath, b, 0644); err != nil {
 		return err
 	}
-	fmt.Printf("imagem salva em %s (%d bytes)\n", outPath, len(b))
-	return nil
-}
-
-// POST /api/card/integration/verify (JSON com data-uri)
-func cmdVerifyCard(baseURL, token, endpointPath, imagePath, id, name, detail string) error {
-	if endpointPath == "" {
-		endpointPath = "/api/card/integration/verify"
-	}
-	dataURI, err := buildDataURIImage(imagePath)
+	meta, err := readImageMeta(outPath)
 	if err != nil {
-		return fmt.Errorf("ler/encode imagem: %w", err)
+		return err
 	}
-	body := map[string]any{
-		"id":     id,
-		"name":   name,
-		"detail": detail,
-		"image":  dataURI,
+	outf("imagem salva em %s (%s)\n", outPath, meta)
+	setResult("saved", outPath)
+	setResult("image", meta)
+	if opt.Compare == "" {
+		return nil
+	}
+	c, err := compareWithOriginal(b, opt.Compare, opt.MaxDistance, opt.MinSSIM)
+	if err != nil {
+		return err
 	}
+	return reportComparison(c)
+}
 
-	url := strings.TrimRight(baseURL, "/") + endpointPath
-	h := authHeader(token)
+func sameImageExt(a, b string) bool {
+	norm := func(e string) string {
+		e = strings.ToLower(e)
+		if e == ".jpeg" || e == ".jpe" {
+			return ".jpg"
+		}
+		return e
+	}
+	return norm(a) == norm(b)
+}
 
-	fmt.Printf("[verify] POST %s (JSON)\n", url)
-	resp, raw, err := doJSON(http.MethodPost, url, h, body)
+// POST /api/card/integration/verify (JSON com data-uri).
+// Com minSimilarity > 0 vira gate: falha se success=false ou se a similaridade ficar abaixo do mínimo.
+func cmdVerifyCard(baseURL, token, endpointPath, imagePath, id, name, detail string, minSimilarity float64, enc string) error {
+	kind := "JSON"
+	if enc == encMultipart {
+		kind = "multipart"
+	}
+	outf("[verify] POST %s (%s)\n", verifyURL(baseURL, endpointPath), kind)
+	resp, raw, err := verifyCard(baseURL, token, endpointPath, imagePath, id, name, detail, enc)
 	if err != nil {
 		return err
 	}
-	fmt.Printf("status=%d\n", resp.StatusCode)
+	outf("status=%d\n", resp.StatusCode)
 	if !quiet {
-		fmt.Println(string(raw))
+		outln(string(raw))
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("requisição falhou: %d", resp.StatusCode)
+	if meta, err := readImageMeta(imagePath); err == nil {
+		outf("[image] %s\n", meta)
+		setResult("image", meta)
+	}
+	if err := checkStatus(resp, raw); err != nil {
+		return err
 	}
 
 	var vresp VerifyResponse
-	if err := json.Unmarshal(raw, &vresp); err == nil {
-		ok := "❌"
-		if vresp.Response.Success {
-			ok = "✅"
+	if err := json.Unmarshal(raw, &vresp); err != nil {
+		if minSimilarity > 0 {
+			return fmt.Errorf(tr("resposta inválida, não dá para checar --min-similarity: %w"), err)
 		}
-		pct := vresp.Response.Percentage
-		if pct == "" {
-			pct = vresp.Percentage
-		}
-		fmt.Printf("[verify] %s match | similaridade=%s | status=%d | idLog=%s\n",
-			ok, pct, vresp.Response.Status, vresp.Response.IDLog)
+		return nil
 	}
-	return nil
-}
-
-// DELETE /api/card/{id}
-func cmdDeleteCard(baseURL, token, id string) error {
-	if id == "" {
-		return fmt.Errorf("--id vazio (defina CARD_ID no .env ou use defaultID())")
+	ok := "❌"
+	if vresp.Response.Success {
+		ok = "✅"
 	}
-	url := strings.TrimRight(baseURL, "/") + "/api/card/" + id
+	pct := vresp.Similarity()
+	outf("[verify] %s match | similaridade=%s | status=%d | idLog=%s\n",
+		ok, pct, vresp.Response.Status, vresp.Response.IDLog)
+	setResult("match", vresp.Response.Success)
+	setResult("similarity", pct)
+	setResult("id_log", vresp.Response.IDLog)
 
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
-	if err != nil {
-		return err
+	if minSimilarity <= 0 {
+		return nil
 	}
-	for k, vv := range authHeader(token) {
-		for _, v := range vv {
-			req.Header.Add(k, v)
-		}
+	if !vresp.Response.Success {
+		return matchError(tr("verify sem match (success=false)"))
 	}
+	sim, valid := parsePercent(pct)
+	if !valid {
+		return fmt.Errorf(tr("similaridade ausente ou inválida: %q"), pct)
+	}
+	if sim < minSimilarity {
+		return matchError(fmt.Sprintf(tr("similaridade %.2f abaixo do mínimo %.2f"), sim, minSimilarity))
+	}
+	return nil
+}
 
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return err
+func verifyURL(baseURL, endpointPath string) string {
+	if endpointPath == "" {
+		endpointPath = "/api/card/integration/verify"
 	}
-	defer resp.Body.Close()
+	return strings.TrimRight(baseURL, "/") + endpointPath
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	fmt.Printf("status=%d\n", resp.StatusCode)
-	if len(body) > 0 && !quiet {
-		fmt.Println(string(body))
+// verify sem imprimir nada (usado também pelo batch); enc "" = data URI
+func verifyCard(baseURL, token, endpointPath, imagePath, id, name, detail, enc string) (*http.Response, []byte, error) {
+	if enc == "" {
+		enc = encDataURI
 	}
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("falha ao deletar: %d", resp.StatusCode)
+	fields := map[string]any{
+		"id":     id,
+		"name":   name,
+		"detail": detail,
 	}
-	return nil
+	ct, body, err := encodeImagePayload(fields, imagePath, enc)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("ler/encode imagem: %w"), err)
+	}
+	h := authHeader(token)
+	h.Set("Content-Type", ct)
+	return doRequestBody(http.MethodPost, verifyURL(baseURL, endpointPath), h, body)
 }
 
-// Deleta ignorando 404/422 (registro não existe)
-func cmdDeleteCardIgnore404(baseURL, token, id string) error {
-	err := cmdDeleteCard(baseURL, token, id)
+// DELETE /api/card/{id}
+func cmdDeleteCard(baseURL, token, id string) error {
+	if id == "" {
+		return fmt.Errorf(tr("--id vazio (defina CARD_ID no .env ou use defaultID())"))
+	}
+	resp, body, err := doRequest(http.MethodDelete, cardURL(baseURL, id), authHeader(token), nil)
 	if err != nil {
-		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "422") {
-			fmt.Printf("[preclean] id=%s não existe ou já foi deletado, seguindo…\n", id)
-			return nil
-		}
 		return err
 	}
-	fmt.Printf("[preclean] id=%s deletado\n", id)
-	return nil
+	outf("status=%d\n", resp.StatusCode)
+	if len(body) > 0 && !quiet {
+		outln(string(body))
+	}
+	return checkStatus(resp, body)
 }
 
 /* ==================== UI ==================== */
@@ -319,133 +622,1496 @@ func cmdDeleteCardIgnore404(baseURL, token, id string) error {
 func usage() {
 	fmt.Println("biodoc-go-runner")
 	fmt.Println()
-	fmt.Println("Comandos:")
-	fmt.Println("  create-card   - Cria card a partir de imagem")
-	fmt.Println("  verify-card   - Verifica imagem atual (POST /api/card/integration/verify)")
-	fmt.Println("  delete-card   - Deleta a carteirinha (DELETE /api/card/{id})")
-	fmt.Println("  main-image    - Baixa imagem principal (header idCard)")
-	fmt.Println("  run-all       - preclean → create → verify → delete")
+	fmt.Println(tr("Comandos:"))
+	fmt.Println(tr("  create-card   - Cria card a partir de imagem (--encoding base64|datauri|multipart)"))
+	fmt.Println(tr("  verify-card   - Verifica imagem atual (POST /api/card/integration/verify; --camera: foto da webcam)"))
+	fmt.Println(tr("                  várias --image (ou glob): melhor/pior/média e veredito agregado (--require all|majority|any)"))
+	fmt.Println(tr("  liveness-check - Prova de vida com selfie ou vídeo curto; falha se o score ficar abaixo de --min-score"))
+	fmt.Println(tr("  verify-document - Lê nome e número de um documento (OCR); --expect-name/--expect-id conferem"))
+	fmt.Println(tr("  get-card      - Mostra os dados do card (GET /api/card/{id})"))
+	fmt.Println(tr("  update-card   - Troca imagem, nome ou consentimento de um card (PATCH/PUT)"))
+	fmt.Println(tr("  list-cards    - Lista cards com paginação e filtro por nome"))
+	fmt.Println(tr("  delete-card   - Deleta a carteirinha (DELETE /api/card/{id})"))
+	fmt.Println(tr("  delete-cards  - Apaga em lote (--ids-file ids.txt ou --prefix 9998*), com workers; 404 não para o lote"))
+	fmt.Println(tr("  reap          - Apaga cards cujo TTL (gravado no detail com --ttl) já venceu"))
+	fmt.Println(tr("  main-image    - Baixa imagem principal (header idCard) com a extensão do conteúdo; valida e mostra as dimensões"))
+	fmt.Println(tr("                  --compare original.jpg: falha se a foto guardada divergir do original (dHash/SSIM)"))
+	fmt.Println(tr("  run-all       - preclean → create → verify → delete (--rehearse: ensaia antes no mock embutido)"))
+	fmt.Println(tr("  run-matrix    - Ciclo completo (preclean → create → verify → delete) para vários ids em paralelo, com tabela pass/fail"))
+	fmt.Println(tr("  run-scenario  - Executa as etapas de um cenário YAML (--file, --var nome=valor)"))
+	fmt.Println(tr("  run-pipeline  - Executa uma pipeline nomeada do config (pipelines:); etapas com when: always|on_failure e retries; sem nome, lista"))
+	fmt.Println(tr("  batch-create  - Cria vários cards a partir de um manifesto CSV/JSON (imagens locais ou s3://, gs://)"))
+	fmt.Println(tr("                  pula id+imagem já criados antes (cache em CREATE_CACHE, 0 desliga; --force recria)"))
+	fmt.Println(tr("  batch-verify  - Verifica todas as imagens de um diretório/glob ou de um bucket (s3://bucket/prefixo, gs://...)"))
+	fmt.Println(tr("  watch         - Observa uma pasta e verifica cada imagem nova que aparecer (id do nome do arquivo ou --id)"))
+	fmt.Println(tr("  anonymize-image - Pixeliza/borra o rosto das imagens (ou das execuções com falha) para anexar em chamados"))
+	fmt.Println(tr("  doctor        - Diagnostica o ambiente: .env/perfil, BASE_URL, DNS/TCP/TLS, token (exp do JWT), latência, com correções"))
+	fmt.Println(tr("  token-info    - Decodifica o JWT do AUTH_TOKEN: issuer, audience, subject e expiração (exit 3 se vencido)"))
+	fmt.Println(tr("  craft-jwt     - Pede ao mock-server um JWT vencido ou quase (--expires-in -5m); imprime só o token"))
+	fmt.Println(tr("  api           - Qualquer operação da spec OpenAPI: api list | describe ID | call ID --param valor (--spec ARQ ou OPENAPI_SPEC)"))
+	fmt.Println(tr("  gen-data      - Gera manifesto de cards sintéticos (CPF/CNS válidos, nomes, imagens do pool); --create já cadastra"))
+	fmt.Println(tr("  fixtures dedupe - Aponta imagens idênticas ou quase iguais (hash perceptual) no pool de fixtures"))
+	fmt.Println(tr("  report sla    - Disponibilidade, p95 e orçamento de erro por hora/dia a partir do results store"))
+	fmt.Println(tr("  history       - Execuções gravadas em SQLite: list, show N|RUN_ID, rerun N repete com as mesmas flags (BIODOC_HISTORY=0 desliga)"))
+	fmt.Println(tr("  login         - Guarda AUTH_TOKEN (ou --oauth: client secret) no keyring do SO, lido do stdin"))
+	fmt.Println(tr("  logout        - Remove as credenciais do perfil atual do keyring"))
+	fmt.Println(tr("  diff-runs A B - Compara status, similaridade e latência de duas execuções (history, results store ou JSON) e aponta regressões"))
+	fmt.Println(tr("  diff-fuzz     - Manda os mesmos payloads gerados a A e B (versões/ambientes) e aponta diferenças"))
+	fmt.Println(tr("  normalize     - Aplica regras de normalização a um JSON (arquivo ou stdin)"))
+	fmt.Println(tr("  load-verify   - Dispara verify em carga (--rps ou --workers) e mede vazão, erros e p50/p95/p99"))
+	fmt.Println(tr("  soak          - verify (ou --mode cycle) em ritmo baixo por horas: taxa de erro, drift de latência e renovações de token por janela"))
+	fmt.Println(tr("  interactive   - Assistente passo a passo: escolhe a operação, a imagem (navegando pelas pastas) e o ID; destaca a similaridade"))
+	fmt.Println(tr("  serve         - Expõe POST /create, POST /verify e DELETE /delete/{id} localmente, repassando ao Biodoc com o token do runner"))
+	fmt.Println(tr("  completion    - Script de autocompletar de comandos e flags: completion [--bin NOME] bash|zsh|fish|powershell"))
+	fmt.Println(tr("  mock-server   - Sobe um Biodoc falso local (register/verify/delete/mainimage/liveness/document)"))
+	fmt.Println(tr("  proxy         - Repassa tráfego para a API gravando cassete e métricas; --replay serve um cassete"))
+	fmt.Println(tr("                  --latency/--reset/--fail/--fault injetam falhas sorteadas (teste de retry e circuit breaker)"))
+	fmt.Println()
+	fmt.Println(tr("Geral (ENV): BASE_URL, AUTH_TOKEN, CARD_ID, CARD_IMAGE, VERIFY_IMAGE (opcionais)"))
+	fmt.Println(tr("  --image - lê a imagem do stdin; --image https://... baixa antes do envio (também em CARD_IMAGE/VERIFY_IMAGE)"))
+	fmt.Println(tr("  sem AUTH_TOKEN/OAUTH_CLIENT_SECRET definidos, lê do keyring gravado pelo login (por perfil)"))
+	fmt.Println(tr("S3/GCS (ENV): AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION, AWS_ENDPOINT_URL_S3;"))
+	fmt.Println(tr("  GCS_ACCESS_TOKEN (ou GOOGLE_OAUTH_ACCESS_TOKEN), STORAGE_EMULATOR_HOST; sem credenciais, bucket público"))
+	fmt.Println("OAuth2 (ENV): OAUTH_TOKEN_URL, OAUTH_CLIENT_ID, OAUTH_CLIENT_SECRET, OAUTH_SCOPE, OAUTH_CACHE=0")
+	fmt.Println(tr("  client credentials no lugar do AUTH_TOKEN; renova sozinho antes de vencer e em 401"))
+	fmt.Println("HMAC (ENV): HMAC_SECRET, HMAC_HEADER, HMAC_TIMESTAMP_HEADER, HMAC_FIELDS, HMAC_ALGORITHM, HMAC_ENCODING")
+	fmt.Println(tr("  assina toda requisição: HMAC de method, path, sha256 do corpo e timestamp (X-Signature + X-Timestamp)"))
+	fmt.Println(tr("Telemetria (opt-in): telemetry: {enabled: true, url: ...} no config ou BIODOC_TELEMETRY=1 + BIODOC_TELEMETRY_URL"))
+	fmt.Println(tr("  envia só comando, nomes das flags, duração e categoria da falha; BIODOC_TELEMETRY=0 desliga"))
 	fmt.Println()
-	fmt.Println("Geral (ENV): BASE_URL, AUTH_TOKEN, CARD_ID (opcional)")
+	fmt.Println(tr("Flags globais (qualquer posição):"))
+	fmt.Println(tr("  -q, --quiet          - não imprime o corpo das respostas"))
+	fmt.Println(tr("  -v, -vv, -vvv        - dump das requisições: tempos (DNS/connect/TLS/TTFB), headers, corpos (token mascarado)"))
+	fmt.Println(tr("  --lang en|pt-BR      - idioma das mensagens (ENV BIODOC_LANG; sem ele, o locale em LANG)"))
+	fmt.Println(tr("  --output text|json   - json: um objeto por comando em stdout (texto vai para stderr)"))
+	fmt.Println(tr("  --log-level NÍVEL    - log estruturado (slog) em stderr: debug (cada requisição), info, warn, error (ENV LOG_LEVEL)"))
+	fmt.Println(tr("  --log-format FMT     - text (chave=valor) ou json, um registro por linha (ENV LOG_FORMAT)"))
+	fmt.Println(tr("  --log-file ARQ       - acrescenta o log ao arquivo no lugar do stderr (ENV LOG_FILE); o resumo segue no stdout"))
+	fmt.Println(tr("  --jq CAMINHO         - imprime só esse campo da última resposta (.response.id_Log, .items[0].id; repetível; também --extract)"))
+	fmt.Println(tr("  --junit ARQ.xml      - grava relatório JUnit (uma etapa por testcase)"))
+	fmt.Println(tr("  --notify-webhook URL - posta o resumo (ok/falhas, etapas com falha, links) no Slack/Teams (ENV NOTIFY_WEBHOOK)"))
+	fmt.Println(tr("  --notify-on failure  - só notifica quando o comando falha (default always; ENV NOTIFY_ON)"))
+	fmt.Println(tr("  --report ARQ.html    - relatório HTML autocontido: etapas, similaridade, latências, ambiente (--report-images: miniaturas)"))
+	fmt.Println(tr("  --validate-schema    - falha a etapa se a resposta 2xx não bater com o schema embutido do endpoint (ENV VALIDATE_SCHEMA=1)"))
+	fmt.Println(tr("  --schema-spec ARQ    - valida contra a spec OpenAPI (YAML/JSON) no lugar dos embutidos (ENV SCHEMA_SPEC)"))
+	fmt.Println(tr("  --no-progress        - sem barra de progresso (batch-create, batch-verify, load-verify); sem terminal já não aparece"))
+	fmt.Println(tr("  --max-latency-p95 D  - falha se o p95 das respostas da API passar de D (também --max-latency-p99)"))
+	fmt.Println(tr("  --slo LISTA          - SLOs que falham a execução, ex.: verify.p95=800ms,create.p99=2s,total.max=30s (ENV LATENCY_SLO)"))
+	fmt.Println(tr("  --expect-status N    - falha (exit 1) se a última resposta não tiver esse status; com ele, o erro HTTP esperado passa"))
+	fmt.Println(tr("  --expect EXPR        - asserção no JSON da última resposta, repetível: 'response.success==true', '.response.percentage>=90'"))
+	fmt.Println(tr("  --expected-duration D - duração prevista; JWT que vence antes disso aborta (exit 3) antes de começar (ENV EXPECTED_DURATION)"))
+	fmt.Println(tr("                         lotes, run-matrix, watch, serve, load-verify e soak já têm estimativa própria"))
+	fmt.Println(tr("  --timing             - no fim, tempo por etapa e por endpoint"))
+	fmt.Println(tr("  --budget LISTA       - orçamentos, ex.: verify=1500ms,create=2s,total=10s (ENV TIMING_BUDGETS)"))
+	fmt.Println(tr("  --metrics-addr ADDR  - expõe métricas Prometheus em http://ADDR/metrics durante a execução"))
+	fmt.Println(tr("  --pushgateway URL    - envia as métricas a um Pushgateway a cada 10s e no fim"))
+	fmt.Println(tr("  --profile NOME       - usa o perfil do ~/.biodoc-runner.yaml (ENV BIODOC_PROFILE, BIODOC_RUNNER_CONFIG)"))
+	fmt.Println(tr("  --tenant NOME        - token do tenant em tokens: do perfil (ENV BIODOC_TENANT); --token/--token-file dão o token direto"))
+	fmt.Println(tr("                         os três valem só para esta execução, na frente de AUTH_TOKEN, keyring e OAuth"))
+	fmt.Println(tr("  --ttl D, --tag T     - cards criados levam tag e expiração no detail (ENV CARD_TTL, CARD_TAG)"))
+	fmt.Println(tr("  --env-file ARQ       - carrega esse .env em vez do diretório atual (repetível; o último ganha)"))
+	fmt.Println(tr("                         cifrado com age (chave em AGE_KEY_FILE/AGE_KEY) ou SOPS é decifrado na hora"))
+	fmt.Println(tr("  --results ARQ.jsonl  - acrescenta cada execução ao results store (ENV RESULTS_STORE)"))
+	fmt.Println(tr("  --proxy URL          - proxy HTTP(S) (ENV PROXY_URL; sem ele vale HTTPS_PROXY/NO_PROXY)"))
+	fmt.Println(tr("  --ca-cert ARQ.pem    - CAs extras (rede corporativa) somadas às do sistema (ENV CA_CERT)"))
+	fmt.Println(tr("  --client-cert/--client-key ARQ.pem - certificado de cliente para mTLS (ENV CLIENT_CERT, CLIENT_KEY)"))
+	fmt.Println(tr("  --timeout DUR        - limite por requisição (ENV HTTP_TIMEOUT; 0 = sem limite). Padrão 20s; 60s em create-card,"))
+	fmt.Println(tr("                         main-image, update-card, batch-create, watch e serve; 5s no doctor"))
+	fmt.Println(tr("  --keep-alive DUR     - intervalo do TCP keep-alive; 0 desliga conexões persistentes (ENV HTTP_KEEP_ALIVE)"))
+	fmt.Println(tr("  --idle-timeout DUR   - tempo de uma conexão ociosa no pool (ENV HTTP_IDLE_TIMEOUT; padrão 90s)"))
+	fmt.Println(tr("  --max-idle-conns N   - conexões ociosas guardadas, total e por host (ENV HTTP_MAX_IDLE_CONNS)"))
+	fmt.Println(tr("  --tls-handshake-timeout DUR - teto do handshake TLS (ENV HTTP_TLS_HANDSHAKE_TIMEOUT; padrão 10s)"))
+	fmt.Println(tr("  --har ARQ.har        - grava todo o tráfego em HAR 1.2 (tokens mascarados; --har-full-images mantém imagens)"))
+	fmt.Println(tr("  --bug-report ARQ.zip - se o comando falhar, gera zip com requisições/respostas, config, versão e log (sem tokens)"))
+	fmt.Println(tr("  --max-dimension PX   - reduz a imagem para esse maior lado antes do envio (reencoda em JPEG)"))
+	fmt.Println(tr("  --max-bytes N        - baixa qualidade/resolução até a imagem caber em N bytes"))
+	fmt.Println(tr("  --jpeg-quality Q     - qualidade do JPEG reencodado, 1..100 (default 85)"))
+	fmt.Println(tr("  --watermark          - carimba \"TEST <run id>\" numa faixa na base das imagens enviadas (--watermark-text T)"))
+	fmt.Println(tr("  --no-exif-fix        - não aplica a orientação do EXIF (por padrão a foto é endireitada e reencodada)"))
+	fmt.Println(tr("  --strip-metadata     - remove EXIF/XMP/IPTC (GPS, aparelho) do JPEG antes do envio"))
+	fmt.Println(tr("  --strict-quality     - aborta antes do envio se a imagem estiver escura, desfocada ou pequena (sem ela só avisa)"))
+	fmt.Println(tr("  --no-quality-check   - não mede a qualidade da imagem antes do envio"))
+	fmt.Println(tr("  --require-single-face - detecta rostos localmente e recusa imagem sem rosto ou com mais de um"))
+	fmt.Println(tr("  --dry-run            - monta e imprime as requisições (token mascarado, imagem resumida) sem enviar; reap/history/delete-cards têm o próprio"))
+	fmt.Println(tr("  --no-strict          - aceita argumentos soltos, imagem inexistente e AUTH_TOKEN ausente (só avisa)"))
+	fmt.Println(tr("  --record ARQ.json    - grava requisições/respostas num cassete (tokens mascarados)"))
+	fmt.Println(tr("  --replay ARQ.json    - responde a partir do cassete, sem rede (sem gravação → 501)"))
+	fmt.Println(tr("  --rps N, --rpm N     - no máximo N requisições por segundo/minuto, somando todos os workers (ENV RATE_LIMIT_RPS, RATE_LIMIT_RPM)"))
+	fmt.Println(tr("  --retries N          - total de tentativas por requisição (ENV RETRY_MAX, default 3)"))
+	fmt.Println(tr("  --retry-delay D      - espera base, dobra a cada tentativa (ENV RETRY_BASE_DELAY, default 500ms)"))
+	fmt.Println(tr("  --retry-jitter F     - variação aleatória da espera, 0..1 (ENV RETRY_JITTER, default 0.2)"))
+	fmt.Println(tr("  --retry-on LISTA     - status que disparam retry (ENV RETRY_STATUS, default 502,503,504)"))
+	fmt.Println(tr("  --retry-post         - repete também POST (cadastro pode duplicar; ENV RETRY_POST=1)"))
+	fmt.Println(tr("  429 espera o Retry-After (ou backoff) sem gastar tentativa: ENV RETRY_429_MAX (default 5), RETRY_429_MAX_WAIT (default 2m)"))
+	fmt.Println()
+	fmt.Println(tr("Exit codes: 0 ok, 1 outras falhas (cenário, --expect, golden, arquivo), 2 uso, 3 autenticação (401/403, sem token),"))
+	fmt.Println(tr("  4 não encontrado (404), 5 sem match/similaridade abaixo do mínimo, 6 rede/timeout, 7 erro do servidor (5xx)"))
+	printAliases()
 }
 
 /* ==================== main ==================== */
 
+// comandos que não falam com a API: sem token, keyring nem OAuth
+var noTokenCommands = map[string]bool{
+	"mock-server": true, "proxy": true, "normalize": true, "login": true, "logout": true,
+	"report": true, "fixtures": true, "craft-jwt": true, "anonymize-image": true, "history": true, "diff-runs": true,
+	"doctor": true, // confere token/OAuth por conta própria, sem abortar antes
+}
+
+// erro de uso (flag obrigatória faltando, valor inválido) → exit 2
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
 func main() {
-	// .env
-	if err := godotenv.Load(); err != nil {
-		fmt.Println("Erro ao carregar o arquivo .env")
+	// --lang en|pt-BR antes de tudo: erros, uso e textos de ajuda já saem no idioma escolhido
+	osArgs, langFlag, _, err := stripValueFlag(os.Args[1:], "--lang")
+	if err == nil {
+		err = setLang(langFlag)
 	}
-
-	if len(os.Args) < 2 {
-		usage()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
 
+	// completion antes de tudo: o script vai direto para o shell, sem aviso de .env no meio
+	if len(osArgs) > 0 && osArgs[0] == "completion" {
+		fs := flag.NewFlagSet("completion", flag.ExitOnError)
+		bin := fs.String("bin", "biodoc-go-runner", "nome do executável no PATH")
+		translateFlags(fs)
+		_ = fs.Parse(osArgs[1:])
+		if err := cmdCompletion(os.Stdout, fs.Arg(0), *bin); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitUsage)
+		}
+		return
+	}
+
 	// aceita --quiet/-q em qualquer posição
-	args, q := stripQuiet(os.Args[1:])
+	args, q := stripQuiet(osArgs)
 	quiet = q
 
-	if len(args) < 1 {
-		usage()
+	// -v/-vv/-vvv: dump das requisições com tempos de DNS/connect/TLS/TTFB
+	args, verbosity, err = stripVerbose(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
-	cmd := args[0]
 
-	baseURL := envOr("BASE_URL", "https://api.develop.biodoc.com.br")
-	token := os.Getenv("AUTH_TOKEN")
-	if token == "" {
-		fmt.Println("[aviso] AUTH_TOKEN não definido; endpoints protegidos vão falhar")
+	// --output json: um objeto JSON em stdout, texto humano vai para stderr
+	args, outMode, _, err := stripValueFlag(args, "--output")
+	if err == nil {
+		err = setOutputMode(outMode)
 	}
-
-	switch cmd {
-
-	case "create-card":
-		fs := flag.NewFlagSet("create-card", flag.ExitOnError)
-		imagePath := fs.String("image", `image\created_1.jpg`, "caminho da imagem")
-		id := fs.String("id", defaultID(), "documento/id do card")
-		name := fs.String("name", "Celso QA", "nome")
-		consent := fs.Bool("consent", false, "consentTermSigned")
-		_ = fs.Parse(args[1:])
-		if err := cmdCreateCard(baseURL, token, *imagePath, *id, *name, *consent); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// --jq/--extract CAMINHO: stdout só com o(s) valor(es); o texto do comando vai para o stderr
+	args, extractPaths, err := stripExtractFlags(args)
+	if err == nil && len(extractPaths) > 0 {
+		if outputJSON {
+			err = usageError(tr("--jq e --output json não combinam"))
 		}
+		humanOut = os.Stderr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	case "main-image":
-		fs := flag.NewFlagSet("main-image", flag.ExitOnError)
-		idCard := fs.String("idcard", "", "valor do header idCard (obrigatório)")
-		out := fs.String("out", "", "arquivo de saída (default: mainimage.bin)")
-		_ = fs.Parse(args[1:])
-		if *idCard == "" {
-			fmt.Fprintln(os.Stderr, "--idcard é obrigatório")
-			os.Exit(2)
-		}
-		if err := cmdMainImage(baseURL, token, *idCard, *out); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
+	// --junit report.xml: cada etapa vira um testcase
+	args, junitPath, _, err := stripValueFlag(args, "--junit")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	case "verify-card":
-		fs := flag.NewFlagSet("verify-card", flag.ExitOnError)
-		endpoint := fs.String("endpoint", "/api/card/integration/verify", "path da rota verify")
-		imagePath := fs.String("image", `image\created_1.jpg`, "imagem para verificação")
-		id := fs.String("id", defaultID(), "id do cadastro (string)")
-		name := fs.String("name", "Celso QA", "nome")
-		detail := fs.String("detail", "", "detalhes (string). Ex.: \"{'guia': '654321', ...}\"")
-		_ = fs.Parse(args[1:])
-		if err := cmdVerifyCard(baseURL, token, *endpoint, *imagePath, *id, *name, *detail); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
+	// --report out.html: relatório HTML autocontido (etapas, similaridade, latência, ambiente)
+	args, htmlReportPath, _, err := stripValueFlag(args, "--report")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, reportThumbs = stripBoolFlag(args, "--report-images")
+	reportEnabled = htmlReportPath != ""
 
-	case "delete-card":
-		fs := flag.NewFlagSet("delete-card", flag.ExitOnError)
-		id := fs.String("id", defaultID(), "ID do card para deletar (usa CARD_ID ou default se vazio)")
-		_ = fs.Parse(args[1:])
-		if err := cmdDeleteCard(baseURL, token, *id); err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			os.Exit(1)
-		}
+	// --notify-webhook URL: resumo da execução no Slack/Teams (ENV NOTIFY_WEBHOOK); --notify-on failure só quando falha
+	args, notifyURL, _, err := stripValueFlag(args, "--notify-webhook")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if notifyURL == "" {
+		notifyURL = os.Getenv("NOTIFY_WEBHOOK")
+	}
+	args, notifyOn, _, err := stripValueFlag(args, "--notify-on")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if notifyOn == "" {
+		notifyOn = envOr("NOTIFY_ON", "always")
+	}
+	if notifyOn != "always" && notifyOn != "failure" {
+		fmt.Fprintf(os.Stderr, tr("--notify-on deve ser always ou failure, veio %q\n"), notifyOn)
+		os.Exit(2)
+	}
 
-	case "run-all":
-		fs := flag.NewFlagSet("run-all", flag.ExitOnError)
-		image := fs.String("image", `image\created_1.jpg`, "imagem para criar/verificar")
-		id := fs.String("id", defaultID(), "id do card (usa CARD_ID do .env se existir)")
-		name := fs.String("name", "Celso QA", "nome")
-		detail := fs.String("detail", "{'guia':'654321'}", "detail (string)")
-		preclean := fs.Bool("preclean", true, "deletar antes se existir (ignora 404/422)")
-		_ = fs.Parse(args[1:])
+	// --record/--replay cassete.json: grava as requisições ou responde a partir delas, sem rede
+	args, recordPath, _, err := stripValueFlag(args, "--record")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, replayPath, _, err := stripValueFlag(args, "--replay")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if recordPath != "" && replayPath != "" {
+		fmt.Fprintln(os.Stderr, tr("--record e --replay não podem ser usados juntos"))
+		os.Exit(2)
+	}
+
+	// --har out.har: todo o tráfego da execução em HTTP Archive (imagens resumidas, salvo --har-full-images)
+	args, harPath, _, err := stripValueFlag(args, "--har")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, harFullImages := stripBoolFlag(args, "--har-full-images")
+
+	// --bug-report out.zip: se o comando falhar, junta requisições (HAR), config e log num zip
+	args, bugReportPath, _, err := stripValueFlag(args, "--bug-report")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if bugReportPath != "" {
+		startBugLog()
+	}
+
+	// --validate-schema: confere o corpo 2xx contra os schemas embutidos (schemas/*.json);
+	// --schema-spec ARQ usa a spec OpenAPI (todos os status declarados) e já liga a validação
+	args, validateSchemas = stripBoolFlag(args, "--validate-schema")
+	args, specPath, specSet, err := stripValueFlag(args, "--schema-spec")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if !specSet {
+		specPath = os.Getenv("SCHEMA_SPEC")
+	}
+	if os.Getenv("VALIDATE_SCHEMA") == "1" {
+		validateSchemas = true
+	}
+	if specPath != "" {
+		if schemaSpec, err = loadOpenAPISpec(specPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		validateSchemas = true
+	}
+
+	// --timing: tempo por etapa/endpoint no fim; --budget verify=1s,...: marca o que estourar
+	// --no-progress: sem barra de progresso nos comandos em lote (logs de CI)
+	args, noProgress = stripBoolFlag(args, "--no-progress")
+	args, timing := stripBoolFlag(args, "--timing")
+	args, budgetSpec, _, err := stripValueFlag(args, "--budget")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// --max-latency-p95 800ms / --slo verify.p95=800ms,...: ao contrário do --budget, falha a execução
+	args, sloRules, err := stripSLOFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	sloLat.enabled = len(sloRules) > 0
+	// --expect-status 201 / --expect 'response.success==true': asserções sobre a última resposta
+	args, expectStatus, expects, err := stripExpectFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// --metrics-addr :9100 expõe /metrics; --pushgateway URL envia para um Pushgateway
+	args, metricsAddr, _, err := stripValueFlag(args, "--metrics-addr")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, pushURL, _, err := stripValueFlag(args, "--pushgateway")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-		if *preclean {
-			if err := cmdDeleteCardIgnore404(baseURL, token, *id); err != nil {
-				fmt.Println("preclean falhou:", err)
-				os.Exit(1)
+	// --max-dimension/--max-bytes/--jpeg-quality: reduz e recomprime a imagem antes do base64
+	args, err = stripImagePrepFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// --watermark: carimba "TEST <run id>" (ou --watermark-text) nas imagens enviadas
+	args, watermark := stripBoolFlag(args, "--watermark")
+	args, wmText, wmSet, err := stripValueFlag(args, "--watermark-text")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if watermark || wmSet {
+		if wmText == "" {
+			wmText = "TEST " + runID
+		}
+		imgPrep.Watermark = wmText
+	}
+	// foto em retrato chega deitada: a Orientation do EXIF é aplicada nos pixels por padrão
+	args, imgPrep.NoExifFix = stripBoolFlag(args, "--no-exif-fix")
+	args, imgPrep.StripMeta = stripBoolFlag(args, "--strip-metadata")
+	// brilho/nitidez/resolução medidos antes do envio: avisa, ou aborta com --strict-quality
+	args, strictQuality = stripBoolFlag(args, "--strict-quality")
+	args, skipQualityScan = stripBoolFlag(args, "--no-quality-check")
+	// detector de rosto local: zero ou vários rostos não chegam a ir para a API
+	args, requireSingleFace = stripBoolFlag(args, "--require-single-face")
+
+	// --profile staging: BASE_URL, token, ID e imagens do ~/.biodoc-runner.yaml
+	args, profileName, _, err := stripValueFlag(args, "--profile")
+	if err == nil {
+		err = applyProfile(profileName)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	// --token/--token-file/--tenant clinicA: credencial só desta execução (outra clínica, outro client)
+	var tokenFlag, tokenFile, tenant string
+	args, tokenFlag, _, err = stripValueFlag(args, "--token")
+	if err == nil {
+		args, tokenFile, _, err = stripValueFlag(args, "--token-file")
+	}
+	if err == nil {
+		args, tenant, _, err = stripValueFlag(args, "--tenant")
+	}
+	if err == nil {
+		err = applyTokenOverride(tokenFlag, tokenFile, tenant)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// --results ARQ.jsonl: cada execução vira uma linha no results store (ENV RESULTS_STORE)
+	args, resultsPath, _, err := stripValueFlag(args, "--results")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// modo estrito: argumento solto, imagem inexistente ou AUTH_TOKEN ausente param antes da rede
+	args, noStrict := stripBoolFlag(args, "--no-strict")
+	strict = !noStrict
+
+	// .env do diretório atual, ou --env-file (repetível; o último ganha). O shell e o perfil vêm antes
+	args, envFiles, err := stripValueFlags(args, "--env-file")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(envFiles) > 0 {
+		if err := loadEnvFiles(envFiles); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		envSources = envFiles
+	} else if err := loadEnvFiles([]string{".env"}); err != nil {
+		if isSecretsError(err) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		outln("Erro ao carregar o arquivo .env")
+	} else {
+		envSources = []string{".env"}
+	}
+	// BIODOC_LANG também pode vir do .env
+	if langFlag == "" {
+		_ = setLang("")
+	}
+
+	// --log-level, --log-format, --log-file (ENV LOG_LEVEL, LOG_FORMAT, LOG_FILE)
+	args, lOpts, err := stripLogFlags(args)
+	if err == nil {
+		err = configureLogging(lOpts)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// --proxy, --ca-cert, --client-cert/--client-key (ENV PROXY_URL, CA_CERT, CLIENT_CERT, CLIENT_KEY);
+	// --timeout, --keep-alive, --idle-timeout, --max-idle-conns, --tls-handshake-timeout (ENV HTTP_*)
+	args, tOpts, err := stripTransportFlags(args)
+	if err == nil {
+		err = configureTransport(tOpts)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// --expected-duration D: duração prevista da execução, para conferir o exp do token antes de começar
+	args, expSpec, expSet, err := stripValueFlag(args, "--expected-duration")
+	if !expSet {
+		expSpec, expSet = os.LookupEnv("EXPECTED_DURATION")
+	}
+	var expectedDuration time.Duration
+	if expSet && expSpec != "" {
+		if expectedDuration, err = time.ParseDuration(expSpec); err != nil || expectedDuration < 0 {
+			err = usageError(fmt.Sprintf(tr("--expected-duration inválido: %q (use uma duração, ex.: 45m)"), expSpec))
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	resultsStore = resultsPath
+	if resultsStore == "" {
+		resultsStore = os.Getenv("RESULTS_STORE")
+	}
+
+	// retry: RETRY_* no ambiente, flags globais por cima
+	if err := loadRetryEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := loadThrottleEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, err = stripRetryFlags(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := loadTTLEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, ttlSpec, ttlSet, err := stripValueFlag(args, "--ttl")
+	if err == nil && ttlSet {
+		err = setCardTTL(ttlSpec)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args, tagSpec, tagSet, err := stripValueFlag(args, "--tag")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if tagSet {
+		cardTTL.Tag = tagSpec
+	}
+	budgets, err := loadBudgets(budgetSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// --dry-run: imprime cada requisição montada (token mascarado, imagem resumida) e não envia;
+	// reap, delete-cards e history rerun ficam com o --dry-run deles
+	if first := firstNonFlag(args); !ownDryRunCommands[first] {
+		args, dryRun = stripBoolFlag(args, "--dry-run")
+	}
+	// --rps/--rpm: teto de requisições por segundo/minuto somando todos os workers
+	args, err = stripRateLimitFlags(args, firstNonFlag(args))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// flags globais digitadas (para o histórico)
+	globals := globalArgs(os.Args[1:], args)
+
+	// aliases e default_command do ~/.biodoc-runner.yaml
+	args, err = expandAlias(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if len(args) < 1 {
+		usage()
+		os.Exit(2)
+	}
+	cmd := args[0]
+	httpClient.Timeout = timeoutFor(cmd)
+	// gen-data só fala com a API no --create
+	if cmd == "gen-data" && !slices.Contains(args[1:], "--create") {
+		noTokenCommands[cmd] = true
+	}
+	// run-pipeline sem nome só lista as pipelines do config
+	if cmd == "run-pipeline" && (len(args) < 2 || strings.HasPrefix(args[1], "-")) {
+		noTokenCommands[cmd] = true
+	}
+
+	baseURL := envOr("BASE_URL", "https://api.develop.biodoc.com.br")
+	if !noTokenCommands[cmd] {
+		loadKeyringSecrets()
+	}
+	token := os.Getenv("AUTH_TOKEN")
+	// OAUTH_TOKEN_URL + client id/secret: token buscado (e renovado em 401) pelo runner
+	if replayPath == "" && !dryRun && !noTokenCommands[cmd] && !tokenOverridden {
+		src, err := loadOAuthEnv()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if src != nil {
+			if token, err = src.Token(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(exitAuth)
 			}
+			oauth = src
 		}
-		if err := cmdCreateCard(baseURL, token, *image, *id, *name, true); err != nil {
-			fmt.Println("create falhou:", err)
-			os.Exit(1)
+	}
+	// HMAC_SECRET: o gateway de produção exige assinatura além do Bearer
+	if signer, err = loadHMACEnv(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if token == "" && dryRun {
+		token = "dry-run"
+	}
+	if token == "" && !noTokenCommands[cmd] {
+		if strict && replayPath == "" && !ownTokenCommands[cmd] {
+			fmt.Fprintln(os.Stderr, tr("AUTH_TOKEN não definido (ENV, --profile, login ou OAUTH_*); --no-strict segue mesmo assim"))
+			os.Exit(exitAuth)
 		}
-		if err := cmdVerifyCard(baseURL, token, "/api/card/integration/verify", *image, *id, *name, *detail); err != nil {
-			fmt.Println("verify falhou:", err)
-			os.Exit(1)
+		outln("[aviso] AUTH_TOKEN não definido; endpoints protegidos vão falhar")
+	}
+	// JWT que vence antes do fim previsto: melhor parar agora do que tomar 401 no meio do lote
+	if replayPath == "" && !dryRun && !noTokenCommands[cmd] && cmd != "token-info" {
+		if err := checkTokenValidity(token, expectedRunDuration(cmd, args[1:], expectedDuration)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(exitAuth)
 		}
-		if err := cmdDeleteCard(baseURL, token, *id); err != nil {
-			fmt.Println("delete final falhou:", err)
-			os.Exit(1)
+	}
+
+	if recordPath != "" {
+		enableRecord(recordPath, baseURL)
+	}
+	if replayPath != "" {
+		if err := enableReplay(replayPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
 		}
-		fmt.Println("✅ fluxo completo: preclean → create → verify → delete")
+	}
+
+	if harPath != "" || bugReportPath != "" {
+		enableHAR(harFullImages)
+	}
+
+	var pg *pushgateway
+	if metricsAddr != "" || pushURL != "" {
+		prom.enabled, prom.started = true, time.Now()
+		if metricsAddr != "" {
+			if err := startMetricsServer(metricsAddr); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+		}
+		if pushURL != "" {
+			pg = startPushgateway(pushURL, "biodoc_runner_"+strings.ReplaceAll(cmd, "-", "_"), 10*time.Second)
+		}
+	}
+
+	logger = logger.With("command", cmd)
+	logger.Info("start", "args", resolvedArgs(args[1:]), "base_url", baseURL, "profile", activeProfile, "tenant", activeTenant, "dry_run", dryRun)
+	started := time.Now()
+	err = run(cmd, args[1:], baseURL, token)
+	elapsed := time.Since(started)
+	if (expectStatus != 0 || len(expects) > 0) && !dryRun {
+		err = checkExpectations(expectStatus, expects, err)
+	}
+	if len(extractPaths) > 0 && err == nil && !dryRun {
+		err = printExtracted(extractPaths)
+	}
+	if len(sloRules) > 0 && !dryRun {
+		if serr := checkSLOs(sloRules, collectSteps(cmd, err, elapsed), elapsed); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if pg != nil {
+		pg.finish()
+	}
+	if recorder != nil {
+		outf("[record] %d interações gravadas em %s\n", recorder.len(), recordPath)
+	}
+	if harPath != "" {
+		if herr := harRec.write(harPath); herr != nil {
+			fmt.Fprintln(os.Stderr, "har:", herr)
+		} else {
+			outf("[har] %d requisições em %s\n", len(harRec.entries), harPath)
+		}
+	}
+	code := exitCodeFor(err)
+	if err != nil {
+		logger.Error("end", "exit_code", code, "duration_ms", elapsed.Milliseconds(), "category", failureCategory(err), "error", err.Error())
+	} else {
+		logger.Info("end", "exit_code", code, "duration_ms", elapsed.Milliseconds())
+	}
+	if dryRun {
+		outf("[dry-run] %d requisição(ões) montada(s), nenhuma enviada\n", dryRunCount.Load())
+	}
+	if bugReportPath != "" && err != nil {
+		if berr := writeBugReport(bugReportPath, cmd, os.Args[1:], baseURL, token, started, elapsed, err, code); berr != nil {
+			fmt.Fprintln(os.Stderr, "bug-report:", berr)
+		} else {
+			outf("[bug-report] pacote para o chamado em %s (requisições, config e log, sem tokens)\n", bugReportPath)
+		}
+	} else if err != nil && code != exitUsage && len(calls) > 0 {
+		outf("[dica] --bug-report ARQ.zip junta requisições, config e log para anexar ao chamado\n")
+	}
+	if timing || len(budgets) > 0 {
+		printTimingReport(cmd, elapsed, collectSteps(cmd, err, elapsed), budgets)
+	}
+	if junitPath != "" {
+		if jerr := writeJUnit(junitPath, cmd, started, elapsed, collectSteps(cmd, err, elapsed)); jerr != nil {
+			fmt.Fprintln(os.Stderr, "junit:", jerr)
+		} else {
+			outf("[junit] relatório em %s\n", junitPath)
+		}
+	}
+	if htmlReportPath != "" {
+		if rerr := writeHTMLReport(htmlReportPath, cmd, baseURL, started, elapsed, collectSteps(cmd, err, elapsed), err, code); rerr != nil {
+			fmt.Fprintln(os.Stderr, "report:", rerr)
+		} else {
+			outf("[report] relatório HTML em %s\n", htmlReportPath)
+		}
+	}
+	if notifyURL != "" && (notifyOn == "always" || err != nil) && !noHistoryCommands[cmd] && !dryRun {
+		sendNotification(notifyURL, buildNotifySummary(cmd, baseURL, collectSteps(cmd, err, elapsed), elapsed, err, code, htmlReportPath))
+	}
+	if resultsStore != "" && cmd != "report" && !dryRun {
+		rec := buildRunRecord(cmd, args[1:], baseURL, started, elapsed, err, code)
+		if serr := appendRun(resultsStore, rec); serr != nil {
+			fmt.Fprintln(os.Stderr, "results:", serr)
+		}
+	}
+	if hp := historyPath(); hp != "" && !noHistoryCommands[cmd] && !dryRun {
+		he := historyEntry{
+			At: started, RunID: runID, Global: globals, Command: cmd, Args: resolvedArgs(args[1:]),
+			Profile: activeProfile, BaseURL: baseURL, ExitCode: code, Duration: elapsed.Milliseconds(),
+		}
+		recordHistory(hp, he, buildRunRecord(cmd, args[1:], baseURL, started, elapsed, err, code))
+	}
+	if u := telemetryURL(); u != "" && cmd != "mock-server" {
+		sendTelemetry(u, cmd, os.Args[1:], elapsed, err, code)
+	}
+	if outputJSON {
+		emitJSONOutput(cmd, err, code)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	exit(code)
+}
+
+func run(cmd string, args []string, baseURL, token string) error {
+	switch cmd {
+
+	case "create-card":
+		fs := flag.NewFlagSet("create-card", flag.ExitOnError)
+		imagePath := fs.String("image", defaultImage(), "caminho da imagem (ENV CARD_IMAGE)")
+		id := fs.String("id", defaultID(), "documento/id do card")
+		name := fs.String("name", "Celso QA", "nome")
+		consent := fs.Bool("consent", false, "consentTermSigned")
+		camera := fs.Bool("camera", false, "captura um quadro da webcam (ffmpeg) no lugar de --image")
+		device := fs.String("camera-device", defaultCameraDevice(), "webcam (ENV CAMERA_DEVICE; /dev/video0, 0 no macOS, nome no Windows)")
+		delay := fs.Duration("camera-delay", 3*time.Second, "contagem regressiva antes da foto")
+		enc := fs.String("encoding", encBase64, "formato da imagem: base64, datauri (JSON) ou multipart")
+		parseFlags(fs, args)
+		if err := validEncoding(*enc); err != nil {
+			return err
+		}
+		if *camera {
+			path, cleanup, err := captureWebcam(*device, *delay)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			*imagePath = path
+		}
+		return cmdCreateCard(baseURL, token, *imagePath, *id, *name, *consent, *enc)
+
+	case "main-image":
+		fs := flag.NewFlagSet("main-image", flag.ExitOnError)
+		idCard := fs.String("idcard", "", "valor do header idCard (obrigatório)")
+		out := fs.String("out", "", "arquivo de saída; sem extensão, ganha a do conteúdo (default: mainimage.jpg/.png/.webp)")
+		decode := fs.Bool("decode", false, "decodifica a imagem inteira para garantir que não veio truncada")
+		compare := fs.String("compare", "", "original local: falha (exit 5) se a imagem guardada divergir dele (dHash + SSIM)")
+		maxDist := fs.Int("max-distance", 10, "distância máxima do dHash no --compare (0..64)")
+		minSSIM := fs.Float64("min-ssim", 0.8, "SSIM mínimo no --compare (0..1)")
+		parseFlags(fs, args)
+		if *idCard == "" {
+			return usageError(tr("--idcard é obrigatório"))
+		}
+		if *compare != "" {
+			if _, err := os.Stat(localPath(*compare)); err != nil {
+				return usageError(fmt.Sprintf(tr("--compare: %v"), err))
+			}
+		}
+		return cmdMainImage(baseURL, token, mainImageOptions{
+			IDCard: *idCard, Out: *out, Decode: *decode, Compare: localPath(*compare), MaxDistance: *maxDist, MinSSIM: *minSSIM,
+		})
+
+	case "verify-card":
+		fs := flag.NewFlagSet("verify-card", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card/integration/verify", "path da rota verify")
+		images := &imageListFlag{paths: []string{defaultVerifyImage()}}
+		fs.Var(images, "image", "imagem para verificação (ENV VERIFY_IMAGE); repetível ou glob entre aspas (\"fotos/*.jpg\") para veredito agregado")
+		id := fs.String("id", defaultID(), "id do cadastro (string)")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "", "detalhes (string). Ex.: \"{'guia': '654321', ...}\"")
+		minSim := fs.Float64("min-similarity", 0, "falha (exit 5) se success=false ou similaridade abaixo disso (0 = não checa)")
+		require := fs.String("require", "all", "com várias imagens, quantas precisam passar: all, majority ou any")
+		camera := fs.Bool("camera", false, "captura um quadro da webcam (ffmpeg) no lugar de --image")
+		device := fs.String("camera-device", defaultCameraDevice(), "webcam (ENV CAMERA_DEVICE; /dev/video0, 0 no macOS, nome no Windows)")
+		delay := fs.Duration("camera-delay", 3*time.Second, "contagem regressiva antes da foto")
+		enc := fs.String("encoding", encDataURI, "formato da imagem: datauri, base64 (JSON) ou multipart")
+		parseFlags(fs, args)
+		if err := validEncoding(*enc); err != nil {
+			return err
+		}
+		if !slices.Contains(verdictRules, *require) {
+			return usageError(tr("--require deve ser all, majority ou any, veio ") + *require)
+		}
+		imagePath := images.paths[0]
+		if *camera {
+			path, cleanup, err := captureWebcam(*device, *delay)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			imagePath = path
+		} else if len(images.paths) > 1 {
+			return cmdVerifyImages(baseURL, token, images.paths, batchVerifyOptions{
+				ID: *id, Name: *name, Detail: *detail, Endpoint: *endpoint, Encoding: *enc,
+			}, *minSim, *require)
+		}
+		return cmdVerifyCard(baseURL, token, *endpoint, imagePath, *id, *name, *detail, *minSim, *enc)
+
+	case "liveness-check":
+		fs := flag.NewFlagSet("liveness-check", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card/integration/liveness", "path da rota de liveness")
+		image := fs.String("image", "", "selfie para a prova de vida (default: VERIFY_IMAGE)")
+		video := fs.String("video", "", "vídeo curto (mp4, mov, webm ou avi; até 20 MB) no lugar de --image")
+		id := fs.String("id", "", "id do card a que a prova de vida se refere (opcional)")
+		minScore := fs.Float64("min-score", 80, "falha (exit 5) se o score (0-100) ficar abaixo disso ou a API responder live=false")
+		scoreScale := fs.Float64("score-scale", 100, "escala do score na resposta: 100 (0-100) ou 1 (0-1, levado a 0-100)")
+		scoreField := fs.String("score-field", "", "caminho do score na resposta (ex.: response.liveness.score); vazio = score, livenessScore, percentage...")
+		parseFlags(fs, args)
+		return cmdLivenessCheck(baseURL, token, livenessOptions{
+			Endpoint: *endpoint, Image: *image, Video: *video, ID: *id, MinScore: *minScore, ScoreScale: *scoreScale, ScoreField: *scoreField,
+		})
+
+	case "verify-document":
+		fs := flag.NewFlagSet("verify-document", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card/integration/document", "path da rota de leitura de documento")
+		image := fs.String("image", os.Getenv("DOCUMENT_IMAGE"), "foto do documento (ENV DOCUMENT_IMAGE)")
+		enc := fs.String("encoding", encDataURI, "formato da imagem: datauri, base64 (JSON) ou multipart")
+		expectName := fs.String("expect-name", "", "nome esperado; falha (exit 5) se o extraído não conferir (ignora acento e caixa)")
+		expectID := fs.String("expect-id", "", "número do documento esperado; falha (exit 5) se não conferir (ignora pontuação)")
+		nameField := fs.String("name-field", "", "caminho do nome na resposta (vazio = response.fields.name, name...)")
+		idField := fs.String("id-field", "", "caminho do número na resposta (vazio = response.fields.documentNumber, cpf...)")
+		parseFlags(fs, args)
+		if err := validEncoding(*enc); err != nil {
+			return err
+		}
+		return cmdVerifyDocument(baseURL, token, documentOptions{
+			Endpoint: *endpoint, Image: *image, Encoding: *enc, ExpectName: *expectName, ExpectID: *expectID,
+			NameField: *nameField, IDField: *idField,
+		})
+
+	case "get-card":
+		fs := flag.NewFlagSet("get-card", flag.ExitOnError)
+		id := fs.String("id", defaultID(), "ID do card (usa CARD_ID ou default se vazio)")
+		parseFlags(fs, args)
+		return cmdGetCard(baseURL, token, *id)
+
+	case "update-card":
+		fs := flag.NewFlagSet("update-card", flag.ExitOnError)
+		id := fs.String("id", defaultID(), "ID do card a alterar")
+		method := fs.String("method", "PATCH", "PATCH ou PUT")
+		endpoint := fs.String("endpoint", "/api/card/{id}", "path da rota ({id} é substituído)")
+		image := fs.String("image", "", "nova imagem")
+		name := fs.String("name", "", "novo nome")
+		consent := fs.Bool("consent", false, "novo consentTermSigned")
+		parseFlags(fs, args)
+		var u cardUpdate
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "image":
+				u.Image = image
+			case "name":
+				u.Name = name
+			case "consent":
+				u.Consent = consent
+			}
+		})
+		return cmdUpdateCard(baseURL, token, *method, *endpoint, *id, u)
+
+	case "list-cards":
+		fs := flag.NewFlagSet("list-cards", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card", "path da rota de listagem")
+		page := fs.Int("page", 0, "página inicial (começa em 0)")
+		size := fs.Int("size", 50, "itens por página")
+		name := fs.String("name", "", "filtra por nome")
+		all := fs.Bool("all", false, "segue paginando até acabar")
+		parseFlags(fs, args)
+		return cmdListCards(baseURL, token, listOptions{
+			Endpoint: *endpoint, Page: *page, Size: *size, Name: *name, All: *all,
+		})
+
+	case "reap":
+		fs := flag.NewFlagSet("reap", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card", "path da rota de listagem")
+		size := fs.Int("size", 100, "itens por página na listagem")
+		name := fs.String("name", "", "só cards com esse nome")
+		allTags := fs.Bool("all-tags", false, "ignora a tag (--tag/CARD_TAG) e apaga vencidos de qualquer tag")
+		dryRun := fs.Bool("dry-run", false, "só lista o que seria apagado")
+		parseFlags(fs, args)
+		tag := cardTTL.Tag
+		if *allTags {
+			tag = ""
+		}
+		return cmdReap(baseURL, token, reapOptions{
+			List: listOptions{Endpoint: *endpoint, Size: *size, Name: *name},
+			Tag:  tag, DryRun: *dryRun,
+		})
+
+	case "delete-card":
+		fs := flag.NewFlagSet("delete-card", flag.ExitOnError)
+		id := fs.String("id", defaultID(), "ID do card para deletar (usa CARD_ID ou default se vazio)")
+		parseFlags(fs, args)
+		return cmdDeleteCard(baseURL, token, *id)
+
+	case "delete-cards":
+		fs := flag.NewFlagSet("delete-cards", flag.ExitOnError)
+		idsFile := fs.String("ids-file", "", "arquivo com um id por linha (# comenta; - = stdin)")
+		prefix := fs.String("prefix", "", "apaga os cards da listagem cujo id casa, ex.: \"9998*\"")
+		endpoint := fs.String("endpoint", "/api/card", "path da rota de listagem (com --prefix)")
+		size := fs.Int("size", 100, "itens por página na listagem")
+		name := fs.String("name", "", "só cards com esse nome (com --prefix)")
+		concurrency := fs.Int("concurrency", 4, "requisições simultâneas")
+		dryRun := fs.Bool("dry-run", false, "só lista o que seria apagado")
+		bo := breakerFlags(fs)
+		parseFlags(fs, args)
+		return cmdDeleteCards(baseURL, token, deleteCardsOptions{
+			IDsFile: *idsFile, Prefix: *prefix, List: listOptions{Endpoint: *endpoint, Size: *size, Name: *name},
+			Concurrency: *concurrency, DryRun: *dryRun, Breaker: *bo,
+		})
+
+	case "batch-create":
+		fs := flag.NewFlagSet("batch-create", flag.ExitOnError)
+		manifest := fs.String("manifest", "", "manifesto .csv (id,name,image,consent) ou .json; image aceita s3:// e gs:// (obrigatório)")
+		concurrency := fs.Int("concurrency", 4, "requisições simultâneas")
+		name := fs.String("name", "Celso QA", "nome quando a linha não tiver")
+		consent := fs.Bool("consent", false, "consentTermSigned quando a linha não tiver")
+		results := fs.String("results", "", "grava resultado por linha (.csv ou .json)")
+		force := fs.Bool("force", false, "recria mesmo o que o cache diz já ter sido criado com a mesma imagem")
+		bo := breakerFlags(fs)
+		parseFlags(fs, args)
+		if *manifest == "" {
+			return usageError(tr("--manifest é obrigatório"))
+		}
+		return cmdBatchCreate(baseURL, token, *manifest, *concurrency, *name, *consent, *results, *force, *bo)
+
+	case "batch-verify":
+		fs := flag.NewFlagSet("batch-verify", flag.ExitOnError)
+		dir := fs.String("dir", "", "diretório (recursivo), glob (\"fotos/*.jpg\") ou bucket (s3://bucket/prefixo, gs://...) (obrigatório)")
+		id := fs.String("id", "", "id fixo do card; vazio = extrai do nome do arquivo")
+		idRegex := fs.String("id-regex", `^([0-9]+)`, "regex aplicada ao nome do arquivo (1º grupo = id)")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "", "detail (string)")
+		concurrency := fs.Int("concurrency", 4, "requisições simultâneas")
+		bo := breakerFlags(fs)
+		parseFlags(fs, args)
+		if *dir == "" {
+			return usageError(tr("--dir é obrigatório"))
+		}
+		return cmdBatchVerify(baseURL, token, batchVerifyOptions{
+			Source: *dir, ID: *id, IDRegex: *idRegex, Name: *name, Detail: *detail, Concurrency: *concurrency, Breaker: *bo,
+		})
+
+	case "watch":
+		fs := flag.NewFlagSet("watch", flag.ExitOnError)
+		dir := fs.String("dir", "", "pasta observada (recursiva) (obrigatório)")
+		id := fs.String("id", "", "id fixo do card; vazio = extrai do nome do arquivo")
+		idRegex := fs.String("id-regex", `^([0-9]+)`, "regex aplicada ao nome do arquivo (1º grupo = id)")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "", "detail (string)")
+		interval := fs.Duration("interval", time.Second, "intervalo entre varreduras da pasta")
+		existing := fs.Bool("existing", false, "verifica também as imagens que já estavam na pasta")
+		maxN := fs.Int("max", 0, "encerra depois de N imagens (0 = até Ctrl+C)")
+		parseFlags(fs, args)
+		if *dir == "" {
+			return usageError(tr("--dir é obrigatório"))
+		}
+		return cmdWatch(baseURL, token, watchOptions{
+			Dir: *dir, Interval: *interval, Existing: *existing, Max: *maxN,
+			Verify: batchVerifyOptions{ID: *id, IDRegex: *idRegex, Name: *name, Detail: *detail},
+		})
+
+	case "interactive":
+		fs := flag.NewFlagSet("interactive", flag.ExitOnError)
+		parseFlags(fs, args)
+		return cmdInteractive(baseURL, token)
+
+	case "serve":
+		fs := flag.NewFlagSet("serve", flag.ExitOnError)
+		addr := fs.String("addr", "127.0.0.1:8088", "endereço de escuta (fora do loopback, use --api-key)")
+		apiKey := fs.String("api-key", os.Getenv("SERVE_API_KEY"), "exige esse valor no header X-API-Key (ENV SERVE_API_KEY)")
+		enc := fs.String("encoding", "", "formato da imagem repassada: datauri, base64 ou multipart (default de cada rota)")
+		consent := fs.Bool("consent", false, "consentTermSigned no /create quando o pedido não mandar")
+		parseFlags(fs, args)
+		if *enc != "" {
+			if err := validEncoding(*enc); err != nil {
+				return err
+			}
+		}
+		return cmdServe(baseURL, token, serveOptions{Addr: *addr, APIKey: *apiKey, Encode: *enc, Consent: *consent})
+
+	case "mock-server":
+		fs := flag.NewFlagSet("mock-server", flag.ExitOnError)
+		addr := fs.String("addr", "127.0.0.1:8089", "endereço de escuta")
+		clock := fs.String("clock", "real", "relógio: real ou sim (avança via POST /__admin/clock)")
+		clockStart := fs.String("clock-start", "", "instante inicial do relógio sim (RFC3339, default 2024-01-01T00:00:00Z)")
+		delay := fs.Duration("delay", 0, "atraso de cada resposta, contado no relógio do mock")
+		scoring := fs.String("scoring", "fixed:99", "score do verify: fixed:N, random:MIN-MAX ou phash (hash perceptual)")
+		seed := fs.Uint64("seed", 0, "semente do scoring random e da latência (0 = aleatória)")
+		threshold := fs.Float64("threshold", 80, "similaridade mínima para success=true")
+		requireToken := fs.String("require-token", "", "exige Authorization: Bearer com esse valor (401 caso contrário); jwt = qualquer JWT cujo exp não passou no relógio do mock")
+		tokenTTL := fs.Duration("token-ttl", time.Hour, "validade dos tokens emitidos em POST /oauth/token (no relógio do mock)")
+		useTLS := fs.Bool("tls", false, "serve HTTPS com certificado autoassinado gerado na hora")
+		tlsExpired := fs.Bool("tls-expired", false, "HTTPS com certificado já vencido (implica --tls)")
+		certOut := fs.String("tls-cert-out", "", "grava o certificado PEM gerado nesse arquivo")
+		latency := endpointFlag{}
+		fs.Var(latency, "latency", "[endpoint=]fixed:200ms | normal:200ms,50ms | pareto:100ms,1.5 (repetível; endpoints: register, verify, mainimage, liveness, document, get, list, update, delete)")
+		bandwidth := endpointFlag{}
+		fs.Var(bandwidth, "bandwidth", "[endpoint=]bytes/s, ex.: 64KB ou mainimage=16KB (repetível)")
+		faults := endpointFlag{}
+		fs.Var(faults, "fault", "[endpoint=]tipo[:taxa]: malformed-json, wrong-content-type, gzip-bomb, chunked-truncation (repetível)")
+		fails := endpointFlag{}
+		fs.Var(fails, "fail", "[endpoint=]taxa[:status]: responde erro HTTP (default 503) nessa fração das requisições (repetível)")
+		stubsFile := fs.String("stubs", "", "arquivo JSON/YAML com respostas prontas (mesmo formato de POST /__admin/stubs)")
+		parseFlags(fs, args)
+		opt := mockOptions{
+			Addr: *addr, Clock: *clock, ClockStart: *clockStart, Delay: *delay,
+			Scoring: *scoring, Seed: *seed, Threshold: *threshold,
+			RequireToken: *requireToken, TokenTTL: *tokenTTL, TLS: *useTLS, TLSExpired: *tlsExpired, TLSCertOut: *certOut,
+			Latency: latency, Bandwidth: bandwidth, Faults: faults, Fails: fails, StubsFile: *stubsFile,
+		}
+		return cmdMockServer(opt)
+
+	case "proxy":
+		fs := flag.NewFlagSet("proxy", flag.ExitOnError)
+		listen := fs.String("listen", "127.0.0.1:8090", "endereço de escuta (aponte o app para cá)")
+		target := fs.String("target", baseURL, "API real para onde o tráfego é repassado")
+		cassettePath := fs.String("cassette", "", "grava as interações nesse arquivo JSON (vazio = só métricas)")
+		redact := fs.Bool("redact", true, "mascara tokens, cookies e campos sensíveis no cassete")
+		placeholders := fs.Bool("placeholder-images", false, "troca as imagens do cassete por um PNG 8x8 (arquivo pequeno e sem biometria)")
+		redactHeaders := fs.String("redact-header", "", "headers extras a mascarar, separados por vírgula")
+		replay := fs.String("replay", "", "serve as respostas desse cassete em vez de repassar à API")
+		match := fs.String("match", "method,path,query,body", "o que comparar no replay: method,path,query,headers,body")
+		matchBody := fs.String("match-body", "json", "comparação do corpo: exact, json (ignora bytes de imagem), structure (só chaves/tipos) ou none")
+		ignoreHeaders := fs.String("ignore-header", "", "headers extras fora da comparação, separados por vírgula")
+		ignoreFields := fs.String("ignore-field", "", "campos JSON fora da comparação (ex.: detail,meta.requestId)")
+		normalize := fs.String("normalize", "", "regras de normalização (YAML: drop, mask, round, sort) aplicadas antes de comparar corpos")
+		fallthru := fs.Bool("fallthrough", false, "replay: requisição sem gravação vai para --target em vez de 501")
+		recordNew := fs.Bool("record-new", false, "com --fallthrough: grava as interações novas (em --cassette, ou no próprio arquivo do --replay)")
+		latency := endpointFlag{}
+		fs.Var(latency, "latency", "[endpoint=]fixed:2s | normal:800ms,200ms | pareto:100ms,1.5, com @taxa opcional (ex.: verify=fixed:3s@0.2); repetível")
+		resets := endpointFlag{}
+		fs.Var(resets, "reset", "[endpoint=]taxa[:before|after]: derruba a conexão antes de repassar ou depois da resposta da API (repetível)")
+		fails := endpointFlag{}
+		fs.Var(fails, "fail", "[endpoint=]taxa[:status]: responde erro HTTP (default 503) sem repassar (repetível)")
+		faults := endpointFlag{}
+		fs.Var(faults, "fault", "[endpoint=]tipo[:taxa]: malformed-json, wrong-content-type, gzip-bomb, chunked-truncation (repetível)")
+		seed := fs.Uint64("seed", 0, "semente do sorteio das falhas (0 = aleatória)")
+		parseFlags(fs, args)
+		if *recordNew && !*fallthru {
+			return usageError(tr("--record-new exige --fallthrough"))
+		}
+		so := sanitizeOptions{Redact: *redact, Images: *placeholders}
+		if *redactHeaders != "" {
+			so.ExtraHeaders = strings.Split(*redactHeaders, ",")
+		}
+		mr := defaultMatchRules()
+		if err := mr.setBodyMode(*matchBody); err != nil {
+			return usageError(err.Error())
+		}
+		if err := mr.setMatchers(*match); err != nil {
+			return usageError(err.Error())
+		}
+		if *ignoreHeaders != "" {
+			mr.IgnoreHeaders = strings.Split(*ignoreHeaders, ",")
+		}
+		if *normalize != "" {
+			nr, err := loadNormRules(*normalize)
+			if err != nil {
+				return err
+			}
+			mr.Normalize = nr
+		}
+		if *ignoreFields != "" {
+			mr.Normalize.Drop = append(mr.Normalize.Drop, strings.Split(*ignoreFields, ",")...)
+		}
+		chaos, err := newProxyChaos(latency, resets, fails, faults, *seed)
+		if err != nil {
+			return usageError(err.Error())
+		}
+		return cmdProxy(proxyOptions{
+			Listen: *listen, Target: *target, Cassette: *cassettePath, Sanitize: so,
+			Replay: *replay, Match: mr, Fallthrough: *fallthru, RecordNew: *recordNew, Chaos: chaos,
+		})
+
+	case "load-verify":
+		fs := flag.NewFlagSet("load-verify", flag.ExitOnError)
+		endpoint := fs.String("endpoint", "/api/card/integration/verify", "path da rota verify")
+		imagePath := fs.String("image", defaultVerifyImage(), "imagem enviada em todas as requisições")
+		id := fs.String("id", defaultID(), "id do cadastro")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "", "detail (string)")
+		rps := fs.Float64("rps", 0, "taxa alvo em req/s (0 = cada worker dispara assim que a anterior volta)")
+		workers := fs.Int("workers", 4, "requisições simultâneas")
+		duration := fs.Duration("duration", 30*time.Second, "duração do teste")
+		maxErr := fs.Float64("max-error-rate", -1, "falha se a taxa de erro (0..1) passar disso (-1 = não checa)")
+		parseFlags(fs, args)
+		return cmdLoadVerify(baseURL, token, loadOptions{
+			Endpoint: *endpoint, Image: *imagePath, ID: *id, Name: *name, Detail: *detail,
+			RPS: *rps, Workers: *workers, Duration: *duration, MaxErrorRate: *maxErr,
+		})
+
+	case "soak":
+		fs := flag.NewFlagSet("soak", flag.ExitOnError)
+		mode := fs.String("mode", "verify", "verify ou cycle (preclean → create → verify → delete)")
+		endpoint := fs.String("endpoint", "/api/card/integration/verify", "path da rota verify")
+		imagePath := fs.String("image", defaultVerifyImage(), "imagem enviada em todas as iterações")
+		id := fs.String("id", defaultID(), "id do cadastro")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "", "detail (string)")
+		duration := fs.Duration("duration", 8*time.Hour, "duração total")
+		interval := fs.Duration("interval", 30*time.Second, "intervalo entre iterações")
+		every := fs.Duration("summary-every", 15*time.Minute, "tamanho da janela de cada resumo")
+		maxErr := fs.Float64("max-error-rate", -1, "falha se a taxa de erro (0..1) passar disso (-1 = não checa)")
+		maxDrift := fs.Float64("max-drift", -1, "falha se o p95 da última janela passar o da primeira em mais de N% (-1 = não checa)")
+		parseFlags(fs, args)
+		return cmdSoak(baseURL, token, soakOptions{
+			Mode: *mode, Endpoint: *endpoint, Image: *imagePath, ID: *id, Name: *name, Detail: *detail,
+			Duration: *duration, Interval: *interval, SummaryEvery: *every, MaxErrorRate: *maxErr, MaxDrift: *maxDrift,
+		})
+
+	case "run-scenario":
+		fs := flag.NewFlagSet("run-scenario", flag.ExitOnError)
+		file := fs.String("file", "", "cenário YAML (obrigatório)")
+		vars := varsFlag{}
+		fs.Var(vars, "var", "nome=valor para ${nome} no cenário (repetível)")
+		normalize := fs.String("normalize", "", "regras de normalização (YAML) somadas às do cenário, aplicadas antes do golden")
+		updateGolden := fs.Bool("update-golden", false, "regrava os arquivos golden com a resposta atual (normalizada)")
+		rehearseFirst := fs.Bool("rehearse", false, "roda o cenário antes contra o mock embutido e só segue se passar")
+		// aceita o arquivo como primeiro argumento: run-scenario fluxo.yaml --var id=1
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			*file, args = args[0], args[1:]
+		}
+		parseFlags(fs, args)
+		if *file == "" {
+			return usageError(tr("--file é obrigatório"))
+		}
+		var nr normRules
+		if *normalize != "" {
+			var err error
+			if nr, err = loadNormRules(*normalize); err != nil {
+				return err
+			}
+		}
+		if *rehearseFirst {
+			err := rehearse("run-scenario", func(baseURL, token string) error {
+				return cmdRunScenario(baseURL, token, *file, vars, nr, false)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return cmdRunScenario(baseURL, token, *file, vars, nr, *updateGolden)
+
+	case "run-pipeline":
+		fs := flag.NewFlagSet("run-pipeline", flag.ExitOnError)
+		vars := varsFlag{}
+		fs.Var(vars, "var", "nome=valor para ${nome} na pipeline (repetível)")
+		rehearseFirst := fs.Bool("rehearse", false, "roda a pipeline antes contra o mock embutido e só segue se passar")
+		// run-pipeline NOME --var id=1; sem nome, lista as pipelines do config
+		var name string
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			name, args = args[0], args[1:]
+		}
+		parseFlags(fs, args)
+		if name == "" {
+			return listPipelines()
+		}
+		if *rehearseFirst {
+			err := rehearse("run-pipeline", func(baseURL, token string) error {
+				return cmdRunPipeline(baseURL, token, name, vars)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return cmdRunPipeline(baseURL, token, name, vars)
+
+	case "login":
+		fs := flag.NewFlagSet("login", flag.ExitOnError)
+		oauthSecret := fs.Bool("oauth", false, "guarda o OAUTH_CLIENT_SECRET em vez do AUTH_TOKEN")
+		parseFlags(fs, args)
+		return cmdLogin(*oauthSecret)
+
+	case "logout":
+		return cmdLogout()
+
+	case "anonymize-image":
+		fs := flag.NewFlagSet("anonymize-image", flag.ExitOnError)
+		mode := fs.String("mode", "pixelate", "pixelate ou blur")
+		block := fs.Int("block", 0, "tamanho do bloco (pixelate) ou raio (blur) em px; 0 = proporcional ao rosto")
+		outDir := fs.String("out", "anonymized", "pasta de saída")
+		failed := fs.String("failed", "", "pega as imagens das execuções com falha no results store: last, all ou o id da execução")
+		var regions regionFlag
+		fs.Var(&regions, "region", "x,y,w,h a cobrir (repetível); sem ela, a região do rosto é estimada")
+		translateFlags(fs)
+		_ = fs.Parse(args)
+		opt := anonymizeOptions{Mode: *mode, Block: *block, OutDir: *outDir, Regions: regions}
+		var files []string
+		for _, a := range fs.Args() {
+			fl, err := collectImages(a)
+			if err != nil {
+				return err
+			}
+			files = append(files, fl...)
+		}
+		if *failed != "" {
+			if resultsStore == "" {
+				return usageError(tr("--failed precisa do results store (--results ou RESULTS_STORE)"))
+			}
+			fl, err := failedRunImages(resultsStore, *failed)
+			if err != nil {
+				return err
+			}
+			files = append(files, fl...)
+		}
+		return cmdAnonymizeImage(files, opt)
+
+	case "gen-data":
+		fs := flag.NewFlagSet("gen-data", flag.ExitOnError)
+		count := fs.Int("count", 10, "quantidade de cards")
+		out := fs.String("out", "", "manifesto gerado (.csv ou .json); vazio = CSV no stdout")
+		images := fs.String("images", "", "pool de imagens (pasta recursiva ou glob), sorteadas por card")
+		idFormat := fs.String("id-format", "cpf", "formato do id: cpf ou cns (dígitos verificadores válidos)")
+		consentRate := fs.Float64("consent-rate", 1, "fração dos cards com consentimento (0 a 1)")
+		seed := fs.Uint64("seed", 0, "semente (mesma semente = mesma massa); 0 = aleatória")
+		create := fs.Bool("create", false, "cadastra a massa na hora (batch-create; exige --images)")
+		concurrency := fs.Int("concurrency", 4, "requisições simultâneas no --create")
+		force := fs.Bool("force", false, "recria mesmo o que o cache diz já ter sido criado com a mesma imagem")
+		bo := breakerFlags(fs)
+		parseFlags(fs, args)
+		if *consentRate < 0 || *consentRate > 1 {
+			return usageError(tr("--consent-rate deve estar entre 0 e 1"))
+		}
+		opt := genDataOptions{Count: *count, Out: *out, Images: *images, IDFormat: *idFormat, ConsentRate: *consentRate, Seed: *seed}
+		if !*create {
+			return cmdGenData(opt)
+		}
+		if *images == "" {
+			return usageError(tr("--create exige --images"))
+		}
+		if opt.Out == "" {
+			f, err := os.CreateTemp("", "gen-data-*.csv")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			defer os.Remove(f.Name())
+			opt.Out = f.Name()
+		}
+		if err := cmdGenData(opt); err != nil {
+			return err
+		}
+		return cmdBatchCreate(baseURL, token, opt.Out, *concurrency, "", false, "", *force, *bo)
+
+	case "api":
+		return cmdAPI(baseURL, token, args)
+
+	case "token-info":
+		fs := flag.NewFlagSet("token-info", flag.ExitOnError)
+		parseFlags(fs, args)
+		return cmdTokenInfo(token)
+
+	case "craft-jwt":
+		fs := flag.NewFlagSet("craft-jwt", flag.ExitOnError)
+		mockURL := fs.String("mock", baseURL, "URL do mock-server que forja o token (exp no relógio dele)")
+		expiresIn := fs.String("expires-in", "", "exp relativo ao relógio do mock: -5m = vencido, 30s = quase vencendo (obrigatório)")
+		sub := fs.String("sub", "qa", "claim sub do token")
+		parseFlags(fs, args)
+		return cmdCraftJWT(*mockURL, *expiresIn, *sub)
+
+	case "doctor":
+		fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+		samples := fs.Int("samples", 3, "requisições para medir a latência")
+		parseFlags(fs, args)
+		return cmdDoctor(baseURL, *samples)
+
+	case "fixtures":
+		if len(args) == 0 || args[0] != "dedupe" {
+			return usageError(tr("uso: fixtures dedupe --dir PASTA|GLOB [--max-distance N]"))
+		}
+		fs := flag.NewFlagSet("fixtures dedupe", flag.ExitOnError)
+		dir := fs.String("dir", "fixtures", "pasta (recursiva) ou glob com o pool de imagens")
+		maxDist := fs.Int("max-distance", 6, "distância de Hamming máxima (de 64 bits do dHash) para considerar duplicata")
+		parseFlags(fs, args[1:])
+		return cmdFixturesDedupe(*dir, *maxDist)
+
+	case "report":
+		if len(args) == 0 || args[0] != "sla" {
+			return usageError(tr("uso: report sla [--by hour|day] [--since D] [--until D] [--endpoint E] [--target 99.5] [--csv ARQ]"))
+		}
+		fs := flag.NewFlagSet("report sla", flag.ExitOnError)
+		by := fs.String("by", "hour", "agrupamento: hour ou day")
+		since := fs.String("since", "", "a partir de (2006-01-02 ou RFC3339)")
+		until := fs.String("until", "", "até, exclusivo (2006-01-02 ou RFC3339)")
+		endpoint := fs.String("endpoint", "", "só esse endpoint (register, verify, get, list, update, delete, mainimage, liveness, document)")
+		target := fs.Float64("target", 99.5, "disponibilidade alvo em %, base do orçamento de erro")
+		csvPath := fs.String("csv", "", "grava também em CSV")
+		parseFlags(fs, args[1:])
+		if resultsStore == "" {
+			return usageError(tr("informe o results store com --results ARQ.jsonl (ou RESULTS_STORE)"))
+		}
+		s, err := parseSLATime(*since)
+		if err != nil {
+			return err
+		}
+		u, err := parseSLATime(*until)
+		if err != nil {
+			return err
+		}
+		return cmdReportSLA(slaOptions{Store: resultsStore, By: *by, Since: s, Until: u, Endpoint: *endpoint, Target: *target, CSV: *csvPath})
+
+	case "history":
+		sub := "list"
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			sub, args = args[0], args[1:]
+		}
+		switch sub {
+		case "list":
+			fs := flag.NewFlagSet("history list", flag.ExitOnError)
+			n := fs.Int("n", 20, "quantas entradas mostrar (0 = todas)")
+			command := fs.String("command", "", "só execuções desse comando (ex.: verify-card)")
+			parseFlags(fs, args)
+			return cmdHistoryList(*n, *command)
+		case "show":
+			if len(args) == 0 {
+				return usageError(tr("uso: history show N|RUN_ID|last [--json]"))
+			}
+			ref := args[0]
+			fs := flag.NewFlagSet("history show", flag.ExitOnError)
+			asJSON := fs.Bool("json", false, "imprime a entrada completa em JSON")
+			parseFlags(fs, args[1:])
+			return cmdHistoryShow(ref, *asJSON)
+		case "rerun":
+			if len(args) == 0 {
+				return usageError(tr("uso: history rerun N|RUN_ID|last [--dry-run]"))
+			}
+			ref := args[0]
+			fs := flag.NewFlagSet("history rerun", flag.ExitOnError)
+			dryRun := fs.Bool("dry-run", false, "só mostra o comando")
+			parseFlags(fs, args[1:])
+			return cmdHistoryRerun(ref, *dryRun)
+		}
+		return usageError(tr("uso: history [list [--n N] [--command C] | show N|RUN_ID|last [--json] | rerun N|RUN_ID|last [--dry-run]]"))
+
+	case "diff-runs":
+		var refs []string
+		for len(refs) < 2 && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			refs, args = append(refs, args[0]), args[1:]
+		}
+		if len(refs) < 2 {
+			return usageError(tr("uso: diff-runs A B (arquivo JSON, N/RUN_ID do history ou run id do --results) [--similarity-tolerance P] [--latency-tolerance F]"))
+		}
+		fs := flag.NewFlagSet("diff-runs", flag.ExitOnError)
+		simTol := fs.Float64("similarity-tolerance", 1, "queda de similaridade (pontos) tolerada antes de apontar regressão")
+		latTol := fs.Float64("latency-tolerance", 0.2, "aumento relativo de latência tolerado (0.2 = +20%)")
+		latMin := fs.Duration("latency-min", 50*time.Millisecond, "aumentos de latência menores que isso são ruído")
+		parseFlags(fs, args)
+		return cmdDiffRuns(refs[0], refs[1], diffRunsOptions{SimTolerance: *simTol, LatTolerance: *latTol, LatMin: *latMin})
+
+	case "diff-fuzz":
+		fs := flag.NewFlagSet("diff-fuzz", flag.ExitOnError)
+		a := fs.String("a", baseURL, "base URL do lado A (ex.: v1 ou ambiente atual)")
+		b := fs.String("b", baseURL, "base URL do lado B (ex.: v2 ou outro ambiente)")
+		aToken := fs.String("a-token", token, "token do lado A")
+		bToken := fs.String("b-token", token, "token do lado B")
+		aRewrite := fs.String("a-rewrite", "", "troca prefixo de path no lado A (ex.: /api/card=/api/v1/card)")
+		bRewrite := fs.String("b-rewrite", "", "troca prefixo de path no lado B (ex.: /api/card=/api/v2/card)")
+		image := fs.String("image", defaultImage(), "imagem válida usada como base dos payloads")
+		n := fs.Int("n", 20, "casos aleatórios (combinações de mutações) além dos fixos")
+		seed := fs.Uint64("seed", 0, "semente do gerador (0 = aleatória; a usada aparece no relatório)")
+		tol := fs.Float64("score-tolerance", 1, "diferença de similaridade tolerada, em pontos percentuais")
+		out := fs.String("out", "", "grava o relatório JSON nesse arquivo")
+		parseFlags(fs, args)
+		ra, err := parseRewrite(*aRewrite)
+		if err != nil {
+			return err
+		}
+		rb, err := parseRewrite(*bRewrite)
+		if err != nil {
+			return err
+		}
+		if *a == *b && ra == rb {
+			return usageError(tr("A e B são iguais: use --b com outro ambiente ou --b-rewrite com outra versão"))
+		}
+		return cmdDiffFuzz(fuzzOptions{
+			A:     fuzzTarget{Label: "A", BaseURL: *a, Token: *aToken, Rewrite: ra},
+			B:     fuzzTarget{Label: "B", BaseURL: *b, Token: *bToken, Rewrite: rb},
+			Image: *image, Cases: *n, Seed: *seed, ScoreTolerance: *tol, Out: *out,
+		})
+
+	case "normalize":
+		fs := flag.NewFlagSet("normalize", flag.ExitOnError)
+		rules := fs.String("rules", "", "arquivo YAML de regras (drop, mask, round, sort)")
+		translateFlags(fs)
+		_ = fs.Parse(args)
+		if *rules == "" {
+			return usageError(tr("--rules é obrigatório"))
+		}
+		return cmdNormalize(*rules, fs.Arg(0))
+
+	case "run-matrix":
+		fs := flag.NewFlagSet("run-matrix", flag.ExitOnError)
+		manifest := fs.String("manifest", "", "manifesto .csv/.json (id,name,image), o mesmo do batch-create")
+		ids := fs.String("ids", "", "ids separados por vírgula (alternativa ao --manifest)")
+		image := fs.String("image", "", "imagem usada com --ids (vazio = CARD_IMAGE)")
+		images := fs.String("images", "", "pool (pasta/glob) distribuído em rodízio entre os --ids, no lugar do --image")
+		name := fs.String("name", "Celso QA", "nome quando a linha não tiver")
+		detail := fs.String("detail", "{'guia':'654321'}", "detail (string)")
+		preclean := fs.Bool("preclean", true, "deleta cada id antes do create (404 = não existia)")
+		concurrency := fs.Int("concurrency", 8, "ciclos simultâneos")
+		bo := breakerFlags(fs)
+		parseFlags(fs, args)
+		var rows []manifestRow
+		switch {
+		case *manifest != "" && *ids != "":
+			return usageError(tr("use --manifest ou --ids, não os dois"))
+		case *manifest != "":
+			r, err := readManifest(*manifest)
+			if err != nil {
+				return err
+			}
+			rows = r
+		case *ids != "":
+			pool := []string{*image}
+			if *image == "" {
+				pool[0] = defaultImage()
+			}
+			if *images != "" {
+				files, err := collectImages(*images)
+				if err != nil {
+					return err
+				}
+				if len(files) == 0 {
+					return fmt.Errorf(tr("nenhuma imagem em %s"), *images)
+				}
+				pool = files
+			}
+			for _, id := range strings.Split(*ids, ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					rows = append(rows, manifestRow{ID: id, Image: pool[len(rows)%len(pool)]})
+				}
+			}
+		default:
+			return usageError(tr("informe --manifest ou --ids"))
+		}
+		for i := range rows {
+			if rows[i].Name == "" {
+				rows[i].Name = *name
+			}
+		}
+		return cmdRunMatrix(baseURL, token, matrixOptions{
+			Rows: rows, Detail: *detail, Preclean: *preclean, Concurrency: *concurrency, Breaker: *bo,
+		})
+
+	case "run-all":
+		fs := flag.NewFlagSet("run-all", flag.ExitOnError)
+		image := fs.String("image", defaultImage(), "imagem para criar/verificar (ENV CARD_IMAGE)")
+		id := fs.String("id", defaultID(), "id do card (usa CARD_ID do .env se existir)")
+		name := fs.String("name", "Celso QA", "nome")
+		detail := fs.String("detail", "{'guia':'654321'}", "detail (string)")
+		preclean := fs.Bool("preclean", true, "deletar antes se existir (consulta via get-card)")
+		rehearseFirst := fs.Bool("rehearse", false, "roda o fluxo antes contra o mock embutido e só segue se passar")
+		parseFlags(fs, args)
+
+		type step struct {
+			name, fail string
+			fn         func() error
+		}
+		runFlow := func(baseURL, token string) error {
+			var flow []step
+			if *preclean {
+				flow = append(flow, step{"preclean", tr("preclean falhou"), func() error {
+					return cmdPreclean(baseURL, token, *id)
+				}})
+			}
+			flow = append(flow,
+				step{"create", tr("create falhou"), func() error {
+					return cmdCreateCard(baseURL, token, *image, *id, *name, true, "")
+				}},
+				step{"verify", tr("verify falhou"), func() error {
+					return cmdVerifyCard(baseURL, token, "/api/card/integration/verify", *image, *id, *name, *detail, 0, "")
+				}},
+				step{"delete", tr("delete final falhou"), func() error {
+					return cmdDeleteCard(baseURL, token, *id)
+				}},
+			)
+			var failed error
+			for _, st := range flow {
+				if failed != nil {
+					skipStep(st.name)
+					continue
+				}
+				if err := runStep(st.name, st.fn); err != nil {
+					failed = fmt.Errorf("%s: %w", st.fail, err)
+				}
+			}
+			return failed
+		}
+		if *rehearseFirst {
+			if err := rehearse("run-all", runFlow); err != nil {
+				return err
+			}
+		}
+		if err := runFlow(baseURL, token); err != nil {
+			return err
+		}
+		outln("✅ fluxo completo: preclean → create → verify → delete")
 
 	default:
 		usage()
 		fmt.Println()
-		fmt.Println("Exemplos:")
+		fmt.Println(tr("Exemplos:"))
 		fmt.Println("  go run . create-card --image imagens/criacao.jpg --id 123 --name 'Fulano' --consent=true")
 		fmt.Println("  go run . verify-card --image imagens/selfie.jpg --id 123")
 		fmt.Println("  go run . delete-card --id 123")
 		fmt.Println("  go run . run-all")
 	}
-
-	_ = filepath.Base("") // evita warning de import
+	return nil
 }
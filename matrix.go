@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== run-matrix (run-all em paralelo, um ciclo por id) ==================== */
+
+type matrixOptions struct {
+	Rows        []manifestRow
+	Detail      string
+	Preclean    bool
+	Concurrency int
+	Breaker     breakerOptions
+}
+
+// uma etapa do ciclo; Status 0 com Skipped = não rodou
+type matrixStep struct {
+	Status    int    `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type matrixCycle struct {
+	ID         string                `json:"id"`
+	Image      string                `json:"image"`
+	Steps      map[string]matrixStep `json:"steps"`
+	Similarity string                `json:"similarity,omitempty"`
+	Match      bool                  `json:"match"`
+	OK         bool                  `json:"ok"`
+	Error      string                `json:"error,omitempty"` // primeira falha do ciclo
+	TotalMS    int64                 `json:"total_ms"`
+	outage     bool
+}
+
+var matrixStepNames = []string{"preclean", "create", "verify", "delete"}
+
+// ciclo isolado por id: nada é compartilhado entre ciclos além do cliente HTTP.
+// Se o create passou, o delete roda mesmo com o verify falhando (não deixa card para trás).
+func runMatrixCycle(baseURL, token string, row manifestRow, opt matrixOptions) matrixCycle {
+	c := matrixCycle{ID: row.ID, Image: row.Image, Steps: map[string]matrixStep{}}
+	start := time.Now()
+	fail := func(name string, st matrixStep) {
+		if c.Error == "" && st.Error != "" {
+			c.Error = name + ": " + st.Error
+		}
+	}
+	call := func(name, method, url string, fn func() (*http.Response, []byte, error), ok func(int) bool) (matrixStep, []byte) {
+		t0 := time.Now()
+		resp, body, err := fn()
+		lat := time.Since(t0)
+		st := matrixStep{LatencyMS: lat.Milliseconds()}
+		if resp != nil {
+			st.Status = resp.StatusCode
+		}
+		switch {
+		case err != nil:
+			st.Error = err.Error()
+		case !ok(resp.StatusCode):
+			st.Error = newAPIError(resp.StatusCode, body).Error()
+		}
+		c.outage = c.outage || isOutage(st.Status, err)
+		rec := stepRecord{Name: fmt.Sprintf("%s id=%s", name, row.ID), Duration: lat, Error: st.Error}
+		rec.Calls = []callRecord{newCallRecord(method, url, resp, body, err, 0, lat)}
+		addStep(rec)
+		return st, body
+	}
+	is2xx := func(s int) bool { return s >= 200 && s < 300 }
+	deleteStep := func(name string, ok func(int) bool) matrixStep {
+		st, _ := call(name, http.MethodDelete, cardURL(baseURL, row.ID), func() (*http.Response, []byte, error) {
+			return doRequest(http.MethodDelete, cardURL(baseURL, row.ID), authHeader(token), nil)
+		}, ok)
+		return st
+	}
+
+	if opt.Preclean {
+		// consulta antes e só deleta o que existe (precleanCard)
+		t0 := time.Now()
+		d, err := precleanCard(baseURL, token, row.ID)
+		lat := time.Since(t0)
+		st := matrixStep{Status: d.GetStatus, LatencyMS: lat.Milliseconds()}
+		if d.Action == "delete" {
+			st.Status = d.DeleteStatus
+		}
+		if err != nil {
+			st.Error = err.Error()
+			c.outage = c.outage || isOutage(st.Status, err)
+		}
+		addStep(stepRecord{Name: "preclean id=" + row.ID, Duration: lat, Error: st.Error})
+		c.Steps["preclean"] = st
+		fail("preclean", st)
+	} else {
+		c.Steps["preclean"] = matrixStep{Skipped: true}
+	}
+
+	created := false
+	if c.Error == "" {
+		st, _ := call("create", http.MethodPost, strings.TrimRight(baseURL, "/")+"/api/card/integration/register", func() (*http.Response, []byte, error) {
+			return createCard(baseURL, token, row.Image, row.ID, row.Name, true, "")
+		}, is2xx)
+		c.Steps["create"] = st
+		fail("create", st)
+		created = st.Error == ""
+	} else {
+		c.Steps["create"] = matrixStep{Skipped: true}
+	}
+
+	if created {
+		st, body := call("verify", http.MethodPost, verifyURL(baseURL, ""), func() (*http.Response, []byte, error) {
+			return verifyCard(baseURL, token, "", row.Image, row.ID, row.Name, opt.Detail, "")
+		}, is2xx)
+		if st.Error == "" {
+			var v VerifyResponse
+			if err := json.Unmarshal(body, &v); err != nil {
+				st.Error = tr("resposta inválida: ") + err.Error()
+			} else {
+				c.Match, c.Similarity = v.Response.Success, v.Similarity()
+				if !c.Match {
+					st.Error = tr("sem match")
+				}
+			}
+		}
+		c.Steps["verify"] = st
+		fail("verify", st)
+		st = deleteStep("delete", is2xx)
+		c.Steps["delete"] = st
+		fail("delete", st)
+	} else {
+		c.Steps["verify"] = matrixStep{Skipped: true}
+		c.Steps["delete"] = matrixStep{Skipped: true}
+	}
+
+	c.OK = c.Error == ""
+	c.TotalMS = time.Since(start).Milliseconds()
+	return c
+}
+
+func cmdRunMatrix(baseURL, token string, opt matrixOptions) error {
+	if len(opt.Rows) == 0 {
+		return usageError(tr("nenhum id para rodar"))
+	}
+	seen := map[string]bool{}
+	for _, r := range opt.Rows {
+		if seen[r.ID] {
+			return usageError(tr("id repetido na matriz: ") + r.ID + tr(" (ciclos em paralelo precisam de ids distintos)"))
+		}
+		seen[r.ID] = true
+	}
+	if opt.Concurrency < 1 {
+		opt.Concurrency = 1
+	}
+	outf("[matrix] %d ciclo(s), %d em paralelo\n", len(opt.Rows), opt.Concurrency)
+
+	cycles := make([]matrixCycle, len(opt.Rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	start := time.Now()
+	bar := newProgressBar("matrix", len(opt.Rows), 0)
+	cb := newCircuitBreaker("run-matrix", baseURL, token, opt.Breaker)
+
+	for w := 0; w < opt.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				row := opt.Rows[i]
+				if !cb.allow() {
+					cycles[i] = matrixCycle{ID: row.ID, Image: row.Image, Error: tr("não rodou: matriz abortada pelo circuit breaker")}
+					bar.add(true)
+					continue
+				}
+				c := runMatrixCycle(baseURL, token, row, opt)
+				cycles[i] = c
+				cb.record(c.outage)
+				bar.add(!c.OK)
+				mu.Lock()
+				done++
+				mark := "✅"
+				if !c.OK {
+					mark = "❌"
+				}
+				outf("[matrix] %d/%d %s id=%s %dms %s\n", done, len(opt.Rows), mark, c.ID, c.TotalMS, c.Error)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range opt.Rows {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	bar.finish()
+
+	printMatrixTable(cycles)
+	passed := 0
+	for _, c := range cycles {
+		if c.OK {
+			passed++
+		}
+	}
+	outf("[matrix] total=%d pass=%d fail=%d em %s\n", len(cycles), passed, len(cycles)-passed, time.Since(start).Round(time.Millisecond))
+	setResult("total", len(cycles))
+	setResult("passed", passed)
+	setResult("failed", len(cycles)-passed)
+	setResult("cycles", cycles)
+	if err := cb.summary(); err != nil {
+		return err
+	}
+	if passed < len(cycles) {
+		return fmt.Errorf(tr("%d de %d ciclo(s) falharam"), len(cycles)-passed, len(cycles))
+	}
+	return nil
+}
+
+func printMatrixTable(cycles []matrixCycle) {
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, tr("ID\tPRECLEAN\tCREATE\tVERIFY\tSIMILARIDADE\tDELETE\tTOTAL\tRESULTADO\tERRO"))
+	for _, c := range cycles {
+		cols := []string{c.ID}
+		for _, name := range matrixStepNames {
+			st, ok := c.Steps[name]
+			switch {
+			case !ok || st.Skipped:
+				cols = append(cols, "-")
+			case st.Error != "":
+				cols = append(cols, fmt.Sprintf("❌ %d", st.Status))
+			default:
+				cols = append(cols, fmt.Sprintf("%d %dms", st.Status, st.LatencyMS))
+			}
+			if name == "verify" {
+				cols = append(cols, orDash(c.Similarity))
+			}
+		}
+		verdict := "PASS"
+		if !c.OK {
+			verdict = "FAIL"
+		}
+		cols = append(cols, fmt.Sprintf("%dms", c.TotalMS), verdict, c.Error)
+		fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== Métricas Prometheus ==================== */
+
+// faixas do histograma de latência (segundos)
+var promBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type promHistogram struct {
+	counts []uint64 // por faixa (não acumulado)
+	sum    float64
+	count  uint64
+}
+
+// métricas de todas as tentativas HTTP do runner (alimentadas por doOnce)
+type promRegistry struct {
+	mu       sync.Mutex
+	enabled  bool
+	requests map[[2]string]uint64 // endpoint, status → total
+	errors   map[string]uint64    // endpoint → falhas de transporte
+	latency  map[string]*promHistogram
+	started  time.Time
+}
+
+var prom = &promRegistry{
+	requests: map[[2]string]uint64{},
+	errors:   map[string]uint64{},
+	latency:  map[string]*promHistogram{},
+}
+
+func (p *promRegistry) observe(method, url string, status int, failed bool, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled {
+		return
+	}
+	ep := callEndpoint(method, url)
+	if failed && status == 0 {
+		p.errors[ep]++
+	} else {
+		p.requests[[2]string{ep, strconv.Itoa(status)}]++
+	}
+	h := p.latency[ep]
+	if h == nil {
+		h = &promHistogram{counts: make([]uint64, len(promBuckets))}
+		p.latency[ep] = h
+	}
+	sec := d.Seconds()
+	for i, b := range promBuckets {
+		if sec <= b {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += sec
+	h.count++
+}
+
+// formato texto do Prometheus (exposition format 0.0.4)
+func (p *promRegistry) render() []byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b bytes.Buffer
+
+	b.WriteString("# HELP biodoc_runner_requests_total Requisições HTTP respondidas, por endpoint e status.\n")
+	b.WriteString("# TYPE biodoc_runner_requests_total counter\n")
+	reqKeys := make([][2]string, 0, len(p.requests))
+	for k := range p.requests {
+		reqKeys = append(reqKeys, k)
+	}
+	sort.Slice(reqKeys, func(i, j int) bool {
+		return reqKeys[i][0]+reqKeys[i][1] < reqKeys[j][0]+reqKeys[j][1]
+	})
+	for _, k := range reqKeys {
+		fmt.Fprintf(&b, "biodoc_runner_requests_total{endpoint=%q,status=%q} %d\n", k[0], k[1], p.requests[k])
+	}
+
+	b.WriteString("# HELP biodoc_runner_errors_total Requisições sem resposta (timeout, conexão recusada...).\n")
+	b.WriteString("# TYPE biodoc_runner_errors_total counter\n")
+	for _, ep := range sortedKeys(p.errors) {
+		fmt.Fprintf(&b, "biodoc_runner_errors_total{endpoint=%q} %d\n", ep, p.errors[ep])
+	}
+
+	b.WriteString("# HELP biodoc_runner_request_duration_seconds Latência das requisições HTTP.\n")
+	b.WriteString("# TYPE biodoc_runner_request_duration_seconds histogram\n")
+	for _, ep := range sortedKeys(p.latency) {
+		h := p.latency[ep]
+		var cum uint64
+		for i, le := range promBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(&b, "biodoc_runner_request_duration_seconds_bucket{endpoint=%q,le=%q} %d\n", ep, strconv.FormatFloat(le, 'g', -1, 64), cum)
+		}
+		fmt.Fprintf(&b, "biodoc_runner_request_duration_seconds_bucket{endpoint=%q,le=\"+Inf\"} %d\n", ep, h.count)
+		fmt.Fprintf(&b, "biodoc_runner_request_duration_seconds_sum{endpoint=%q} %g\n", ep, h.sum)
+		fmt.Fprintf(&b, "biodoc_runner_request_duration_seconds_count{endpoint=%q} %d\n", ep, h.count)
+	}
+
+	b.WriteString("# HELP biodoc_runner_start_time_seconds Início da execução (unix).\n")
+	b.WriteString("# TYPE biodoc_runner_start_time_seconds gauge\n")
+	fmt.Fprintf(&b, "biodoc_runner_start_time_seconds %d\n", p.started.Unix())
+	return b.Bytes()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// --metrics-addr: expõe GET /metrics enquanto o comando roda
+func startMetricsServer(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("--metrics-addr: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write(prom.render())
+	})
+	go func() { _ = http.Serve(ln, mux) }()
+	outf("[metrics] Prometheus em http://%s/metrics\n", ln.Addr())
+	return nil
+}
+
+// --pushgateway: envia as métricas a cada intervalo e uma última vez no fim
+type pushgateway struct {
+	url  string
+	stop chan struct{}
+	done chan struct{}
+}
+
+func startPushgateway(baseURL, job string, every time.Duration) *pushgateway {
+	instance, _ := os.Hostname()
+	pg := &pushgateway{
+		url:  strings.TrimRight(baseURL, "/") + "/metrics/job/" + job + "/instance/" + instance,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(pg.done)
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			select {
+			case <-pg.stop:
+				return
+			case <-t.C:
+				pg.push()
+			}
+		}
+	}()
+	return pg
+}
+
+func (pg *pushgateway) push() {
+	req, err := http.NewRequest(http.MethodPut, pg.url, bytes.NewReader(prom.render()))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	// cliente próprio: o push não entra no retry, no cassete nem nas próprias métricas
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		outf("[metrics] pushgateway: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		outf("[metrics] pushgateway respondeu %d\n", resp.StatusCode)
+	}
+}
+
+// último envio com os números finais
+func (pg *pushgateway) finish() {
+	close(pg.stop)
+	<-pg.done
+	pg.push()
+}
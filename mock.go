@@ -0,0 +1,536 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+/* ==================== Mock server ==================== */
+
+// servidor Biodoc falso para testar o runner (e pipelines) sem a API de develop
+type mockServer struct {
+	clock  *mockClock
+	delay  time.Duration // atraso de cada resposta, medido no relógio do mock
+	seq    atomic.Int64  // gera id_Log
+	issued atomic.Int64  // jti dos JWTs emitidos
+	stubs  stubStore
+	cards  *cardStore
+
+	score     scoreFunc
+	threshold float64 // score mínimo para success=true
+
+	requireToken string        // se não vazio, exige esse bearer ("jwt": qualquer JWT dentro do exp)
+	tokenTTL     time.Duration // expires_in dos tokens de /oauth/token
+	shape        *shaping
+	faults       *faultInjector
+}
+
+type mockOptions struct {
+	Addr       string
+	Clock      string // "real" ou "sim"
+	ClockStart string // RFC3339, só para sim
+	Delay      time.Duration
+	Scoring    string // ver parseScoring
+	Seed       uint64
+	Threshold  float64
+
+	RequireToken string
+	TokenTTL     time.Duration // validade dos tokens de /oauth/token (no relógio do mock)
+	TLS          bool          // HTTPS com certificado autoassinado gerado na hora
+	TLSExpired   bool          // ... já vencido (testa clientes contra cert expirado)
+	TLSCertOut   string        // grava o certificado PEM (para o cliente confiar)
+
+	Latency   map[string]string // endpoint ("*" = todos) → distribuição
+	Bandwidth map[string]string // endpoint → bytes/s
+	Faults    map[string]string // endpoint → tipo[:taxa] (ver faultKinds)
+	Fails     map[string]string // endpoint → taxa[:status]
+	StubsFile string            // respostas prontas carregadas na subida (JSON ou YAML)
+}
+
+func newMockServer(opt mockOptions) (*mockServer, error) {
+	m := &mockServer{
+		delay:     opt.Delay,
+		cards:     newCardStore(),
+		threshold: opt.Threshold,
+
+		requireToken: opt.RequireToken,
+		tokenTTL:     opt.TokenTTL,
+	}
+	if m.tokenTTL <= 0 {
+		m.tokenTTL = time.Hour
+	}
+	score, err := parseScoring(opt.Scoring, opt.Seed)
+	if err != nil {
+		return nil, err
+	}
+	m.score = score
+	if len(opt.Latency) > 0 || len(opt.Bandwidth) > 0 {
+		m.shape, err = newShaping(opt.Latency, opt.Bandwidth, opt.Seed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(opt.Faults) > 0 || len(opt.Fails) > 0 {
+		m.faults, err = newFaultInjector(opt.Faults, opt.Fails, opt.Seed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if opt.StubsFile != "" {
+		stubs, err := loadStubsFile(opt.StubsFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stubs {
+			m.stubs.put(s)
+		}
+	}
+	switch opt.Clock {
+	case "", "real":
+		m.clock = newRealClock()
+	case "sim":
+		start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		if opt.ClockStart != "" {
+			t, err := time.Parse(time.RFC3339, opt.ClockStart)
+			if err != nil {
+				return nil, fmt.Errorf(tr("--clock-start inválido (use RFC3339): %w"), err)
+			}
+			start = t
+		}
+		m.clock = newSimClock(start)
+	default:
+		return nil, fmt.Errorf(tr("--clock deve ser real ou sim, veio %q"), opt.Clock)
+	}
+	return m, nil
+}
+
+func (m *mockServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/card/integration/register", m.handleRegister)
+	mux.HandleFunc("POST /api/card/integration/verify", m.handleVerify)
+	mux.HandleFunc("GET /api/card/integration/mainimage", m.handleMainImage)
+	mux.HandleFunc("POST /api/card/integration/liveness", m.handleLiveness)
+	mux.HandleFunc("POST /api/card/integration/document", m.handleDocument)
+	mux.HandleFunc("GET /api/card", m.handleListCards)
+	mux.HandleFunc("GET /api/card/{id}", m.handleGetCard)
+	mux.HandleFunc("PATCH /api/card/{id}", m.handleUpdateCard)
+	mux.HandleFunc("PUT /api/card/{id}", m.handleUpdateCard)
+	mux.HandleFunc("DELETE /api/card/{id}", m.handleDelete)
+	mux.HandleFunc("POST "+mockTokenPath, m.handleOAuthToken)
+	mux.HandleFunc("POST /__admin/jwt", m.handleJWTCraft)
+	mux.HandleFunc("GET /__admin/clock", m.handleClockGet)
+	mux.HandleFunc("POST /__admin/clock", m.handleClockPost)
+	mux.HandleFunc("GET /__admin/stubs", m.handleStubsList)
+	mux.HandleFunc("POST /__admin/stubs", m.handleStubsCreate)
+	mux.HandleFunc("DELETE /__admin/stubs", m.handleStubsClear)
+	mux.HandleFunc("PUT /__admin/stubs/{id}", m.handleStubsUpdate)
+	mux.HandleFunc("DELETE /__admin/stubs/{id}", m.handleStubsDelete)
+	mux.HandleFunc("GET /__admin/cards", m.handleCardsList)
+	mux.HandleFunc("DELETE /__admin/cards", m.handleCardsReset)
+	return m.withAuth(m.withShaping(m.withFaults(m.withStubs(mux))))
+}
+
+// espera o atraso configurado no relógio do mock (no modo sim, até alguém avançar)
+func (m *mockServer) wait(r *http.Request) bool {
+	if m.delay <= 0 {
+		return true
+	}
+	select {
+	case <-m.clock.After(m.delay):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (m *mockServer) handleRegister(w http.ResponseWriter, r *http.Request) {
+	in, err := readMockUpload(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(err.Error())})
+		return
+	}
+	img := in.image
+	if !m.wait(r) {
+		return
+	}
+	c := &mockCard{
+		ID:        in.ID,
+		Name:      in.Name,
+		Consent:   in.Consent,
+		Detail:    in.Detail,
+		CreatedAt: m.clock.Now(),
+		Image:     img,
+		ImageSize: len(img),
+	}
+	if !m.cards.add(c) {
+		writeJSON(w, http.StatusConflict, map[string]any{"success": false, "message": "card já cadastrado", "id": in.ID})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"success":   true,
+		"message":   "card cadastrado",
+		"id":        in.ID,
+		"createdAt": c.CreatedAt.Format(time.RFC3339),
+	})
+}
+
+func (m *mockServer) handleVerify(w http.ResponseWriter, r *http.Request) {
+	in, err := readMockUpload(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(err.Error())})
+		return
+	}
+	probe := in.image
+	if !m.wait(r) {
+		return
+	}
+	c, ok := m.cards.get(in.ID)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": "card não encontrado", "id": in.ID})
+		return
+	}
+	score := m.score(c.Image, probe)
+	pct := fmt.Sprintf("%.2f", score)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"percentage": pct,
+		"response": map[string]any{
+			"id_Log":       strconv.FormatInt(m.seq.Add(1), 10),
+			"percentage":   pct,
+			"success":      score >= m.threshold,
+			"status":       200,
+			"message":      "verificado",
+			"reference_Id": in.ID,
+			"date":         m.clock.Now().Format(time.RFC3339),
+		},
+	})
+}
+
+// score do liveness segue o --scoring contra a própria captura (phash: sempre 100)
+func (m *mockServer) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		ID    string `json:"id"`
+		Image string `json:"image"`
+		Video string `json:"video"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(errMockPayload.Error())})
+		return
+	}
+	field := in.Image
+	if field == "" {
+		field = in.Video
+	}
+	media, err := decodeImageField(field)
+	if err != nil || len(media) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "imagem ou vídeo ausente/inválido"})
+		return
+	}
+	if !m.wait(r) {
+		return
+	}
+	score := m.score(media, media)
+	pct := fmt.Sprintf("%.2f", score)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"score": pct,
+		"response": map[string]any{
+			"id_Log":       strconv.FormatInt(m.seq.Add(1), 10),
+			"score":        pct,
+			"live":         score >= m.threshold,
+			"status":       200,
+			"message":      "liveness verificado",
+			"reference_Id": in.ID,
+			"date":         m.clock.Now().Format(time.RFC3339),
+		},
+	})
+}
+
+// o mock não faz OCR: "lê" o documento achando o card cuja foto mais se parece com ele (phash)
+// e devolve o nome e o id desse card como número; sem card parecido, 422
+func (m *mockServer) handleDocument(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Image string `json:"image"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(errMockPayload.Error())})
+		return
+	}
+	img, err := decodeImageField(in.Image)
+	if err != nil || len(img) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(errMockImage.Error())})
+		return
+	}
+	if !m.wait(r) {
+		return
+	}
+	var best *mockCard
+	bestScore := -1.0
+	for _, c := range m.cards.list() {
+		if s := phashSimilarity(c.Image, img); s > bestScore {
+			best, bestScore = &c, s
+		}
+	}
+	if best == nil || bestScore < m.threshold {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]any{"success": false, "message": "documento ilegível"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"response": map[string]any{
+			"id_Log":     strconv.FormatInt(m.seq.Add(1), 10),
+			"success":    true,
+			"status":     200,
+			"message":    "documento lido",
+			"confidence": fmt.Sprintf("%.2f", bestScore),
+			"fields": map[string]any{
+				"name":           best.Name,
+				"documentNumber": best.ID,
+			},
+			"date": m.clock.Now().Format(time.RFC3339),
+		},
+	})
+}
+
+func (m *mockServer) handleMainImage(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get("idCard")
+	if id == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "header idCard ausente"})
+		return
+	}
+	if !m.wait(r) {
+		return
+	}
+	c, ok := m.cards.get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": "card não encontrado", "id": id})
+		return
+	}
+	w.Header().Set("Content-Type", imageContentType(c.Image))
+	_, _ = w.Write(c.Image)
+}
+
+func (m *mockServer) handleGetCard(w http.ResponseWriter, r *http.Request) {
+	if !m.wait(r) {
+		return
+	}
+	id := r.PathValue("id")
+	c, ok := m.cards.get(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": "card não encontrado", "id": id})
+		return
+	}
+	writeJSON(w, http.StatusOK, cardView(*c))
+}
+
+// representação do card nas respostas de get/list
+func cardView(c mockCard) map[string]any {
+	v := map[string]any{
+		"id":                c.ID,
+		"name":              c.Name,
+		"createdAt":         c.CreatedAt.Format(time.RFC3339),
+		"status":            "active",
+		"consentTermSigned": c.Consent,
+	}
+	if c.Detail != "" {
+		v["detail"] = c.Detail
+	}
+	return v
+}
+
+// GET /api/card?page=0&size=50&name=... (página no formato Spring)
+func (m *mockServer) handleListCards(w http.ResponseWriter, r *http.Request) {
+	if !m.wait(r) {
+		return
+	}
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	size, _ := strconv.Atoi(q.Get("size"))
+	if size <= 0 {
+		size = 20
+	}
+	name := strings.ToLower(q.Get("name"))
+	var match []map[string]any
+	for _, c := range m.cards.list() {
+		if name != "" && !strings.Contains(strings.ToLower(c.Name), name) {
+			continue
+		}
+		match = append(match, cardView(c))
+	}
+	from := min(page*size, len(match))
+	to := min(from+size, len(match))
+	totalPages := (len(match) + size - 1) / size
+	writeJSON(w, http.StatusOK, map[string]any{
+		"content":       append([]map[string]any{}, match[from:to]...),
+		"number":        page,
+		"size":          size,
+		"totalElements": len(match),
+		"totalPages":    totalPages,
+		"last":          page+1 >= totalPages,
+	})
+}
+
+// PATCH/PUT /api/card/{id}: altera só os campos presentes
+func (m *mockServer) handleUpdateCard(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Name    *string `json:"name"`
+		Consent *bool   `json:"consentTermSigned"`
+		Image   *string `json:"image"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr("payload inválido")})
+		return
+	}
+	var img []byte
+	if in.Image != nil {
+		var err error
+		img, err = decodeImageField(*in.Image)
+		if err != nil || len(img) == 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr("imagem inválida")})
+			return
+		}
+	}
+	if !m.wait(r) {
+		return
+	}
+	id := r.PathValue("id")
+	c, ok := m.cards.update(id, func(c *mockCard) {
+		if in.Name != nil {
+			c.Name = *in.Name
+		}
+		if in.Consent != nil {
+			c.Consent = *in.Consent
+		}
+		if img != nil {
+			c.Image, c.ImageSize = img, len(img)
+		}
+	})
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": "card não encontrado", "id": id})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "message": "card atualizado", "id": c.ID})
+}
+
+func (m *mockServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	if !m.wait(r) {
+		return
+	}
+	id := r.PathValue("id")
+	if !m.cards.remove(id) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"success": false, "message": "card não encontrado", "id": id})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"success": true, "id": id})
+}
+
+// GET /__admin/clock → {"now": ..., "simulated": bool, "pending": n}
+func (m *mockServer) handleClockGet(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"now":       m.clock.Now().Format(time.RFC3339Nano),
+		"simulated": m.clock.Simulated(),
+		"pending":   m.clock.Pending(),
+	})
+}
+
+// POST /__admin/clock {"advance":"5m"} ou {"set":"2024-01-01T10:00:00Z"}
+func (m *mockServer) handleClockPost(w http.ResponseWriter, r *http.Request) {
+	if !m.clock.Simulated() {
+		writeJSON(w, http.StatusConflict, map[string]any{"error": "relógio real; suba o mock com --clock sim"})
+		return
+	}
+	var in struct {
+		Advance string `json:"advance"`
+		Set     string `json:"set"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": tr("JSON inválido: ") + err.Error()})
+		return
+	}
+	switch {
+	case in.Advance != "":
+		d, err := time.ParseDuration(in.Advance)
+		if err != nil || d < 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "advance inválido: " + in.Advance})
+			return
+		}
+		m.clock.Advance(d)
+	case in.Set != "":
+		t, err := time.Parse(time.RFC3339, in.Set)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"error": "set inválido (RFC3339): " + in.Set})
+			return
+		}
+		if _, err := m.clock.Set(t); err != nil {
+			writeJSON(w, http.StatusConflict, map[string]any{"error": err.Error(), "now": m.clock.Now().Format(time.RFC3339Nano)})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "informe advance ou set"})
+		return
+	}
+	m.handleClockGet(w, r)
+}
+
+// mock-server: bloqueia servindo até o processo morrer
+func cmdMockServer(opt mockOptions) error {
+	m, err := newMockServer(opt)
+	if err != nil {
+		return err
+	}
+	mode := "real"
+	if m.clock.Simulated() {
+		mode = "sim @ " + m.clock.Now().Format(time.RFC3339)
+	}
+	switch m.requireToken {
+	case "":
+	case mockJWTAuth:
+		mode += tr(", exige JWT dentro do exp")
+	default:
+		mode += tr(", exige bearer token")
+	}
+	if n := len(m.stubs.list()); n > 0 {
+		mode += fmt.Sprintf(", %d stubs", n)
+	}
+	// porta ocupada falha aqui, antes de anunciar que está ouvindo
+	ln, err := net.Listen("tcp", opt.Addr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	if !opt.TLS && !opt.TLSExpired {
+		outf("[mock] ouvindo em http://%s (relógio %s)\n", ln.Addr(), mode)
+		return http.Serve(ln, m.handler())
+	}
+
+	cert, certPEM, err := selfSignedCert(certHosts(opt.Addr), time.Now(), opt.TLSExpired)
+	if err != nil {
+		return fmt.Errorf(tr("gerar certificado: %w"), err)
+	}
+	if opt.TLSCertOut != "" {
+		if err := os.WriteFile(opt.TLSCertOut, certPEM, 0644); err != nil {
+			return err
+		}
+		outf("[mock] certificado salvo em %s\n", opt.TLSCertOut)
+	}
+	if opt.TLSExpired {
+		mode += tr(", cert vencido")
+	}
+	srv := &http.Server{
+		Handler:   m.handler(),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+	outf("[mock] ouvindo em https://%s (relógio %s)\n", ln.Addr(), mode)
+	return srv.ServeTLS(ln, "", "")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+/* ==================== Relógio do mock ==================== */
+
+// relógio do mock-server: real (time.Now) ou simulado, avançado só via /__admin/clock.
+// Tudo que depende de tempo no mock (timestamps, expirações, atrasos) passa por aqui.
+type mockClock struct {
+	mu      sync.Mutex
+	sim     bool
+	now     time.Time // só usado quando sim
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newRealClock() *mockClock { return &mockClock{} }
+
+func newSimClock(start time.Time) *mockClock {
+	return &mockClock{sim: true, now: start.UTC()}
+}
+
+func (c *mockClock) Simulated() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sim
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.sim {
+		return time.Now().UTC()
+	}
+	return c.now
+}
+
+// como time.After, mas no modo simulado só dispara quando o relógio é avançado
+func (c *mockClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.sim {
+		return time.After(d)
+	}
+	ch := make(chan time.Time, 1)
+	at := c.now.Add(d)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: at, ch: ch})
+	return ch
+}
+
+// avança o relógio simulado e libera quem estava esperando
+func (c *mockClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(c.now.Add(d))
+	return c.now
+}
+
+// posiciona o relógio simulado num instante absoluto; voltar no tempo é erro (os After
+// pendentes e os tokens já emitidos contam com um relógio que só anda para frente)
+func (c *mockClock) Set(t time.Time) (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.Before(c.now) {
+		return c.now, fmt.Errorf(tr("set %s está antes do relógio atual %s: o relógio não volta"),
+			t.UTC().Format(time.RFC3339), c.now.Format(time.RFC3339))
+	}
+	c.setLocked(t.UTC())
+	return c.now, nil
+}
+
+func (c *mockClock) setLocked(t time.Time) {
+	c.now = t
+	sort.Slice(c.waiters, func(i, j int) bool { return c.waiters[i].at.Before(c.waiters[j].at) })
+	keep := c.waiters[:0]
+	for _, w := range c.waiters {
+		if !w.at.After(c.now) {
+			w.ch <- c.now
+			continue
+		}
+		keep = append(keep, w)
+	}
+	c.waiters = keep
+}
+
+// quantos After() ainda estão pendentes (útil para o teste saber que pode avançar)
+func (c *mockClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/* ==================== Falhas de protocolo (chaos) ==================== */
+
+// tipos de falha que o mock sabe injetar
+var faultKinds = map[string]string{
+	"malformed-json":     "200 com JSON cortado no meio",
+	"wrong-content-type": "200 com página HTML (text/html) no lugar do JSON",
+	"gzip-bomb":          "Content-Encoding: gzip que descomprime para ~1GiB",
+	"chunked-truncation": "resposta chunked interrompida antes do chunk final",
+}
+
+// tamanho descomprimido da gzip-bomb
+const gzipBombSize = 1 << 30
+
+var (
+	gzipBombOnce sync.Once
+	gzipBombData []byte
+)
+
+// gzip de 1GiB de zeros (~1MB comprimido), gerado uma vez sob demanda
+func gzipBomb() []byte {
+	gzipBombOnce.Do(func() {
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		zeros := make([]byte, 1<<20)
+		for n := 0; n < gzipBombSize; n += len(zeros) {
+			_, _ = zw.Write(zeros)
+		}
+		_ = zw.Close()
+		gzipBombData = buf.Bytes()
+	})
+	return gzipBombData
+}
+
+// escreve a falha kind na resposta
+func writeFault(w http.ResponseWriter, kind string) {
+	switch kind {
+	case "malformed-json":
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"percentage":"99.00","response":{"id_Log":"1","success":tr`))
+	case "wrong-content-type":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("<html><head><title>Gateway</title></head><body><h1>Service temporarily unavailable</h1></body></html>"))
+	case "gzip-bomb":
+		b := gzipBomb()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(b)
+	case "chunked-truncation":
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"percentage":"99.00","response":{`))
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		// derruba a conexão sem o chunk final de tamanho zero
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func validFault(kind string) error {
+	if _, ok := faultKinds[kind]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(faultKinds))
+	for k := range faultKinds {
+		names = append(names, k)
+	}
+	return fmt.Errorf(tr("falha desconhecida %q (use %s)"), kind, strings.Join(names, ", "))
+}
+
+type faultRule struct {
+	kind string
+	rate float64
+}
+
+// erro HTTP sorteado (--fail [endpoint=]taxa[:status])
+type failRule struct {
+	rate   float64
+	status int
+}
+
+// falhas configuradas por endpoint: de protocolo (--fault) e de status (--fail)
+type faultInjector struct {
+	mu    sync.Mutex
+	rng   *rand.Rand
+	rules map[string]faultRule
+	fails map[string]failRule
+}
+
+func newFaultInjector(specs, failSpecs map[string]string, seed uint64) (*faultInjector, error) {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	fi := &faultInjector{rng: rand.New(rand.NewPCG(seed, ^seed)), rules: map[string]faultRule{}, fails: map[string]failRule{}}
+	for ep, spec := range failSpecs {
+		rateStr, statusStr, hasStatus := strings.Cut(spec, ":")
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf(tr("--fail %s: taxa deve estar entre 0 e 1, veio %q"), ep, rateStr)
+		}
+		status := http.StatusServiceUnavailable
+		if hasStatus {
+			status, err = strconv.Atoi(statusStr)
+			if err != nil || status < 400 || status > 599 {
+				return nil, fmt.Errorf(tr("--fail %s: status deve ser 4xx/5xx, veio %q"), ep, statusStr)
+			}
+		}
+		fi.fails[ep] = failRule{rate: rate, status: status}
+	}
+	for ep, spec := range specs {
+		kind, rateStr, hasRate := strings.Cut(spec, ":")
+		if err := validFault(kind); err != nil {
+			return nil, fmt.Errorf("--fault %s: %w", ep, err)
+		}
+		rate := 1.0
+		if hasRate {
+			v, err := strconv.ParseFloat(rateStr, 64)
+			if err != nil || v < 0 || v > 1 {
+				return nil, fmt.Errorf(tr("--fault %s: taxa deve estar entre 0 e 1, veio %q"), ep, rateStr)
+			}
+			rate = v
+		}
+		fi.rules[ep] = faultRule{kind: kind, rate: rate}
+	}
+	return fi, nil
+}
+
+// sorteia se a requisição deste endpoint leva falha; "" se não
+func (fi *faultInjector) pick(ep string) string {
+	rule, ok := fi.rules[ep]
+	if !ok {
+		rule, ok = fi.rules["*"]
+	}
+	if !ok {
+		return ""
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if fi.rng.Float64() < rule.rate {
+		return rule.kind
+	}
+	return ""
+}
+
+// sorteia se a requisição deste endpoint leva erro HTTP; 0 se não
+func (fi *faultInjector) pickFail(ep string) int {
+	rule, ok := fi.fails[ep]
+	if !ok {
+		rule, ok = fi.fails["*"]
+	}
+	if !ok {
+		return 0
+	}
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if fi.rng.Float64() < rule.rate {
+		return rule.status
+	}
+	return 0
+}
+
+// middleware: injeta a falha sorteada antes de chegar aos stubs/rotas
+func (m *mockServer) withFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.faults == nil || strings.HasPrefix(r.URL.Path, "/__admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ep := endpointKey(r)
+		if kind := m.faults.pick(ep); kind != "" {
+			writeFault(w, kind)
+			return
+		}
+		if status := m.faults.pickFail(ep); status != 0 {
+			writeJSON(w, status, map[string]any{"success": false, "message": "falha injetada (--fail " + ep + ")"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
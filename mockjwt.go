@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/* ==================== Tokens do mock (OAuth e JWT sob medida) ==================== */
+
+// o mock emite JWTs com iat/exp no relógio dele: POST /oauth/token (client credentials, para o
+// OAUTH_TOKEN_URL do runner) e POST /__admin/jwt (token já vencido ou quase, para testar o
+// 401 → renovação → repetição). Com --require-token jwt só o exp é conferido; a assinatura
+// HS256 com chave fixa existe para o token ter o formato de um de verdade
+const mockTokenPath = "/oauth/token"
+
+var mockJWTKey = []byte("biodoc-mock")
+
+func craftJWT(claims map[string]any) string {
+	enc := func(v any) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := enc(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." + enc(claims)
+	mac := hmac.New(sha256.New, mockJWTKey)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// JWT emitido agora (relógio do mock) que vence em expiresIn; negativo = já vencido.
+// O jti impede que dois tokens do mesmo segundo saiam iguais (a renovação precisa de outro)
+func (m *mockServer) issueJWT(sub string, expiresIn time.Duration) (string, time.Time) {
+	now := m.clock.Now()
+	exp := now.Add(expiresIn)
+	return craftJWT(map[string]any{
+		"iss": "biodoc-mock",
+		"sub": sub,
+		"iat": now.Unix(),
+		"exp": exp.Unix(),
+		"jti": strconv.FormatInt(m.issued.Add(1), 10),
+	}), exp
+}
+
+// POST /oauth/token (grant_type=client_credentials; client por Basic auth ou no form)
+func (m *mockServer) handleOAuthToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": "unsupported_grant_type"})
+		return
+	}
+	client, _, ok := r.BasicAuth()
+	if !ok {
+		client = r.PostForm.Get("client_id")
+	}
+	if client == "" {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": "invalid_client"})
+		return
+	}
+	tok, _ := m.issueJWT(client, m.tokenTTL)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": tok,
+		"token_type":   "Bearer",
+		"expires_in":   int64(m.tokenTTL / time.Second),
+	})
+}
+
+// POST /__admin/jwt {"expiresIn":"-5m","sub":"qa"} → token vencido (ou que vence em breve)
+func (m *mockServer) handleJWTCraft(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		ExpiresIn string `json:"expiresIn"`
+		Sub       string `json:"sub"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": tr("JSON inválido: ") + err.Error()})
+		return
+	}
+	d, err := time.ParseDuration(in.ExpiresIn)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": tr("informe expiresIn (ex.: -5m vencido, 30s quase vencendo)")})
+		return
+	}
+	if in.Sub == "" {
+		in.Sub = "qa"
+	}
+	tok, exp := m.issueJWT(in.Sub, d)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"token": tok,
+		"exp":   exp.Format(time.RFC3339),
+		"now":   m.clock.Now().Format(time.RFC3339),
+	})
+}
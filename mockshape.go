@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== Latência e banda no mock ==================== */
+
+// distribuição de latência: fixed:200ms | normal:200ms,50ms | pareto:100ms,1.5
+type latencyDist struct {
+	kind  string
+	a     time.Duration // fixed: valor; normal: média; pareto: escala (mínimo)
+	b     time.Duration // normal: desvio padrão
+	alpha float64       // pareto: forma
+}
+
+// teto para caudas longas (pareto) não travarem o cliente para sempre
+const maxShapedLatency = 60 * time.Second
+
+func parseLatencyDist(spec string) (latencyDist, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	parts := strings.Split(arg, ",")
+	bad := fmt.Errorf(tr("latência inválida %q (use fixed:200ms, normal:200ms,50ms ou pareto:100ms,1.5)"), spec)
+	switch kind {
+	case "fixed":
+		d, err := time.ParseDuration(arg)
+		if err != nil || d < 0 {
+			return latencyDist{}, bad
+		}
+		return latencyDist{kind: kind, a: d}, nil
+	case "normal":
+		if len(parts) != 2 {
+			return latencyDist{}, bad
+		}
+		mean, err1 := time.ParseDuration(parts[0])
+		sd, err2 := time.ParseDuration(parts[1])
+		if err1 != nil || err2 != nil || mean < 0 || sd < 0 {
+			return latencyDist{}, bad
+		}
+		return latencyDist{kind: kind, a: mean, b: sd}, nil
+	case "pareto":
+		if len(parts) != 2 {
+			return latencyDist{}, bad
+		}
+		xm, err1 := time.ParseDuration(parts[0])
+		alpha, err2 := strconv.ParseFloat(parts[1], 64)
+		if err1 != nil || err2 != nil || xm <= 0 || alpha <= 0 {
+			return latencyDist{}, bad
+		}
+		return latencyDist{kind: kind, a: xm, alpha: alpha}, nil
+	}
+	return latencyDist{}, bad
+}
+
+func (d latencyDist) sample(rng *rand.Rand) time.Duration {
+	var v float64
+	switch d.kind {
+	case "fixed":
+		return d.a
+	case "normal":
+		v = float64(d.a) + rng.NormFloat64()*float64(d.b)
+	case "pareto":
+		u := 1 - rng.Float64() // (0,1]
+		v = float64(d.a) / math.Pow(u, 1/d.alpha)
+	}
+	if v < 0 {
+		v = 0
+	}
+	if v > float64(maxShapedLatency) {
+		v = float64(maxShapedLatency)
+	}
+	return time.Duration(v)
+}
+
+// "64KB", "1.5MB", "512" → bytes
+func parseByteSize(s string) (int64, error) {
+	u := strings.ToUpper(strings.TrimSpace(s))
+	mult := 1.0
+	for _, suf := range []struct {
+		s string
+		m float64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(u, suf.s) {
+			u = strings.TrimSuffix(u, suf.s)
+			mult = suf.m
+			break
+		}
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(u), 64)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf(tr("tamanho inválido %q (ex.: 512, 64KB, 1.5MB)"), s)
+	}
+	return int64(v * mult), nil
+}
+
+// endpoint do mock a que a requisição pertence (chave usada por --latency/--bandwidth)
+func endpointKey(r *http.Request) string {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/integration/register"):
+		return "register"
+	case strings.HasSuffix(r.URL.Path, "/integration/verify"):
+		return "verify"
+	case strings.HasSuffix(r.URL.Path, "/integration/mainimage"):
+		return "mainimage"
+	case strings.HasSuffix(r.URL.Path, "/integration/liveness"):
+		return "liveness"
+	case strings.HasSuffix(r.URL.Path, "/integration/document"):
+		return "document"
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/card/"):
+		return "delete"
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/card/"):
+		return "get"
+	case r.Method == http.MethodGet && r.URL.Path == "/api/card":
+		return "list"
+	case (r.Method == http.MethodPatch || r.Method == http.MethodPut) && strings.HasPrefix(r.URL.Path, "/api/card/"):
+		return "update"
+	}
+	return "other"
+}
+
+// flag repetível "[endpoint=]valor"; sem endpoint vale para todos ("*")
+type endpointFlag map[string]string
+
+func (f endpointFlag) String() string { return fmt.Sprint(map[string]string(f)) }
+
+func (f endpointFlag) Set(v string) error {
+	ep, val, ok := strings.Cut(v, "=")
+	if !ok {
+		ep, val = "*", v
+	}
+	f[ep] = val
+	return nil
+}
+
+// latência e banda por endpoint
+type shaping struct {
+	mu        sync.Mutex
+	rng       *rand.Rand
+	latency   map[string]latencyDist
+	bandwidth map[string]int64 // bytes/s
+}
+
+func newShaping(lat, bw map[string]string, seed uint64) (*shaping, error) {
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	sh := &shaping{
+		rng:       rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15)),
+		latency:   map[string]latencyDist{},
+		bandwidth: map[string]int64{},
+	}
+	for ep, spec := range lat {
+		d, err := parseLatencyDist(spec)
+		if err != nil {
+			return nil, fmt.Errorf("--latency %s: %w", ep, err)
+		}
+		sh.latency[ep] = d
+	}
+	for ep, spec := range bw {
+		n, err := parseByteSize(spec)
+		if err != nil {
+			return nil, fmt.Errorf("--bandwidth %s: %w", ep, err)
+		}
+		sh.bandwidth[ep] = n
+	}
+	return sh, nil
+}
+
+func (sh *shaping) latencyFor(ep string) (time.Duration, bool) {
+	d, ok := sh.latency[ep]
+	if !ok {
+		d, ok = sh.latency["*"]
+	}
+	if !ok {
+		return 0, false
+	}
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return d.sample(sh.rng), true
+}
+
+func (sh *shaping) bandwidthFor(ep string) int64 {
+	if n, ok := sh.bandwidth[ep]; ok {
+		return n
+	}
+	return sh.bandwidth["*"]
+}
+
+// tamanho dos blocos do throttle (~20 pausas por segundo)
+func throttleChunk(rate int64) int {
+	c := int(rate / 20)
+	if c < 1 {
+		c = 1
+	}
+	return c
+}
+
+// writer que limita a taxa de escrita a rate bytes/s
+type throttledWriter struct {
+	http.ResponseWriter
+	rate int64
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	chunk := throttleChunk(t.rate)
+	n := 0
+	for len(p) > 0 {
+		c := min(chunk, len(p))
+		w, err := t.ResponseWriter.Write(p[:c])
+		n += w
+		if err != nil {
+			return n, err
+		}
+		if f, ok := t.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+		time.Sleep(time.Duration(float64(c) / float64(t.rate) * float64(time.Second)))
+		p = p[c:]
+	}
+	return n, nil
+}
+
+// reader que limita a leitura do corpo da requisição (upload lento)
+type throttledReader struct {
+	io.ReadCloser
+	rate int64
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if c := throttleChunk(t.rate); len(p) > c {
+		p = p[:c]
+	}
+	n, err := t.ReadCloser.Read(p)
+	if n > 0 {
+		time.Sleep(time.Duration(float64(n) / float64(t.rate) * float64(time.Second)))
+	}
+	return n, err
+}
+
+// middleware: aplica latência (no relógio do mock) e limite de banda do endpoint
+func (m *mockServer) withShaping(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.shape == nil || strings.HasPrefix(r.URL.Path, "/__admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ep := endpointKey(r)
+		if rate := m.shape.bandwidthFor(ep); rate > 0 {
+			r.Body = &throttledReader{ReadCloser: r.Body, rate: rate}
+			w = &throttledWriter{ResponseWriter: w, rate: rate}
+		}
+		if d, ok := m.shape.latencyFor(ep); ok && d > 0 {
+			select {
+			case <-m.clock.After(d):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
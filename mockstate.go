@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== Estado do mock (ciclo de cadastro) ==================== */
+
+// card cadastrado no mock via register
+type mockCard struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Consent   bool      `json:"consentTermSigned"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Image     []byte    `json:"-"`
+	ImageSize int       `json:"imageBytes"`
+}
+
+type cardStore struct {
+	mu    sync.Mutex
+	cards map[string]*mockCard
+}
+
+func newCardStore() *cardStore { return &cardStore{cards: map[string]*mockCard{}} }
+
+// false se o id já existe
+func (cs *cardStore) add(c *mockCard) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.cards[c.ID]; ok {
+		return false
+	}
+	cs.cards[c.ID] = c
+	return true
+}
+
+func (cs *cardStore) get(id string) (*mockCard, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	c, ok := cs.cards[id]
+	return c, ok
+}
+
+// aplica fn ao card sob o lock; devolve uma cópia do resultado
+func (cs *cardStore) update(id string, fn func(*mockCard)) (mockCard, bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	c, ok := cs.cards[id]
+	if !ok {
+		return mockCard{}, false
+	}
+	fn(c)
+	return *c, true
+}
+
+func (cs *cardStore) remove(id string) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if _, ok := cs.cards[id]; !ok {
+		return false
+	}
+	delete(cs.cards, id)
+	return true
+}
+
+func (cs *cardStore) list() []mockCard {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	out := make([]mockCard, 0, len(cs.cards))
+	for _, c := range cs.cards {
+		out = append(out, *c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func (cs *cardStore) reset() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.cards = map[string]*mockCard{}
+}
+
+// calcula a similaridade (0..100) entre a imagem cadastrada e a enviada no verify
+type scoreFunc func(enrolled, probe []byte) float64
+
+// score fixo, independente das imagens
+func fixedScore(v float64) scoreFunc {
+	return func(_, _ []byte) float64 { return v }
+}
+
+// score uniforme em [lo, hi]; seed != 0 torna a sequência reprodutível
+func randomScore(lo, hi float64, seed uint64) scoreFunc {
+	var mu sync.Mutex
+	var rng *rand.Rand
+	if seed != 0 {
+		rng = rand.New(rand.NewPCG(seed, seed))
+	} else {
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return func(_, _ []byte) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return lo + rng.Float64()*(hi-lo)
+	}
+}
+
+// --scoring: "fixed:99", "random:70-95" ou "phash"
+func parseScoring(spec string, seed uint64) (scoreFunc, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "fixed":
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil || v < 0 || v > 100 {
+			return nil, fmt.Errorf(tr("scoring fixed:N com N entre 0 e 100, veio %q"), spec)
+		}
+		return fixedScore(v), nil
+	case "random":
+		a, b, ok := strings.Cut(arg, "-")
+		lo, errA := strconv.ParseFloat(a, 64)
+		hi, errB := strconv.ParseFloat(b, 64)
+		if !ok || errA != nil || errB != nil || lo < 0 || hi > 100 || lo > hi {
+			return nil, fmt.Errorf(tr("scoring random:MIN-MAX (0..100), veio %q"), spec)
+		}
+		return randomScore(lo, hi, seed), nil
+	case "phash":
+		return phashSimilarity, nil
+	}
+	return nil, fmt.Errorf(tr("scoring desconhecido %q (use fixed:N, random:MIN-MAX ou phash)"), spec)
+}
+
+// aceita base64 puro (register) ou data URI (verify)
+func decodeImageField(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "data:") {
+		i := strings.Index(s, ",")
+		if i < 0 {
+			return nil, errors.New(tr("data URI sem vírgula"))
+		}
+		s = s[i+1:]
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
+
+// corpo do register/verify: JSON (imagem base64/data URI) ou multipart/form-data (parte "image")
+type mockUpload struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Consent bool   `json:"consentTermSigned"`
+	Detail  string `json:"detail"`
+	Image   string `json:"image"`
+
+	image []byte
+}
+
+// traduzidos na saída (tr(err.Error())): o idioma só é definido depois da inicialização
+var (
+	errMockPayload = errors.New("payload inválido")
+	errMockImage   = errors.New("imagem inválida")
+)
+
+func readMockUpload(r *http.Request) (mockUpload, error) {
+	var in mockUpload
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt == "multipart/form-data" {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return in, errMockPayload
+		}
+		in.ID = r.FormValue("id")
+		in.Name = r.FormValue("name")
+		in.Detail = r.FormValue("detail")
+		in.Consent, _ = strconv.ParseBool(r.FormValue("consentTermSigned"))
+		f, _, err := r.FormFile("image")
+		if err != nil {
+			return in, errMockImage
+		}
+		defer f.Close()
+		if in.image, err = io.ReadAll(f); err != nil || len(in.image) == 0 {
+			return in, errMockImage
+		}
+		if in.ID == "" {
+			return in, errMockPayload
+		}
+		return in, nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || in.ID == "" {
+		return in, errMockPayload
+	}
+	img, err := decodeImageField(in.Image)
+	if err != nil || len(img) == 0 {
+		return in, errMockImage
+	}
+	in.image = img
+	return in, nil
+}
+
+func imageContentType(b []byte) string {
+	ct := http.DetectContentType(b)
+	if !strings.HasPrefix(ct, "image/") {
+		return "application/octet-stream"
+	}
+	return ct
+}
+
+// GET /__admin/cards
+func (m *mockServer) handleCardsList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, m.cards.list())
+}
+
+// DELETE /__admin/cards (zera o estado)
+func (m *mockServer) handleCardsReset(w http.ResponseWriter, r *http.Request) {
+	m.cards.reset()
+	w.WriteHeader(http.StatusNoContent)
+}
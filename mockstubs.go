@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* ==================== Stubs dinâmicos do mock ==================== */
+
+// stub registrado em runtime via /__admin/stubs; vale o primeiro que casar (ordem de cadastro)
+type mockStub struct {
+	ID string `json:"id"`
+
+	// regras de match (campo vazio = qualquer)
+	Method       string            `json:"method,omitempty"`
+	Path         string            `json:"path,omitempty"`    // exato, ou prefixo terminando em *
+	Headers      map[string]string `json:"headers,omitempty"` // header precisa ter exatamente esse valor
+	BodyContains string            `json:"bodyContains,omitempty"`
+	JSON         map[string]any    `json:"json,omitempty"` // campos do corpo JSON (top-level) que precisam bater
+
+	// resposta
+	Status          int               `json:"status,omitempty"` // default 200
+	Body            json.RawMessage   `json:"body,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	Delay           string            `json:"delay,omitempty"`      // ex.: "2s", contado no relógio do mock
+	FailRate        float64           `json:"failRate,omitempty"`   // 0..1: chance de responder FailStatus (ou Fault)
+	FailStatus      int               `json:"failStatus,omitempty"` // default 503
+	Fault           string            `json:"fault,omitempty"`      // falha de protocolo (ver faultKinds)
+	Times           int               `json:"times,omitempty"`      // quantas vezes vale (0 = sempre)
+
+	Hits int `json:"hits"`
+
+	delay time.Duration
+}
+
+type stubStore struct {
+	mu    sync.Mutex
+	stubs []*mockStub
+	next  int
+}
+
+func (s *mockStub) validate() error {
+	if s.Status == 0 {
+		s.Status = http.StatusOK
+	}
+	if s.Status < 100 || s.Status > 599 {
+		return fmt.Errorf(tr("status inválido: %d"), s.Status)
+	}
+	if s.FailRate < 0 || s.FailRate > 1 {
+		return fmt.Errorf(tr("failRate deve estar entre 0 e 1"))
+	}
+	if s.FailStatus == 0 {
+		s.FailStatus = http.StatusServiceUnavailable
+	}
+	if s.Delay != "" {
+		d, err := time.ParseDuration(s.Delay)
+		if err != nil || d < 0 {
+			return fmt.Errorf(tr("delay inválido: %q"), s.Delay)
+		}
+		s.delay = d
+	}
+	if s.Fault != "" {
+		if err := validFault(s.Fault); err != nil {
+			return err
+		}
+	}
+	s.Method = strings.ToUpper(s.Method)
+	return nil
+}
+
+func (s *mockStub) matches(r *http.Request, body []byte) bool {
+	if s.Method != "" && s.Method != r.Method {
+		return false
+	}
+	if s.Path != "" {
+		if p, ok := strings.CutSuffix(s.Path, "*"); ok {
+			if !strings.HasPrefix(r.URL.Path, p) {
+				return false
+			}
+		} else if s.Path != r.URL.Path {
+			return false
+		}
+	}
+	for k, v := range s.Headers {
+		if r.Header.Get(k) != v {
+			return false
+		}
+	}
+	if s.BodyContains != "" && !bytes.Contains(body, []byte(s.BodyContains)) {
+		return false
+	}
+	if len(s.JSON) > 0 {
+		var got map[string]any
+		if json.Unmarshal(body, &got) != nil {
+			return false
+		}
+		for k, want := range s.JSON {
+			if fmt.Sprint(got[k]) != fmt.Sprint(want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// procura stub que case e consome um "hit"; nil se nenhum
+func (st *stubStore) match(r *http.Request, body []byte) *mockStub {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for _, s := range st.stubs {
+		if s.Times > 0 && s.Hits >= s.Times {
+			continue
+		}
+		if s.matches(r, body) {
+			s.Hits++
+			cp := *s
+			return &cp
+		}
+	}
+	return nil
+}
+
+func (st *stubStore) put(s *mockStub) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if s.ID == "" {
+		st.next++
+		s.ID = "stub-" + strconv.Itoa(st.next)
+	}
+	for i, old := range st.stubs {
+		if old.ID == s.ID {
+			st.stubs[i] = s
+			return
+		}
+	}
+	st.stubs = append(st.stubs, s)
+}
+
+func (st *stubStore) remove(id string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	for i, s := range st.stubs {
+		if s.ID == id {
+			st.stubs = append(st.stubs[:i], st.stubs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (st *stubStore) list() []mockStub {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	out := make([]mockStub, 0, len(st.stubs))
+	for _, s := range st.stubs {
+		out = append(out, *s)
+	}
+	return out
+}
+
+func (st *stubStore) clear() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.stubs = nil
+}
+
+// lê stubs de um arquivo (JSON ou YAML): lista, ou objeto com a chave "stubs"
+func loadStubsFile(path string) ([]*mockStub, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// YAML é superconjunto de JSON; passa por JSON para reaproveitar as tags de mockStub
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("stubs %s: %w", path, err)
+	}
+	if m, ok := doc.(map[string]any); ok {
+		doc = m["stubs"]
+	}
+	jb, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("stubs %s: %w", path, err)
+	}
+	var stubs []*mockStub
+	if err := json.Unmarshal(jb, &stubs); err != nil {
+		return nil, fmt.Errorf(tr("stubs %s: esperado lista de stubs: %w"), path, err)
+	}
+	for i, s := range stubs {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("stubs %s, item %d: %w", path, i+1, err)
+		}
+	}
+	return stubs, nil
+}
+
+// middleware: stubs têm precedência sobre as rotas padrão do mock (exceto /__admin)
+func (m *mockServer) withStubs(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/__admin/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s := m.stubs.match(r, body)
+		if s == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if s.delay > 0 {
+			select {
+			case <-m.clock.After(s.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		// com fault e sem failRate, a falha de protocolo sai sempre
+		failing := s.FailRate > 0 && rand.Float64() < s.FailRate
+		if s.Fault != "" && (s.FailRate == 0 || failing) {
+			writeFault(w, s.Fault)
+			return
+		}
+		if failing {
+			writeJSON(w, s.FailStatus, map[string]any{"success": false, "message": "falha injetada (" + s.ID + ")"})
+			return
+		}
+		for k, v := range s.ResponseHeaders {
+			w.Header().Set(k, v)
+		}
+		if w.Header().Get("Content-Type") == "" && len(s.Body) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(s.Status)
+		_, _ = w.Write(s.Body)
+	})
+}
+
+// GET /__admin/stubs
+func (m *mockServer) handleStubsList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, m.stubs.list())
+}
+
+// POST /__admin/stubs (cria, ou substitui se o id já existir)
+func (m *mockServer) handleStubsCreate(w http.ResponseWriter, r *http.Request) {
+	var s mockStub
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": tr("JSON inválido: ") + err.Error()})
+		return
+	}
+	if err := s.validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	s.Hits = 0
+	m.stubs.put(&s)
+	writeJSON(w, http.StatusCreated, s)
+}
+
+// PUT /__admin/stubs/{id}
+func (m *mockServer) handleStubsUpdate(w http.ResponseWriter, r *http.Request) {
+	var s mockStub
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": tr("JSON inválido: ") + err.Error()})
+		return
+	}
+	s.ID = r.PathValue("id")
+	if err := s.validate(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()})
+		return
+	}
+	s.Hits = 0
+	m.stubs.put(&s)
+	writeJSON(w, http.StatusOK, s)
+}
+
+// DELETE /__admin/stubs/{id}
+func (m *mockServer) handleStubsDelete(w http.ResponseWriter, r *http.Request) {
+	if !m.stubs.remove(r.PathValue("id")) {
+		writeJSON(w, http.StatusNotFound, map[string]any{"error": "stub não encontrado"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DELETE /__admin/stubs
+func (m *mockServer) handleStubsClear(w http.ResponseWriter, r *http.Request) {
+	m.stubs.clear()
+	w.WriteHeader(http.StatusNoContent)
+}
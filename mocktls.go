@@ -0,0 +1,121 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/* ==================== TLS e auth do mock ==================== */
+
+// certificado autoassinado para os hosts dados; expired=true gera um já vencido
+func selfSignedCert(hosts []string, now time.Time, expired bool) (tls.Certificate, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	notBefore, notAfter := now.Add(-time.Hour), now.Add(365*24*time.Hour)
+	if expired {
+		notBefore, notAfter = now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "biodoc-go-runner mock", Organization: []string{"biodoc-go-runner"}},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	return cert, certPEM, err
+}
+
+// hosts do certificado: sempre localhost/127.0.0.1, mais o host de --addr
+func certHosts(addr string) []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if h, _, err := net.SplitHostPort(addr); err == nil && h != "" && h != "0.0.0.0" && h != "::" {
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// --require-token jwt: aceita qualquer JWT (assinatura não é conferida) cujo exp ainda não
+// passou no relógio do mock; com --clock sim, avançar o relógio vence o token do cliente
+const mockJWTAuth = "jwt"
+
+// motivo da recusa do JWT ("" = válido) e o error_description (ASCII, vai no header)
+func (m *mockServer) checkJWT(tok string) (string, string) {
+	claims, ok := decodeJWTClaims(tok)
+	if !ok {
+		return "token não é um JWT", "malformed token"
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return "JWT sem exp", "missing exp claim"
+	}
+	if at := time.Unix(int64(exp), 0).UTC(); !m.clock.Now().Before(at) {
+		return "token expirado em " + at.Format(time.RFC3339), "token expired"
+	}
+	return "", ""
+}
+
+// exige "Authorization: Bearer <token>" nas rotas da API (não em /__admin nem no /oauth/token)
+func (m *mockServer) withAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.requireToken == "" || strings.HasPrefix(r.URL.Path, "/__admin/") || r.URL.Path == mockTokenPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || got == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="biodoc-mock"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "message": "token ausente"})
+			return
+		}
+		if m.requireToken == mockJWTAuth {
+			if reason, desc := m.checkJWT(got); reason != "" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="biodoc-mock", error="invalid_token", error_description=%q`, desc))
+				writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "message": reason})
+				return
+			}
+		} else if got != m.requireToken {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="biodoc-mock", error="invalid_token"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "message": "token inválido"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
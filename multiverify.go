@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+/* ==================== verify-card com várias imagens ==================== */
+
+// quantas imagens precisam passar para o veredito agregado
+var verdictRules = []string{"all", "majority", "any"}
+
+type multiVerdict struct {
+	Images   int     `json:"images"`
+	Passed   int     `json:"passed"`
+	Errors   int     `json:"errors"`
+	Best     float64 `json:"best_similarity"`
+	Worst    float64 `json:"worst_similarity"`
+	Average  float64 `json:"average_similarity"`
+	Majority bool    `json:"majority"`
+	Require  string  `json:"require"`
+	OK       bool    `json:"ok"`
+}
+
+// passou = respondeu, deu match e (com --min-similarity) ficou acima do mínimo
+func imagePassed(r verifyResult, minSimilarity float64) bool {
+	if r.Error != "" || !r.Match {
+		return false
+	}
+	return minSimilarity <= 0 || r.HasScore && r.Similarity >= minSimilarity
+}
+
+func aggregateVerdict(results []verifyResult, minSimilarity float64, require string) multiVerdict {
+	v := multiVerdict{Images: len(results), Require: require}
+	scored := 0
+	for _, r := range results {
+		if r.Error != "" {
+			v.Errors++
+		}
+		if imagePassed(r, minSimilarity) {
+			v.Passed++
+		}
+		if !r.HasScore {
+			continue
+		}
+		if scored == 0 || r.Similarity > v.Best {
+			v.Best = r.Similarity
+		}
+		if scored == 0 || r.Similarity < v.Worst {
+			v.Worst = r.Similarity
+		}
+		v.Average += r.Similarity
+		scored++
+	}
+	if scored > 0 {
+		v.Average /= float64(scored)
+	}
+	v.Majority = v.Passed*2 > v.Images
+	switch require {
+	case "any":
+		v.OK = v.Passed > 0
+	case "majority":
+		v.OK = v.Majority
+	default:
+		v.OK = v.Passed == v.Images
+	}
+	return v
+}
+
+// várias imagens contra o mesmo card, em sequência; o veredito segue --require (all, majority, any)
+func cmdVerifyImages(baseURL, token string, images []string, opt batchVerifyOptions, minSimilarity float64, require string) error {
+	if opt.ID == "" {
+		return usageError(tr("--id é obrigatório com várias imagens"))
+	}
+	outf("[verify] %d imagem(ns) contra o id=%s (exige: %s)\n", len(images), opt.ID, require)
+	url := verifyURL(baseURL, opt.Endpoint)
+	results := make([]verifyResult, len(images))
+	start := time.Now()
+	for i, img := range images {
+		results[i] = verifyImageFile(baseURL, token, url, nil, img, opt)
+	}
+	printVerifyTable(results)
+	printVerifySummary("verify", results, time.Since(start))
+
+	v := aggregateVerdict(results, minSimilarity, require)
+	setResult("results", results)
+	setResult("verdict", v)
+	majority := tr("não")
+	if v.Majority {
+		majority = tr("sim")
+	}
+	mark := "✅"
+	if !v.OK {
+		mark = "❌"
+	}
+	outf("[verify] %s veredito: %d/%d passaram | maioria=%s | exige=%s\n", mark, v.Passed, v.Images, majority, require)
+	if v.OK {
+		return nil
+	}
+	// sem nenhuma resposta o problema é a chamada, não o rosto
+	if v.Errors == v.Images {
+		return fmt.Errorf(tr("%d de %d verificação(ões) com erro"), v.Errors, v.Images)
+	}
+	return matchError(fmt.Sprintf(tr("veredito agregado: %d de %d imagem(ns) passaram (exige %s)"), v.Passed, v.Images, require))
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* ==================== Normalização de respostas ==================== */
+
+// regras aplicadas antes de comparar respostas (golden, replay, diff).
+// Caminhos com pontos; "*" casa qualquer chave ou índice: items.*.id
+type normRules struct {
+	Drop  []string       `yaml:"drop" json:"drop,omitempty"`   // remove o campo
+	Mask  []string       `yaml:"mask" json:"mask,omitempty"`   // troca o valor por "<masked>"
+	Round map[string]int `yaml:"round" json:"round,omitempty"` // casas decimais (número ou string numérica)
+	Sort  []string       `yaml:"sort" json:"sort,omitempty"`   // ordena o array pelo JSON de cada item
+}
+
+const maskedValue = "<masked>"
+
+// junta regras (ex.: as do arquivo com as do cenário)
+func (nr normRules) merge(o normRules) normRules {
+	out := normRules{
+		Drop: append(append([]string(nil), nr.Drop...), o.Drop...),
+		Mask: append(append([]string(nil), nr.Mask...), o.Mask...),
+		Sort: append(append([]string(nil), nr.Sort...), o.Sort...),
+	}
+	if len(nr.Round)+len(o.Round) > 0 {
+		out.Round = map[string]int{}
+		for k, v := range nr.Round {
+			out.Round[k] = v
+		}
+		for k, v := range o.Round {
+			out.Round[k] = v
+		}
+	}
+	return out
+}
+
+func loadNormRules(path string) (normRules, error) {
+	var nr normRules
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nr, err
+	}
+	if err := yaml.Unmarshal(b, &nr); err != nil {
+		return nr, fmt.Errorf(tr("regras %s: %w"), path, err)
+	}
+	return nr, nil
+}
+
+// devolve uma cópia normalizada de v (JSON decodificado)
+func (nr normRules) apply(v any) any {
+	return nr.walk(v, nil)
+}
+
+func (nr normRules) walk(v any, path []string) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, vv := range x {
+			p := append(path[:len(path):len(path)], k)
+			if anyPathMatch(nr.Drop, p) {
+				continue
+			}
+			out[k] = nr.walk(vv, p)
+		}
+		return out
+	case []any:
+		out := make([]any, 0, len(x))
+		for i, vv := range x {
+			p := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if anyPathMatch(nr.Drop, p) {
+				continue
+			}
+			out = append(out, nr.walk(vv, p))
+		}
+		if anyPathMatch(nr.Sort, path) {
+			sort.SliceStable(out, func(i, j int) bool { return canonicalJSON(out[i]) < canonicalJSON(out[j]) })
+		}
+		return out
+	}
+	if v != nil && anyPathMatch(nr.Mask, path) {
+		return maskedValue
+	}
+	for pat, places := range nr.Round {
+		if pathMatch(pat, path) {
+			return roundValue(v, places)
+		}
+	}
+	return v
+}
+
+func roundValue(v any, places int) any {
+	p := math.Pow(10, float64(places))
+	switch x := v.(type) {
+	case float64:
+		return math.Round(x*p) / p
+	case string:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(x, ",", "."), 64)
+		if err != nil {
+			return x
+		}
+		return strconv.FormatFloat(math.Round(f*p)/p, 'f', places, 64)
+	}
+	return v
+}
+
+func anyPathMatch(patterns []string, path []string) bool {
+	for _, p := range patterns {
+		if pathMatch(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func pathMatch(pattern string, path []string) bool {
+	segs := strings.Split(pattern, ".")
+	if len(segs) != len(path) {
+		return false
+	}
+	for i, s := range segs {
+		if s != "*" && s != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// JSON com chaves ordenadas (encoding/json já ordena mapas)
+func canonicalJSON(v any) string {
+	return strings.TrimSuffix(string(indentJSON(v, "")), "\n")
+}
+
+// JSON sem escapar <, > e & (o "<masked>" fica legível no golden)
+func indentJSON(v any, indent string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", indent)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(v)
+	return buf.Bytes()
+}
+
+// diferenças entre dois JSON decodificados, no formato "caminho: a → b" (no máximo max)
+func jsonDiff(a, b any, max int) []string {
+	var out []string
+	var rec func(a, b any, path string)
+	rec = func(a, b any, path string) {
+		if len(out) >= max {
+			return
+		}
+		label := path
+		if label == "" {
+			label = tr("(raiz)")
+		}
+		am, aok := a.(map[string]any)
+		bm, bok := b.(map[string]any)
+		if aok && bok {
+			keys := map[string]bool{}
+			for k := range am {
+				keys[k] = true
+			}
+			for k := range bm {
+				keys[k] = true
+			}
+			sorted := make([]string, 0, len(keys))
+			for k := range keys {
+				sorted = append(sorted, k)
+			}
+			sort.Strings(sorted)
+			for _, k := range sorted {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				av, ain := am[k]
+				bv, bin := bm[k]
+				switch {
+				case !ain:
+					out = append(out, fmt.Sprintf(tr("%s: (ausente) → %s"), p, canonicalJSON(bv)))
+				case !bin:
+					out = append(out, fmt.Sprintf(tr("%s: %s → (ausente)"), p, canonicalJSON(av)))
+				default:
+					rec(av, bv, p)
+				}
+				if len(out) >= max {
+					return
+				}
+			}
+			return
+		}
+		as, aok := a.([]any)
+		bs, bok := b.([]any)
+		if aok && bok && len(as) == len(bs) {
+			for i := range as {
+				p := strconv.Itoa(i)
+				if path != "" {
+					p = path + "." + p
+				}
+				rec(as[i], bs[i], p)
+			}
+			return
+		}
+		if ca, cb := canonicalJSON(a), canonicalJSON(b); ca != cb {
+			out = append(out, fmt.Sprintf("%s: %s → %s", label, ca, cb))
+		}
+	}
+	rec(a, b, "")
+	return out
+}
+
+// normalize --rules r.yaml [arquivo|-]: imprime o JSON normalizado (para diff externo)
+func cmdNormalize(rulesPath, input string) error {
+	nr, err := loadNormRules(rulesPath)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if input == "" || input == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(input)
+	}
+	if err != nil {
+		return err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Errorf(tr("entrada não é JSON: %w"), err)
+	}
+	_, err = os.Stdout.Write(indentJSON(nr.apply(v), "  "))
+	return err
+}
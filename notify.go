@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+/* ==================== --notify-webhook (Slack / Teams) ==================== */
+
+// no máximo tantas etapas com falha listadas na mensagem
+const notifyMaxFailed = 10
+
+type notifySummary struct {
+	Command  string
+	OK       bool
+	ExitCode int
+	Error    string
+	Total    int
+	Passed   int
+	Failed   []string // "etapa: erro"
+	Skipped  int
+	Duration time.Duration
+	Env      string
+	Links    [][2]string // rótulo, URL ou caminho
+}
+
+// link da execução no CI (GitHub Actions, GitLab, Jenkins, Azure) ou NOTIFY_LINK
+func ciRunLink() string {
+	if v := os.Getenv("NOTIFY_LINK"); v != "" {
+		return v
+	}
+	if s, r, id := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"); s != "" && r != "" && id != "" {
+		return s + "/" + r + "/actions/runs/" + id
+	}
+	for _, k := range []string{"CI_JOB_URL", "BUILD_URL"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	if c, p, id := os.Getenv("SYSTEM_COLLECTIONURI"), os.Getenv("SYSTEM_TEAMPROJECT"), os.Getenv("BUILD_BUILDID"); c != "" && id != "" {
+		return strings.TrimRight(c, "/") + "/" + url.PathEscape(p) + "/_build/results?buildId=" + id
+	}
+	return ""
+}
+
+func buildNotifySummary(cmd, baseURL string, st []stepRecord, elapsed time.Duration, runErr error, code int, reportPath string) notifySummary {
+	s := notifySummary{Command: cmd, OK: runErr == nil, ExitCode: code, Duration: elapsed, Env: baseURL}
+	if activeProfile != "" {
+		s.Env = activeProfile + " (" + baseURL + ")"
+	}
+	if runErr != nil {
+		s.Error = runErr.Error()
+	}
+	for _, x := range st {
+		switch {
+		case x.Skipped:
+			s.Skipped++
+		case x.Error != "":
+			s.Failed = append(s.Failed, x.Name+": "+x.Error)
+		default:
+			s.Passed++
+		}
+		s.Total++
+	}
+	if l := ciRunLink(); l != "" {
+		s.Links = append(s.Links, [2]string{tr("execução no CI"), l})
+	}
+	if reportPath != "" {
+		s.Links = append(s.Links, [2]string{tr("relatório"), reportPath})
+	}
+	return s
+}
+
+// texto da mensagem; bold e link no dialeto de cada destino (Slack mrkdwn ou markdown do Teams)
+func (s notifySummary) text(slack bool) string {
+	bold := func(t string) string {
+		if slack {
+			return "*" + t + "*"
+		}
+		return "**" + t + "**"
+	}
+	link := func(label, target string) string {
+		if !strings.Contains(target, "://") {
+			return label + ": " + target
+		}
+		if slack {
+			return "<" + target + "|" + label + ">"
+		}
+		return "[" + label + "](" + target + ")"
+	}
+	var b strings.Builder
+	icon, state := "✅", "OK"
+	if !s.OK {
+		icon, state = "❌", fmt.Sprintf(tr("FALHOU (exit %d)"), s.ExitCode)
+	}
+	fmt.Fprintf(&b, tr("%s %s %s em %s — %s\n"), icon, bold("biodoc-go-runner "+s.Command), state, s.Env, s.Duration.Round(time.Second))
+	fmt.Fprintf(&b, tr("%d etapa(s): %d ok, %d com falha"), s.Total, s.Passed, len(s.Failed))
+	if s.Skipped > 0 {
+		fmt.Fprintf(&b, tr(", %d pulada(s)"), s.Skipped)
+	}
+	b.WriteString("\n")
+	for i, f := range s.Failed {
+		if i == notifyMaxFailed {
+			fmt.Fprintf(&b, tr("• … e mais %d\n"), len(s.Failed)-notifyMaxFailed)
+			break
+		}
+		b.WriteString("• " + f + "\n")
+	}
+	if len(s.Failed) == 0 && s.Error != "" {
+		b.WriteString("• " + s.Error + "\n")
+	}
+	for _, l := range s.Links {
+		b.WriteString(link(l[0], l[1]) + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Slack (hooks.slack.com) e Teams (webhook.office.com / Workflows) aceitam {"text": ...};
+// no Teams o MessageCard ganha a cor da barra lateral
+func notifyPayload(webhook string, s notifySummary) []byte {
+	u, _ := url.Parse(webhook)
+	host := ""
+	if u != nil {
+		host = u.Hostname()
+	}
+	if strings.HasSuffix(host, "webhook.office.com") || strings.HasSuffix(host, "logic.azure.com") {
+		color := "2EB886"
+		if !s.OK {
+			color = "D00000"
+		}
+		b, _ := json.Marshal(map[string]any{
+			"@type": "MessageCard", "@context": "https://schema.org/extensions",
+			"summary": "biodoc-go-runner " + s.Command, "themeColor": color,
+			"text": strings.ReplaceAll(s.text(false), "\n", "\n\n"),
+		})
+		return b
+	}
+	b, _ := json.Marshal(map[string]any{"text": s.text(true)})
+	return b
+}
+
+// posta o resumo; falha de envio só avisa, nunca muda o exit code
+func sendNotification(webhook string, s notifySummary) {
+	body := notifyPayload(webhook, s)
+	// cliente próprio: fora do retry, do cassete, do HAR e das métricas
+	resp, err := (&http.Client{Timeout: 10 * time.Second, Transport: baseTransport}).Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		outf("[notify] envio falhou: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		outf("[notify] webhook respondeu %d\n", resp.StatusCode)
+		return
+	}
+	outf("[notify] resumo enviado para %s\n", webhookHost(webhook))
+}
+
+// só o host: a URL do webhook é o segredo
+func webhookHost(webhook string) string {
+	if u, err := url.Parse(webhook); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "webhook"
+}
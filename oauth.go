@@ -0,0 +1,223 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== OAuth2 client credentials ==================== */
+
+// renova antes de vencer para não perder requisição no meio do caminho
+const oauthEarlyRefresh = 60 * time.Second
+
+type oauthSource struct {
+	tokenURL, clientID, clientSecret, scope string
+	cachePath                               string // "" = sem cache em disco
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+	issued  map[string]bool // tokens vindos desta fonte (os de outros perfis não são trocados)
+	renewed int             // tokens trocados depois do primeiro (vencimento ou 401)
+}
+
+type oauthCached struct {
+	AccessToken string    `json:"access_token"`
+	Expires     time.Time `json:"expires"`
+}
+
+// ativo quando OAUTH_TOKEN_URL está definido; nil = token estático (AUTH_TOKEN)
+var oauth *oauthSource
+
+// lê OAUTH_TOKEN_URL, OAUTH_CLIENT_ID, OAUTH_CLIENT_SECRET, OAUTH_SCOPE e OAUTH_CACHE (0 desliga o cache)
+func loadOAuthEnv() (*oauthSource, error) {
+	tokenURL := os.Getenv("OAUTH_TOKEN_URL")
+	if tokenURL == "" {
+		return nil, nil
+	}
+	s := &oauthSource{
+		tokenURL:     tokenURL,
+		clientID:     os.Getenv("OAUTH_CLIENT_ID"),
+		clientSecret: os.Getenv("OAUTH_CLIENT_SECRET"),
+		scope:        os.Getenv("OAUTH_SCOPE"),
+	}
+	if s.clientID == "" || s.clientSecret == "" {
+		return nil, fmt.Errorf(tr("OAUTH_TOKEN_URL definido: faltam OAUTH_CLIENT_ID/OAUTH_CLIENT_SECRET"))
+	}
+	if os.Getenv("OAUTH_CACHE") != "0" {
+		if dir, err := os.UserCacheDir(); err == nil {
+			sum := sha256.Sum256([]byte(tokenURL + "\x00" + s.clientID + "\x00" + s.scope))
+			s.cachePath = filepath.Join(dir, "biodoc-runner", "token-"+hex.EncodeToString(sum[:8])+".json")
+		}
+	}
+	return s, nil
+}
+
+// token válido (memória → cache em disco → endpoint)
+func (s *oauthSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Until(s.expires) > oauthEarlyRefresh {
+		return s.token, nil
+	}
+	if s.token == "" && s.loadCache() {
+		return s.token, nil
+	}
+	return s.fetchLocked()
+}
+
+// token atual sem ir à rede (usado para montar o header de cada requisição)
+func (s *oauthSource) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+// quantas vezes o token foi renovado neste processo (soak acompanha)
+func (s *oauthSource) renewals() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renewed
+}
+
+// busca um token novo depois de um 401; se outra goroutine já renovou, só devolve o novo
+func (s *oauthSource) refresh(stale string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.token != stale {
+		return s.token, nil
+	}
+	return s.fetchLocked()
+}
+
+// troca o token vigente por um de fora (JWT forjado no mock): o cliente acha que ele ainda
+// vale, então só o 401 da API revela o vencimento; é esse caminho que o cenário exercita
+func (s *oauthSource) adopt(tok string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = tok
+	s.expires = time.Now().Add(time.Hour)
+	s.remember(tok)
+}
+
+func (s *oauthSource) fetchLocked() (string, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if s.scope != "" {
+		form.Set("scope", s.scope)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("oauth: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	// cliente próprio: o pedido de token não entra no retry, no cassete nem nas métricas
+	resp, err := (&http.Client{Timeout: 15 * time.Second, Transport: baseTransport}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf(tr("oauth: pedir token: %w"), err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf(tr("oauth: ler resposta: %w"), err)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	_ = json.Unmarshal(b, &tok)
+	if resp.StatusCode != http.StatusOK || tok.AccessToken == "" {
+		msg := tok.Error
+		if tok.Description != "" {
+			msg += ": " + tok.Description
+		}
+		if msg == "" {
+			msg = tr("sem access_token na resposta")
+		}
+		return "", fmt.Errorf(tr("oauth: token endpoint respondeu %d (%s)"), resp.StatusCode, msg)
+	}
+	if tok.ExpiresIn <= 0 {
+		tok.ExpiresIn = 3600
+	}
+	if s.token != "" {
+		s.renewed++
+	}
+	s.token = tok.AccessToken
+	s.remember(s.token)
+	s.expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	outf("[oauth] token obtido, vence em %s\n", time.Duration(tok.ExpiresIn)*time.Second)
+	s.saveCache()
+	return s.token, nil
+}
+
+func (s *oauthSource) loadCache() bool {
+	if s.cachePath == "" {
+		return false
+	}
+	b, err := os.ReadFile(s.cachePath)
+	if err != nil {
+		return false
+	}
+	var c oauthCached
+	if json.Unmarshal(b, &c) != nil || c.AccessToken == "" || time.Until(c.Expires) <= oauthEarlyRefresh {
+		return false
+	}
+	s.token, s.expires = c.AccessToken, c.Expires
+	s.remember(s.token)
+	return true
+}
+
+func (s *oauthSource) remember(tok string) {
+	if s.issued == nil {
+		s.issued = map[string]bool{}
+	}
+	s.issued[tok] = true
+}
+
+func (s *oauthSource) owns(tok string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issued[tok]
+}
+
+func (s *oauthSource) saveCache() {
+	if s.cachePath == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cachePath), 0700); err != nil {
+		return
+	}
+	b, _ := json.Marshal(oauthCached{AccessToken: s.token, Expires: s.expires})
+	_ = os.WriteFile(s.cachePath, b, 0600)
+}
+
+// troca o bearer da requisição pelo token OAuth vigente (renovando se estiver para vencer)
+func applyOAuth(req *http.Request) string {
+	bearer, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if oauth == nil || !ok || !oauth.owns(bearer) {
+		return ""
+	}
+	tok, err := oauth.Token()
+	if err != nil {
+		outf("[oauth] %v; usando o token anterior\n", err)
+		tok = oauth.current()
+	}
+	if tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	return tok
+}
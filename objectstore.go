@@ -0,0 +1,343 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ==================== Imagens no S3/GCS (s3://bucket/prefixo, gs://bucket/prefixo) ==================== */
+
+// s3: AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY (+ AWS_SESSION_TOKEN), AWS_REGION, AWS_ENDPOINT_URL_S3
+// (MinIO e afins, path-style); sem credenciais, acesso anônimo (bucket público).
+// gs: GCS_ACCESS_TOKEN ou GOOGLE_OAUTH_ACCESS_TOKEN (gcloud auth print-access-token);
+// STORAGE_EMULATOR_HOST aponta para um emulador. Os objetos são baixados um a um, na vez de cada
+// worker, para um arquivo temporário apagado logo depois do envio
+
+func isObjectURL(s string) bool {
+	return strings.HasPrefix(s, "s3://") || strings.HasPrefix(s, "gs://")
+}
+
+// "s3://bucket/fotos/x.jpg" → ("s3", "bucket", "fotos/x.jpg")
+func splitObjectURL(s string) (scheme, bucket, key string, err error) {
+	scheme, rest, _ := strings.Cut(s, "://")
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", "", usageError(fmt.Sprintf(tr("URL de bucket inválida %q (use s3://bucket/prefixo ou gs://bucket/prefixo)"), s))
+	}
+	return scheme, bucket, key, nil
+}
+
+// objetos de imagem sob o prefixo, como URLs s3:// ou gs://, em ordem
+func listObjectImages(src string) ([]string, error) {
+	scheme, bucket, prefix, err := splitObjectURL(src)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	if scheme == "s3" {
+		keys, err = s3List(bucket, prefix)
+	} else {
+		keys, err = gcsList(bucket, prefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(tr("listando %s: %w"), src, err)
+	}
+	var out []string
+	for _, k := range keys {
+		if imageExts[strings.ToLower(path.Ext(k))] {
+			out = append(out, scheme+"://"+bucket+"/"+k)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// caminho local da imagem: objeto do bucket vira arquivo temporário (mesmo nome, para o
+// --id-regex); caminho local volta como veio
+func localImage(src string) (string, func(), error) {
+	if !isObjectURL(src) {
+		return src, func() {}, nil
+	}
+	scheme, bucket, key, err := splitObjectURL(src)
+	if err != nil {
+		return "", nil, err
+	}
+	var data []byte
+	if scheme == "s3" {
+		data, err = s3Get(bucket, key)
+	} else {
+		data, err = gcsGet(bucket, key)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf(tr("baixando %s: %w"), src, err)
+	}
+	dir, err := os.MkdirTemp("", "biodoc-object-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+	p := filepath.Join(dir, path.Base(key))
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return p, cleanup, nil
+}
+
+func storageGet(req *http.Request) ([]byte, error) {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFetchedImage+1))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp.StatusCode, body)
+	}
+	if len(body) > maxFetchedImage {
+		return nil, fmt.Errorf(tr("objeto passa de %d MB"), maxFetchedImage>>20)
+	}
+	return body, nil
+}
+
+/* ---------- S3 (ListObjectsV2 + GetObject, assinatura SigV4) ---------- */
+
+func s3Region() string {
+	for _, k := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return "us-east-1"
+}
+
+// virtual-hosted na AWS; path-style com AWS_ENDPOINT_URL_S3 (MinIO, LocalStack)
+func s3URL(bucket, key string, q url.Values) *url.URL {
+	u := &url.URL{Scheme: "https", Host: bucket + ".s3." + s3Region() + ".amazonaws.com", Path: "/" + key}
+	if ep := envOr("AWS_ENDPOINT_URL_S3", os.Getenv("AWS_ENDPOINT_URL")); ep != "" {
+		if e, err := url.Parse(ep); err == nil && e.Host != "" {
+			u.Scheme, u.Host, u.Path = e.Scheme, e.Host, strings.TrimRight(e.Path, "/")+"/"+bucket+"/"+key
+		}
+	}
+	u.RawPath = awsEscapePath(u.Path)
+	if q != nil {
+		u.RawQuery = awsCanonicalQuery(q)
+	}
+	return u
+}
+
+func s3Request(bucket, key string, q url.Values) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, s3URL(bucket, key, q).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	signS3(req, time.Now().UTC())
+	return req, nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func s3List(bucket, prefix string) ([]string, error) {
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		req, err := s3Request(bucket, "", q)
+		if err != nil {
+			return nil, err
+		}
+		body, err := storageGet(req)
+		if err != nil {
+			return nil, err
+		}
+		var r s3ListResult
+		if err := xml.Unmarshal(body, &r); err != nil {
+			return nil, fmt.Errorf(tr("resposta do S3 inválida: %w"), err)
+		}
+		for _, c := range r.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !r.IsTruncated || r.NextContinuationToken == "" {
+			return keys, nil
+		}
+		token = r.NextContinuationToken
+	}
+}
+
+func s3Get(bucket, key string) ([]byte, error) {
+	req, err := s3Request(bucket, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	return storageGet(req)
+}
+
+// AWS Signature V4 do GET (corpo vazio); sem AWS_ACCESS_KEY_ID a requisição vai anônima
+func signS3(req *http.Request, now time.Time) {
+	keyID, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if keyID == "" || secret == "" {
+		return
+	}
+	const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", emptyHash)
+	if st := os.Getenv("AWS_SESSION_TOKEN"); st != "" {
+		req.Header.Set("x-amz-security-token", st)
+	}
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("x-amz-security-token") != "" {
+		names = append(names, "x-amz-security-token")
+	}
+	var canonHeaders strings.Builder
+	for _, n := range names {
+		v := req.Header.Get(n)
+		if n == "host" {
+			v = req.URL.Host
+		}
+		canonHeaders.WriteString(n + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signed := strings.Join(names, ";")
+	canonical := strings.Join([]string{req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonHeaders.String(), signed, emptyHash}, "\n")
+	scope := day + "/" + s3Region() + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+	k := hmacSHA256([]byte("AWS4"+secret), day)
+	k = hmacSHA256(k, s3Region())
+	k = hmacSHA256(k, "s3")
+	k = hmacSHA256(k, "aws4_request")
+	sig := hex.EncodeToString(hmacSHA256(k, toSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", keyID, scope, signed, sig))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// RFC 3986 como a AWS pede: só A-Z a-z 0-9 - _ . ~ passam sem escape
+func awsEscape(s string, keepSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && keepSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func awsEscapePath(p string) string { return awsEscape(p, true) }
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			parts = append(parts, awsEscape(k, false)+"="+awsEscape(v, false))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+/* ---------- GCS (API JSON) ---------- */
+
+func gcsBase() string {
+	if h := os.Getenv("STORAGE_EMULATOR_HOST"); h != "" {
+		if !strings.Contains(h, "://") {
+			h = "http://" + h
+		}
+		return strings.TrimRight(h, "/")
+	}
+	return "https://storage.googleapis.com"
+}
+
+func gcsRequest(u string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if t := envOr("GCS_ACCESS_TOKEN", os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")); t != "" {
+		req.Header.Set("Authorization", "Bearer "+t)
+	}
+	return req, nil
+}
+
+func gcsList(bucket, prefix string) ([]string, error) {
+	var keys []string
+	page := ""
+	for {
+		q := url.Values{"prefix": {prefix}, "fields": {"items(name),nextPageToken"}}
+		if page != "" {
+			q.Set("pageToken", page)
+		}
+		req, err := gcsRequest(gcsBase() + "/storage/v1/b/" + url.PathEscape(bucket) + "/o?" + q.Encode())
+		if err != nil {
+			return nil, err
+		}
+		body, err := storageGet(req)
+		if err != nil {
+			return nil, err
+		}
+		var r struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.Unmarshal(body, &r); err != nil {
+			return nil, fmt.Errorf(tr("resposta do GCS inválida: %w"), err)
+		}
+		for _, it := range r.Items {
+			keys = append(keys, it.Name)
+		}
+		if r.NextPageToken == "" {
+			return keys, nil
+		}
+		page = r.NextPageToken
+	}
+}
+
+func gcsGet(bucket, key string) ([]byte, error) {
+	req, err := gcsRequest(gcsBase() + "/storage/v1/b/" + url.PathEscape(bucket) + "/o/" + url.PathEscape(key) + "?alt=media")
+	if err != nil {
+		return nil, err
+	}
+	return storageGet(req)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* ==================== Spec OpenAPI (YAML ou JSON) ==================== */
+
+type openAPISpec struct {
+	path string
+	root map[string]any
+}
+
+// YAML é superconjunto de JSON: um parser só para os dois formatos
+func loadOpenAPISpec(path string) (*openAPISpec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("spec %s: %w", path, err)
+	}
+	root, ok := normalizeYAML(doc).(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(tr("spec %s: documento não é um objeto"), path)
+	}
+	if _, ok := root["paths"].(map[string]any); !ok {
+		return nil, fmt.Errorf(tr("spec %s: sem \"paths\" (é OpenAPI?)"), path)
+	}
+	return &openAPISpec{path: path, root: root}, nil
+}
+
+// chaves não-string do YAML (ex.: 200: sem aspas) viram string, como no JSON
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, x := range t {
+			t[k] = normalizeYAML(x)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, x := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(x)
+		}
+		return m
+	case []any:
+		for i, x := range t {
+			t[i] = normalizeYAML(x)
+		}
+	}
+	return v
+}
+
+// segue "#/a/b" dentro do próprio documento
+func resolveRef(root map[string]any, ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf(tr("$ref externo não suportado: %s"), ref)
+	}
+	var cur any = root
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf(tr("$ref inválido: %s"), ref)
+		}
+		if cur, ok = m[part]; !ok {
+			return nil, fmt.Errorf(tr("$ref não encontrado: %s"), ref)
+		}
+	}
+	m, ok := cur.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf(tr("$ref não aponta para um objeto: %s"), ref)
+	}
+	return m, nil
+}
+
+// operação do método/caminho; o template casa com o fim do caminho (a base da URL pode ter prefixo).
+// Devolve o template ("/api/card/{id}") e os parâmetros de caminho extraídos.
+func (s *openAPISpec) findOperation(method, path string) (map[string]any, string, map[string]string, bool) {
+	paths := s.root["paths"].(map[string]any)
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	var best map[string]any
+	bestTmpl, bestLiterals := "", -1
+	var bestParams map[string]string
+	for _, tmpl := range sortedKeys(paths) {
+		item, ok := paths[tmpl].(map[string]any)
+		if !ok {
+			continue
+		}
+		op, ok := item[strings.ToLower(method)].(map[string]any)
+		if !ok {
+			continue
+		}
+		ts := strings.Split(strings.Trim(tmpl, "/"), "/")
+		if len(ts) > len(segs) {
+			continue
+		}
+		tail := segs[len(segs)-len(ts):]
+		params, literals, ok := map[string]string{}, 0, true
+		for i, t := range ts {
+			switch {
+			case strings.HasPrefix(t, "{") && strings.HasSuffix(t, "}"):
+				params[t[1:len(t)-1]] = tail[i]
+			case t == tail[i]:
+				literals++
+			default:
+				ok = false
+			}
+			if !ok {
+				break
+			}
+		}
+		// literal ganha de parâmetro: /api/card/integration/verify antes de /api/card/{id}/x
+		if ok && literals > bestLiterals {
+			best, bestTmpl, bestLiterals, bestParams = op, tmpl, literals, params
+		}
+	}
+	return best, bestTmpl, bestParams, best != nil
+}
+
+// schema JSON da resposta: status exato, depois 2XX, depois default
+func (s *openAPISpec) responseSchema(op map[string]any, status int) (map[string]any, bool) {
+	resps, _ := op["responses"].(map[string]any)
+	code := fmt.Sprint(status)
+	for _, k := range []string{code, code[:1] + "XX", code[:1] + "xx", "default"} {
+		r, ok := resps[k].(map[string]any)
+		if !ok {
+			continue
+		}
+		if ref, ok := r["$ref"].(string); ok {
+			var err error
+			if r, err = resolveRef(s.root, ref); err != nil {
+				return nil, false
+			}
+		}
+		content, _ := r["content"].(map[string]any)
+		for _, ct := range sortedKeys(content) {
+			if !strings.Contains(ct, "json") {
+				continue
+			}
+			media, _ := content[ct].(map[string]any)
+			if sch, ok := media["schema"].(map[string]any); ok {
+				return sch, true
+			}
+		}
+		// OpenAPI 2 (swagger): schema direto na resposta
+		if sch, ok := r["schema"].(map[string]any); ok {
+			return sch, true
+		}
+		return nil, false
+	}
+	return nil, false
+}
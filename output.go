@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+/* ==================== Saída ==================== */
+
+// saída para humanos: stdout, ou stderr quando --output json (stdout fica só com o JSON)
+var humanOut io.Writer = os.Stdout
+
+// --output text|json
+var outputJSON bool
+
+// o formato do outf e os textos do outln passam pelo tr() (--lang); linhas "[tag] ..." vão também para o log
+func outf(format string, a ...any) {
+	logTagged(format, a...)
+	aroundProgress(func() { fmt.Fprintf(humanOut, tr(format), a...) })
+}
+
+func outln(a ...any) {
+	for i, x := range a {
+		if s, ok := x.(string); ok {
+			a[i] = tr(s)
+		}
+	}
+	aroundProgress(func() { fmt.Fprintln(humanOut, a...) })
+}
+
+func setOutputMode(v string) error {
+	switch v {
+	case "", "text":
+		outputJSON = false
+		humanOut = os.Stdout
+	case "json":
+		outputJSON = true
+		humanOut = os.Stderr
+	default:
+		return fmt.Errorf(tr("--output deve ser text ou json, veio %q"), v)
+	}
+	return nil
+}
+
+// uma chamada HTTP feita pelo comando (registrada por doRequest)
+type callRecord struct {
+	At        time.Time       `json:"at"`
+	Method    string          `json:"method"`
+	URL       string          `json:"url"`
+	Status    int             `json:"status,omitempty"`
+	LatencyMS int64           `json:"latency_ms"`
+	Attempts  int             `json:"attempts"`
+	Response  json.RawMessage `json:"response,omitempty"` // corpo JSON (ou string JSON se texto)
+	BodyBytes int             `json:"body_bytes"`
+	Error     string          `json:"error,omitempty"`
+}
+
+var (
+	callsMu sync.Mutex
+	calls   []callRecord
+	results = map[string]any{} // dados extras do comando (similaridade, arquivo salvo...)
+	steps   []stepRecord
+)
+
+// etapa de um fluxo (run-all, batch...) com as chamadas HTTP feitas nela
+type stepRecord struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"-"`
+	MS       int64         `json:"duration_ms"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Calls    []callRecord  `json:"-"`
+}
+
+// executa fn como etapa nomeada, guardando duração, erro e as chamadas que ela fez
+func runStep(name string, fn func() error) error {
+	callsMu.Lock()
+	first := len(calls)
+	callsMu.Unlock()
+	start := time.Now()
+	err := fn()
+	d := time.Since(start)
+
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	st := stepRecord{Name: name, Duration: d, MS: d.Milliseconds()}
+	st.Calls = append(st.Calls, calls[first:]...)
+	if err != nil {
+		st.Error = err.Error()
+	}
+	steps = append(steps, st)
+	return err
+}
+
+// registra etapa que não rodou (fluxo interrompido antes)
+func skipStep(name string) {
+	callsMu.Lock()
+	steps = append(steps, stepRecord{Name: name, Skipped: true})
+	callsMu.Unlock()
+}
+
+// etapas da execução; comando sem etapas explícitas vira uma etapa só
+func collectSteps(cmd string, err error, total time.Duration) []stepRecord {
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	if len(steps) > 0 {
+		return steps
+	}
+	st := stepRecord{Name: cmd, Duration: total, MS: total.Milliseconds(), Calls: calls}
+	if err != nil {
+		st.Error = err.Error()
+	}
+	return []stepRecord{st}
+}
+
+// registra etapa já medida por quem chamou (etapas concorrentes do batch)
+func addStep(st stepRecord) {
+	st.MS = st.Duration.Milliseconds()
+	callsMu.Lock()
+	steps = append(steps, st)
+	callsMu.Unlock()
+}
+
+func recordCall(method, url string, resp *http.Response, body []byte, err error, attempts int, elapsed time.Duration) {
+	c := newCallRecord(method, url, resp, body, err, attempts, elapsed)
+	callsMu.Lock()
+	calls = append(calls, c)
+	callsMu.Unlock()
+}
+
+func newCallRecord(method, url string, resp *http.Response, body []byte, err error, attempts int, elapsed time.Duration) callRecord {
+	c := callRecord{
+		At:        time.Now().Add(-elapsed),
+		Method:    method,
+		URL:       url,
+		LatencyMS: elapsed.Milliseconds(),
+		Attempts:  attempts,
+		BodyBytes: len(body),
+	}
+	if resp != nil {
+		c.Status = resp.StatusCode
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		c.Response = json.RawMessage(body)
+	case utf8.Valid(body):
+		c.Response, _ = json.Marshal(string(body))
+	}
+	return c
+}
+
+func setResult(key string, v any) {
+	callsMu.Lock()
+	results[key] = v
+	callsMu.Unlock()
+}
+
+// objeto único emitido em stdout no modo --output json
+type commandOutput struct {
+	Command  string         `json:"command"`
+	OK       bool           `json:"ok"`
+	ExitCode int            `json:"exit_code"`
+	Error    string         `json:"error,omitempty"`
+	Retries  int            `json:"retries"`
+	Result   map[string]any `json:"result,omitempty"`
+	Steps    []stepRecord   `json:"steps,omitempty"`
+	Calls    []callRecord   `json:"calls"`
+}
+
+func emitJSONOutput(cmd string, err error, code int) {
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	o := commandOutput{
+		Command:  cmd,
+		OK:       err == nil,
+		ExitCode: code,
+		Retries:  int(retryCount.Load()),
+		Steps:    steps,
+		Calls:    calls,
+	}
+	if o.Calls == nil {
+		o.Calls = []callRecord{}
+	}
+	if len(results) > 0 {
+		o.Result = results
+	}
+	if err != nil {
+		o.Error = err.Error()
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(o)
+}
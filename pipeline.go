@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/* ==================== run-pipeline (fluxos nomeados do ~/.biodoc-runner.yaml) ==================== */
+
+// pipelines:
+//   smoke-squad-a:
+//     vars: {image: fixtures/ref.jpg}
+//     steps:
+//       - {type: preclean}
+//       - {type: create}
+//       - {type: verify, retries: 2, retryDelay: 1s}
+//       - {type: delete, when: always}
+//
+// cada pipeline é um cenário (mesmas etapas e expect do run-scenario) guardado no config
+
+func loadPipeline(name string, overrides map[string]string) (*scenario, error) {
+	path := configPath()
+	c, err := loadRunnerConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("pipelines: %w", err)
+	}
+	p, ok := c.Pipelines[name]
+	if !ok {
+		return nil, usageError(fmt.Sprintf(tr("pipeline %q não existe em %s (disponíveis: %s)"), name, path, orDash(strings.Join(pipelineNames(c), ", "))))
+	}
+	if err := p.prepare("pipeline "+name, filepath.Dir(path), overrides); err != nil {
+		return nil, err
+	}
+	if p.Name == "" {
+		p.Name = name
+	}
+	return &p, nil
+}
+
+func pipelineNames(c *runnerConfig) []string {
+	names := make([]string, 0, len(c.Pipelines))
+	for n := range c.Pipelines {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// run-pipeline sem nome: lista as pipelines com as etapas
+func listPipelines() error {
+	path := configPath()
+	c, err := loadRunnerConfig(path)
+	if err != nil {
+		return fmt.Errorf("pipelines: %w", err)
+	}
+	names := pipelineNames(c)
+	if len(names) == 0 {
+		outf("[pipeline] nenhuma pipeline em %s\n", path)
+		return nil
+	}
+	for _, n := range names {
+		var steps []string
+		for _, st := range c.Pipelines[n].Steps {
+			label := st.Type
+			if st.Name != "" && st.Name != st.Type {
+				label = st.Name + "(" + st.Type + ")"
+			}
+			if st.When != "" && st.When != "on_success" {
+				label += "@" + st.When
+			}
+			if st.Retries > 0 {
+				label += fmt.Sprintf("×%d", st.Retries+1)
+			}
+			steps = append(steps, label)
+		}
+		outf("  %-16s %s\n", n, strings.Join(steps, " → "))
+	}
+	setResult("pipelines", names)
+	return nil
+}
+
+func cmdRunPipeline(baseURL, token, name string, vars map[string]string) error {
+	sc, err := loadPipeline(name, vars)
+	if err != nil {
+		return err
+	}
+	if err := runScenario(baseURL, token, sc, "pipeline", sc.Name); err != nil {
+		return fmt.Errorf(tr("pipeline %s falhou em: %s"), name, err)
+	}
+	outf("✅ pipeline completa: %s\n", sc.Name)
+	return nil
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* ==================== Perfis (~/.biodoc-runner.yaml) ==================== */
+
+// de onde vem um token: do perfil ou de um tenant dele
+type tokenSource struct {
+	Token     string `yaml:"token"`      // literal; prefira token_env ou token_file
+	TokenEnv  string `yaml:"token_env"`  // variável de onde ler o token
+	TokenFile string `yaml:"token_file"` // arquivo com o token (espaços nas pontas ignorados)
+}
+
+// um ambiente nomeado (dev, staging, prod...)
+type profile struct {
+	BaseURL     string `yaml:"base_url"`
+	tokenSource `yaml:",inline"`
+	Tokens      map[string]tokenSource `yaml:"tokens"` // credenciais por tenant (clínica), escolhidas com --tenant
+	CardID      string                 `yaml:"card_id"`
+	Images      struct {
+		Create string `yaml:"create"`
+		Verify string `yaml:"verify"`
+	} `yaml:"images"`
+	Env map[string]string `yaml:"env"` // demais variáveis (RETRY_MAX, CARD_TAG...)
+}
+
+type runnerConfig struct {
+	Default        string              `yaml:"default"`
+	Profiles       map[string]profile  `yaml:"profiles"`
+	Aliases        map[string]string   `yaml:"aliases"`         // nome → comando e flags
+	DefaultCommand string              `yaml:"default_command"` // usado quando nenhum comando é dado
+	Telemetry      telemetryConfig     `yaml:"telemetry"`       // opt-in, ver telemetry.go
+	Pipelines      map[string]scenario `yaml:"pipelines"`       // run-pipeline NOME, ver pipeline.go
+}
+
+// BIODOC_RUNNER_CONFIG ou ~/.biodoc-runner.yaml
+func configPath() string {
+	if p := os.Getenv("BIODOC_RUNNER_CONFIG"); p != "" {
+		return p
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".biodoc-runner.yaml")
+}
+
+func expandHome(p string) string {
+	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[1:])
+		}
+	}
+	return p
+}
+
+func loadRunnerConfig(path string) (*runnerConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c runnerConfig
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// aplica o perfil escolhido (--profile, BIODOC_PROFILE ou "default" do arquivo) no ambiente.
+// Precedência: variável já definida no shell > perfil > .env
+func applyProfile(name string) error {
+	if name == "" {
+		name = os.Getenv("BIODOC_PROFILE")
+	}
+	path := configPath()
+	c, err := loadRunnerConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if name != "" {
+			return fmt.Errorf(tr("perfil %q pedido, mas %s não existe"), name, path)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = c.Default
+	}
+	if name == "" {
+		return nil
+	}
+	p, err := c.profile(name, path)
+	if err != nil {
+		return err
+	}
+	token, err := p.resolveToken(name)
+	if err != nil {
+		return err
+	}
+
+	set := map[string]string{
+		"BASE_URL":     p.BaseURL,
+		"AUTH_TOKEN":   token,
+		"CARD_ID":      p.CardID,
+		"CARD_IMAGE":   expandHome(p.Images.Create),
+		"VERIFY_IMAGE": expandHome(p.Images.Verify),
+	}
+	for k, v := range p.Env {
+		set[k] = v
+	}
+	for k, v := range set {
+		if v == "" {
+			continue
+		}
+		if _, exists := os.LookupEnv(k); exists {
+			continue
+		}
+		_ = os.Setenv(k, v)
+	}
+	activeProfile = name
+	outf("[profile] %s → %s\n", name, envOr("BASE_URL", tr("(BASE_URL padrão)")))
+	return nil
+}
+
+func (c *runnerConfig) profile(name, path string) (profile, error) {
+	p, ok := c.Profiles[name]
+	if !ok {
+		names := make([]string, 0, len(c.Profiles))
+		for n := range c.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return p, fmt.Errorf(tr("perfil %q não encontrado em %s (disponíveis: %s)"), name, path, strings.Join(names, ", "))
+	}
+	return p, nil
+}
+
+// token literal, de token_env ou de token_file; name = perfil (ou perfil/tenant) para as mensagens
+func (ts tokenSource) resolveToken(name string) (string, error) {
+	switch {
+	case ts.TokenEnv != "":
+		token := os.Getenv(ts.TokenEnv)
+		if token == "" {
+			outf("[profile] aviso: %s vazio (token_env do perfil %s)\n", ts.TokenEnv, name)
+		}
+		return token, nil
+	case ts.TokenFile != "":
+		b, err := os.ReadFile(expandHome(ts.TokenFile))
+		if err != nil {
+			return "", fmt.Errorf(tr("perfil %s: token_file: %w"), name, err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return ts.Token, nil
+}
+
+// --token/--token-file/--tenant desta execução: passam na frente de AUTH_TOKEN, do perfil,
+// do keyring e do OAuth
+var tokenOverridden bool
+
+var activeTenant string
+
+// --tenant clinicA (ENV BIODOC_TENANT) escolhe o token em tokens: do perfil ativo
+func applyTokenOverride(token, tokenFile, tenant string) error {
+	if tenant == "" {
+		tenant = os.Getenv("BIODOC_TENANT")
+	}
+	given := 0
+	for _, v := range []string{token, tokenFile, tenant} {
+		if v != "" {
+			given++
+		}
+	}
+	switch {
+	case given == 0:
+		return nil
+	case given > 1:
+		return usageError(tr("use só um de --token, --token-file e --tenant"))
+	case tokenFile != "":
+		b, err := os.ReadFile(expandHome(tokenFile))
+		if err != nil {
+			return fmt.Errorf("--token-file: %w", err)
+		}
+		if token = strings.TrimSpace(string(b)); token == "" {
+			return fmt.Errorf(tr("--token-file: %s está vazio"), tokenFile)
+		}
+	case tenant != "":
+		var err error
+		if token, err = tenantToken(tenant); err != nil {
+			return err
+		}
+		activeTenant = tenant
+	}
+	tokenOverridden = true
+	return os.Setenv("AUTH_TOKEN", token)
+}
+
+func tenantToken(tenant string) (string, error) {
+	path := configPath()
+	if activeProfile == "" {
+		return "", fmt.Errorf(tr("--tenant %s: nenhum perfil ativo (use --profile ou default: em %s)"), tenant, path)
+	}
+	c, err := loadRunnerConfig(path)
+	if err != nil {
+		return "", err
+	}
+	p, err := c.profile(activeProfile, path)
+	if err != nil {
+		return "", err
+	}
+	ts, ok := p.Tokens[tenant]
+	if !ok {
+		names := make([]string, 0, len(p.Tokens))
+		for n := range p.Tokens {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf(tr("tenant %q não está em tokens: do perfil %s (disponíveis: %s)"), tenant, activeProfile, strings.Join(names, ", "))
+	}
+	token, err := ts.resolveToken(activeProfile + "/" + tenant)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf(tr("tenant %s do perfil %s sem token"), tenant, activeProfile)
+	}
+	outf("[tenant] %s (perfil %s)\n", tenant, activeProfile)
+	return token, nil
+}
+
+// base_url e token de um perfil sem mexer no ambiente (etapas de cenário com profile:)
+func loadProfileTarget(name string) (baseURL, token string, err error) {
+	path := configPath()
+	c, err := loadRunnerConfig(path)
+	if err != nil {
+		return "", "", fmt.Errorf(tr("perfil %q: %w"), name, err)
+	}
+	p, err := c.profile(name, path)
+	if err != nil {
+		return "", "", err
+	}
+	if p.BaseURL == "" {
+		return "", "", fmt.Errorf(tr("perfil %s sem base_url"), name)
+	}
+	token, err = p.resolveToken(name)
+	return p.BaseURL, token, err
+}
+
+// imagens padrão: perfil/ENV (CARD_IMAGE, VERIFY_IMAGE) ou o caminho histórico
+var hardDefaultImage = filepath.Join("image", "created_1.jpg")
+
+func defaultImage() string {
+	return localPath(envOr("CARD_IMAGE", hardDefaultImage))
+}
+
+func defaultVerifyImage() string {
+	return localPath(envOr("VERIFY_IMAGE", defaultImage()))
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ==================== Barra de progresso (batch-create, batch-verify, load-verify) ==================== */
+
+// --no-progress: sem barra (logs de CI); sem terminal no stderr ela já não aparece
+var noProgress bool
+
+const (
+	progressWidth = 28
+	progressEvery = 200 * time.Millisecond
+)
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// barra redesenhada no lugar, no stderr. Com total > 0 conta itens; com dur > 0 conta tempo (carga)
+type progressBar struct {
+	tag   string
+	total int64
+	dur   time.Duration
+	start time.Time
+	done  atomic.Int64
+	errs  atomic.Int64
+
+	mu   sync.Mutex // redesenho × linhas impressas por cima
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// nil quando desligada; os métodos aceitam nil
+func newProgressBar(tag string, total int, dur time.Duration) *progressBar {
+	if noProgress || verbosity > 0 || !isTerminal(os.Stderr) {
+		return nil
+	}
+	p := &progressBar{tag: tag, total: int64(total), dur: dur, start: time.Now(), stop: make(chan struct{})}
+	activeBar.Store(p)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(progressEvery)
+		defer t.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-t.C:
+				p.mu.Lock()
+				p.draw()
+				p.mu.Unlock()
+			}
+		}
+	}()
+	return p
+}
+
+func (p *progressBar) add(failed bool) {
+	if p == nil {
+		return
+	}
+	p.done.Add(1)
+	if failed {
+		p.errs.Add(1)
+	}
+}
+
+// barra na tela agora; outf/outln imprimem por cima dela sem embaralhar
+var activeBar atomic.Pointer[progressBar]
+
+func aroundProgress(print func()) {
+	p := activeBar.Load()
+	if p == nil {
+		print()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(os.Stderr, "\r\033[K")
+	print()
+	p.draw()
+}
+
+// para o redesenho e apaga a barra; o resumo final vem depois
+func (p *progressBar) finish() {
+	if p == nil {
+		return
+	}
+	activeBar.CompareAndSwap(p, nil)
+	close(p.stop)
+	p.wg.Wait()
+	fmt.Fprint(os.Stderr, "\r\033[K")
+}
+
+func (p *progressBar) draw() {
+	el := time.Since(p.start)
+	done, errs := p.done.Load(), p.errs.Load()
+	frac, eta := 0.0, time.Duration(0)
+	switch {
+	case p.total > 0:
+		frac = float64(done) / float64(p.total)
+		if done > 0 {
+			eta = time.Duration(float64(el) / float64(done) * float64(p.total-done))
+		}
+	case p.dur > 0:
+		frac = float64(el) / float64(p.dur)
+		eta = p.dur - el
+	}
+	frac = min(max(frac, 0), 1)
+	filled := int(frac * progressWidth)
+	var b strings.Builder
+	fmt.Fprintf(&b, "\r\033[K[%s] %s%s %3.0f%% ", p.tag, strings.Repeat("█", filled), strings.Repeat("░", progressWidth-filled), frac*100)
+	if p.total > 0 {
+		fmt.Fprintf(&b, "%d/%d", done, p.total)
+	} else {
+		fmt.Fprintf(&b, tr("%d enviadas"), done)
+	}
+	rate := 0.0
+	if done > 0 {
+		rate = float64(errs) * 100 / float64(done)
+	}
+	fmt.Fprintf(&b, tr(" · erros %d (%.1f%%) · %.1f/s"), errs, rate, float64(done)/max(el.Seconds(), 0.001))
+	if eta > 0 {
+		fmt.Fprintf(&b, " · ETA %s", eta.Round(time.Second))
+	}
+	fmt.Fprint(os.Stderr, b.String())
+}
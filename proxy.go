@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+/* ==================== proxy (captura de sessões reais) ==================== */
+
+// métricas agregadas por endpoint
+type endpointMetrics struct {
+	Count     int         `json:"count"`
+	Errors    int         `json:"errors"` // falha de transporte (sem resposta da API)
+	Status    map[int]int `json:"status"`
+	BytesIn   int64       `json:"bytesIn"`  // corpo das requisições
+	BytesOut  int64       `json:"bytesOut"` // corpo das respostas
+	latencies []time.Duration
+}
+
+type proxyMetrics struct {
+	mu      sync.Mutex
+	started time.Time
+	eps     map[string]*endpointMetrics
+}
+
+func (pm *proxyMetrics) observe(ep string, status int, d time.Duration, in, out int) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	m := pm.eps[ep]
+	if m == nil {
+		m = &endpointMetrics{Status: map[int]int{}}
+		pm.eps[ep] = m
+	}
+	m.Count++
+	if status == 0 {
+		m.Errors++
+	} else {
+		m.Status[status]++
+	}
+	m.BytesIn += int64(in)
+	m.BytesOut += int64(out)
+	m.latencies = append(m.latencies, d)
+}
+
+type endpointSnapshot struct {
+	endpointMetrics
+	P50MS int64 `json:"p50Ms"`
+	P95MS int64 `json:"p95Ms"`
+	MaxMS int64 `json:"maxMs"`
+}
+
+func (pm *proxyMetrics) snapshot() map[string]endpointSnapshot {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	out := make(map[string]endpointSnapshot, len(pm.eps))
+	for ep, m := range pm.eps {
+		s := endpointSnapshot{endpointMetrics: *m}
+		s.Status = make(map[int]int, len(m.Status))
+		for k, v := range m.Status {
+			s.Status[k] = v
+		}
+		s.P50MS = percentileDur(m.latencies, 50).Milliseconds()
+		s.P95MS = percentileDur(m.latencies, 95).Milliseconds()
+		s.MaxMS = percentileDur(m.latencies, 100).Milliseconds()
+		out[ep] = s
+	}
+	return out
+}
+
+type proxyOptions struct {
+	Listen   string
+	Target   string
+	Cassette string // "" = só métricas
+	Sanitize sanitizeOptions
+	Replay   string // serve respostas desse cassete em vez de repassar à API
+	Match    matchRules
+
+	Fallthrough bool // replay: sem gravação, repassa à API em vez de 501
+	RecordNew   bool // fallthrough: grava as interações novas (em Cassette, ou no próprio cassete do replay)
+
+	Chaos *proxyChaos // nil = repassa sem falhas
+}
+
+// captura a resposta do upstream para o cassete sem alterar o que o cliente recebe
+type capturingBody struct {
+	status  int
+	headers http.Header
+	body    []byte
+}
+
+func cmdProxy(opt proxyOptions) error {
+	metrics := &proxyMetrics{started: time.Now(), eps: map[string]*endpointMetrics{}}
+	mux := http.NewServeMux()
+	var cw *cassetteWriter
+	mux.HandleFunc("GET /__proxy/metrics", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]any{
+			"target":       opt.Target,
+			"uptimeSec":    int(time.Since(metrics.started).Seconds()),
+			"endpoints":    metrics.snapshot(),
+			"interactions": cassetteLen(cw),
+		}
+		if opt.Chaos != nil {
+			body["faults"] = opt.Chaos.counts()
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+	// falhas injetadas ficam na frente de tudo (repasse e replay); /__proxy/ fica de fora
+	root := func(h http.Handler) http.Handler {
+		if opt.Chaos == nil {
+			return h
+		}
+		return opt.Chaos.wrap(h, metrics)
+	}
+	if opt.Chaos != nil {
+		outf("[chaos] injetando falhas (seed=%d; mesma seed = mesmo sorteio para a mesma sequência de requisições)\n", opt.Chaos.seed)
+	}
+	printSummary := func(path string) {
+		printProxySummary(metrics, cw, path)
+		if opt.Chaos != nil {
+			printChaosSummary(opt.Chaos)
+		}
+	}
+
+	var target *url.URL
+	if opt.Replay == "" || opt.Fallthrough {
+		var err error
+		target, err = url.Parse(opt.Target)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return usageError(fmt.Sprintf(tr("--target inválido: %q"), opt.Target))
+		}
+	}
+	record := func(it interaction) {
+		if _, err := cw.add(it); err != nil {
+			outf("[proxy] falha ao gravar cassete: %v\n", err)
+		}
+	}
+
+	if opt.Replay != "" {
+		c, err := readCassette(opt.Replay)
+		if err != nil {
+			return err
+		}
+		player := newCassettePlayer(c, opt.Match)
+		var fallback http.Handler
+		if opt.Fallthrough {
+			var rec func(interaction)
+			if opt.RecordNew {
+				out := opt.Cassette
+				if out == "" {
+					out = opt.Replay
+				}
+				cw = newCassetteWriterFrom(out, c, opt.Sanitize)
+				// o que foi gravado agora já vale para as próximas requisições
+				rec = func(it interaction) {
+					saved, err := cw.add(it)
+					if err != nil {
+						outf("[proxy] falha ao gravar cassete: %v\n", err)
+					}
+					player.add(saved)
+				}
+			}
+			fallback = forwardHandler(target, metrics, rec)
+		}
+		mux.Handle("/", root(replayHandler(player, metrics, fallback)))
+		outf("[replay] ouvindo em http://%s, %d interações de %s (body=%s)\n",
+			opt.Listen, len(c.Interactions), opt.Replay, opt.Match.Body)
+		if fallback != nil {
+			outf("[replay] sem gravação → %s\n", opt.Target)
+		}
+		if cw != nil {
+			outf("[replay] interações novas vão para %s\n", cw.path)
+		}
+		err = serveUntilSignal(opt.Listen, mux)
+		outf("[replay] %d gravações não usadas\n", player.unused())
+		printSummary(cassettePath(cw))
+		return err
+	}
+
+	if opt.Cassette != "" {
+		cw = newCassetteWriter(opt.Cassette, opt.Target, opt.Sanitize)
+		mux.Handle("/", root(forwardHandler(target, metrics, record)))
+	} else {
+		mux.Handle("/", root(forwardHandler(target, metrics, nil)))
+	}
+
+	outf("[proxy] ouvindo em http://%s → %s\n", opt.Listen, opt.Target)
+	if cw != nil {
+		outf("[proxy] gravando cassete em %s (segredos mascarados=%v, imagens=%v)\n",
+			opt.Cassette, opt.Sanitize.Redact, opt.Sanitize.Images)
+	}
+	if err := serveUntilSignal(opt.Listen, mux); err != nil {
+		return err
+	}
+	printSummary(opt.Cassette)
+	return nil
+}
+
+// repassa à API real; record (se não nil) recebe cada interação completa
+func forwardHandler(target *url.URL, metrics *proxyMetrics, record func(interaction)) http.Handler {
+	type captureKey struct{}
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			// deixa o transporte negociar gzip e descomprimir: o cassete guarda o corpo legível
+			pr.Out.Header.Del("Accept-Encoding")
+		},
+		Transport: httpClient.Transport,
+		ModifyResponse: func(resp *http.Response) error {
+			body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+			resp.Body.Close()
+			if err != nil {
+				return err
+			}
+			if len(body) > maxResponseBytes {
+				return fmt.Errorf(tr("resposta maior que %d bytes"), maxResponseBytes)
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if c, ok := resp.Request.Context().Value(captureKey{}).(*capturingBody); ok {
+				c.status, c.headers, c.body = resp.StatusCode, resp.Header.Clone(), body
+			}
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			outf("[proxy] %s %s → erro: %v\n", r.Method, r.URL.Path, err)
+			writeJSON(w, http.StatusBadGateway, map[string]any{"success": false, "message": "proxy: " + err.Error()})
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "proxy: " + err.Error()})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(reqBody))
+		capt := &capturingBody{}
+		r = r.WithContext(context.WithValue(r.Context(), captureKey{}, capt))
+
+		start := time.Now()
+		rp.ServeHTTP(w, r)
+		elapsed := time.Since(start)
+
+		metrics.observe(endpointKey(r), capt.status, elapsed, len(reqBody), len(capt.body))
+		if capt.status == 0 {
+			return
+		}
+		outf("[proxy] %s %s → %d (%dms)\n", r.Method, r.URL.RequestURI(), capt.status, elapsed.Milliseconds())
+		if record == nil {
+			return
+		}
+		record(interaction{
+			RecordedAt: start.UTC(),
+			LatencyMS:  elapsed.Milliseconds(),
+			Request: cassetteRequest{
+				Method:  r.Method,
+				URL:     r.URL.RequestURI(),
+				Headers: r.Header.Clone(),
+				Body:    newCassetteBody(reqBody),
+			},
+			Response: cassetteResponse{Status: capt.status, Headers: capt.headers, Body: newCassetteBody(capt.body)},
+		})
+	})
+}
+
+// serve até Ctrl+C/SIGTERM e encerra sem derrubar requisições em andamento
+func serveUntilSignal(addr string, h http.Handler) error {
+	srv := &http.Server{Addr: addr, Handler: h}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// responde com a gravação que casar; sem match repassa a fallback ou, sem fallback, devolve 501 explicando o motivo
+func replayHandler(player *cassettePlayer, metrics *proxyMetrics, fallback http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "replay: " + err.Error()})
+			return
+		}
+		start := time.Now()
+		it, why := player.match(r.Method, r.URL.RequestURI(), r.Header, body)
+		if it == nil && fallback != nil {
+			outf("[replay] %s %s → sem gravação (%s), repassando\n", r.Method, r.URL.RequestURI(), why)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fallback.ServeHTTP(w, r)
+			return
+		}
+		if it == nil {
+			outf("[replay] %s %s → sem gravação (%s)\n", r.Method, r.URL.RequestURI(), why)
+			writeJSON(w, http.StatusNotImplemented, map[string]any{"success": false, "message": "replay: sem gravação (" + why + ")"})
+			metrics.observe(endpointKey(r), http.StatusNotImplemented, time.Since(start), len(body), 0)
+			return
+		}
+		writeRecorded(w, it)
+		outf("[replay] %s %s → %d\n", r.Method, r.URL.RequestURI(), it.Response.Status)
+		metrics.observe(endpointKey(r), it.Response.Status, time.Since(start), len(body), len(it.Response.Body.Bytes()))
+	})
+}
+
+func cassettePath(cw *cassetteWriter) string {
+	if cw == nil {
+		return ""
+	}
+	return cw.path
+}
+
+func cassetteLen(cw *cassetteWriter) int {
+	if cw == nil {
+		return 0
+	}
+	return cw.len()
+}
+
+// resumo ao encerrar (Ctrl+C)
+func printProxySummary(pm *proxyMetrics, cw *cassetteWriter, path string) {
+	snap := pm.snapshot()
+	eps := make([]string, 0, len(snap))
+	for ep := range snap {
+		eps = append(eps, ep)
+	}
+	sort.Strings(eps)
+	outln("[proxy] encerrado")
+	for _, ep := range eps {
+		s := snap[ep]
+		st, _ := json.Marshal(s.Status)
+		outf("[proxy] %-9s n=%d erros=%d status=%s p50=%dms p95=%dms max=%dms\n",
+			ep, s.Count, s.Errors, st, s.P50MS, s.P95MS, s.MaxMS)
+	}
+	if cw != nil {
+		outf("[proxy] %d interações em %s\n", cw.len(), path)
+	}
+	setResult("proxy", snap)
+}
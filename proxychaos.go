@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* ==================== proxy com falhas injetadas (resiliência) ==================== */
+
+// entre o runner (ou outro cliente) e o Biodoc: latência, conexão derrubada e erros HTTP
+// sorteados por endpoint, para ver retry/circuit breaker e consumidores degradarem direito.
+// Mesma sintaxe do mock: --latency [endpoint=]dist[@taxa], --fail [endpoint=]taxa[:status],
+// --fault [endpoint=]tipo[:taxa]; e --reset [endpoint=]taxa[:before|after]
+type proxyChaos struct {
+	*faultInjector
+	seed     uint64
+	latency  map[string]latencyDist
+	latRate  map[string]float64
+	resets   map[string]resetRule
+	mu       sync.Mutex
+	injected map[string]int // por tipo: latency, reset, fail, malformed-json...
+}
+
+// reset antes de repassar (a API nunca vê a requisição) ou depois (a API processou,
+// o cliente não sabe: o retry repete um efeito colateral)
+type resetRule struct {
+	rate  float64
+	after bool
+}
+
+// nil quando nenhuma falha foi pedida
+func newProxyChaos(latency, resets, fails, faults map[string]string, seed uint64) (*proxyChaos, error) {
+	if len(latency)+len(resets)+len(fails)+len(faults) == 0 {
+		return nil, nil
+	}
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	fi, err := newFaultInjector(faults, fails, seed)
+	if err != nil {
+		return nil, err
+	}
+	pc := &proxyChaos{
+		faultInjector: fi, seed: seed,
+		latency: map[string]latencyDist{}, latRate: map[string]float64{},
+		resets: map[string]resetRule{}, injected: map[string]int{},
+	}
+	for ep, spec := range latency {
+		spec, rateStr, hasRate := strings.Cut(spec, "@")
+		d, err := parseLatencyDist(spec)
+		if err != nil {
+			return nil, fmt.Errorf("--latency %s: %w", ep, err)
+		}
+		rate := 1.0
+		if hasRate {
+			rate, err = strconv.ParseFloat(rateStr, 64)
+			if err != nil || rate < 0 || rate > 1 {
+				return nil, fmt.Errorf(tr("--latency %s: taxa deve estar entre 0 e 1, veio %q"), ep, rateStr)
+			}
+		}
+		pc.latency[ep], pc.latRate[ep] = d, rate
+	}
+	for ep, spec := range resets {
+		rateStr, when, _ := strings.Cut(spec, ":")
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf(tr("--reset %s: taxa deve estar entre 0 e 1, veio %q"), ep, rateStr)
+		}
+		if when != "" && when != "before" && when != "after" {
+			return nil, fmt.Errorf(tr("--reset %s: use before ou after, veio %q"), ep, when)
+		}
+		pc.resets[ep] = resetRule{rate: rate, after: when == "after"}
+	}
+	return pc, nil
+}
+
+// regra do endpoint ou, sem ela, a de "*"
+func ruleFor[T any](m map[string]T, ep string) (T, bool) {
+	if v, ok := m[ep]; ok {
+		return v, true
+	}
+	v, ok := m["*"]
+	return v, ok
+}
+
+func (pc *proxyChaos) roll(rate float64) bool {
+	pc.faultInjector.mu.Lock()
+	defer pc.faultInjector.mu.Unlock()
+	return pc.rng.Float64() < rate
+}
+
+func (pc *proxyChaos) pickLatency(ep string) time.Duration {
+	d, ok := ruleFor(pc.latency, ep)
+	if !ok {
+		return 0
+	}
+	rate, _ := ruleFor(pc.latRate, ep)
+	if !pc.roll(rate) {
+		return 0
+	}
+	pc.faultInjector.mu.Lock()
+	defer pc.faultInjector.mu.Unlock()
+	return d.sample(pc.rng)
+}
+
+// 0 = sem reset; 1 = antes de repassar; 2 = depois da resposta da API
+func (pc *proxyChaos) pickReset(ep string) int {
+	rule, ok := ruleFor(pc.resets, ep)
+	if !ok || !pc.roll(rule.rate) {
+		return 0
+	}
+	if rule.after {
+		return 2
+	}
+	return 1
+}
+
+// conta por tipo; what é o que sai no log (ex.: +800ms, 503)
+func (pc *proxyChaos) count(kind, what string, r *http.Request) {
+	pc.mu.Lock()
+	pc.injected[kind]++
+	pc.mu.Unlock()
+	outf("[chaos] %s %s → %s\n", r.Method, r.URL.RequestURI(), what)
+}
+
+func (pc *proxyChaos) counts() map[string]int {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	out := make(map[string]int, len(pc.injected))
+	for k, v := range pc.injected {
+		out[k] = v
+	}
+	return out
+}
+
+// derruba a conexão do cliente com RST (SO_LINGER 0), sem resposta nenhuma
+func resetConn(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tc, ok := conn.(*net.TCPConn); ok {
+		_ = tc.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// resposta da API descartada: o reset "after" só precisa que ela tenha acontecido
+type discardResponse struct{ h http.Header }
+
+func (d *discardResponse) Header() http.Header {
+	if d.h == nil {
+		d.h = http.Header{}
+	}
+	return d.h
+}
+func (d *discardResponse) Write(p []byte) (int, error) { return len(p), nil }
+func (d *discardResponse) WriteHeader(int)             {}
+
+// middleware na frente do repasse (ou do replay): latência, depois reset/erro/falha de protocolo
+func (pc *proxyChaos) wrap(next http.Handler, metrics *proxyMetrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ep := endpointKey(r)
+		start := time.Now()
+		if d := pc.pickLatency(ep); d > 0 {
+			pc.count("latency", fmt.Sprintf("+%s", d.Round(time.Millisecond)), r)
+			select {
+			case <-time.After(d):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		in := int(max(r.ContentLength, 0))
+		switch pc.pickReset(ep) {
+		case 1:
+			pc.count("reset", "reset", r)
+			metrics.observe(ep, 0, time.Since(start), in, 0)
+			resetConn(w)
+			return
+		case 2:
+			next.ServeHTTP(&discardResponse{}, r)
+			pc.count("reset-after", "reset (depois da API)", r)
+			resetConn(w)
+			return
+		}
+		if kind := pc.pick(ep); kind != "" {
+			pc.count(kind, kind, r)
+			metrics.observe(ep, http.StatusOK, time.Since(start), in, 0)
+			writeFault(w, kind)
+			return
+		}
+		if status := pc.pickFail(ep); status != 0 {
+			pc.count("fail", strconv.Itoa(status), r)
+			metrics.observe(ep, status, time.Since(start), in, 0)
+			writeJSON(w, status, map[string]any{"success": false, "message": "proxy: falha injetada (--fail " + ep + ")"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func printChaosSummary(pc *proxyChaos) {
+	c := pc.counts()
+	setResult("faults", map[string]any{"seed": pc.seed, "injected": c})
+	if len(c) == 0 {
+		outf("[chaos] nenhuma falha injetada (seed=%d)\n", pc.seed)
+		return
+	}
+	kinds := make([]string, 0, len(c))
+	for k := range c {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, k := range kinds {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, c[k]))
+	}
+	outf("[chaos] falhas injetadas: %s (seed=%d)\n", strings.Join(parts, " "), pc.seed)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"os"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+/* ==================== Pré-checagem de qualidade da imagem ==================== */
+
+// limites abaixo dos quais a verificação costuma falhar por causa da foto, não da API
+const (
+	qualityMinSide      = 240  // menor lado em px
+	qualityMinSharpness = 50.0 // variância do laplaciano (na imagem reduzida a 512px)
+	qualityMinBright    = 40.0 // luminância média 0..255
+	qualityMaxBright    = 220.0
+)
+
+// --strict-quality: aborta antes da chamada em vez de só avisar; --no-quality-check desliga
+var (
+	strictQuality   bool
+	skipQualityScan bool
+)
+
+type imageQuality struct {
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Brightness float64 `json:"brightness"`
+	Sharpness  float64 `json:"sharpness"`
+}
+
+// mede em tons de cinza reduzidos a 512px no maior lado (nitidez comparável entre resoluções)
+func measureQuality(img image.Image) imageQuality {
+	b := img.Bounds()
+	q := imageQuality{Width: b.Dx(), Height: b.Dy()}
+	long := max(b.Dx(), b.Dy())
+	w, h := b.Dx(), b.Dy()
+	if long > 512 {
+		w, h = max(1, w*512/long), max(1, h*512/long)
+	}
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	draw.ApproxBiLinear.Scale(g, g.Bounds(), img, b, draw.Src, nil)
+
+	var sum float64
+	for _, p := range g.Pix {
+		sum += float64(p)
+	}
+	q.Brightness = sum / float64(len(g.Pix))
+
+	// laplaciano 4-vizinhos; variância baixa = poucas bordas = foto tremida/desfocada
+	if w < 3 || h < 3 {
+		return q
+	}
+	var s, s2 float64
+	n := 0
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			c := int(g.Pix[y*g.Stride+x])
+			l := int(g.Pix[y*g.Stride+x-1]) + int(g.Pix[y*g.Stride+x+1]) +
+				int(g.Pix[(y-1)*g.Stride+x]) + int(g.Pix[(y+1)*g.Stride+x]) - 4*c
+			s += float64(l)
+			s2 += float64(l * l)
+			n++
+		}
+	}
+	mean := s / float64(n)
+	q.Sharpness = s2/float64(n) - mean*mean
+	return q
+}
+
+func (q imageQuality) problems() []string {
+	var out []string
+	if min(q.Width, q.Height) < qualityMinSide {
+		out = append(out, fmt.Sprintf(tr("resolução baixa (%dx%d, mínimo %dpx no menor lado)"), q.Width, q.Height, qualityMinSide))
+	}
+	if q.Sharpness < qualityMinSharpness {
+		out = append(out, fmt.Sprintf(tr("desfocada (nitidez %.0f < %.0f)"), q.Sharpness, qualityMinSharpness))
+	}
+	if q.Brightness < qualityMinBright {
+		out = append(out, fmt.Sprintf(tr("escura (brilho %.0f < %.0f)"), q.Brightness, qualityMinBright))
+	}
+	if q.Brightness > qualityMaxBright {
+		out = append(out, fmt.Sprintf(tr("estourada (brilho %.0f > %.0f)"), q.Brightness, qualityMaxBright))
+	}
+	return out
+}
+
+// avisa (ou, com --strict-quality, falha) antes de gastar uma chamada com foto ruim
+func checkImageQuality(path string, img image.Image) error {
+	if skipQualityScan {
+		return nil
+	}
+	q := measureQuality(img)
+	if verbosity > 0 {
+		outf("[quality] %s: %dx%d brilho=%.0f nitidez=%.0f\n", path, q.Width, q.Height, q.Brightness, q.Sharpness)
+	}
+	probs := q.problems()
+	if len(probs) == 0 {
+		return nil
+	}
+	if strictQuality {
+		return fmt.Errorf(tr("qualidade de %s: %s (--strict-quality)"), path, strings.Join(probs, "; "))
+	}
+	outf("[quality] ⚠ %s: %s\n", path, strings.Join(probs, "; "))
+	return nil
+}
+
+// checagens locais antes do envio (qualidade, --require-single-face); decodifica direto do
+// arquivo, sem manter os bytes na memória. head = início do arquivo (para o EXIF)
+func preflightImage(path string, head []byte) error {
+	if skipQualityScan && !requireSingleFace {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(bufio.NewReader(f))
+	f.Close()
+	if err != nil {
+		return nil // formato que não decodificamos: a API decide
+	}
+	if err := checkImageQuality(path, img); err != nil {
+		return err
+	}
+	return checkSingleFace(path, img, head)
+}
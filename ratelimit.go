@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/* ==================== Limite de taxa no cliente (--rps / --rpm) ==================== */
+
+// espaçamento fixo entre requisições, compartilhado por todos os workers (batch, load, watch...);
+// cada tentativa conta, inclusive retry e a repetição após renovar o OAuth
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	waited   int
+	waitSum  time.Duration
+}
+
+var limiter *rateLimiter
+
+// reserva a próxima vaga e dorme até ela
+func (l *rateLimiter) wait() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	d := slot.Sub(now)
+	if d > 0 {
+		l.waited++
+		l.waitSum += d
+	}
+	l.mu.Unlock()
+	time.Sleep(d)
+}
+
+func (l *rateLimiter) summary() string {
+	if l == nil {
+		return ""
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.waited == 0 {
+		return ""
+	}
+	return fmt.Sprintf(tr("[rate] %d requisição(ões) seguradas pelo limite (espera total %s)"), l.waited, l.waitSum.Round(time.Millisecond))
+}
+
+// --rps N / --rpm N (ENV RATE_LIMIT_RPS, RATE_LIMIT_RPM); load-verify mantém o --rps próprio (taxa alvo)
+func stripRateLimitFlags(args []string, cmd string) ([]string, error) {
+	rps, rpm := os.Getenv("RATE_LIMIT_RPS"), os.Getenv("RATE_LIMIT_RPM")
+	var err error
+	var v string
+	var set bool
+	if cmd != "load-verify" {
+		if args, v, set, err = stripValueFlag(args, "--rps"); err != nil {
+			return nil, err
+		}
+		if set {
+			rps = v
+		}
+	}
+	if args, v, set, err = stripValueFlag(args, "--rpm"); err != nil {
+		return nil, err
+	}
+	if set {
+		rpm = v
+	}
+	if rps != "" && rpm != "" {
+		return nil, fmt.Errorf(tr("use --rps ou --rpm, não os dois"))
+	}
+	per, unit := rps, time.Second
+	if rpm != "" {
+		per, unit = rpm, time.Minute
+	}
+	if per == "" {
+		return args, nil
+	}
+	n, err := strconv.ParseFloat(per, 64)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf(tr("limite de taxa inválido: %q (número > 0)"), per)
+	}
+	limiter = &rateLimiter{interval: time.Duration(float64(unit) / n)}
+	return args, nil
+}
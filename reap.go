@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+/* ==================== TTL de dados de teste e reap ==================== */
+
+// TTL/tag gravados no detail dos cards criados (CARD_TTL/CARD_TAG ou --ttl/--tag globais)
+var cardTTL struct {
+	TTL time.Duration
+	Tag string
+}
+
+func loadTTLEnv() error {
+	if v := os.Getenv("CARD_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return fmt.Errorf(tr("CARD_TTL inválido: %q"), v)
+		}
+		cardTTL.TTL = d
+	}
+	if v := os.Getenv("CARD_TAG"); v != "" {
+		cardTTL.Tag = v
+	}
+	return nil
+}
+
+func setCardTTL(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf(tr("--ttl inválido: %q (ex.: 24h)"), v)
+	}
+	cardTTL.TTL = d
+	return nil
+}
+
+// detail no mesmo formato usado no verify: "{'runner':'biodoc-go-runner','tag':'qa','expiresAt':'...'}"
+// vazio se não houver TTL configurado
+func ttlDetail(now time.Time) string {
+	if cardTTL.TTL <= 0 {
+		return ""
+	}
+	tag := cardTTL.Tag
+	if tag == "" {
+		tag = "runner"
+	}
+	return fmt.Sprintf("{'runner':'biodoc-go-runner','tag':'%s','expiresAt':'%s'}",
+		tag, now.Add(cardTTL.TTL).UTC().Format(time.RFC3339))
+}
+
+var (
+	ttlExpiresRe = regexp.MustCompile(`["']expiresAt["']\s*:\s*["']([^"']+)["']`)
+	ttlTagRe     = regexp.MustCompile(`["']tag["']\s*:\s*["']([^"']*)["']`)
+)
+
+// lê tag e expiração do detail; ok=false se o card não tiver TTL do runner
+func parseTTLDetail(detail string) (tag string, expires time.Time, ok bool) {
+	m := ttlExpiresRe.FindStringSubmatch(detail)
+	if m == nil {
+		return "", time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, m[1])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	if tm := ttlTagRe.FindStringSubmatch(detail); tm != nil {
+		tag = tm[1]
+	}
+	return tag, t, true
+}
+
+type reapOptions struct {
+	List   listOptions
+	Tag    string // só cards com essa tag ("" = qualquer tag)
+	DryRun bool
+}
+
+// apaga os cards cujo TTL já venceu
+func cmdReap(baseURL, token string, opt reapOptions) error {
+	opt.List.All = true
+	cards, _, err := listCards(baseURL, token, opt.List)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var expired, failed []string
+	kept := 0
+	for _, c := range cards {
+		tag, exp, ok := parseTTLDetail(c.Detail)
+		if !ok || (opt.Tag != "" && tag != opt.Tag) {
+			continue
+		}
+		if exp.After(now) {
+			kept++
+			continue
+		}
+		expired = append(expired, c.ID)
+		if opt.DryRun {
+			outf("[reap] id=%s tag=%s venceu em %s (dry-run, não apagado)\n", c.ID, orDash(tag), exp.Format(time.RFC3339))
+			continue
+		}
+		resp, _, err := doRequest(http.MethodDelete, cardURL(baseURL, c.ID), authHeader(token), nil)
+		switch {
+		case err != nil:
+			outf("[reap] id=%s falhou: %v\n", c.ID, err)
+			failed = append(failed, c.ID)
+		case resp.StatusCode == http.StatusNotFound || (resp.StatusCode >= 200 && resp.StatusCode < 300):
+			outf("[reap] id=%s tag=%s venceu em %s, apagado\n", c.ID, orDash(tag), exp.Format(time.RFC3339))
+		default:
+			outf("[reap] id=%s falhou: status %d\n", c.ID, resp.StatusCode)
+			failed = append(failed, c.ID)
+		}
+	}
+	outf("[reap] %d cards analisados, %d vencidos, %d ainda no prazo\n", len(cards), len(expired), kept)
+	setResult("expired", expired)
+	setResult("failed", failed)
+	if len(failed) > 0 {
+		return fmt.Errorf(tr("falha ao apagar %d card(s): %s"), len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
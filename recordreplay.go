@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/* ==================== --record / --replay do runner ==================== */
+
+// grava cada requisição feita pelo runner no cassete (sanitizado, como no proxy)
+type recordingTransport struct {
+	base http.RoundTripper
+	cw   *cassetteWriter
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = b
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	it := interaction{
+		RecordedAt: start.UTC(),
+		LatencyMS:  time.Since(start).Milliseconds(),
+		Request: cassetteRequest{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: req.Header.Clone(),
+			Body:    newCassetteBody(reqBody),
+		},
+		Response: cassetteResponse{Status: resp.StatusCode, Headers: resp.Header.Clone(), Body: newCassetteBody(body)},
+	}
+	if _, err := t.cw.add(it); err != nil {
+		outf("[record] falha ao gravar cassete: %v\n", err)
+	}
+	return resp, nil
+}
+
+// responde só a partir do cassete, sem rede; sem gravação vira 501 (não entra no retry)
+type replayTransport struct {
+	player *cassettePlayer
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+	it, why := t.player.match(req.Method, req.URL.RequestURI(), req.Header, body)
+	if it == nil {
+		outf("[replay] %s %s → sem gravação (%s)\n", req.Method, req.URL.RequestURI(), why)
+		msg := []byte(`{"success":false,"message":"replay: sem gravação (` + why + `)"}`)
+		h := http.Header{"Content-Type": {"application/json"}}
+		return syntheticResponse(req, http.StatusNotImplemented, h, msg), nil
+	}
+	return syntheticResponse(req, it.Response.Status, it.Response.Headers.Clone(), it.Response.Body.Bytes()), nil
+}
+
+func syntheticResponse(req *http.Request, status int, h http.Header, body []byte) *http.Response {
+	if h == nil {
+		h = http.Header{}
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+var recorder *cassetteWriter
+
+// liga a gravação no httpClient; o arquivo é regravado a cada requisição
+func enableRecord(path, baseURL string) {
+	base := httpClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	recorder = newCassetteWriter(path, baseURL, sanitizeOptions{Redact: true})
+	httpClient.Transport = &recordingTransport{base: base, cw: recorder}
+}
+
+// troca a rede pelo cassete
+func enableReplay(path string) error {
+	c, err := readCassette(path)
+	if err != nil {
+		return err
+	}
+	httpClient.Transport = &replayTransport{player: newCassettePlayer(c, defaultMatchRules())}
+	outf("[replay] %d interações de %s (sem rede)\n", len(c.Interactions), path)
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+/* ==================== --rehearse (ensaio contra o mock) ==================== */
+
+// verdadeiro enquanto o fluxo roda contra o mock embutido (golden não é lido nem gravado)
+var rehearsing bool
+
+// roda fn contra um mock stateful local; só devolve nil se o fluxo inteiro passar.
+// Chamadas, etapas e métricas do ensaio não entram no relatório da execução real.
+func rehearse(label string, fn func(baseURL, token string) error) error {
+	m, err := newMockServer(mockOptions{Scoring: "phash", Threshold: 80})
+	if err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf(tr("ensaio: %w"), err)
+	}
+	srv := &http.Server{Handler: m.handler()}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+	url := "http://" + ln.Addr().String()
+	outf("[rehearse] ensaio de %s contra o mock em %s\n", label, url)
+
+	// isola o estado da execução real
+	callsMu.Lock()
+	savedCalls, savedSteps, savedResults := calls, steps, results
+	calls, steps, results = nil, nil, map[string]any{}
+	callsMu.Unlock()
+	savedRetries := retryCount.Load()
+	savedTransport := httpClient.Transport
+	httpClient.Transport = nil // sem cassete no ensaio
+	prom.mu.Lock()
+	savedProm := prom.enabled
+	prom.enabled = false
+	prom.mu.Unlock()
+	rehearsing = true
+
+	start := time.Now()
+	err = fn(url, "rehearsal")
+	d := time.Since(start)
+
+	rehearsing = false
+	prom.mu.Lock()
+	prom.enabled = savedProm
+	prom.mu.Unlock()
+	httpClient.Transport = savedTransport
+	retryCount.Store(savedRetries)
+	callsMu.Lock()
+	calls, steps, results = savedCalls, savedSteps, savedResults
+	callsMu.Unlock()
+
+	if err != nil {
+		setResult("rehearsal", map[string]any{"ok": false, "duration_ms": d.Milliseconds(), "error": err.Error()})
+		return fmt.Errorf(tr("ensaio contra o mock falhou, ambiente real não foi tocado: %w"), err)
+	}
+	setResult("rehearsal", map[string]any{"ok": true, "duration_ms": d.Milliseconds()})
+	outf("[rehearse] ✅ ensaio ok em %s; seguindo para %s\n", d.Round(time.Millisecond), label)
+	return nil
+}
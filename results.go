@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+/* ==================== Results store (JSONL) ==================== */
+
+// arquivo do store (--results ou RESULTS_STORE); vazio = não grava
+var resultsStore string
+
+// chamada resumida guardada no store (sem corpo de resposta)
+type storedCall struct {
+	At        time.Time `json:"at"`
+	Method    string    `json:"method"`
+	Endpoint  string    `json:"endpoint"`
+	URL       string    `json:"url"`
+	Status    int       `json:"status,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// uma execução do runner, uma linha no arquivo
+type runRecord struct {
+	ID         string         `json:"id"`
+	Command    string         `json:"command"`
+	Args       []string       `json:"args,omitempty"`
+	Profile    string         `json:"profile,omitempty"`
+	BaseURL    string         `json:"base_url"`
+	StartedAt  time.Time      `json:"started_at"`
+	DurationMS int64          `json:"duration_ms"`
+	OK         bool           `json:"ok"`
+	ExitCode   int            `json:"exit_code"`
+	Error      string         `json:"error,omitempty"`
+	Result     map[string]any `json:"result,omitempty"`
+	Steps      []stepRecord   `json:"steps,omitempty"`
+	Calls      []storedCall   `json:"calls,omitempty"`
+}
+
+func newRunID(t time.Time) string {
+	return fmt.Sprintf("%s-%04x", t.UTC().Format("20060102-150405"), rand.IntN(1<<16))
+}
+
+// id desta execução (gerado no início; vai no store e no carimbo --watermark)
+var runID = newRunID(time.Now())
+
+// monta o registro com o estado acumulado da execução (chamadas, etapas, resultados)
+func buildRunRecord(cmd string, args []string, baseURL string, started time.Time, elapsed time.Duration, err error, code int) runRecord {
+	callsMu.Lock()
+	defer callsMu.Unlock()
+	r := runRecord{
+		ID:         runID,
+		Command:    cmd,
+		Args:       args,
+		Profile:    activeProfile,
+		BaseURL:    baseURL,
+		StartedAt:  started.UTC(),
+		DurationMS: elapsed.Milliseconds(),
+		OK:         err == nil,
+		ExitCode:   code,
+		Steps:      steps,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if len(results) > 0 {
+		r.Result = results
+	}
+	for _, c := range calls {
+		r.Calls = append(r.Calls, storedCall{
+			At: c.At.UTC(), Method: c.Method, Endpoint: callEndpoint(c.Method, c.URL), URL: c.URL,
+			Status: c.Status, LatencyMS: c.LatencyMS, Attempts: c.Attempts, Error: c.Error,
+		})
+	}
+	return r
+}
+
+// acrescenta uma linha; O_APPEND mantém as execuções paralelas sem se atropelar
+func appendRun(path string, r runRecord) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// lê o store inteiro; linhas corrompidas são avisadas e puladas
+func readRuns(path string) ([]runRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []runRecord
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 1<<20), 64<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var r runRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			outf("[results] %s:%d ignorada: %v\n", path, n, err)
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, sc.Err()
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* ==================== Retry ==================== */
+
+// política de retentativa aplicada a todas as requisições (doRequest)
+type retryConfig struct {
+	MaxAttempts int           // total de tentativas (1 = sem retry)
+	BaseDelay   time.Duration // espera antes da 2ª tentativa; dobra a cada nova
+	MaxDelay    time.Duration // teto da espera
+	Jitter      float64       // fração aleatória aplicada à espera (0.2 = ±20%)
+	Statuses    map[int]bool  // status HTTP que disparam retry
+	POST        bool          // repete POST também (register pode duplicar se o 1º chegou)
+}
+
+var retryCfg = retryConfig{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+	Jitter:      0.2,
+	Statuses:    map[int]bool{502: true, 503: true, 504: true},
+}
+
+// quantas retentativas foram feitas na execução (mostrado no fim do comando)
+var retryCount atomic.Int64
+
+// lê RETRY_* do ambiente por cima dos defaults
+func loadRetryEnv() error {
+	if v := os.Getenv("RETRY_MAX"); v != "" {
+		if err := setRetryMax(v); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("RETRY_BASE_DELAY"); v != "" {
+		if err := setRetryDelay(v); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("RETRY_JITTER"); v != "" {
+		if err := setRetryJitter(v); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("RETRY_STATUS"); v != "" {
+		if err := setRetryStatuses(v); err != nil {
+			return err
+		}
+	}
+	if v := os.Getenv("RETRY_POST"); v != "" {
+		retryCfg.POST = v == "1" || strings.EqualFold(v, "true")
+	}
+	return nil
+}
+
+func setRetryMax(v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return fmt.Errorf(tr("retries inválido: %q (mínimo 1)"), v)
+	}
+	retryCfg.MaxAttempts = n
+	return nil
+}
+
+func setRetryDelay(v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return fmt.Errorf(tr("retry-delay inválido: %q (ex.: 500ms, 2s)"), v)
+	}
+	retryCfg.BaseDelay = d
+	return nil
+}
+
+func setRetryJitter(v string) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || f > 1 {
+		return fmt.Errorf(tr("retry-jitter inválido: %q (entre 0 e 1)"), v)
+	}
+	retryCfg.Jitter = f
+	return nil
+}
+
+// "502,503,504" → set de status
+func setRetryStatuses(v string) error {
+	m := map[int]bool{}
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 100 || n > 599 {
+			return fmt.Errorf(tr("retry-on inválido: %q"), s)
+		}
+		m[n] = true
+	}
+	retryCfg.Statuses = m
+	return nil
+}
+
+// espera antes da tentativa attempt+1 (attempt começa em 1)
+func backoffDelay(attempt int) time.Duration {
+	d := retryCfg.BaseDelay << (attempt - 1)
+	// d <= 0 com base > 0 é estouro do shift; --retry-delay 0 continua sem espera
+	if d > retryCfg.MaxDelay || (retryCfg.BaseDelay > 0 && d <= 0) {
+		d = retryCfg.MaxDelay
+	}
+	if retryCfg.Jitter > 0 {
+		f := 1 + retryCfg.Jitter*(2*rand.Float64()-1)
+		d = time.Duration(float64(d) * f)
+	}
+	return d
+}
+
+// resposta (ou erro de rede) merece nova tentativa? Erro local (montar/assinar a requisição,
+// corpo grande demais) não muda na repetição; POST só com --retry-post, porque um timeout
+// não diz se o cadastro chegou
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	if method == http.MethodPost && !retryCfg.POST {
+		return false
+	}
+	if err != nil {
+		var ne net.Error
+		return errors.As(err, &ne) || errors.Is(err, io.ErrUnexpectedEOF)
+	}
+	return retryCfg.Statuses[resp.StatusCode]
+}
+
+/* ==================== 429 / Retry-After ==================== */
+
+// 429 tem orçamento próprio: não gasta as tentativas do retry normal (RETRY_MAX pode ser 1)
+var throttleCfg = struct {
+	MaxRetries int           // 429 seguidos tolerados por requisição (ENV RETRY_429_MAX)
+	MaxWait    time.Duration // Retry-After acima disso desiste (ENV RETRY_429_MAX_WAIT)
+}{MaxRetries: 5, MaxWait: 2 * time.Minute}
+
+var (
+	throttleMu    sync.Mutex
+	throttleUntil time.Time // pausa valendo para todos os workers
+	throttleCount int
+	throttleWait  time.Duration
+)
+
+func loadThrottleEnv() error {
+	if v := os.Getenv("RETRY_429_MAX"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf(tr("RETRY_429_MAX inválido: %q"), v)
+		}
+		throttleCfg.MaxRetries = n
+	}
+	if v := os.Getenv("RETRY_429_MAX_WAIT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf(tr("RETRY_429_MAX_WAIT inválido: %q (ex.: 2m)"), v)
+		}
+		throttleCfg.MaxWait = d
+	}
+	return nil
+}
+
+// Retry-After em segundos ou data HTTP; sem ele, backoff exponencial a partir de 1s
+func retryAfterDelay(h string, n int) time.Duration {
+	h = strings.TrimSpace(h)
+	if s, err := strconv.Atoi(h); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil {
+		return max(time.Until(t), 0)
+	}
+	d := time.Second << (n - 1)
+	if d > throttleCfg.MaxWait || d <= 0 {
+		d = throttleCfg.MaxWait
+	}
+	return d
+}
+
+// pausa todos os workers até passar o Retry-After (vale o mais distante)
+func pauseForThrottle(d time.Duration) {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+	if until := time.Now().Add(d); until.After(throttleUntil) {
+		throttleUntil = until
+	}
+	throttleCount++
+	throttleWait += d
+}
+
+// chamado antes de cada envio (sendOnce)
+func waitThrottle() {
+	throttleMu.Lock()
+	d := time.Until(throttleUntil)
+	throttleMu.Unlock()
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+func throttleSummary() string {
+	throttleMu.Lock()
+	defer throttleMu.Unlock()
+	if throttleCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf(tr("[429] %d resposta(s) 429, espera total %s"), throttleCount, throttleWait.Round(time.Millisecond))
+}
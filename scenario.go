@@ -0,0 +1,541 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+/* ==================== run-scenario (fluxos em YAML) ==================== */
+
+// arquivo de cenário: variáveis + etapas executadas em ordem
+type scenario struct {
+	Name      string            `yaml:"name"`
+	Vars      map[string]string `yaml:"vars"`
+	Normalize normRules         `yaml:"normalize"` // aplicadas antes da comparação com o golden
+	Steps     []scenarioStep    `yaml:"steps"`
+
+	dir          string // diretório do arquivo (golden relativo a ele)
+	updateGolden bool
+	clients      map[string]scenarioClient // perfil → destino (profile: nas etapas)
+	token        string                    // JWT da última etapa token (no lugar do da linha de comando)
+}
+
+type scenarioStep struct {
+	Name string `yaml:"name"` // default: type
+	// create, verify, get, update, delete, list, main-image, preclean, request,
+	// clock ou token (só contra o mock-server; ver timetravel.go)
+	Type string `yaml:"type"`
+
+	ID     string `yaml:"id"`     // default: ${id}
+	Image  string `yaml:"image"`  // caminho; default: ${image}
+	Detail string `yaml:"detail"` // verify
+
+	Method  string            `yaml:"method"` // request/update; sobrescreve o padrão do tipo
+	Path    string            `yaml:"path"`   // sobrescreve a rota padrão do tipo ({id} é substituído)
+	Headers map[string]string `yaml:"headers"`
+	Payload map[string]any    `yaml:"payload"` // sobrescreve campos do corpo; valor nulo remove o campo
+
+	Advance   string `yaml:"advance"`   // clock: quanto avançar o relógio do mock (2h)
+	Set       string `yaml:"set"`       // clock: instante absoluto (RFC3339)
+	ExpiresIn string `yaml:"expiresIn"` // token: exp relativo ao relógio do mock (-5m = vencido)
+
+	// perfil do ~/.biodoc-runner.yaml para esta etapa (base_url e token próprios);
+	// ex.: cadastra no ambiente A e verifica no B para testar a replicação
+	Profile string `yaml:"profile"`
+
+	Expect          scenarioExpect `yaml:"expect"`
+	ContinueOnError bool           `yaml:"continueOnError"`
+
+	// condições: when = on_success (padrão: só se nada falhou antes), always (limpeza) ou
+	// on_failure; retries = novas tentativas da etapa, com retryDelay entre elas
+	When       string        `yaml:"when"`
+	Retries    int           `yaml:"retries"`
+	RetryDelay time.Duration `yaml:"retryDelay"`
+}
+
+var stepWhens = []string{"on_success", "always", "on_failure"}
+
+// destino de uma etapa: o ambiente da linha de comando ou o de um perfil
+type scenarioClient struct {
+	Profile string
+	BaseURL string
+	Token   string
+}
+
+type scenarioExpect struct {
+	Status        statusList     `yaml:"status"` // default: qualquer 2xx
+	JSON          map[string]any `yaml:"json"`   // caminho com pontos (response.success) → valor esperado
+	MinSimilarity *float64       `yaml:"minSimilarity"`
+	MaxSimilarity *float64       `yaml:"maxSimilarity"`
+	Golden        string         `yaml:"golden"`    // arquivo com a resposta esperada (normalizada)
+	Refreshed     *bool          `yaml:"refreshed"` // o token OAuth foi renovado (401 → refresh) nesta etapa
+}
+
+// aceita "status: 200" ou "status: [200, 404]"
+type statusList []int
+
+func (s *statusList) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		var v int
+		if err := n.Decode(&v); err != nil {
+			return err
+		}
+		*s = statusList{v}
+		return nil
+	}
+	var vs []int
+	if err := n.Decode(&vs); err != nil {
+		return err
+	}
+	*s = vs
+	return nil
+}
+
+func loadScenario(path string, overrides map[string]string) (*scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var sc scenario
+	if err := yaml.Unmarshal(b, &sc); err != nil {
+		return nil, fmt.Errorf(tr("cenário %s: %w"), path, err)
+	}
+	if err := sc.prepare(tr("cenário ")+path, filepath.Dir(path), overrides); err != nil {
+		return nil, err
+	}
+	return &sc, nil
+}
+
+// valida as etapas e aplica os --var; src identifica o cenário nas mensagens
+func (sc *scenario) prepare(src, dir string, overrides map[string]string) error {
+	if len(sc.Steps) == 0 {
+		return fmt.Errorf(tr("%s sem etapas"), src)
+	}
+	if sc.Vars == nil {
+		sc.Vars = map[string]string{}
+	}
+	sc.dir = dir
+	sc.clients = map[string]scenarioClient{}
+	for k, v := range overrides {
+		sc.Vars[k] = v
+	}
+	for i := range sc.Steps {
+		st := &sc.Steps[i]
+		st.Type = strings.ToLower(st.Type)
+		if _, ok := stepRoutes[st.Type]; !ok && !localStepTypes[st.Type] {
+			return fmt.Errorf(tr("%s, etapa %d: tipo desconhecido %q"), src, i+1, st.Type)
+		}
+		if st.Type == "clock" && (st.Advance == "") == (st.Set == "") {
+			return fmt.Errorf(tr("%s, etapa %d: clock exige advance ou set (um dos dois)"), src, i+1)
+		}
+		if st.Type == "token" && st.ExpiresIn == "" {
+			return fmt.Errorf(tr("%s, etapa %d: token exige expiresIn (ex.: -5m)"), src, i+1)
+		}
+		if st.Type == "request" && st.Path == "" {
+			return fmt.Errorf(tr("%s, etapa %d: request exige path"), src, i+1)
+		}
+		if st.When == "" {
+			st.When = "on_success"
+		}
+		if !slices.Contains(stepWhens, st.When) {
+			return fmt.Errorf(tr("%s, etapa %d: when deve ser on_success, always ou on_failure, veio %q"), src, i+1, st.When)
+		}
+		if st.Retries < 0 {
+			return fmt.Errorf(tr("%s, etapa %d: retries negativo"), src, i+1)
+		}
+		if st.Name == "" {
+			st.Name = st.Type
+		}
+	}
+	return nil
+}
+
+var varRe = regexp.MustCompile(`\$\{([A-Za-z0-9_.]+)\}`)
+
+// ${nome}: vars do cenário/--var, depois variáveis de ambiente; sem valor fica como está
+func (sc *scenario) expand(s string) string {
+	return varRe.ReplaceAllStringFunc(s, func(m string) string {
+		k := m[2 : len(m)-1]
+		if v, ok := sc.Vars[k]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(k); ok {
+			return v
+		}
+		return m
+	})
+}
+
+func (sc *scenario) expandAny(v any) any {
+	switch x := v.(type) {
+	case string:
+		return sc.expand(x)
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, vv := range x {
+			out[k] = sc.expandAny(vv)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, vv := range x {
+			out[i] = sc.expandAny(vv)
+		}
+		return out
+	}
+	return v
+}
+
+// valor da etapa (expandido); se vazio, a variável key; se também não existir, def
+func (sc *scenario) field(v, key, def string) string {
+	if v != "" {
+		return sc.expand(v)
+	}
+	ref := "${" + key + "}"
+	if got := sc.expand(ref); got != ref {
+		return got
+	}
+	return def
+}
+
+// método e rota padrão de cada tipo de etapa
+var stepRoutes = map[string]struct{ method, path string }{
+	"create":     {http.MethodPost, "/api/card/integration/register"},
+	"verify":     {http.MethodPost, "/api/card/integration/verify"},
+	"get":        {http.MethodGet, "/api/card/{id}"},
+	"update":     {http.MethodPatch, "/api/card/{id}"},
+	"delete":     {http.MethodDelete, "/api/card/{id}"},
+	"list":       {http.MethodGet, "/api/card"},
+	"main-image": {http.MethodGet, "/api/card/integration/mainimage"},
+	"request":    {http.MethodGet, ""},
+}
+
+// monta a requisição da etapa: corpo padrão do tipo + overrides de payload
+func (sc *scenario) buildRequest(st scenarioStep, token string) (method, path string, h http.Header, body map[string]any, err error) {
+	id := sc.field(st.ID, "id", defaultID())
+	image := sc.field(st.Image, "image", "")
+	name := sc.field("", "name", "Celso QA")
+
+	route := stepRoutes[st.Type]
+	method, path = route.method, route.path
+	if st.Method != "" {
+		method = strings.ToUpper(sc.expand(st.Method))
+	}
+	if st.Path != "" {
+		path = sc.expand(st.Path)
+	}
+	path = strings.ReplaceAll(path, "{id}", url.PathEscape(id))
+
+	h = authHeader(token)
+	switch st.Type {
+	case "create":
+		img64, err := readImageAsBase64(image)
+		if err != nil {
+			return "", "", nil, nil, fmt.Errorf(tr("ler imagem: %w"), err)
+		}
+		body = map[string]any{"id": id, "name": name, "consentTermSigned": true, "image": img64}
+		if d := ttlDetail(time.Now()); d != "" {
+			body["detail"] = d
+		}
+	case "verify":
+		dataURI, err := buildDataURIImage(image)
+		if err != nil {
+			return "", "", nil, nil, fmt.Errorf(tr("ler/encode imagem: %w"), err)
+		}
+		body = map[string]any{"id": id, "name": name, "detail": sc.expand(st.Detail), "image": dataURI}
+	case "update":
+		body = map[string]any{"id": id}
+	case "main-image":
+		h.Set("idCard", id)
+	}
+	if len(st.Payload) > 0 && body == nil {
+		body = map[string]any{}
+	}
+	for k, v := range st.Payload {
+		if v == nil {
+			delete(body, k)
+			continue
+		}
+		body[k] = sc.expandAny(v)
+	}
+	for k, v := range st.Headers {
+		h.Set(k, sc.expand(v))
+	}
+	return method, path, h, body, nil
+}
+
+// valor em caminho com pontos (a.b.0.c) dentro do JSON decodificado
+func jsonPath(v any, path string) (any, bool) {
+	for _, p := range strings.Split(path, ".") {
+		switch x := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = x[p]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, false
+			}
+			v = x[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// confere status, campos do JSON e similaridade; devolve a primeira divergência
+func checkExpect(ex scenarioExpect, status int, body []byte) error {
+	if len(ex.Status) == 0 {
+		if status < 200 || status >= 300 {
+			return fmt.Errorf(tr("status %d, esperado 2xx"), status)
+		}
+	} else {
+		ok := false
+		for _, s := range ex.Status {
+			ok = ok || s == status
+		}
+		if !ok {
+			return fmt.Errorf(tr("status %d, esperado %v"), status, []int(ex.Status))
+		}
+	}
+	if len(ex.JSON) == 0 && ex.MinSimilarity == nil && ex.MaxSimilarity == nil {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf(tr("resposta não é JSON: %w"), err)
+	}
+	for path, want := range ex.JSON {
+		got, ok := jsonPath(doc, path)
+		if !ok {
+			return fmt.Errorf(tr("campo %s ausente"), path)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf(tr("campo %s = %v, esperado %v"), path, got, want)
+		}
+	}
+	if ex.MinSimilarity != nil || ex.MaxSimilarity != nil {
+		var vr VerifyResponse
+		_ = json.Unmarshal(body, &vr)
+		sim, ok := parsePercent(vr.Similarity())
+		if !ok {
+			return fmt.Errorf(tr("resposta sem similaridade"))
+		}
+		if ex.MinSimilarity != nil && sim < *ex.MinSimilarity {
+			return fmt.Errorf(tr("similaridade %.2f abaixo do mínimo %.2f"), sim, *ex.MinSimilarity)
+		}
+		if ex.MaxSimilarity != nil && sim > *ex.MaxSimilarity {
+			return fmt.Errorf(tr("similaridade %.2f acima do máximo %.2f"), sim, *ex.MaxSimilarity)
+		}
+	}
+	return nil
+}
+
+// cliente da etapa; cada perfil é resolvido uma vez e reaproveitado nas etapas seguintes
+func (sc *scenario) client(st scenarioStep, def scenarioClient) (scenarioClient, error) {
+	name := sc.expand(st.Profile)
+	// no ensaio tudo vai para o mock embutido
+	if name == "" || rehearsing {
+		return def, nil
+	}
+	if c, ok := sc.clients[name]; ok {
+		return c, nil
+	}
+	baseURL, token, err := loadProfileTarget(name)
+	if err != nil {
+		return scenarioClient{}, err
+	}
+	c := scenarioClient{Profile: name, BaseURL: baseURL, Token: token}
+	sc.clients[name] = c
+	return c, nil
+}
+
+func (sc *scenario) runStep(st scenarioStep, baseURL, token string) error {
+	switch st.Type {
+	case "preclean":
+		return cmdPreclean(baseURL, token, sc.field(st.ID, "id", defaultID()))
+	case "clock":
+		return sc.runClockStep(st, baseURL)
+	case "token":
+		return sc.runTokenStep(st, baseURL)
+	}
+	method, path, h, body, err := sc.buildRequest(st, token)
+	if err != nil {
+		return err
+	}
+	url := strings.TrimRight(baseURL, "/") + path
+	var resp *http.Response
+	var raw []byte
+	if body != nil {
+		resp, raw, err = doJSON(method, url, h, body)
+	} else {
+		resp, raw, err = doRequest(method, url, h, nil)
+	}
+	if err != nil {
+		return err
+	}
+	outf("[%s] %s %s → status=%d\n", st.Name, method, path, resp.StatusCode)
+	if !quiet && st.Type != "main-image" {
+		outln(string(raw))
+	}
+	if err := checkExpect(st.Expect, resp.StatusCode, raw); err != nil {
+		return err
+	}
+	if st.Expect.Golden != "" {
+		return sc.checkGolden(sc.expand(st.Expect.Golden), raw)
+	}
+	return nil
+}
+
+// compara a resposta normalizada com o golden (ou regrava com --update-golden)
+func (sc *scenario) checkGolden(path string, raw []byte) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(sc.dir, path)
+	}
+	var got any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		return fmt.Errorf(tr("golden: resposta não é JSON: %w"), err)
+	}
+	got = sc.Normalize.apply(got)
+	if rehearsing {
+		return nil
+	}
+	if sc.updateGolden {
+		if err := os.WriteFile(path, indentJSON(got, "  "), 0644); err != nil {
+			return err
+		}
+		outf("[golden] %s atualizado\n", path)
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf(tr("golden: %w (rode com --update-golden para criar)"), err)
+	}
+	var want any
+	if err := json.Unmarshal(b, &want); err != nil {
+		return fmt.Errorf(tr("golden %s inválido: %w"), path, err)
+	}
+	want = sc.Normalize.apply(want)
+	if diffs := jsonDiff(want, got, 10); len(diffs) > 0 {
+		for _, d := range diffs {
+			outf("[golden] %s\n", d)
+		}
+		return fmt.Errorf(tr("resposta difere do golden %s (%d diferenças)"), path, len(diffs))
+	}
+	return nil
+}
+
+func cmdRunScenario(baseURL, token, path string, vars map[string]string, nr normRules, updateGolden bool) error {
+	sc, err := loadScenario(path, vars)
+	if err != nil {
+		return err
+	}
+	sc.Normalize = nr.merge(sc.Normalize)
+	sc.updateGolden = updateGolden
+	title := sc.Name
+	if title == "" {
+		title = path
+	}
+	if err := runScenario(baseURL, token, sc, "scenario", title); err != nil {
+		return fmt.Errorf(tr("cenário falhou em: %s"), err)
+	}
+	outf("✅ cenário completo: %s\n", title)
+	return nil
+}
+
+// executa as etapas em ordem respeitando when/retries; o erro lista as etapas que falharam.
+// tag é o prefixo das linhas ([scenario], [pipeline])
+func runScenario(baseURL, token string, sc *scenario, tag, title string) error {
+	outf("[%s] %s (%d etapas)\n", tag, title, len(sc.Steps))
+
+	var failed []string
+	images := map[string]imageMeta{} // etapa de verify → imagem usada
+	broken := false                  // alguma etapa falhou sem continueOnError
+	for _, st := range sc.Steps {
+		run := true
+		switch st.When {
+		case "on_success":
+			run = !broken
+		case "on_failure":
+			run = broken
+		}
+		if !run {
+			skipStep(st.Name)
+			continue
+		}
+		if st.Type == "verify" {
+			if meta, err := readImageMeta(sc.field(st.Image, "image", "")); err == nil {
+				images[st.Name] = meta
+			}
+		}
+		err := runStep(st.Name, func() error {
+			def := scenarioClient{BaseURL: baseURL, Token: token}
+			if sc.token != "" {
+				def.Token = sc.token
+			}
+			c, err := sc.client(st, def)
+			if err != nil {
+				return err
+			}
+			if c.Profile != "" {
+				outf("[%s] perfil %s → %s\n", st.Name, c.Profile, c.BaseURL)
+			}
+			for attempt := 1; ; attempt++ {
+				renewals := tokenRenewals()
+				err = sc.runStep(st, c.BaseURL, c.Token)
+				if err == nil {
+					err = checkRefreshed(st.Expect.Refreshed, tokenRenewals()-renewals)
+				}
+				if err == nil || attempt > st.Retries {
+					return err
+				}
+				outf("[%s] tentativa %d/%d falhou: %v; de novo em %s\n", st.Name, attempt, st.Retries+1, err, st.RetryDelay)
+				time.Sleep(st.RetryDelay)
+			}
+		})
+		if err == nil {
+			outf("[%s] ✅ %s\n", tag, st.Name)
+			continue
+		}
+		outf("[%s] ❌ %s: %v\n", tag, st.Name, err)
+		failed = append(failed, st.Name)
+		broken = broken || !st.ContinueOnError
+	}
+	setResult("failed_steps", failed)
+	if len(images) > 0 {
+		setResult("images", images)
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// --var k=v (repetível)
+type varsFlag map[string]string
+
+func (f varsFlag) String() string { return fmt.Sprint(map[string]string(f)) }
+
+func (f varsFlag) Set(v string) error {
+	k, val, ok := strings.Cut(v, "=")
+	if !ok || k == "" {
+		return fmt.Errorf(tr("use --var nome=valor"))
+	}
+	f[k] = val
+	return nil
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/* ==================== --validate-schema (contrato das respostas) ==================== */
+
+// schemas do contrato atual, por endpoint (chave de endpointKey); só respostas 2xx
+//
+//go:embed schemas/*.json
+var bundledSchemas embed.FS
+
+var (
+	validateSchemas bool
+	schemaSpec      *openAPISpec // --schema-spec: usa a spec no lugar dos embutidos
+
+	bundledOnce sync.Once
+	bundled     map[string]map[string]any
+	bundledErr  error
+)
+
+// falha de contrato: a API respondeu, mas o corpo não bate com o schema
+type schemaError struct {
+	Endpoint string
+	Source   string
+	Problems []string
+}
+
+func (e *schemaError) Error() string {
+	return fmt.Sprintf(tr("resposta de %s fora do schema (%s): %s"), e.Endpoint, e.Source, strings.Join(e.Problems, "; "))
+}
+
+func isSchemaError(err error) bool {
+	var se *schemaError
+	return errors.As(err, &se)
+}
+
+func loadBundledSchemas() (map[string]map[string]any, error) {
+	bundledOnce.Do(func() {
+		bundled = map[string]map[string]any{}
+		entries, err := bundledSchemas.ReadDir("schemas")
+		if err != nil {
+			bundledErr = err
+			return
+		}
+		for _, e := range entries {
+			b, err := bundledSchemas.ReadFile("schemas/" + e.Name())
+			if err != nil {
+				bundledErr = err
+				return
+			}
+			var m map[string]any
+			if err := json.Unmarshal(b, &m); err != nil {
+				bundledErr = fmt.Errorf("schemas/%s: %w", e.Name(), err)
+				return
+			}
+			bundled[strings.TrimSuffix(e.Name(), ".json")] = m
+		}
+	})
+	return bundled, bundledErr
+}
+
+// chamado no fim do doRequestBody: nil quando desligado, sem schema para o endpoint ou corpo não-JSON
+func validateResponse(resp *http.Response, body []byte) error {
+	if !validateSchemas || dryRun || resp == nil || resp.Request == nil || len(body) == 0 {
+		return nil
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != "" && !strings.Contains(mt, "json") {
+		return nil
+	}
+	ep := endpointKey(resp.Request)
+	var sch, root map[string]any
+	source := ""
+	if schemaSpec != nil {
+		op, tmpl, _, ok := schemaSpec.findOperation(resp.Request.Method, resp.Request.URL.Path)
+		if !ok {
+			return nil
+		}
+		if sch, ok = schemaSpec.responseSchema(op, resp.StatusCode); !ok {
+			return nil
+		}
+		root, ep, source = schemaSpec.root, resp.Request.Method+" "+tmpl, schemaSpec.path
+	} else {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil
+		}
+		all, err := loadBundledSchemas()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if sch, ok = all[ep]; !ok {
+			return nil
+		}
+		root, source = sch, "schemas/"+ep+".json"
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return &schemaError{Endpoint: ep, Source: source, Problems: []string{tr("JSON inválido: ") + err.Error()}}
+	}
+	var probs []string
+	checkSchema(root, sch, v, "$", &probs)
+	if len(probs) == 0 {
+		return nil
+	}
+	sort.Strings(probs)
+	if len(probs) > 10 {
+		probs = append(probs[:10], fmt.Sprintf(tr("… e mais %d"), len(probs)-10))
+	}
+	return &schemaError{Endpoint: ep, Source: source, Problems: probs}
+}
+
+// subconjunto de JSON Schema que cobre contratos de API: $ref, allOf/anyOf/oneOf, type (+nullable),
+// enum, required, properties, additionalProperties, items, min/max, minLength/maxLength, pattern
+func checkSchema(root, sch map[string]any, v any, at string, probs *[]string) {
+	if ref, ok := sch["$ref"].(string); ok {
+		target, err := resolveRef(root, ref)
+		if err != nil {
+			*probs = append(*probs, at+": "+err.Error())
+			return
+		}
+		checkSchema(root, target, v, at, probs)
+		return
+	}
+	if v == nil && sch["nullable"] == true {
+		return
+	}
+	for _, sub := range schemaList(sch["allOf"]) {
+		checkSchema(root, sub, v, at, probs)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		subs := schemaList(sch[key])
+		if len(subs) == 0 {
+			continue
+		}
+		matched := false
+		for _, sub := range subs {
+			var p []string
+			if checkSchema(root, sub, v, at, &p); len(p) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: não bate com nenhuma opção de %s"), at, key))
+		}
+	}
+	if t, ok := sch["type"]; ok && !typeMatches(t, v) {
+		*probs = append(*probs, fmt.Sprintf(tr("%s: esperado %v, veio %s"), at, t, jsonType(v)))
+		return
+	}
+	if enum, ok := sch["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: %v fora do enum %v"), at, v, enum))
+		}
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		for _, r := range anyList(sch["required"]) {
+			if _, ok := x[fmt.Sprint(r)]; !ok {
+				*probs = append(*probs, fmt.Sprintf(tr("%s: campo obrigatório %q ausente"), at, r))
+			}
+		}
+		props, _ := sch["properties"].(map[string]any)
+		for k, val := range x {
+			if ps, ok := props[k].(map[string]any); ok {
+				checkSchema(root, ps, val, at+"."+k, probs)
+				continue
+			}
+			switch ap := sch["additionalProperties"].(type) {
+			case bool:
+				if !ap {
+					*probs = append(*probs, fmt.Sprintf(tr("%s: campo %q não previsto"), at, k))
+				}
+			case map[string]any:
+				checkSchema(root, ap, val, at+"."+k, probs)
+			}
+		}
+	case []any:
+		if items, ok := sch["items"].(map[string]any); ok {
+			for i, it := range x {
+				checkSchema(root, items, it, fmt.Sprintf("%s[%d]", at, i), probs)
+			}
+		}
+	case float64:
+		if m, ok := schemaNumber(sch["minimum"]); ok && x < m {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: %v < mínimo %v"), at, x, m))
+		}
+		if m, ok := schemaNumber(sch["maximum"]); ok && x > m {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: %v > máximo %v"), at, x, m))
+		}
+	case string:
+		n := len([]rune(x))
+		if m, ok := schemaNumber(sch["minLength"]); ok && float64(n) < m {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: tamanho %d < minLength %v"), at, n, m))
+		}
+		if m, ok := schemaNumber(sch["maxLength"]); ok && float64(n) > m {
+			*probs = append(*probs, fmt.Sprintf(tr("%s: tamanho %d > maxLength %v"), at, n, m))
+		}
+		if p, ok := sch["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(x) {
+				*probs = append(*probs, fmt.Sprintf(tr("%s: %q não casa com %s"), at, x, p))
+			}
+		}
+	}
+}
+
+// "type" pode ser string ou lista ("null" incluso)
+func typeMatches(t, v any) bool {
+	names := anyList(t)
+	if s, ok := t.(string); ok {
+		names = []any{s}
+	}
+	got := jsonType(v)
+	for _, n := range names {
+		if want := fmt.Sprint(n); want == got || (want == "number" && got == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if x == float64(int64(x)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func anyList(v any) []any {
+	l, _ := v.([]any)
+	return l
+}
+
+func schemaList(v any) []map[string]any {
+	var out []map[string]any
+	for _, x := range anyList(v) {
+		if m, ok := x.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// número do schema: float64 (JSON) ou int (YAML)
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+/* ==================== .env cifrado (age, SOPS) ==================== */
+
+// --env-file (e o .env do diretório) pode vir cifrado para ficar no repositório de QA:
+//   - age: .env inteiro cifrado (age -e -r age1... .env > .env.age); chave em AGE_KEY_FILE ou
+//     AGE_KEY (conteúdo), também aceitas como SOPS_AGE_KEY_FILE/SOPS_AGE_KEY
+//   - SOPS: YAML, JSON ou dotenv com os valores cifrados; o sops acha a chave sozinho
+//     (SOPS_AGE_KEY_FILE, KMS, PGP...)
+//
+// Decifra com os binários age/sops do PATH; o texto claro só existe na memória, e o AGE_KEY
+// chega ao age por um pipe (no Windows, num temporário 0600 de diretório próprio, apagado no fim)
+type secretsError string
+
+func (e secretsError) Error() string { return string(e) }
+
+// erro de decifrar (sem binário, sem chave, chave errada): aborta mesmo no .env implícito
+func isSecretsError(err error) bool {
+	var se secretsError
+	return errors.As(err, &se)
+}
+
+var (
+	sopsYAMLMarker   = regexp.MustCompile(`(?m)^sops:\s*$`)
+	sopsJSONMarker   = regexp.MustCompile(`"sops"\s*:\s*\{`)
+	sopsDotenvMarker = regexp.MustCompile(`(?m)^sops_version=`)
+)
+
+// "age", "sops-yaml", "sops-json", "sops-dotenv" ou "" (texto claro)
+func encryptedKind(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte("age-encryption.org/v1")), bytes.HasPrefix(b, []byte("-----BEGIN AGE ENCRYPTED FILE-----")):
+		return "age"
+	case sopsDotenvMarker.Match(b):
+		return "sops-dotenv"
+	case sopsYAMLMarker.Match(b):
+		return "sops-yaml"
+	case sopsJSONMarker.Match(b):
+		return "sops-json"
+	}
+	return ""
+}
+
+// variáveis do arquivo: direto (godotenv) ou decifradas antes
+func readEnvFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kind := encryptedKind(b)
+	if kind == "" {
+		return godotenv.UnmarshalBytes(b)
+	}
+	var plain []byte
+	if kind == "age" {
+		plain, err = decryptAge(path)
+	} else {
+		plain, err = decryptSops(path, strings.TrimPrefix(kind, "sops-"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, err := godotenv.UnmarshalBytes(plain)
+	if err != nil {
+		return nil, secretsError(fmt.Sprintf(tr("%s decifrado não é um .env válido: %v"), path, err))
+	}
+	outf("[secrets] %s decifrado (%s, %d variáveis)\n", path, strings.SplitN(kind, "-", 2)[0], len(m))
+	return m, nil
+}
+
+// identidade do age: arquivo (AGE_KEY_FILE) ou conteúdo (AGE_KEY, devolvido em key);
+// sem nenhum dos dois, o keys.txt padrão do sops
+func ageIdentity() (path, key string, err error) {
+	for _, k := range []string{"AGE_KEY_FILE", "SOPS_AGE_KEY_FILE"} {
+		if p := os.Getenv(k); p != "" {
+			return expandHome(p), "", nil
+		}
+	}
+	for _, k := range []string{"AGE_KEY", "SOPS_AGE_KEY"} {
+		if v := os.Getenv(k); v != "" {
+			return "", strings.TrimSpace(v) + "\n", nil
+		}
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		p := filepath.Join(dir, "sops", "age", "keys.txt")
+		if _, err := os.Stat(p); err == nil {
+			return p, "", nil
+		}
+	}
+	return "", "", secretsError(tr("arquivo cifrado com age: defina AGE_KEY_FILE (ou AGE_KEY com a chave AGE-SECRET-KEY-...)"))
+}
+
+func decryptAge(path string) ([]byte, error) {
+	bin, err := exec.LookPath("age")
+	if err != nil {
+		return nil, secretsError(fmt.Sprintf(tr("%s está cifrado com age: instale o age (https://age-encryption.org) no PATH"), path))
+	}
+	identity, key, err := ageIdentity()
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return runDecrypt(path, exec.Command(bin, "--decrypt", "--identity", identity, path))
+	}
+	if runtime.GOOS == "windows" {
+		return decryptAgeTempKey(bin, path, key)
+	}
+	// a chave vai pelo fd 3 do age (/dev/fd/3): cabe no buffer do pipe, então escreve e fecha antes
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	_, err = w.WriteString(key)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := exec.Command(bin, "--decrypt", "--identity", "/dev/fd/3", path)
+	c.ExtraFiles = []*os.File{r}
+	return runDecrypt(path, c)
+}
+
+// Windows não passa fds extras: a chave fica num 0600 dentro de um diretório 0700 só nosso
+func decryptAgeTempKey(bin, path, key string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "biodoc-age-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	identity := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(identity, []byte(key), 0600); err != nil {
+		return nil, err
+	}
+	return runDecrypt(path, exec.Command(bin, "--decrypt", "--identity", identity, path))
+}
+
+func decryptSops(path, format string) ([]byte, error) {
+	bin, err := exec.LookPath("sops")
+	if err != nil {
+		return nil, secretsError(fmt.Sprintf(tr("%s está cifrado com SOPS: instale o sops no PATH"), path))
+	}
+	// a chave do age também vale para o sops com os nomes do runner
+	c := exec.Command(bin, "--decrypt", "--input-type", format, "--output-type", "dotenv", path)
+	c.Env = os.Environ()
+	if os.Getenv("SOPS_AGE_KEY_FILE") == "" && os.Getenv("AGE_KEY_FILE") != "" {
+		c.Env = append(c.Env, "SOPS_AGE_KEY_FILE="+expandHome(os.Getenv("AGE_KEY_FILE")))
+	}
+	if os.Getenv("SOPS_AGE_KEY") == "" && os.Getenv("AGE_KEY") != "" {
+		c.Env = append(c.Env, "SOPS_AGE_KEY="+os.Getenv("AGE_KEY"))
+	}
+	return runDecrypt(path, c)
+}
+
+func runDecrypt(path string, c *exec.Cmd) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c.Stdout, c.Stderr = &stdout, &stderr
+	if err := c.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, secretsError(fmt.Sprintf(tr("decifrar %s (%s): %s"), path, filepath.Base(c.Path), msg))
+	}
+	return stdout.Bytes(), nil
+}
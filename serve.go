@@ -0,0 +1,190 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+/* ==================== serve (runner como serviço REST local) ==================== */
+
+// chamadas guardadas em memória; o serviço fica no ar por dias
+const serveKeepCalls = 500
+
+type serveOptions struct {
+	Addr    string
+	APIKey  string // exigido em X-API-Key quando definido
+	Encode  string // formato da imagem repassada ao Biodoc ("" = default de cada rota)
+	Consent bool   // consentTermSigned quando o create não mandar
+}
+
+// serve: /create, /verify e /delete repassados ao Biodoc com o token configurado;
+// quem chama manda só a imagem e os campos, sem conhecer o token
+func cmdServe(baseURL, token string, opt serveOptions) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"ok": true, "base_url": baseURL})
+	})
+	mux.HandleFunc("POST /create", func(w http.ResponseWriter, r *http.Request) {
+		in, err := readMockUpload(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(err.Error())})
+			return
+		}
+		if in.Name == "" {
+			in.Name = "Celso QA"
+		}
+		consent := in.Consent || opt.Consent
+		serveWithImage(w, in.image, func(path string) (*http.Response, []byte, error) {
+			return createCard(baseURL, token, path, in.ID, in.Name, consent, opt.Encode)
+		})
+	})
+	mux.HandleFunc("POST /verify", func(w http.ResponseWriter, r *http.Request) {
+		in, err := readMockUpload(r)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": tr(err.Error())})
+			return
+		}
+		serveWithImage(w, in.image, func(path string) (*http.Response, []byte, error) {
+			return verifyCard(baseURL, token, "", path, in.ID, in.Name, in.Detail, opt.Encode)
+		})
+	})
+	deleteCard := func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if id == "" {
+			id = r.URL.Query().Get("id")
+		}
+		if id == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]any{"success": false, "message": "id obrigatório (/delete/{id} ou ?id=)"})
+			return
+		}
+		resp, body, err := doRequest(http.MethodDelete, cardURL(baseURL, id), authHeader(token), nil)
+		serveRelay(w, resp, body, err)
+	}
+	mux.HandleFunc("DELETE /delete", deleteCard)
+	mux.HandleFunc("DELETE /delete/{id}", deleteCard)
+
+	h := serveLog(serveAuth(opt.APIKey, mux))
+	if opt.APIKey == "" && !isLoopbackAddr(opt.Addr) {
+		outf("[serve] ⚠ %s aceita conexões de fora sem --api-key: qualquer um na rede usa o token do runner\n", opt.Addr)
+	}
+	outf("[serve] ouvindo em http://%s → %s (POST /create, POST /verify, DELETE /delete/{id}); Ctrl+C encerra\n", opt.Addr, baseURL)
+	return serveUntilSignal(opt.Addr, h)
+}
+
+// grava a imagem recebida num temporário: o envio passa pelo mesmo pré-processamento dos outros comandos
+func serveWithImage(w http.ResponseWriter, img []byte, call func(path string) (*http.Response, []byte, error)) {
+	f, err := os.CreateTemp("", "biodoc-serve-*"+extForContentType(imageContentType(img)))
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "message": err.Error()})
+		return
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(img)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]any{"success": false, "message": err.Error()})
+		return
+	}
+	resp, body, err := call(f.Name())
+	serveRelay(w, resp, body, err)
+}
+
+// devolve status e corpo do Biodoc como vieram; sem resposta vira 502
+func serveRelay(w http.ResponseWriter, resp *http.Response, body []byte, err error) {
+	trimCalls(serveKeepCalls)
+	if err != nil || resp == nil {
+		msg := "sem resposta do Biodoc"
+		if err != nil {
+			msg = err.Error()
+		}
+		writeJSON(w, http.StatusBadGateway, map[string]any{"success": false, "message": msg})
+		return
+	}
+	ct := resp.Header.Get("Content-Type")
+	if ct == "" && json.Valid(body) {
+		ct = "application/json"
+	}
+	if ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(body)
+}
+
+func extForContentType(ct string) string {
+	switch ct {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/webp":
+		return ".webp"
+	}
+	return ""
+}
+
+// só as últimas keep chamadas ficam para o --output json/--har do encerramento (serve, soak:
+// processos que rodam horas); o resto do array é zerado para os corpos antigos irem embora
+func trimCalls(keep int) {
+	callsMu.Lock()
+	if n := len(calls); n > keep {
+		calls = append(calls[:0], calls[n-keep:]...)
+		clear(calls[keep:n])
+	}
+	callsMu.Unlock()
+	harRec.trim(keep)
+}
+
+func serveAuth(key string, next http.Handler) http.Handler {
+	if key == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" && subtle.ConstantTimeCompare([]byte(r.Header.Get("X-API-Key")), []byte(key)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]any{"success": false, "message": "X-API-Key ausente ou inválida"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// uma linha por requisição atendida
+func serveLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		if r.URL.Path == "/healthz" {
+			return
+		}
+		outf("[serve] %s %s → %d (%s)\n", r.Method, r.URL.Path, sw.status, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusWriter) WriteHeader(code int) {
+	s.status = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
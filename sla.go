@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== report sla ==================== */
+
+type slaOptions struct {
+	Store    string
+	By       string // hour | day
+	Since    time.Time
+	Until    time.Time
+	Endpoint string  // "" = todos
+	Target   float64 // disponibilidade alvo em %, base do orçamento de erro
+	CSV      string
+}
+
+type slaRow struct {
+	Period       string  `json:"period"`
+	Requests     int     `json:"requests"`
+	Failures     int     `json:"failures"`
+	Availability float64 `json:"availability_pct"`
+	P50MS        int64   `json:"p50_ms"`
+	P95MS        int64   `json:"p95_ms"`
+	BudgetUsed   float64 `json:"error_budget_used_pct"`
+}
+
+// falha para SLA: sem resposta ou 5xx (4xx é erro do cliente, a API estava de pé)
+func slaFailed(c storedCall) bool {
+	return c.Status == 0 || c.Status >= 500
+}
+
+func slaPeriod(t time.Time, by string) string {
+	t = t.Local()
+	if by == "day" {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:00")
+}
+
+func newSLARow(period string, cs []storedCall, target float64) slaRow {
+	r := slaRow{Period: period, Requests: len(cs)}
+	lats := make([]time.Duration, 0, len(cs))
+	for _, c := range cs {
+		if slaFailed(c) {
+			r.Failures++
+		}
+		lats = append(lats, time.Duration(c.LatencyMS)*time.Millisecond)
+	}
+	if r.Requests > 0 {
+		r.Availability = 100 * float64(r.Requests-r.Failures) / float64(r.Requests)
+	}
+	r.P50MS = percentileDur(lats, 50).Milliseconds()
+	r.P95MS = percentileDur(lats, 95).Milliseconds()
+	// falhas permitidas = (1 - alvo) × requisições
+	if allowed := (100 - target) / 100 * float64(r.Requests); allowed > 0 {
+		r.BudgetUsed = 100 * float64(r.Failures) / allowed
+	}
+	return r
+}
+
+func cmdReportSLA(opt slaOptions) error {
+	if opt.By != "hour" && opt.By != "day" {
+		return usageError(tr("--by deve ser hour ou day"))
+	}
+	if opt.Target <= 0 || opt.Target >= 100 {
+		return usageError(tr("--target deve estar entre 0 e 100 (ex.: 99.5)"))
+	}
+	runs, err := readRuns(opt.Store)
+	if err != nil {
+		return fmt.Errorf("results store: %w", err)
+	}
+	byPeriod := map[string][]storedCall{}
+	var all []storedCall
+	for _, r := range runs {
+		for _, c := range r.Calls {
+			if !opt.Since.IsZero() && c.At.Before(opt.Since) || !opt.Until.IsZero() && !c.At.Before(opt.Until) {
+				continue
+			}
+			if opt.Endpoint != "" && c.Endpoint != opt.Endpoint {
+				continue
+			}
+			p := slaPeriod(c.At, opt.By)
+			byPeriod[p] = append(byPeriod[p], c)
+			all = append(all, c)
+		}
+	}
+	if len(all) == 0 {
+		return fmt.Errorf(tr("nenhuma requisição no período em %s"), opt.Store)
+	}
+	periods := sortedKeys(byPeriod)
+	rows := make([]slaRow, 0, len(periods))
+	for _, p := range periods {
+		rows = append(rows, newSLARow(p, byPeriod[p], opt.Target))
+	}
+	total := newSLARow("total", all, opt.Target)
+
+	scope := tr("todos os endpoints")
+	if opt.Endpoint != "" {
+		scope = "endpoint " + opt.Endpoint
+	}
+	outf("[sla] %d execuções, %d requisições (%s), alvo %.2f%%\n", len(runs), len(all), scope, opt.Target)
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, tr("período\treq\tfalhas\tdisponib.\tp50\tp95\torçamento usado\t\n"))
+	for _, r := range append(rows, total) {
+		flag := ""
+		if r.Availability < opt.Target {
+			flag = " ⚠"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.3f%%%s\t%dms\t%dms\t%.1f%%\t\n",
+			r.Period, r.Requests, r.Failures, r.Availability, flag, r.P50MS, r.P95MS, r.BudgetUsed)
+	}
+	_ = tw.Flush()
+	allowed := (100 - opt.Target) / 100 * float64(total.Requests)
+	outf("[sla] orçamento de erro: %.2f falhas permitidas, %d usadas (%.1f%%)\n", allowed, total.Failures, total.BudgetUsed)
+
+	setResult("sla", map[string]any{"by": opt.By, "target_pct": opt.Target, "periods": rows, "total": total})
+	if opt.CSV != "" {
+		if err := writeSLACSV(opt.CSV, append(rows, total)); err != nil {
+			return err
+		}
+		outf("[sla] CSV em %s\n", opt.CSV)
+	}
+	return nil
+}
+
+func writeSLACSV(path string, rows []slaRow) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"period", "requests", "failures", "availability_pct", "p50_ms", "p95_ms", "error_budget_used_pct"})
+	for _, r := range rows {
+		_ = w.Write([]string{
+			r.Period, strconv.Itoa(r.Requests), strconv.Itoa(r.Failures),
+			strconv.FormatFloat(r.Availability, 'f', 3, 64),
+			strconv.FormatInt(r.P50MS, 10), strconv.FormatInt(r.P95MS, 10),
+			strconv.FormatFloat(r.BudgetUsed, 'f', 1, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// aceita data (2026-09-01) ou RFC3339
+func parseSLATime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, usageError(fmt.Sprintf(tr("data inválida %q (use 2006-01-02 ou RFC3339)"), s))
+	}
+	return t, nil
+}
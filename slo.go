@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== SLO de latência (--max-latency-p95, --slo) ==================== */
+
+// diferente do --budget (só marca), SLO violado falha a execução.
+// Alvo: "all" (toda resposta da API), endpoint (verify, register...), etapa (create, verify...) ou "total"
+type sloRule struct {
+	Target string
+	Pct    float64 // 50, 95, 99...; 100 = máx
+	Limit  time.Duration
+}
+
+func (r sloRule) label() string {
+	if r.Pct == 100 {
+		return r.Target + ".max"
+	}
+	return fmt.Sprintf("%s.p%s", r.Target, strconv.FormatFloat(r.Pct, 'f', -1, 64))
+}
+
+type sloError string
+
+func (e sloError) Error() string { return string(e) }
+
+func isSLOError(err error) bool {
+	var se sloError
+	return errors.As(err, &se)
+}
+
+// latência de cada resposta recebida (todas as tentativas, inclusive load-verify), por endpoint
+type sloRecorder struct {
+	mu      sync.Mutex
+	enabled bool
+	byEP    map[string][]time.Duration
+}
+
+var sloLat = &sloRecorder{byEP: map[string][]time.Duration{}}
+
+func (s *sloRecorder) observe(method, url string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.enabled {
+		return
+	}
+	ep := callEndpoint(method, url)
+	s.byEP[ep] = append(s.byEP[ep], d)
+}
+
+// "verify.p95=800ms,create.p99=2s,total.max=10s"; sem alvo ("p95=800ms") vale para "all"
+func parseSLOs(spec string) ([]sloRule, error) {
+	var rules []sloRule
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if !ok || err != nil || d <= 0 {
+			return nil, fmt.Errorf(tr("SLO inválido %q (use alvo.p95=800ms)"), part)
+		}
+		target, pct, found := strings.Cut(strings.TrimSpace(k), ".")
+		if !found {
+			target, pct = "all", target
+		}
+		r := sloRule{Target: target, Limit: d}
+		switch {
+		case pct == "max":
+			r.Pct = 100
+		case strings.HasPrefix(pct, "p"):
+			r.Pct, err = strconv.ParseFloat(pct[1:], 64)
+			if err != nil || r.Pct <= 0 || r.Pct > 100 {
+				return nil, fmt.Errorf(tr("SLO inválido %q: percentil %q"), part, pct)
+			}
+		default:
+			return nil, fmt.Errorf(tr("SLO inválido %q: use p50, p95, p99... ou max"), part)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// ENV LATENCY_SLO, --slo por cima, e os atalhos --max-latency-p95/--max-latency-p99 (alvo "all")
+func stripSLOFlags(args []string) ([]string, []sloRule, error) {
+	rules, err := parseSLOs(os.Getenv("LATENCY_SLO"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("LATENCY_SLO: %w", err)
+	}
+	args, spec, _, err := stripValueFlag(args, "--slo")
+	if err != nil {
+		return nil, nil, err
+	}
+	more, err := parseSLOs(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules = append(rules, more...)
+	for _, p := range []string{"95", "99"} {
+		var v string
+		var set bool
+		if args, v, set, err = stripValueFlag(args, "--max-latency-p"+p); err != nil {
+			return nil, nil, err
+		}
+		if !set {
+			continue
+		}
+		r, err := parseSLOs("all.p" + p + "=" + v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--max-latency-p%s: %w", p, err)
+		}
+		rules = append(rules, r...)
+	}
+	return args, rules, nil
+}
+
+type sloResult struct {
+	Rule     string `json:"rule"`
+	Samples  int    `json:"samples"`
+	ActualMS int64  `json:"actual_ms"`
+	LimitMS  int64  `json:"limit_ms"`
+	OK       bool   `json:"ok"`
+}
+
+// avalia no fim da execução; alvo sem amostras não falha (só avisa)
+func checkSLOs(rules []sloRule, stepList []stepRecord, total time.Duration) error {
+	sloLat.mu.Lock()
+	byEP := sloLat.byEP
+	sloLat.mu.Unlock()
+	var all []time.Duration
+	for _, ep := range sortedKeys(byEP) {
+		all = append(all, byEP[ep]...)
+	}
+	bySteps := map[string][]time.Duration{}
+	for _, st := range stepList {
+		if !st.Skipped {
+			bySteps[st.Name] = append(bySteps[st.Name], st.Duration)
+		}
+	}
+
+	var results []sloResult
+	var breached []string
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, tr("[slo]\tn\tmedido\tlimite\t\n"))
+	for _, r := range rules {
+		var ds []time.Duration
+		switch {
+		case r.Target == "all":
+			ds = all
+		case r.Target == "total":
+			ds = []time.Duration{total}
+		case len(bySteps[r.Target]) > 0:
+			ds = bySteps[r.Target]
+		default:
+			ds = byEP[r.Target]
+		}
+		if len(ds) == 0 {
+			fmt.Fprintf(tw, tr("  %s\t0\t-\t%s\tsem amostras\n"), r.label(), r.Limit)
+			continue
+		}
+		got := percentileDur(ds, r.Pct)
+		res := sloResult{Rule: r.label(), Samples: len(ds), ActualMS: got.Milliseconds(), LimitMS: r.Limit.Milliseconds(), OK: got <= r.Limit}
+		results = append(results, res)
+		mark := "✅"
+		if !res.OK {
+			mark = "❌"
+			breached = append(breached, fmt.Sprintf("%s=%s > %s", r.label(), got.Round(time.Millisecond), r.Limit))
+		}
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\t%s\n", r.label(), len(ds), got.Round(time.Millisecond), r.Limit, mark)
+	}
+	_ = tw.Flush()
+	setResult("slo", results)
+	if len(breached) > 0 {
+		sort.Strings(breached)
+		return sloError(tr("SLO de latência violado: ") + strings.Join(breached, ", "))
+	}
+	return nil
+}
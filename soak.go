@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+/* ==================== soak (estabilidade ao longo de horas) ==================== */
+
+// chamadas guardadas para o --output json/--har; as estatísticas ficam nas janelas
+const soakKeepCalls = 200
+
+// uma iteração por --interval durante --duration; a cada --summary-every fecha uma janela com
+// taxa de erro, p50/p95, drift do p95 contra a primeira janela e renovações de token.
+// Ctrl+C/SIGTERM encerra e ainda imprime o resumo
+type soakOptions struct {
+	Mode         string // verify ou cycle (preclean → create → verify → delete)
+	Endpoint     string
+	Image        string
+	ID           string
+	Name         string
+	Detail       string
+	Duration     time.Duration
+	Interval     time.Duration
+	SummaryEvery time.Duration
+	MaxErrorRate float64 // < 0 = não falha por taxa de erro
+	MaxDrift     float64 // % do p95 da última janela sobre a primeira; < 0 = não checa
+}
+
+type soakWindow struct {
+	Start    time.Time   `json:"start"`
+	Requests int         `json:"iterations"`
+	Errors   int         `json:"errors"`
+	Status   map[int]int `json:"status"`
+	P50      int64       `json:"p50_ms"`
+	P95      int64       `json:"p95_ms"`
+	Drift    float64     `json:"p95_drift_pct"`
+	Renewals int         `json:"token_renewals"`
+
+	lats []time.Duration
+}
+
+func (w *soakWindow) errorRate() float64 {
+	if w.Requests == 0 {
+		return 0
+	}
+	return float64(w.Errors) / float64(w.Requests)
+}
+
+// renovações do OAuth até agora; token estático não renova
+func tokenRenewals() int {
+	if oauth == nil {
+		return 0
+	}
+	return oauth.renewals()
+}
+
+// status da última resposta e erro da iteração (non-2xx conta como erro)
+func soakIteration(baseURL, token string, opt soakOptions) (int, error) {
+	step := func(resp *http.Response, body []byte, err error) (int, error) {
+		if err != nil {
+			if resp != nil {
+				return resp.StatusCode, err
+			}
+			return 0, err
+		}
+		return resp.StatusCode, checkStatus(resp, body)
+	}
+	if opt.Mode == "verify" {
+		return step(verifyCard(baseURL, token, opt.Endpoint, opt.Image, opt.ID, opt.Name, opt.Detail, ""))
+	}
+	if d, err := precleanCard(baseURL, token, opt.ID); err != nil {
+		return d.DeleteStatus, fmt.Errorf("preclean: %w", err)
+	}
+	if st, err := step(createCard(baseURL, token, opt.Image, opt.ID, opt.Name, true, "")); err != nil {
+		return st, fmt.Errorf("create: %w", err)
+	}
+	if st, err := step(verifyCard(baseURL, token, opt.Endpoint, opt.Image, opt.ID, opt.Name, opt.Detail, "")); err != nil {
+		return st, fmt.Errorf("verify: %w", err)
+	}
+	st, err := step(doRequest(http.MethodDelete, cardURL(baseURL, opt.ID), authHeader(token), nil))
+	if err != nil {
+		return st, fmt.Errorf("delete: %w", err)
+	}
+	return st, nil
+}
+
+func cmdSoak(baseURL, token string, opt soakOptions) error {
+	if opt.Mode != "verify" && opt.Mode != "cycle" {
+		return usageError(fmt.Sprintf(tr("--mode inválido: %q (use verify ou cycle)"), opt.Mode))
+	}
+	if opt.Duration <= 0 || opt.Interval <= 0 || opt.SummaryEvery <= 0 {
+		return usageError(tr("--duration, --interval e --summary-every devem ser > 0"))
+	}
+	if opt.Mode == "cycle" && opt.ID == "" {
+		return usageError(tr("--id vazio: o modo cycle cria e apaga esse card"))
+	}
+	outf("[soak] %s a cada %s por %s (resumo a cada %s)\n", opt.Mode, opt.Interval, opt.Duration, opt.SummaryEvery)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, opt.Duration)
+	defer cancel()
+
+	started := time.Now()
+	renewalsAtStart := tokenRenewals()
+	var windows []*soakWindow
+	cur := &soakWindow{Start: started, Status: map[int]int{}}
+	renewalsAtWindow := renewalsAtStart
+
+	closeWindow := func() {
+		if cur.Requests == 0 {
+			return
+		}
+		cur.P50 = percentileDur(cur.lats, 50).Milliseconds()
+		cur.P95 = percentileDur(cur.lats, 95).Milliseconds()
+		if len(windows) > 0 && windows[0].P95 > 0 {
+			cur.Drift = 100 * float64(cur.P95-windows[0].P95) / float64(windows[0].P95)
+		}
+		now := tokenRenewals()
+		cur.Renewals, renewalsAtWindow = now-renewalsAtWindow, now
+		windows = append(windows, cur)
+		outf("[soak] t=%s janela %d: %d iterações, erros=%d (%.2f%%) p50=%dms p95=%dms drift p95=%+.1f%% renovações=%d status=%s\n",
+			time.Since(started).Round(time.Second), len(windows), cur.Requests, cur.Errors, cur.errorRate()*100,
+			cur.P50, cur.P95, cur.Drift, cur.Renewals, formatStatusCounts(cur.Status))
+		cur = &soakWindow{Start: time.Now(), Status: map[int]int{}}
+	}
+
+	tick := time.NewTicker(opt.Interval)
+	defer tick.Stop()
+	for n := 1; ; n++ {
+		t0 := time.Now()
+		status, err := soakIteration(baseURL, token, opt)
+		cur.lats = append(cur.lats, time.Since(t0))
+		cur.Requests++
+		cur.Status[status]++
+		if err != nil {
+			cur.Errors++
+			outf("[soak] ❌ #%d %v\n", n, err)
+		}
+		trimCalls(soakKeepCalls)
+		// a imagem é sempre a mesma: o aviso de qualidade sai só na primeira iteração
+		skipQualityScan = true
+		if time.Since(cur.Start) >= opt.SummaryEvery {
+			closeWindow()
+		}
+		select {
+		case <-ctx.Done():
+		case <-tick.C:
+			continue
+		}
+		break
+	}
+	closeWindow()
+	if ctx.Err() == context.Canceled {
+		outln("[soak] interrompido: resumo parcial")
+	}
+	return printSoakReport(windows, time.Since(started), tokenRenewals()-renewalsAtStart, opt)
+}
+
+func printSoakReport(windows []*soakWindow, elapsed time.Duration, renewals int, opt soakOptions) error {
+	var total, errs int
+	var lats []time.Duration
+	for _, w := range windows {
+		total += w.Requests
+		errs += w.Errors
+		lats = append(lats, w.lats...)
+	}
+	if total == 0 {
+		return fmt.Errorf(tr("nenhuma iteração concluída em %s"), elapsed.Round(time.Second))
+	}
+	errRate := float64(errs) / float64(total)
+	outf("[soak] %d iterações em %s, erros=%d (%.2f%%) p50=%s p95=%s renovações de token=%d\n",
+		total, elapsed.Round(time.Second), errs, errRate*100,
+		percentileDur(lats, 50).Round(time.Millisecond), percentileDur(lats, 95).Round(time.Millisecond), renewals)
+	outf("  %-3s %-8s %6s %6s %8s %8s %8s\n", "#", tr("início"), "iter", tr("erros"), "p50", "p95", "drift")
+	for i, w := range windows {
+		outf("  %-3d %-8s %6d %6d %6dms %6dms %+7.1f%%\n", i+1, w.Start.Format("15:04:05"), w.Requests, w.Errors, w.P50, w.P95, w.Drift)
+	}
+	last := windows[len(windows)-1]
+	setResult("soak", map[string]any{
+		"mode":           opt.Mode,
+		"iterations":     total,
+		"duration_ms":    elapsed.Milliseconds(),
+		"errors":         errs,
+		"error_rate":     errRate,
+		"token_renewals": renewals,
+		"p95_drift_pct":  last.Drift,
+		"windows":        windows,
+	})
+	if opt.MaxErrorRate >= 0 && errRate > opt.MaxErrorRate {
+		return fmt.Errorf(tr("taxa de erro %.2f%% acima do limite %.2f%%"), errRate*100, opt.MaxErrorRate*100)
+	}
+	if opt.MaxDrift >= 0 && len(windows) > 1 && last.Drift > opt.MaxDrift {
+		return fmt.Errorf(tr("p95 da última janela %.1f%% acima da primeira (limite %.1f%%)"), last.Drift, opt.MaxDrift)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+/* ==================== Modo estrito (padrão; --no-strict desliga) ==================== */
+
+// erro cedo, antes de qualquer chamada: argumento solto, AUTH_TOKEN ausente, imagem inexistente
+var strict = true
+
+// comandos cujo token vem das próprias flags (--a-token/--b-token)
+var ownTokenCommands = map[string]bool{"diff-fuzz": true}
+
+// fs.Parse + checagens do modo estrito; como o flag.ExitOnError, imprime o uso e sai com 2
+func parseFlags(fs *flag.FlagSet, args []string) {
+	translateFlags(fs)
+	_ = fs.Parse(args)
+	lastFlagSet = fs
+	images := imageFlag(fs)
+	if !strict {
+		return
+	}
+	fail := func(format string, a ...any) {
+		fmt.Fprintf(fs.Output(), format+tr(" (--no-strict ignora)\n"), a...)
+		fs.Usage()
+		// exit, não os.Exit: apaga os temporários do stdin/URL que o imageFlag já baixou
+		exit(2)
+	}
+	// "create-card --id 1 x --name y" para de ler em "x" e ignora o resto
+	if fs.NArg() > 0 {
+		fail(tr("argumento inesperado: %q"), fs.Arg(0))
+	}
+	if img := fs.Lookup("image"); img != nil && img.Value.String() != "" {
+		if cam := fs.Lookup("camera"); cam != nil && cam.Value.String() == "true" {
+			return
+		}
+		for _, p := range images {
+			if _, err := os.Stat(p); err != nil {
+				fail(tr("imagem não encontrada: %s"), p)
+			}
+		}
+	}
+}
+
+// acerta o separador do --image, expande os globs e baixa stdin/URL: a flag fica com os caminhos
+// (várias imagens só nos comandos de multiImageCommands). Devolve o que checar; glob sem arquivo volta como veio
+func imageFlag(fs *flag.FlagSet) []string {
+	img := fs.Lookup("image")
+	if img == nil || img.Value.String() == "" {
+		return nil
+	}
+	list, isList := img.Value.(*imageListFlag)
+	args := []string{img.Value.String()}
+	if isList {
+		args = list.paths
+	}
+	camera := fs.Lookup("camera")
+	var all []string
+	for _, a := range args {
+		if (a == "-" || isRemoteImage(a)) && (camera == nil || camera.Value.String() != "true") {
+			p, err := fetchImageArg(a)
+			if err != nil {
+				fmt.Fprintln(fs.Output(), err)
+				exit(exitCodeFor(err))
+			}
+			all = append(all, p)
+			continue
+		}
+		matches, err := expandImageArg(a)
+		if err != nil {
+			// sem nenhum arquivo: o modo estrito acusa "imagem não encontrada"
+			matches = []string{a}
+		}
+		all = append(all, matches...)
+	}
+	if len(all) > 1 && !multiImageCommands[fs.Name()] {
+		fmt.Fprintf(fs.Output(), tr("--image %q casou %d imagens; %s usa uma só\n"), img.Value.String(), len(all), fs.Name())
+		exit(2)
+	}
+	if isList {
+		list.paths = all
+	} else {
+		_ = fs.Set("image", all[0])
+	}
+	return all
+}
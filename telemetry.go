@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ==================== Telemetria de uso (opt-in) ==================== */
+
+// telemetry: {enabled: true, url: https://...} no ~/.biodoc-runner.yaml (vale para a máquina toda);
+// ENV BIODOC_TELEMETRY=1/0 e BIODOC_TELEMETRY_URL sobrescrevem. Só nomes de comando/flag e
+// categorias de falha: nada de valores, tokens, URLs ou imagens
+type telemetryConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	URL     string `yaml:"url"`
+}
+
+type telemetryEvent struct {
+	RunID      string   `json:"run_id"`
+	Machine    string   `json:"machine"` // hash do hostname
+	OS         string   `json:"os"`
+	Arch       string   `json:"arch"`
+	Version    string   `json:"version,omitempty"`
+	Command    string   `json:"command"`
+	Flags      []string `json:"flags,omitempty"`
+	OK         bool     `json:"ok"`
+	ExitCode   int      `json:"exit_code"`
+	Category   string   `json:"category,omitempty"`
+	DurationMS int64    `json:"duration_ms"`
+	Calls      int      `json:"calls"`
+}
+
+// URL de envio, "" = desligada
+func telemetryURL() string {
+	var tc telemetryConfig
+	if c, err := loadRunnerConfig(configPath()); err == nil {
+		tc = c.Telemetry
+	}
+	if v, ok := os.LookupEnv("BIODOC_TELEMETRY"); ok {
+		tc.Enabled = v == "1" || strings.EqualFold(v, "true")
+	}
+	if u := os.Getenv("BIODOC_TELEMETRY_URL"); u != "" {
+		tc.URL = u
+	}
+	if !tc.Enabled {
+		return ""
+	}
+	return tc.URL
+}
+
+// só os nomes das flags (--image, -q), sem valores e sem repetir; o valor de uma flag que
+// recebe valor (--expires-in -5m) é pulado mesmo começando com "-"
+func flagNames(args []string) []string {
+	seen := map[string]bool{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if !strings.HasPrefix(a, "-") || a == "-" || a == "--" {
+			continue
+		}
+		name, _, hasValue := strings.Cut(a, "=")
+		seen[name] = true
+		if !hasValue && isValueFlag(strings.TrimLeft(name, "-")) {
+			i++
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for n := range seen {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// flag do comando (o último FlagSet lido) ou global que não é booleana
+func isValueFlag(name string) bool {
+	if lastFlagSet != nil {
+		if f := lastFlagSet.Lookup(name); f != nil {
+			b, ok := f.Value.(interface{ IsBoolFlag() bool })
+			return !ok || !b.IsBoolFlag()
+		}
+	}
+	return slices.Contains(completionGlobalFlags, name+"=")
+}
+
+// categoria da falha: usage, auth, network, timeout, http_4xx, http_5xx, no_match, schema, slo,
+// expect (--expect/--expect-status não bateu) ou check (demais falhas do runner: cenário, arquivo...)
+func failureCategory(err error) string {
+	if err == nil {
+		return ""
+	}
+	if _, ok := err.(usageError); ok {
+		return "usage"
+	}
+	if isMatchError(err) {
+		return "no_match"
+	}
+	if isSchemaError(err) {
+		return "schema"
+	}
+	if isSLOError(err) {
+		return "slo"
+	}
+	if isExpectError(err) {
+		return "expect"
+	}
+	if isAuthError(err) {
+		return "auth"
+	}
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return "timeout"
+	}
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return statusCategory(ae.StatusCode)
+	}
+	if errors.As(err, &ne) {
+		return "network"
+	}
+	return "check"
+}
+
+func statusCategory(status int) string {
+	switch {
+	case status == 401 || status == 403:
+		return "auth"
+	case status >= 500:
+		return "http_5xx"
+	}
+	return "http_4xx"
+}
+
+// envia um evento no fim da execução; falha de envio nunca afeta o exit code
+func sendTelemetry(url, cmd string, rawArgs []string, elapsed time.Duration, err error, code int) {
+	host, _ := os.Hostname()
+	sum := sha256.Sum256([]byte("biodoc-runner\x00" + host))
+	ev := telemetryEvent{
+		RunID:      runID,
+		Machine:    hex.EncodeToString(sum[:8]),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Command:    cmd,
+		Flags:      flagNames(rawArgs),
+		OK:         err == nil,
+		ExitCode:   code,
+		Category:   failureCategory(err),
+		DurationMS: elapsed.Milliseconds(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		ev.Version = bi.Main.Version
+	}
+	callsMu.Lock()
+	ev.Calls = len(calls)
+	callsMu.Unlock()
+
+	b, _ := json.Marshal(ev)
+	// cliente próprio e curto: fora do retry, do cassete, do HAR e das métricas
+	resp, perr := (&http.Client{Timeout: 2 * time.Second, Transport: baseTransport}).Post(url, "application/json", bytes.NewReader(b))
+	if perr != nil {
+		if verbosity > 0 {
+			outf("[telemetry] envio falhou: %v\n", perr)
+		}
+		return
+	}
+	resp.Body.Close()
+	if verbosity > 0 {
+		outf("[telemetry] %s → %d\n", cmd, resp.StatusCode)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+/* ==================== viagem no tempo contra o mock (token vencido e renovação) ==================== */
+
+// etapas de cenário para o mock-server --clock sim --require-token jwt:
+//
+//	steps:
+//	  - type: clock          # POST /__admin/clock
+//	    advance: 2h          # ou set: 2024-01-01T03:00:00Z
+//	  - type: token          # JWT forjado pelo mock; as etapas seguintes usam ele
+//	    expiresIn: -5m       # negativo = já vencido; 30s = quase vencendo
+//	  - type: verify
+//	    expect: {refreshed: true}
+//
+// Com OAUTH_TOKEN_URL apontando para o /oauth/token do mock, o token forjado entra no lugar
+// do OAuth e o 401 dispara a renovação; expect.refreshed confere se ela aconteceu na etapa
+var localStepTypes = map[string]bool{"preclean": true, "clock": true, "token": true}
+
+// POST numa rota /__admin do mock; a resposta 200 é decodificada em out
+func mockAdmin(baseURL, path string, in, out any) error {
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	h := http.Header{}
+	h.Set("Content-Type", "application/json")
+	resp, raw, err := doRequest(http.MethodPost, strings.TrimRight(baseURL, "/")+path, h, b)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf(tr("%s respondeu %d (%s): o destino é um mock-server?"), path, resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	return json.Unmarshal(raw, out)
+}
+
+type mockJWT struct {
+	Token string `json:"token"`
+	Exp   string `json:"exp"`
+	Now   string `json:"now"`
+}
+
+func craftMockJWT(baseURL, expiresIn, sub string) (mockJWT, error) {
+	var tok mockJWT
+	err := mockAdmin(baseURL, "/__admin/jwt", map[string]string{"expiresIn": expiresIn, "sub": sub}, &tok)
+	return tok, err
+}
+
+func (sc *scenario) runClockStep(st scenarioStep, baseURL string) error {
+	in := map[string]string{"advance": sc.expand(st.Advance)}
+	if st.Set != "" {
+		in = map[string]string{"set": sc.expand(st.Set)}
+	}
+	var out struct {
+		Now string `json:"now"`
+	}
+	if err := mockAdmin(baseURL, "/__admin/clock", in, &out); err != nil {
+		return err
+	}
+	outf("[%s] relógio do mock → %s\n", st.Name, out.Now)
+	return nil
+}
+
+func (sc *scenario) runTokenStep(st scenarioStep, baseURL string) error {
+	tok, err := craftMockJWT(baseURL, sc.expand(st.ExpiresIn), sc.field("", "name", "qa"))
+	if err != nil {
+		return err
+	}
+	sc.token = tok.Token
+	if oauth != nil {
+		oauth.adopt(tok.Token)
+	}
+	outf("[%s] JWT forjado: exp=%s (relógio do mock %s)\n", st.Name, tok.Exp, tok.Now)
+	return nil
+}
+
+// expect.refreshed: o token foi (ou não) renovado durante a etapa
+func checkRefreshed(want *bool, renewed int) error {
+	if want == nil {
+		return nil
+	}
+	if *want && oauth == nil {
+		return fmt.Errorf(tr("expect.refreshed: sem OAuth (OAUTH_TOKEN_URL) o token nunca é renovado"))
+	}
+	switch {
+	case *want && renewed == 0:
+		return fmt.Errorf(tr("esperava 401 → renovação do token nesta etapa, e não houve"))
+	case !*want && renewed > 0:
+		return fmt.Errorf(tr("o token foi renovado %d vez(es) nesta etapa, esperado nenhuma"), renewed)
+	}
+	return nil
+}
+
+// craft-jwt: imprime só o token no stdout (AUTH_TOKEN=$(... craft-jwt --expires-in -5m))
+func cmdCraftJWT(mockURL, expiresIn, sub string) error {
+	if expiresIn == "" {
+		return usageError(tr("--expires-in é obrigatório (ex.: -5m vencido, 30s quase vencendo)"))
+	}
+	tok, err := craftMockJWT(mockURL, expiresIn, sub)
+	if err != nil {
+		return err
+	}
+	setResult("jwt", tok)
+	fmt.Fprintf(os.Stderr, tr("[jwt] exp=%s (relógio do mock %s)\n"), tok.Exp, tok.Now)
+	fmt.Println(tok.Token)
+	return nil
+}
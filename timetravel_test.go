@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mock com relógio simulado exigindo JWT dentro do exp, e o runner com OAuth apontando
+// para o /oauth/token do próprio mock
+func newJWTMock(t *testing.T) string {
+	t.Helper()
+	m, err := newMockServer(mockOptions{Clock: "sim", Scoring: "fixed:99", RequireToken: mockJWTAuth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(m.handler())
+	t.Cleanup(srv.Close)
+
+	prev := oauth
+	oauth = &oauthSource{tokenURL: srv.URL + mockTokenPath, clientID: "qa", clientSecret: "qa"}
+	t.Cleanup(func() { oauth = prev })
+	return srv.URL
+}
+
+func getCardList(t *testing.T, baseURL, tok string) int {
+	t.Helper()
+	resp, _, err := doRequest(http.MethodGet, baseURL+"/api/card", authHeader(tok), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+func TestExpiredJWTIsRefreshed(t *testing.T) {
+	baseURL := newJWTMock(t)
+	tok, err := craftMockJWT(baseURL, "-5m", "qa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oauth.adopt(tok.Token)
+
+	if got := getCardList(t, baseURL, tok.Token); got != http.StatusOK {
+		t.Fatalf("status = %d, want 200 depois da renovação", got)
+	}
+	if got := oauth.renewals(); got != 1 {
+		t.Fatalf("renewals = %d, want 1", got)
+	}
+	if oauth.current() == tok.Token {
+		t.Fatal("o token vencido continua em uso")
+	}
+}
+
+func TestNearExpiryJWTRefreshedAfterClockAdvance(t *testing.T) {
+	baseURL := newJWTMock(t)
+	tok, err := craftMockJWT(baseURL, "30s", "qa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oauth.adopt(tok.Token)
+
+	if got := getCardList(t, baseURL, tok.Token); got != http.StatusOK || oauth.renewals() != 0 {
+		t.Fatalf("status = %d, renewals = %d; want 200 sem renovação antes do exp", got, oauth.renewals())
+	}
+	var out struct {
+		Now string `json:"now"`
+	}
+	if err := mockAdmin(baseURL, "/__admin/clock", map[string]string{"advance": "1m"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := getCardList(t, baseURL, tok.Token); got != http.StatusOK {
+		t.Fatalf("status = %d, want 200 depois da renovação", got)
+	}
+	if err := checkRefreshed(new(bool), oauth.renewals()); err == nil {
+		t.Fatal("checkRefreshed(false) deveria falhar depois de uma renovação")
+	}
+}
+
+func TestExpiredJWTWithoutOAuthIs401(t *testing.T) {
+	baseURL := newJWTMock(t)
+	tok, err := craftMockJWT(baseURL, "-1s", "qa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	oauth = nil
+	if got := getCardList(t, baseURL, tok.Token); got != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401 com token estático vencido", got)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+/* ==================== Relatório de tempo e orçamentos ==================== */
+
+// orçamento por etapa, endpoint (register, verify...) ou "total"
+type timingBudgets map[string]time.Duration
+
+// "verify=1500ms,create=2s,total=10s"
+func parseBudgets(spec string) (timingBudgets, error) {
+	b := timingBudgets{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if !ok || k == "" || err != nil || d <= 0 {
+			return nil, fmt.Errorf(tr("orçamento inválido %q (use nome=duração, ex.: verify=1500ms)"), part)
+		}
+		b[strings.TrimSpace(k)] = d
+	}
+	return b, nil
+}
+
+// endpoint da chamada, com a mesma classificação do mock/proxy
+func callEndpoint(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "other"
+	}
+	return endpointKey(&http.Request{Method: method, URL: u})
+}
+
+type timingRow struct {
+	Name     string `json:"name"`
+	Count    int    `json:"count"`
+	TotalMS  int64  `json:"total_ms"`
+	AvgMS    int64  `json:"avg_ms"`
+	P95MS    int64  `json:"p95_ms"`
+	MaxMS    int64  `json:"max_ms"`
+	BudgetMS int64  `json:"budget_ms,omitempty"`
+	Over     bool   `json:"over_budget,omitempty"`
+}
+
+func newTimingRow(name string, ds []time.Duration, budgets timingBudgets) timingRow {
+	total := sumDur(ds)
+	r := timingRow{Name: name, Count: len(ds), TotalMS: total.Milliseconds()}
+	if len(ds) > 0 {
+		r.AvgMS = (total / time.Duration(len(ds))).Milliseconds()
+		r.P95MS = percentileDur(ds, 95).Milliseconds()
+		r.MaxMS = percentileDur(ds, 100).Milliseconds()
+	}
+	if b, ok := budgets[name]; ok {
+		r.BudgetMS = b.Milliseconds()
+		// orçamento vale para cada execução: estoura se a mais lenta passar
+		r.Over = percentileDur(ds, 100) > b
+	}
+	return r
+}
+
+// imprime tempo por etapa e por endpoint e marca o que passou do orçamento; devolve os estouros
+func printTimingReport(cmd string, total time.Duration, stepList []stepRecord, budgets timingBudgets) []string {
+	byStep := map[string][]time.Duration{}
+	var stepOrder []string
+	byEP := map[string][]time.Duration{}
+	for _, st := range stepList {
+		if st.Skipped {
+			continue
+		}
+		if _, seen := byStep[st.Name]; !seen {
+			stepOrder = append(stepOrder, st.Name)
+		}
+		byStep[st.Name] = append(byStep[st.Name], st.Duration)
+		for _, c := range st.Calls {
+			ep := callEndpoint(c.Method, c.URL)
+			byEP[ep] = append(byEP[ep], time.Duration(c.LatencyMS)*time.Millisecond)
+		}
+	}
+	epOrder := make([]string, 0, len(byEP))
+	for ep := range byEP {
+		epOrder = append(epOrder, ep)
+	}
+	sort.Slice(epOrder, func(i, j int) bool {
+		return sumDur(byEP[epOrder[i]]) > sumDur(byEP[epOrder[j]])
+	})
+
+	var over []string
+	var stepRows, epRows []timingRow
+	tw := tabwriter.NewWriter(humanOut, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, tr("[timing]\tn\ttotal\tmédia\tp95\tmáx\torçamento\t\n"))
+	for _, name := range stepOrder {
+		r := newTimingRow(name, byStep[name], budgets)
+		stepRows = append(stepRows, r)
+		writeTimingRow(tw, tr("etapa ")+name, r)
+		if r.Over {
+			over = append(over, tr("etapa ")+name)
+		}
+	}
+	for _, ep := range epOrder {
+		r := newTimingRow(ep, byEP[ep], budgets)
+		epRows = append(epRows, r)
+		writeTimingRow(tw, "endpoint "+ep, r)
+		if r.Over {
+			over = append(over, "endpoint "+ep)
+		}
+	}
+	totalRow := newTimingRow("total", []time.Duration{total}, budgets)
+	writeTimingRow(tw, "total ("+cmd+")", totalRow)
+	if totalRow.Over {
+		over = append(over, "total")
+	}
+	_ = tw.Flush()
+	if len(over) > 0 {
+		outf("[timing] ⚠ acima do orçamento: %s\n", strings.Join(over, ", "))
+	}
+	setResult("timing", map[string]any{"steps": stepRows, "endpoints": epRows, "total": totalRow, "over_budget": over})
+	return over
+}
+
+func writeTimingRow(tw *tabwriter.Writer, label string, r timingRow) {
+	budget, flag := "-", ""
+	if r.BudgetMS > 0 {
+		budget = fmt.Sprintf("%dms", r.BudgetMS)
+		if r.Over {
+			flag = "⚠"
+		}
+	}
+	fmt.Fprintf(tw, "  %s\t%d\t%dms\t%dms\t%dms\t%dms\t%s\t%s\n",
+		label, r.Count, r.TotalMS, r.AvgMS, r.P95MS, r.MaxMS, budget, flag)
+}
+
+func sumDur(ds []time.Duration) time.Duration {
+	var t time.Duration
+	for _, d := range ds {
+		t += d
+	}
+	return t
+}
+
+// budgets do ambiente (TIMING_BUDGETS) com --budget por cima
+func loadBudgets(flagVal string) (timingBudgets, error) {
+	b, err := parseBudgets(os.Getenv("TIMING_BUDGETS"))
+	if err != nil {
+		return nil, fmt.Errorf("TIMING_BUDGETS: %w", err)
+	}
+	fb, err := parseBudgets(flagVal)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range fb {
+		b[k] = v
+	}
+	return b, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+/* ==================== token-info e validade do token antes de execuções longas ==================== */
+
+type tokenInfo struct {
+	JWT       bool       `json:"jwt"`
+	Length    int        `json:"length"`
+	Issuer    string     `json:"iss,omitempty"`
+	Subject   string     `json:"sub,omitempty"`
+	ClientID  string     `json:"client_id,omitempty"`
+	Audience  []string   `json:"aud,omitempty"`
+	Scope     string     `json:"scope,omitempty"`
+	IssuedAt  *time.Time `json:"iat,omitempty"`
+	NotBefore *time.Time `json:"nbf,omitempty"`
+	ExpiresAt *time.Time `json:"exp,omitempty"`
+	Expired   bool       `json:"expired"`
+}
+
+// token vencido ou ausente detectado localmente → exit 3, como o 401
+type authError string
+
+func (e authError) Error() string { return string(e) }
+
+func isAuthError(err error) bool {
+	var ae authError
+	return errors.As(err, &ae)
+}
+
+// claims conhecidas do JWT; token opaco só informa o tamanho
+func inspectToken(token string) tokenInfo {
+	ti := tokenInfo{Length: len(token)}
+	claims, ok := decodeJWTClaims(token)
+	if !ok {
+		return ti
+	}
+	ti.JWT = true
+	str := func(k string) string { s, _ := claims[k].(string); return s }
+	ti.Issuer, ti.Subject, ti.Scope = str("iss"), str("sub"), str("scope")
+	ti.ClientID = str("client_id")
+	if ti.ClientID == "" {
+		ti.ClientID = str("azp")
+	}
+	switch aud := claims["aud"].(type) {
+	case string:
+		ti.Audience = []string{aud}
+	case []any:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				ti.Audience = append(ti.Audience, s)
+			}
+		}
+	}
+	unix := func(k string) *time.Time {
+		f, ok := claims[k].(float64)
+		if !ok {
+			return nil
+		}
+		t := time.Unix(int64(f), 0)
+		return &t
+	}
+	ti.IssuedAt, ti.NotBefore, ti.ExpiresAt = unix("iat"), unix("nbf"), unix("exp")
+	ti.Expired = ti.ExpiresAt != nil && !time.Now().Before(*ti.ExpiresAt)
+	return ti
+}
+
+// tempo até o exp; false sem exp (ou sem JWT)
+func (ti tokenInfo) validFor() (time.Duration, bool) {
+	if ti.ExpiresAt == nil {
+		return 0, false
+	}
+	return time.Until(*ti.ExpiresAt), true
+}
+
+func cmdTokenInfo(token string) error {
+	if token == "" {
+		return authError(tr("AUTH_TOKEN não definido (ENV, --profile, login ou OAUTH_*)"))
+	}
+	ti := inspectToken(token)
+	setResult("token", ti)
+	if !ti.JWT {
+		outf("[token] opaco (%d caracteres, não é JWT): emissor e validade não verificáveis\n", ti.Length)
+		return nil
+	}
+	row := func(k, v string) {
+		if v != "" {
+			outf("  %-10s %s\n", k, v)
+		}
+	}
+	at := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Local().Format(time.RFC3339)
+	}
+	aud := append([]string(nil), ti.Audience...)
+	sort.Strings(aud)
+	row("issuer", ti.Issuer)
+	row("audience", strings.Join(aud, ", "))
+	row("subject", ti.Subject)
+	row("client_id", ti.ClientID)
+	row("scope", ti.Scope)
+	row(tr("emitido"), at(ti.IssuedAt))
+	row(tr("válido de"), at(ti.NotBefore))
+	left, ok := ti.validFor()
+	switch {
+	case !ok:
+		row(tr("expira"), tr("sem exp"))
+	case left <= 0:
+		row(tr("expira"), fmt.Sprintf(tr("%s (expirou há %s)"), at(ti.ExpiresAt), (-left).Round(time.Second)))
+		return authError(fmt.Sprintf(tr("token expirou em %s"), at(ti.ExpiresAt)))
+	default:
+		row(tr("expira"), fmt.Sprintf(tr("%s (em %s)"), at(ti.ExpiresAt), left.Round(time.Second)))
+	}
+	return nil
+}
+
+// duração esperada dos comandos longos, para conferir o exp antes de começar;
+// --expected-duration (ENV EXPECTED_DURATION) vale para qualquer comando
+var longRunEstimates = map[string]time.Duration{
+	"batch-create": 15 * time.Minute,
+	"batch-verify": 15 * time.Minute,
+	"delete-cards": 10 * time.Minute,
+	"run-matrix":   10 * time.Minute,
+	"gen-data":     15 * time.Minute,
+	"watch":        time.Hour,
+	"serve":        time.Hour,
+}
+
+func expectedRunDuration(cmd string, args []string, override time.Duration) time.Duration {
+	if override > 0 {
+		return override
+	}
+	// load-verify e soak sabem quanto duram: --duration (default 30s / 8h) com folga
+	if cmd == "load-verify" || cmd == "soak" {
+		d := 30 * time.Second
+		if cmd == "soak" {
+			d = 8 * time.Hour
+		}
+		if _, v, ok, _ := stripValueFlag(args, "--duration"); ok {
+			if pd, err := time.ParseDuration(v); err == nil {
+				d = pd
+			}
+		}
+		return d + time.Minute
+	}
+	return longRunEstimates[cmd]
+}
+
+// antes de uma execução longa: token que vence no meio dela aborta (exit 3) no modo estrito;
+// com --no-strict só avisa. OAuth renova sozinho e não entra na conta
+func checkTokenValidity(token string, expected time.Duration) error {
+	if token == "" || oauth != nil {
+		return nil
+	}
+	left, ok := inspectToken(token).validFor()
+	if !ok {
+		return nil
+	}
+	var msg string
+	switch {
+	case left <= 0:
+		msg = fmt.Sprintf(tr("token expirou há %s"), (-left).Round(time.Second))
+	case expected > 0 && left < expected:
+		msg = fmt.Sprintf(tr("token expira em %s, antes do fim esperado da execução (~%s)"), left.Round(time.Second), expected)
+	default:
+		return nil
+	}
+	if expected > 0 && strict {
+		return authError(msg + tr("; renove (login/OAuth), ajuste --expected-duration ou use --no-strict"))
+	}
+	outf("[aviso] %s\n", msg)
+	return nil
+}
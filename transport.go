@@ -0,0 +1,211 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+/* ==================== Transporte HTTP (proxy, CA, mTLS, timeouts) ==================== */
+
+type transportOptions struct {
+	Proxy      string // URL do proxy; vazio = HTTP_PROXY/HTTPS_PROXY/NO_PROXY do ambiente
+	CACert     string // bundle PEM somado às CAs do sistema
+	ClientCert string // certificado do cliente (mTLS), PEM
+	ClientKey  string // chave do certificado do cliente, PEM
+
+	// ajuste fino; vazio = padrão do Go (ou do comando, no caso do Timeout)
+	Timeout      string // por requisição, do envio ao fim do corpo; 0 = sem limite
+	KeepAlive    string // intervalo do TCP keep-alive; 0 desliga conexões persistentes
+	IdleTimeout  string // quanto uma conexão ociosa fica no pool
+	MaxIdleConns string // conexões ociosas guardadas (total e por host)
+	TLSHandshake string // teto do handshake TLS
+}
+
+func (o transportOptions) empty() bool {
+	return o == transportOptions{}
+}
+
+// transporte usado pelo httpClient e pelo pedido de token OAuth
+var baseTransport http.RoundTripper = http.DefaultTransport
+
+// timeout do httpClient por comando: upload de imagem grande precisa de folga, o doctor não
+var commandTimeouts = map[string]time.Duration{
+	"create-card":  60 * time.Second,
+	"main-image":   60 * time.Second,
+	"update-card":  60 * time.Second,
+	"batch-create": 60 * time.Second,
+	"watch":        60 * time.Second,
+	"serve":        60 * time.Second,
+	"doctor":       5 * time.Second,
+}
+
+const defaultTimeout = 20 * time.Second
+
+// --timeout/HTTP_TIMEOUT, quando dado, vale para qualquer comando
+var timeoutOverride *time.Duration
+
+func timeoutFor(cmd string) time.Duration {
+	if timeoutOverride != nil {
+		return *timeoutOverride
+	}
+	if d, ok := commandTimeouts[cmd]; ok {
+		return d
+	}
+	return defaultTimeout
+}
+
+func parseTransportDuration(name, v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if v == "0" {
+		d, err = 0, nil
+	}
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf(tr("%s inválido: %q (use uma duração, ex.: 30s)"), name, v)
+	}
+	return d, nil
+}
+
+func buildTransport(o transportOptions) (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.KeepAlive != "" {
+		d, err := parseTransportDuration("--keep-alive", o.KeepAlive)
+		if err != nil {
+			return nil, err
+		}
+		if d == 0 {
+			t.DisableKeepAlives = true
+		} else {
+			t.DialContext = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: d}).DialContext
+		}
+	}
+	if o.IdleTimeout != "" {
+		d, err := parseTransportDuration("--idle-timeout", o.IdleTimeout)
+		if err != nil {
+			return nil, err
+		}
+		t.IdleConnTimeout = d
+	}
+	if o.MaxIdleConns != "" {
+		n, err := strconv.Atoi(o.MaxIdleConns)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf(tr("--max-idle-conns inválido: %q"), o.MaxIdleConns)
+		}
+		// o padrão do Go (2 por host) faz o load-verify abrir conexão nova a cada worker
+		t.MaxIdleConns, t.MaxIdleConnsPerHost = n, n
+	}
+	if o.TLSHandshake != "" {
+		d, err := parseTransportDuration("--tls-handshake-timeout", o.TLSHandshake)
+		if err != nil {
+			return nil, err
+		}
+		t.TLSHandshakeTimeout = d
+	}
+	if o.Proxy != "" {
+		u, err := url.Parse(o.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf(tr("--proxy inválido: %q"), o.Proxy)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	if o.CACert == "" && o.ClientCert == "" && o.ClientKey == "" {
+		return t, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if o.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(o.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("--ca-cert: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf(tr("--ca-cert: nenhum certificado PEM em %s"), o.CACert)
+		}
+		cfg.RootCAs = pool
+	}
+	if (o.ClientCert == "") != (o.ClientKey == "") {
+		return nil, fmt.Errorf(tr("--client-cert e --client-key precisam vir juntos"))
+	}
+	if o.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(o.ClientCert, o.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf(tr("certificado do cliente: %w"), err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = cfg
+	return t, nil
+}
+
+// flags por cima do ambiente (PROXY_URL, CA_CERT, CLIENT_CERT, CLIENT_KEY, HTTP_*)
+func stripTransportFlags(args []string) ([]string, transportOptions, error) {
+	o := transportOptions{
+		Proxy:        os.Getenv("PROXY_URL"),
+		CACert:       os.Getenv("CA_CERT"),
+		ClientCert:   os.Getenv("CLIENT_CERT"),
+		ClientKey:    os.Getenv("CLIENT_KEY"),
+		Timeout:      os.Getenv("HTTP_TIMEOUT"),
+		KeepAlive:    os.Getenv("HTTP_KEEP_ALIVE"),
+		IdleTimeout:  os.Getenv("HTTP_IDLE_TIMEOUT"),
+		MaxIdleConns: os.Getenv("HTTP_MAX_IDLE_CONNS"),
+		TLSHandshake: os.Getenv("HTTP_TLS_HANDSHAKE_TIMEOUT"),
+	}
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{"--proxy", &o.Proxy},
+		{"--ca-cert", &o.CACert},
+		{"--client-cert", &o.ClientCert},
+		{"--client-key", &o.ClientKey},
+		{"--timeout", &o.Timeout},
+		{"--keep-alive", &o.KeepAlive},
+		{"--idle-timeout", &o.IdleTimeout},
+		{"--max-idle-conns", &o.MaxIdleConns},
+		{"--tls-handshake-timeout", &o.TLSHandshake},
+	} {
+		var v string
+		var ok bool
+		var err error
+		args, v, ok, err = stripValueFlag(args, f.name)
+		if err != nil {
+			return nil, o, err
+		}
+		if ok {
+			*f.dst = expandHome(v)
+		}
+	}
+	return args, o, nil
+}
+
+// aplica no httpClient; chamado antes de --record/--replay, que embrulham este transporte.
+// O timeout só entra no httpClient quando o comando é conhecido (timeoutFor)
+func configureTransport(o transportOptions) error {
+	if o.Timeout != "" {
+		d, err := parseTransportDuration("--timeout", o.Timeout)
+		if err != nil {
+			return err
+		}
+		timeoutOverride = &d
+		o.Timeout = ""
+	}
+	if o.empty() {
+		return nil
+	}
+	t, err := buildTransport(o)
+	if err != nil {
+		return err
+	}
+	baseTransport = t
+	httpClient.Transport = t
+	return nil
+}
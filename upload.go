@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/* ==================== --encoding (base64, data URI ou multipart) ==================== */
+
+const (
+	encBase64    = "base64"    // JSON com a imagem em base64 puro (padrão do register)
+	encDataURI   = "datauri"   // JSON com data:image/...;base64, (padrão do verify)
+	encMultipart = "multipart" // multipart/form-data com a imagem em binário (sem os +33% do base64)
+)
+
+func validEncoding(enc string) error {
+	switch enc {
+	case "", encBase64, encDataURI, encMultipart:
+		return nil
+	}
+	return usageError(fmt.Sprintf(tr("--encoding inválido: %q (use multipart, base64 ou datauri)"), enc))
+}
+
+// corpo com os campos + imagem no formato pedido; devolve o Content-Type junto.
+// Sem pré-processamento a imagem não é carregada: vai do arquivo direto para o corpo
+func encodeImagePayload(fields map[string]any, imagePath, enc string) (string, reqBody, error) {
+	img, size, mimeType, err := openUploadImage(imagePath)
+	if err != nil {
+		return "", reqBody{}, err
+	}
+	var ct string
+	var prefix, suffix []byte
+	if enc != encMultipart {
+		ct = "application/json"
+		prefix, suffix, err = jsonImageFrame(fields, mimeType, enc == encDataURI)
+	} else {
+		ct, prefix, suffix, err = multipartImageFrame(fields, imagePath, mimeType)
+	}
+	if err != nil {
+		return "", reqBody{}, err
+	}
+	part := imagePart{path: imagePath, data: img, size: size, base64: enc != encMultipart}
+	if img != nil && len(img) < streamThreshold {
+		return ct, reqBody{data: part.bytes(prefix, suffix)}, nil
+	}
+	return ct, reqBody{stream: &streamBody{prefix: prefix, part: part, suffix: suffix}}, nil
+}
+
+// {"campos...","image":"  +  imagem  +  "}  (json.Marshal ordena as chaves; image vai por último)
+func jsonImageFrame(fields map[string]any, mimeType string, dataURI bool) ([]byte, []byte, error) {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf(tr("marshal body: %w"), err)
+	}
+	prefix := bytes.TrimSuffix(b, []byte("}"))
+	if len(fields) > 0 {
+		prefix = append(prefix, ',')
+	}
+	prefix = append(prefix, `"image":"`...)
+	if dataURI {
+		prefix = append(prefix, "data:"+mimeType+";base64,"...)
+	}
+	return prefix, []byte(`"}`), nil
+}
+
+// campos + cabeçalho da parte "image" antes, boundary final depois
+func multipartImageFrame(fields map[string]any, imagePath, mimeType string) (string, []byte, []byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := mw.WriteField(k, fmt.Sprint(fields[k])); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	// a imagem pode ter sido reencodada em JPEG pelo pré-processamento
+	name := filepath.Base(imagePath)
+	if mimeType == "image/jpeg" && !strings.HasSuffix(strings.ToLower(name), ".jpg") && !strings.HasSuffix(strings.ToLower(name), ".jpeg") {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + ".jpg"
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="image"; filename=%q`, name))
+	h.Set("Content-Type", mimeType)
+	if _, err := mw.CreatePart(h); err != nil {
+		return "", nil, nil, err
+	}
+	prefix := bytes.Clone(buf.Bytes())
+	buf.Reset()
+	if err := mw.Close(); err != nil {
+		return "", nil, nil, err
+	}
+	return mw.FormDataContentType(), prefix, bytes.Clone(buf.Bytes()), nil
+}
+
+/* ==================== Corpo em stream ==================== */
+
+// abaixo disso (imagem já em memória) não compensa o pipe
+const streamThreshold = 256 << 10
+
+// corpo da requisição: bytes prontos ou montado do arquivo a cada tentativa
+type reqBody struct {
+	data   []byte
+	stream *streamBody
+}
+
+// reader novo a cada chamada (o retry reenvia do começo); size = Content-Length
+func (b reqBody) open() (io.Reader, int64, error) {
+	if b.stream != nil {
+		r, err := b.stream.open()
+		return r, b.stream.size(), err
+	}
+	if b.data == nil {
+		return nil, 0, nil
+	}
+	return bytes.NewReader(b.data), int64(len(b.data)), nil
+}
+
+// o que o -vv mostra: com stream só as bordas, sem ler a imagem
+func (b reqBody) dumpable() []byte {
+	if b.stream == nil {
+		return b.data
+	}
+	s := b.stream
+	return []byte(fmt.Sprintf("%s<imagem em stream: %s, %d bytes>%s", s.prefix, s.part.path, s.part.encodedSize(), s.suffix))
+}
+
+type streamBody struct {
+	prefix []byte
+	part   imagePart
+	suffix []byte
+}
+
+func (s *streamBody) size() int64 {
+	return int64(len(s.prefix)) + s.part.encodedSize() + int64(len(s.suffix))
+}
+
+// prefix + imagem (base64 via pipe, ou binária) + suffix
+func (s *streamBody) open() (io.ReadCloser, error) {
+	img, err := s.part.open()
+	if err != nil {
+		return nil, err
+	}
+	r := io.MultiReader(bytes.NewReader(s.prefix), img, bytes.NewReader(s.suffix))
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, img}, nil
+}
+
+// a imagem do corpo: em memória (pré-processada) ou lida do disco
+type imagePart struct {
+	path   string
+	data   []byte // nil = lê de path
+	size   int64  // bytes crus
+	base64 bool
+}
+
+func (p imagePart) encodedSize() int64 {
+	if p.base64 {
+		return int64(base64.StdEncoding.EncodedLen(int(p.size)))
+	}
+	return p.size
+}
+
+func (p imagePart) open() (io.ReadCloser, error) {
+	var src io.ReadCloser
+	if p.data != nil {
+		src = io.NopCloser(bytes.NewReader(p.data))
+	} else {
+		f, err := os.Open(p.path)
+		if err != nil {
+			return nil, err
+		}
+		// o arquivo mudou entre a checagem e o envio: o Content-Length já não bate
+		if st, err := f.Stat(); err != nil || st.Size() != p.size {
+			f.Close()
+			return nil, fmt.Errorf(tr("%s mudou durante o envio"), p.path)
+		}
+		src = f
+	}
+	if !p.base64 {
+		return src, nil
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		enc := base64.NewEncoder(base64.StdEncoding, pw)
+		_, err := io.Copy(enc, src)
+		if err == nil {
+			err = enc.Close()
+		}
+		src.Close()
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+// monta o corpo inteiro em memória (imagens pequenas)
+func (p imagePart) bytes(prefix, suffix []byte) []byte {
+	out := make([]byte, 0, int64(len(prefix))+p.encodedSize()+int64(len(suffix)))
+	out = append(out, prefix...)
+	if p.base64 {
+		out = base64.StdEncoding.AppendEncode(out, p.data)
+	} else {
+		out = append(out, p.data...)
+	}
+	return append(out, suffix...)
+}
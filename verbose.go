@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/* ==================== -v/--verbose (dump de requisições) ==================== */
+
+// 0 = desligado; 1 = linha + tempos; 2 = + headers e corpos truncados; 3 = corpos inteiros
+var verbosity int
+
+// tamanho máximo de corpo impresso no nível 2
+const verboseBodyLimit = 2048
+
+// -v, -vv, -vvv, --verbose (= 1) e --verbose=N em qualquer posição
+func stripVerbose(all []string) ([]string, int, error) {
+	out := make([]string, 0, len(all))
+	level := 0
+	for _, a := range all {
+		switch {
+		case a == "-v" || a == "-vv" || a == "-vvv":
+			level = max(level, len(a)-1)
+		case a == "--verbose":
+			level = max(level, 1)
+		case strings.HasPrefix(a, "--verbose="):
+			n, err := strconv.Atoi(strings.TrimPrefix(a, "--verbose="))
+			if err != nil || n < 0 || n > 3 {
+				return nil, 0, fmt.Errorf(tr("--verbose deve ser 0..3, veio %q"), a)
+			}
+			level = max(level, n)
+		default:
+			out = append(out, a)
+		}
+	}
+	return out, level, nil
+}
+
+// marcos da conexão, preenchidos pelo httptrace
+type requestTrace struct {
+	start                  time.Time
+	dnsStart, dnsDone      time.Time
+	connStart, connDone    time.Time
+	tlsStart, tlsDone      time.Time
+	gotConn, firstByte     time.Time
+	reused                 bool
+	remoteAddr, tlsVersion string
+}
+
+func withTrace(req *http.Request) (*http.Request, *requestTrace) {
+	rt := &requestTrace{start: time.Now()}
+	ct := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { rt.dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { rt.dnsDone = time.Now() },
+		ConnectStart:      func(_, _ string) { rt.connStart = time.Now() },
+		ConnectDone:       func(_, _ string, _ error) { rt.connDone = time.Now() },
+		TLSHandshakeStart: func() { rt.tlsStart = time.Now() },
+		TLSHandshakeDone: func(cs tls.ConnectionState, _ error) {
+			rt.tlsDone = time.Now()
+			rt.tlsVersion = tls.VersionName(cs.Version)
+		},
+		GotConn: func(ci httptrace.GotConnInfo) {
+			rt.gotConn = time.Now()
+			rt.reused = ci.Reused
+			if ci.Conn != nil {
+				rt.remoteAddr = ci.Conn.RemoteAddr().String()
+			}
+		},
+		GotFirstResponseByte: func() { rt.firstByte = time.Now() },
+	}
+	ctx := httptrace.WithClientTrace(req.Context(), ct)
+	return req.WithContext(ctx), rt
+}
+
+func spanMS(a, b time.Time) string {
+	if a.IsZero() || b.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%dms", b.Sub(a).Milliseconds())
+}
+
+// "dns=3ms connect=10ms tls=25ms ttfb=180ms total=190ms"
+func (rt *requestTrace) breakdown(end time.Time) string {
+	ttfb := "-"
+	if !rt.firstByte.IsZero() {
+		from := rt.gotConn
+		if from.IsZero() {
+			from = rt.start
+		}
+		ttfb = spanMS(from, rt.firstByte)
+	}
+	s := fmt.Sprintf("dns=%s connect=%s tls=%s ttfb=%s total=%s",
+		spanMS(rt.dnsStart, rt.dnsDone), spanMS(rt.connStart, rt.connDone),
+		spanMS(rt.tlsStart, rt.tlsDone), ttfb, spanMS(rt.start, end))
+	if rt.reused {
+		s += tr(" (conexão reaproveitada)")
+	}
+	if rt.remoteAddr != "" {
+		s += " remoto=" + rt.remoteAddr
+	}
+	if rt.tlsVersion != "" {
+		s += " " + rt.tlsVersion
+	}
+	return s
+}
+
+func dumpRequest(req *http.Request, body []byte) {
+	outf("[http] → %s %s\n", req.Method, redactQuery(req.URL.String()))
+	if verbosity < 2 {
+		return
+	}
+	dumpHeaders("→", req.Header)
+	dumpBody("→", body)
+}
+
+func dumpResponse(resp *http.Response, body []byte, rt *requestTrace, err error) {
+	end := time.Now()
+	if err != nil {
+		outf("[http] ← erro: %v | %s\n", err, rt.breakdown(end))
+		return
+	}
+	outf("[http] ← %s %s | %s\n", resp.Proto, resp.Status, rt.breakdown(end))
+	if verbosity < 2 {
+		return
+	}
+	dumpHeaders("←", resp.Header)
+	dumpBody("←", body)
+}
+
+func dumpHeaders(dir string, h http.Header) {
+	c := h.Clone()
+	redactHeaders(c, nil)
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		outf("[http] %s %s: %s\n", dir, k, strings.Join(c[k], ", "))
+	}
+}
+
+func dumpBody(dir string, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	outf("[http] %s corpo (%d bytes):\n%s\n", dir, len(b), abbreviateBody(b, verbosity >= 3))
+}
+
+// JSON com segredos mascarados e imagens resumidas; binário vira só o tamanho
+func abbreviateBody(b []byte, full bool) string {
+	var v any
+	var s string
+	switch {
+	case json.Unmarshal(b, &v) == nil:
+		s = string(indentJSON(abbreviateJSON(v, ""), "  "))
+		s = strings.TrimSuffix(s, "\n")
+	case utf8.Valid(b):
+		s = string(b)
+	default:
+		return fmt.Sprintf(tr("<binário, %d bytes>"), len(b))
+	}
+	if !full && len(s) > verboseBodyLimit {
+		s = s[:verboseBodyLimit] + fmt.Sprintf(tr("… (+%d bytes; -vvv mostra tudo)"), len(s)-verboseBodyLimit)
+	}
+	return s
+}
+
+func abbreviateJSON(v any, key string) any {
+	k := strings.ToLower(key)
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for kk, vv := range x {
+			out[kk] = abbreviateJSON(vv, kk)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i := range x {
+			out[i] = abbreviateJSON(x[i], key)
+		}
+		return out
+	case string:
+		if sensitiveKeys[k] {
+			return redacted
+		}
+		// corpo em stream já chega resumido pelo dumpable
+		if imageKeys[k] && len(x) > 64 && !strings.Contains(x, "<imagem em stream") {
+			prefix := ""
+			if i := strings.Index(x, ","); strings.HasPrefix(x, "data:") && i > 0 {
+				prefix = x[:i] + " "
+			}
+			return fmt.Sprintf(tr("<%simagem base64, %d bytes>"), prefix, len(x))
+		}
+	}
+	return v
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
+)
+
+/* ==================== watch (pasta do quiosque) ==================== */
+
+type watchOptions struct {
+	Dir      string
+	Interval time.Duration
+	Existing bool // verifica também o que já estava na pasta ao iniciar
+	Max      int  // para depois de N imagens (0 = até Ctrl+C)
+	Verify   batchVerifyOptions
+}
+
+// estado de um arquivo visto na varredura; só é verificado quando tamanho e
+// mtime repetem entre duas varreduras (a cópia/gravação terminou)
+type watchedFile struct {
+	size int64
+	mod  time.Time
+	done bool
+}
+
+// watch: varre a pasta a cada intervalo e verifica cada imagem nova, imprimindo na hora
+func cmdWatch(baseURL, token string, opt watchOptions) error {
+	if st, err := os.Stat(opt.Dir); err != nil {
+		return err
+	} else if !st.IsDir() {
+		return usageError(opt.Dir + tr(" não é um diretório"))
+	}
+	var re *regexp.Regexp
+	if opt.Verify.ID == "" {
+		var err error
+		re, err = regexp.Compile(opt.Verify.IDRegex)
+		if err != nil {
+			return usageError(tr("--id-regex inválida: ") + err.Error())
+		}
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = time.Second
+	}
+
+	seen := map[string]*watchedFile{}
+	files, err := collectImages(opt.Dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if st, err := os.Stat(f); err == nil {
+			// sem --existing o que já estava lá conta como verificado
+			seen[f] = &watchedFile{size: st.Size(), mod: st.ModTime(), done: !opt.Existing}
+		}
+	}
+	if opt.Existing {
+		outf("[watch] observando %s (%d imagem(ns) já presentes entram na fila); Ctrl+C encerra\n", opt.Dir, len(files))
+	} else {
+		outf("[watch] observando %s (ignorando %d imagem(ns) já presentes); Ctrl+C encerra\n", opt.Dir, len(files))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	url := verifyURL(baseURL, "")
+	var results []verifyResult
+	start := time.Now()
+	t := time.NewTicker(opt.Interval)
+	defer t.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-t.C:
+		}
+		files, err := collectImages(opt.Dir)
+		if err != nil {
+			outf("[watch] ⚠ varredura falhou: %v\n", err)
+			continue
+		}
+		for _, f := range files {
+			if ctx.Err() != nil {
+				break loop
+			}
+			st, err := os.Stat(f)
+			if err != nil {
+				continue // apagado entre a listagem e o stat
+			}
+			w := seen[f]
+			switch {
+			case w == nil:
+				seen[f] = &watchedFile{size: st.Size(), mod: st.ModTime()}
+				continue
+			case w.size != st.Size() || !w.mod.Equal(st.ModTime()):
+				// ainda sendo gravado, ou substituído depois de verificado
+				w.size, w.mod, w.done = st.Size(), st.ModTime(), false
+				continue
+			case w.done:
+				continue
+			}
+			w.done = true
+			res := verifyImageFile(baseURL, token, url, re, f, opt.Verify)
+			printWatchResult(res)
+			results = append(results, res)
+			if opt.Max > 0 && len(results) >= opt.Max {
+				break loop
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		outln("[watch] encerrado sem nenhuma imagem nova")
+		return nil
+	}
+	errs := printVerifySummary("watch", results, time.Since(start))
+	setResult("results", results)
+	if errs > 0 {
+		return fmt.Errorf(tr("%d de %d verificação(ões) com erro"), errs, len(results))
+	}
+	return nil
+}
+
+func printWatchResult(r verifyResult) {
+	name := filepath.Base(r.File)
+	switch {
+	case r.Error != "":
+		outf("[watch] ⚠ %s id=%s: %s\n", name, orDash(r.ID), r.Error)
+	case r.Match:
+		outf("[watch] ✅ %s id=%s similaridade=%s (%dms)\n", name, r.ID, watchSim(r), r.LatencyMS)
+	default:
+		outf("[watch] ❌ %s id=%s sem match, similaridade=%s (%dms)\n", name, r.ID, watchSim(r), r.LatencyMS)
+	}
+}
+
+func watchSim(r verifyResult) string {
+	if !r.HasScore {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", r.Similarity)
+}
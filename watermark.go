@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	stddraw "image/draw"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+/* ==================== --watermark (carimbo TEST nas imagens enviadas) ==================== */
+
+// faixa na base da imagem (longe do rosto, para não mexer no score) com texto branco
+func stampWatermark(img image.Image, text string) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	stddraw.Draw(dst, dst.Bounds(), img, b.Min, stddraw.Src)
+
+	// texto na fonte bitmap 7x13 e ampliado para ~60% da largura
+	face := basicfont.Face7x13
+	tw := font.MeasureString(face, text).Ceil() + 4
+	small := image.NewRGBA(image.Rect(0, 0, tw, 17))
+	d := &font.Drawer{Dst: small, Src: image.White, Face: face, Dot: fixed.P(2, 13)}
+	d.DrawString(text)
+
+	scale := max(1, dst.Bounds().Dx()*3/5/tw)
+	bandH := min(small.Bounds().Dy()*scale+scale*4, dst.Bounds().Dy()/4)
+	scale = max(1, min(scale, (bandH-4)/small.Bounds().Dy()))
+	band := image.Rect(0, dst.Bounds().Dy()-bandH, dst.Bounds().Dx(), dst.Bounds().Dy())
+	stddraw.Draw(dst, band, image.NewUniform(color.NRGBA{200, 0, 0, 170}), image.Point{}, stddraw.Over)
+
+	w, h := tw*scale, small.Bounds().Dy()*scale
+	x0 := (dst.Bounds().Dx() - w) / 2
+	y0 := band.Min.Y + (bandH-h)/2
+	draw.NearestNeighbor.Scale(dst, image.Rect(x0, y0, x0+w, y0+h), small, small.Bounds(), draw.Over, nil)
+	return dst
+}